@@ -81,10 +81,13 @@ def updateRelease(id, owner, repo, release_name, body, prerelease):
     url = f"{apiHost}/repos/{owner}/{repo}/releases/{id}"
 
     data = {
-        "name": release_name,
-        "body": body,
         "prerelease": prerelease
     }
+    # Only overwrite the name and notes when given, so uploading another asset keeps them
+    if release_name is not None:
+        data["name"] = release_name
+    if body is not None:
+        data["body"] = body
 
     resp = requests.patch(url, headers=authHeader, json=data)
     if not resp.ok:
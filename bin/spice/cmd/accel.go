@@ -0,0 +1,301 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const (
+	limitFlag    = "limit"
+	snapshotFlag = "snapshot"
+	engineFlag   = "engine"
+	modeFlag     = "mode"
+
+	// Share of --limit at which a dataset is flagged as close to it.
+	nearLimitRatio = 0.8
+)
+
+type accelChange struct {
+	Setting string
+	Before  string
+	After   string
+}
+
+type accelUsageRow struct {
+	Dataset string
+	Engine  string
+	Mode    string
+	Size    string
+	Growth  string
+	Status  string
+	Path    string
+}
+
+var accelCmd = &cobra.Command{
+	Use:   "accel",
+	Short: "Inspect dataset accelerations",
+	Example: `
+spice accel usage
+spice accel enable taxi_trips --engine duckdb --mode file
+spice accel disable taxi_trips
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var accelUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Summarize the storage used by each accelerated dataset",
+	Example: `
+spice accel usage
+spice accel usage --limit 10GiB
+spice accel usage --snapshot
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+
+		var limit int64
+		if limitValue, _ := cmd.Flags().GetString(limitFlag); limitValue != "" {
+			var err error
+			limit, err = util.ParseBytes(limitValue)
+			if err != nil {
+				return err
+			}
+		}
+
+		datasets, err := spicepod.LoadDatasets(rtcontext.AppDir())
+		if err != nil {
+			return err
+		}
+
+		usages, err := accel.MeasureUsage(rtcontext.AppDir(), datasets)
+		if err != nil {
+			return err
+		}
+		if len(usages) == 0 {
+			cmd.Println("No accelerated datasets found in spicepod.yaml.")
+			return nil
+		}
+
+		snapshot, err := accel.LoadUsageSnapshot(rtcontext.AppDir())
+		if err != nil {
+			return err
+		}
+
+		var rows []interface{}
+		for _, usage := range usages {
+			row := accelUsageRow{Dataset: usage.Dataset, Engine: usage.Engine, Mode: usage.Mode, Size: "-", Growth: "-", Path: "-"}
+			if len(usage.Paths) > 0 {
+				paths := make([]string, 0, len(usage.Paths))
+				for _, path := range usage.Paths {
+					paths = append(paths, rtcontext.GetSpiceAppRelativePath(path))
+				}
+				row.Path = strings.Join(paths, ",")
+			}
+			if usage.Size >= 0 {
+				row.Size = util.FormatBytes(float64(usage.Size))
+				if snapshot != nil {
+					if previous, ok := snapshot.Sizes[usage.Dataset]; ok {
+						row.Growth = formatGrowth(usage.Size - previous)
+					}
+				}
+				switch {
+				case limit > 0 && usage.Size >= limit:
+					row.Status = "over limit"
+				case limit > 0 && float64(usage.Size) >= float64(limit)*nearLimitRatio:
+					row.Status = "near limit"
+				}
+			}
+			rows = append(rows, row)
+		}
+		if err := output(cmd).WriteTable(rows); err != nil {
+			return err
+		}
+
+		if snapshot != nil {
+			cmd.Printf("Growth is since the snapshot taken %s.\n", snapshot.Time.Local().Format(time.RFC1123))
+		}
+		if saveSnapshot, _ := cmd.Flags().GetBool(snapshotFlag); saveSnapshot {
+			err = accel.SaveUsageSnapshot(rtcontext.AppDir(), usages, time.Now())
+			if err != nil {
+				return err
+			}
+			cmd.Println("Saved a snapshot of the current sizes.")
+		}
+		return nil
+	},
+}
+
+var accelEnableCmd = &cobra.Command{
+	Use:               "enable <dataset>",
+	Short:             "Accelerate a dataset and report how long its initial load took",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice accel enable taxi_trips
+spice accel enable taxi_trips --engine duckdb --mode file
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAcceleration(cmd, args[0], true)
+	},
+}
+
+var accelDisableCmd = &cobra.Command{
+	Use:               "disable <dataset>",
+	Short:             "Stop accelerating a dataset so queries are federated to its source",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice accel disable taxi_trips
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAcceleration(cmd, args[0], false)
+	},
+}
+
+// setAcceleration switches a dataset between federated and accelerated in its definition. The
+// runtime has no API to change a dataset's acceleration, so the change is applied when the
+// runtime reloads the spicepod.
+func setAcceleration(cmd *cobra.Command, datasetName string, enabled bool) error {
+	rtcontext := newRuntimeContext(cmd)
+	definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), datasetName)
+	if err != nil {
+		return err
+	}
+
+	type setting struct {
+		path  string
+		value interface{}
+	}
+	settings := []setting{{path: "acceleration.enabled", value: enabled}}
+	if cmd.Flags().Changed(engineFlag) {
+		engine, _ := cmd.Flags().GetString(engineFlag)
+		if !slices.Contains(accel.Engines, engine) {
+			return fmt.Errorf("Unsupported acceleration engine '%s'", engine)
+		}
+		settings = append(settings, setting{path: "acceleration.engine", value: engine})
+	}
+	if cmd.Flags().Changed(modeFlag) {
+		mode, _ := cmd.Flags().GetString(modeFlag)
+		if mode != accel.MODE_MEMORY && mode != accel.MODE_FILE {
+			return fmt.Errorf("Unsupported acceleration mode '%s', use %s or %s", mode, accel.MODE_MEMORY, accel.MODE_FILE)
+		}
+		settings = append(settings, setting{path: "acceleration.mode", value: mode})
+	}
+
+	var changes []interface{}
+	for _, s := range settings {
+		before := "-"
+		if value := definition.Get(s.path); value != nil {
+			before = fmt.Sprintf("%v", value)
+		}
+		after := fmt.Sprintf("%v", s.value)
+		if before == after {
+			continue
+		}
+		definition.Set(s.path, s.value)
+		changes = append(changes, accelChange{Setting: s.path, Before: before, After: after})
+	}
+
+	if len(changes) == 0 {
+		cmd.Printf("No changes, %s is already configured as requested.\n", definition.Name)
+		return nil
+	}
+
+	err = definition.Save()
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Updated %s\n", rtcontext.GetSpiceAppRelativePath(definition.FilePath))
+	if err := output(cmd).WriteTable(changes); err != nil {
+		return err
+	}
+
+	wait, _ := cmd.Flags().GetBool(waitFlag)
+	if !wait || rtcontext.IsRuntimeHealthy(2*time.Second) != nil {
+		cmd.Println("The change applies when the runtime loads spicepod.yaml.")
+		return nil
+	}
+
+	readyTimeout, _ := cmd.Flags().GetDuration(readyTimeoutFlag)
+	message := fmt.Sprintf("Waiting for %s to load", definition.Name)
+	if !enabled {
+		message = fmt.Sprintf("Waiting for %s to reload", definition.Name)
+	}
+	start := time.Now()
+	spinner := progress.NewSpinner(progressOutput(cmd), message)
+	spinner.Start()
+	err = bench.WaitForDataset(rtcontext, metricsEndpoint(cmd), definition.Name, readyTimeout)
+	if err != nil {
+		spinner.Stop("failed")
+		return err
+	}
+	spinner.Stop("ready")
+
+	if enabled {
+		cmd.Printf("%s is accelerated, the initial load took %s.\n", definition.Name, time.Since(start).Round(time.Millisecond))
+	} else {
+		cmd.Printf("%s is federated, queries now go to its source.\n", definition.Name)
+	}
+	return nil
+}
+
+func formatGrowth(delta int64) string {
+	if delta < 0 {
+		return "-" + util.FormatBytes(float64(-delta))
+	}
+	return "+" + util.FormatBytes(float64(delta))
+}
+
+func init() {
+	accelUsageCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	accelUsageCmd.Flags().String(limitFlag, "", "Flag datasets whose acceleration is close to or over this size, e.g. 10GiB")
+	accelUsageCmd.Flags().Bool(snapshotFlag, false, "Save the current sizes to compare later reports against")
+	accelCmd.AddCommand(accelUsageCmd)
+
+	accelEnableCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	accelEnableCmd.Flags().String(engineFlag, "", "Acceleration engine: arrow, duckdb, sqlite or postgres")
+	accelEnableCmd.Flags().String(modeFlag, "", "Acceleration mode: memory or file")
+	accelEnableCmd.Flags().Bool(waitFlag, true, "Wait for the running runtime to load the dataset")
+	accelEnableCmd.Flags().Duration(readyTimeoutFlag, 5*time.Minute, "How long to wait for the dataset to load")
+	accelCmd.AddCommand(accelEnableCmd)
+
+	accelDisableCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	accelDisableCmd.Flags().Bool(waitFlag, true, "Wait for the running runtime to reload the dataset")
+	accelDisableCmd.Flags().Duration(readyTimeoutFlag, 5*time.Minute, "How long to wait for the dataset to reload")
+	accelCmd.AddCommand(accelDisableCmd)
+
+	accelCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(accelCmd)
+}
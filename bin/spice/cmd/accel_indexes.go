@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+const keyFlag = "key"
+
+var accelIndexesCmd = &cobra.Command{
+	Use:               "indexes <dataset>",
+	Short:             "Show the indexes, primary key and on-conflict behavior of a dataset's acceleration",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice accel indexes orders
+spice accel indexes orders --key customer_id
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		datasets, err := spicepod.LoadDatasets(rtcontext.AppDir())
+		if err != nil {
+			return err
+		}
+
+		var dataset *spec.DatasetSpec
+		for _, d := range datasets {
+			if d.Name == args[0] {
+				dataset = d
+			}
+		}
+		if dataset == nil {
+			return fmt.Errorf("Dataset %s not found in spicepod.yaml", args[0])
+		}
+		if !accel.IsAccelerated(dataset) {
+			return fmt.Errorf("Dataset %s is not accelerated", dataset.Name)
+		}
+
+		indexes, err := accel.InspectIndexes(rtcontext.AppDir(), dataset)
+		if err != nil {
+			cmd.PrintErrf("Unable to inspect the acceleration: %s\n", err.Error())
+		} else if len(indexes) == 0 {
+			cmd.Printf("The %s acceleration of dataset %s has no indexes or key constraints\n", accel.Engine(dataset.Acceleration), dataset.Name)
+		} else {
+			table := make([]interface{}, len(indexes))
+			for i, index := range indexes {
+				table[i] = index
+			}
+			if err := output(cmd).WriteTable(table); err != nil {
+				return err
+			}
+		}
+
+		refreshMode := dataset.Acceleration.RefreshMode
+		if refreshMode == "" {
+			refreshMode = accel.REFRESH_MODE_FULL
+		}
+		onConflict := accel.ConflictBehavior(refreshMode, indexes)
+		if err != nil && refreshMode == accel.REFRESH_MODE_APPEND && accel.Engine(dataset.Acceleration) != accel.ENGINE_ARROW {
+			onConflict = "unknown, indexes could not be inspected"
+		}
+		if err := output(cmd).WriteTable([]interface{}{
+			settingRow{Setting: "engine", Value: accel.Engine(dataset.Acceleration)},
+			settingRow{Setting: "mode", Value: accel.Mode(dataset.Acceleration)},
+			settingRow{Setting: "refresh_mode", Value: refreshMode},
+			settingRow{Setting: "on conflict", Value: onConflict},
+		}); err != nil {
+			return err
+		}
+
+		keyColumns, _ := cmd.Flags().GetStringSlice(keyFlag)
+		if len(keyColumns) == 0 {
+			keyColumns = accel.KeyColumns(indexes)
+		}
+		if len(keyColumns) == 0 {
+			cmd.Printf("No key or indexed columns to test a lookup with, use --%s to choose columns\n", keyFlag)
+			return nil
+		}
+		if err := rtcontext.IsRuntimeHealthy(2 * time.Second); err != nil {
+			cmd.Println("Start the runtime with spice run to time a test lookup")
+			return nil
+		}
+
+		samples, err := api.Sql[map[string]interface{}](rtcontext, accel.KeySampleSql(dataset.Name, keyColumns))
+		if err != nil {
+			return err
+		}
+		if len(samples) == 0 {
+			cmd.Printf("Dataset %s is empty, skipping the test lookup\n", dataset.Name)
+			return nil
+		}
+
+		lookupSql := accel.LookupSql(dataset.Name, keyColumns, samples[0])
+		start := time.Now()
+		rows, cacheStatus, err := api.SqlWithCacheStatus[json.RawMessage](rtcontext, lookupSql)
+		if err != nil {
+			return err
+		}
+		elapsed := time.Since(start)
+
+		cmd.Println(lookupSql)
+		cmd.Printf("Test lookup on (%s) returned %d rows in %s", strings.Join(keyColumns, ", "), len(rows), elapsed.Round(time.Microsecond))
+		if cacheStatus == api.CACHE_STATUS_HIT {
+			cmd.Print(", served from the results cache")
+		}
+		cmd.Println()
+		return nil
+	},
+}
+
+func init() {
+	accelIndexesCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	accelIndexesCmd.Flags().StringSlice(keyFlag, nil, "Columns to test a lookup on (default the primary key or first index)")
+	accelCmd.AddCommand(accelIndexesCmd)
+}
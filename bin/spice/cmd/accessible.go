@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+)
+
+const accessibleFlag = "accessible"
+
+// isAccessible reports whether --accessible, SPICE_ACCESSIBLE or the accessible setting of the
+// CLI config asks for output without animation and colors.
+func isAccessible(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed(accessibleFlag) {
+		accessible, _ := cmd.Flags().GetBool(accessibleFlag)
+		return accessible
+	}
+	if env := os.Getenv("SPICE_ACCESSIBLE"); env != "" {
+		return env == "1" || env == "true"
+	}
+	cliConfig, err := loadConfig(cmd)
+	return err == nil && cliConfig.Accessible
+}
+
+// colors highlights messages unless NO_COLOR is set or output is accessible.
+func colors(cmd *cobra.Command) aurora.Aurora {
+	return aurora.NewAurora(os.Getenv("NO_COLOR") == "" && !isAccessible(cmd))
+}
+
+// progressOutput is where spinners and bars are rendered, in the mode of --progress. Accessible
+// output replaces them with sentences printed one after another, unless --progress is given.
+func progressOutput(cmd *cobra.Command) io.Writer {
+	mode, _ := cmd.Flags().GetString(progressFlag)
+	if !cmd.Flags().Changed(progressFlag) && isAccessible(cmd) {
+		mode = progress.MODE_ACCESSIBLE
+	}
+	return &progress.Writer{Writer: cmd.OutOrStderr(), Mode: mode}
+}
+
+func init() {
+	RootCmd.PersistentFlags().Bool(accessibleFlag, false, i18n.T("help.accessible_flag"))
+}
@@ -21,10 +21,9 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 
-	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
-	"github.com/spiceai/spiceai/bin/spice/pkg/context"
 	"github.com/spiceai/spiceai/bin/spice/pkg/registry"
 	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
 	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
@@ -38,57 +37,69 @@ var addCmd = &cobra.Command{
 	Args:  cobra.MinimumNArgs(1),
 	Example: `
 spice add spiceai/quickstart
+spice add spiceai/quickstart@v0.1.0
+spice add spiceai/quickstart@^0.1
+spice add oci://ghcr.io/myorg/taxi:1.2.0
+spice add oci://ghcr.io/myorg/taxi:~1.2
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		podPath := args[0]
 
 		cmd.Printf("Getting Spicepod %s ...\n", podPath)
 
-		r := registry.GetRegistry(podPath)
-		downloadPath, err := r.GetPod(podPath)
+		r := registry.GetRegistry(newRuntimeContext(cmd), podPath)
+		if spicerack, ok := r.(*registry.SpiceRackRegistry); ok {
+			// Best effort, the download below reports a missing Spicepod
+			details, err := spicerack.GetPodDetails(strings.Split(podPath, "@")[0])
+			if err == nil && details.Description != "" {
+				cmd.Printf("%s: %s\n", details.Path, details.Description)
+			}
+		}
+
+		lock, err := spicepod.LoadLockFile(".")
+		if err != nil {
+			return err
+		}
+
+		downloadPath, err := installDependency(cmd, podPath, lock, false)
 		if err != nil {
 			var itemNotFound *registry.RegistryItemNotFound
 			if errors.As(err, &itemNotFound) {
-				cmd.Printf("No Spicepod found at '%s'.\n", podPath)
+				cmd.Printf("No Spicepod found at '%s'. Find Spicepods with: spice registry search <term>\n", podPath)
 			} else {
 				cmd.Println(err)
 			}
-			return
+			return nil
 		}
 
-		relativePath := context.NewContext().GetSpiceAppRelativePath(downloadPath)
+		relativePath := newRuntimeContext(cmd).GetSpiceAppRelativePath(downloadPath)
 
 		spicepodBytes, err := os.ReadFile("spicepod.yaml")
 		if err != nil {
 			if os.IsNotExist(err) {
 				wd, err := os.Getwd()
 				if err != nil {
-					cmd.PrintErrf("Error getting current working directory: %s\n", err.Error())
-					os.Exit(1)
+					return fmt.Errorf("Error getting current working directory: %s", err.Error())
 				}
 				name := path.Base(wd)
 				spicepodPath, err := spicepod.CreateManifest(name, ".")
 				if err != nil {
-					cmd.PrintErrf("Error creating spicepod.yaml: %s\n", err.Error())
-					os.Exit(1)
+					return fmt.Errorf("Error creating spicepod.yaml: %s", err.Error())
 				}
-				cmd.Println(aurora.BrightGreen(fmt.Sprintf("%s initialized!", spicepodPath)))
+				cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("%s initialized!", spicepodPath)))
 				spicepodBytes, err = os.ReadFile("spicepod.yaml")
 				if err != nil {
-					cmd.PrintErrf("Error reading spicepod.yaml: %s\n", err.Error())
-					os.Exit(1)
+					return fmt.Errorf("Error reading spicepod.yaml: %s", err.Error())
 				}
 			} else {
-				cmd.Println(err)
-				os.Exit(1)
+				return err
 			}
 		}
 
 		var spicePod spec.SpicepodSpec
 		err = yaml.Unmarshal(spicepodBytes, &spicePod)
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		var podReferenced bool
@@ -103,26 +114,80 @@ spice add spiceai/quickstart
 			spicePod.Dependencies = append(spicePod.Dependencies, podPath)
 			spicepodBytes, err = yaml.Marshal(spicePod)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(1)
+				return err
 			}
 
 			err = os.WriteFile("spicepod.yaml", spicepodBytes, 0766)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(1)
+				return err
 			}
 		}
 
+		if err = saveLockFile(lock); err != nil {
+			return fmt.Errorf("Error writing %s: %s", spicepod.LockFileName, err.Error())
+		}
+
 		cmd.Printf("Added %s\n", relativePath)
 
-		err = checkLatestCliReleaseVersion()
+		err = checkLatestCliReleaseVersion(cmd)
 		if err != nil && util.IsDebug() {
 			cmd.PrintErrf("failed to check for latest CLI release version: %s\n", err.Error())
 		}
+		return nil
 	},
 }
 
+// installDependency resolves a dependency's version constraint, downloads it and records the
+// resolved version in the lockfile. Unless update is set, locked versions and digests are reused.
+func installDependency(cmd *cobra.Command, dependency string, lock *spicepod.LockFile, update bool) (string, error) {
+	locked := lock.Get(dependency)
+	if update {
+		locked = nil
+	}
+
+	lockedVersion := ""
+	if locked != nil {
+		lockedVersion = locked.Version
+	}
+	rtcontext := newRuntimeContext(cmd)
+	resolution, err := registry.Resolve(rtcontext, dependency, lockedVersion)
+	if err != nil {
+		return "", err
+	}
+	if resolution.Path != dependency {
+		cmd.Printf("Resolved %s to %s\n", dependency, resolution.Version)
+	}
+
+	r := registry.GetRegistry(rtcontext, resolution.Path)
+	ociRegistry, isOci := r.(*registry.OciRegistry)
+	if isOci && locked != nil && locked.Version == resolution.Version && locked.Digest != "" {
+		cmd.Printf("Using %s pinned in %s\n", locked.Digest, spicepod.LockFileName)
+		ociRegistry.PinnedDigest = locked.Digest
+	}
+
+	downloadPath, err := r.GetPod(resolution.Path)
+	if err != nil {
+		return "", err
+	}
+
+	switch r.(type) {
+	case *registry.OciRegistry:
+		lock.Set(spicepod.LockedDependency{Name: dependency, Source: spicepod.SOURCE_OCI, Version: resolution.Version, Digest: ociRegistry.Digest})
+	case *registry.SpiceRackRegistry:
+		lock.Set(spicepod.LockedDependency{Name: dependency, Source: spicepod.SOURCE_SPICERACK, Version: resolution.Version})
+	}
+
+	return downloadPath, nil
+}
+
+// saveLockFile writes the lockfile once any registry dependency is locked.
+func saveLockFile(lock *spicepod.LockFile) error {
+	if len(lock.Dependencies) == 0 {
+		return nil
+	}
+	return lock.Save(".")
+}
+
 func init() {
 	addCmd.Flags().BoolP("help", "h", false, "Print this help message")
 	RootCmd.AddCommand(addCmd)
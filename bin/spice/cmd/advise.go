@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const (
+	minQueriesFlag   = "min-queries"
+	memoryBudgetFlag = "memory-budget"
+)
+
+type adviseRow struct {
+	Dataset        string
+	Queries        int
+	CacheHits      int
+	AvgLatency     string
+	P95Latency     string
+	Acceleration   string
+	Recommendation string
+}
+
+type indexSuggestionRow struct {
+	Dataset   string
+	Column    string
+	Queries   int
+	Statement string
+}
+
+var adviseCmd = &cobra.Command{
+	Use:   "advise",
+	Short: "Recommend accelerations and indexes from the runtime's query history",
+	Example: `
+spice advise
+spice advise --min-queries 20 --memory-budget 4GiB
+spice advise --output-file accelerations.yaml
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		last, _ := cmd.Flags().GetInt(lastFlag)
+		minQueries, _ := cmd.Flags().GetInt(minQueriesFlag)
+		outputFile, _ := cmd.Flags().GetString(outputFileFlag)
+		budgetValue, _ := cmd.Flags().GetString(memoryBudgetFlag)
+		memoryBudget, err := util.ParseBytes(budgetValue)
+		if err != nil {
+			return fmt.Errorf("Invalid --%s: %s", memoryBudgetFlag, err.Error())
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		if err := rtcontext.IsRuntimeHealthy(2 * time.Second); err != nil {
+			return errors.New("The runtime must be running to read its query history. Start it with spice run.")
+		}
+
+		datasets, err := spicepod.LoadDatasets(rtcontext.AppDir())
+		if err != nil {
+			return err
+		}
+		specs := map[string]*spec.DatasetSpec{}
+		names := make([]string, len(datasets))
+		for i, dataset := range datasets {
+			specs[dataset.Name] = dataset
+			names[i] = dataset.Name
+		}
+
+		records, err := api.Sql[accel.QueryRecord](rtcontext, accel.QueryHistorySql(last))
+		if err != nil {
+			return err
+		}
+		activities := accel.AnalyzeQueryHistory(records, names)
+		if len(activities) == 0 {
+			cmd.Printf("None of the %d most recent queries read a dataset in spicepod.yaml\n", len(records))
+			return nil
+		}
+
+		var rows []interface{}
+		var suggestions []interface{}
+		var recommendations []accel.Recommendation
+		for _, activity := range activities {
+			dataset := specs[activity.Dataset]
+			row := adviseRow{
+				Dataset:        activity.Dataset,
+				Queries:        activity.Queries,
+				CacheHits:      activity.CacheHits,
+				AvgLatency:     activity.AverageLatency().Round(time.Millisecond).String(),
+				P95Latency:     activity.P95Latency().Round(time.Millisecond).String(),
+				Acceleration:   "none",
+				Recommendation: "-",
+			}
+
+			engine := ""
+			if accel.IsAccelerated(dataset) {
+				engine = accel.Engine(dataset.Acceleration)
+				row.Acceleration = fmt.Sprintf("%s (%s)", engine, accel.Mode(dataset.Acceleration))
+			} else if activity.Queries >= minQueries {
+				recommendation := recommendAcceleration(rtcontext, activity, memoryBudget)
+				engine = recommendation.Engine
+				row.Recommendation = recommendation.Reason
+				recommendations = append(recommendations, recommendation)
+			}
+			rows = append(rows, row)
+
+			if engine == "" || engine == accel.ENGINE_ARROW {
+				continue
+			}
+			columns := make([]string, 0, len(activity.FilterColumns))
+			for column, count := range activity.FilterColumns {
+				if count >= minQueries {
+					columns = append(columns, column)
+				}
+			}
+			sort.Slice(columns, func(i, j int) bool { return activity.FilterColumns[columns[i]] > activity.FilterColumns[columns[j]] })
+			for _, column := range columns {
+				suggestions = append(suggestions, indexSuggestionRow{
+					Dataset:   activity.Dataset,
+					Column:    column,
+					Queries:   activity.FilterColumns[column],
+					Statement: accel.IndexSql(activity.Dataset, column),
+				})
+			}
+		}
+
+		cmd.Printf("Analyzed %d queries\n\n", len(records))
+		if err := output(cmd).WriteTable(rows); err != nil {
+			return err
+		}
+
+		if len(suggestions) > 0 {
+			cmd.Println("Columns frequently filtered by value, index them in the acceleration engine:")
+			if err := output(cmd).WriteTable(suggestions); err != nil {
+				return err
+			}
+		}
+
+		if len(recommendations) == 0 {
+			cmd.Printf("No unaccelerated dataset was read by at least %d queries\n", minQueries)
+			return nil
+		}
+
+		patch, err := accel.AccelerationPatch(recommendations)
+		if err != nil {
+			return err
+		}
+		if outputFile != "" {
+			if err := os.WriteFile(outputFile, patch, 0644); err != nil {
+				return err
+			}
+			cmd.Printf("Wrote the spicepod patch to %s\n", outputFile)
+			return nil
+		}
+		cmd.Println("Spicepod patch, merge into the matching datasets in spicepod.yaml:")
+		cmd.Println()
+		fmt.Fprint(cmd.OutOrStdout(), string(patch))
+		return nil
+	},
+}
+
+// recommendAcceleration estimates the dataset's in-memory size to choose between Arrow and a
+// DuckDB file. The estimate scans the dataset once through the runtime.
+func recommendAcceleration(rtcontext *context.RuntimeContext, activity *accel.DatasetActivity, memoryBudget int64) accel.Recommendation {
+	estimatedSize := int64(-1)
+	columns, err := api.GetDatasetColumns(rtcontext, activity.Dataset)
+	if err == nil {
+		samples, err := api.Sql[accel.SizeSample](rtcontext, accel.SizeEstimateSql(activity.Dataset, columns))
+		if err == nil && len(samples) == 1 {
+			estimatedSize = accel.EstimateSize(columns, samples[0])
+		}
+	}
+
+	engine, mode := accel.RecommendEngine(estimatedSize, memoryBudget)
+	reason := fmt.Sprintf("accelerate with %s (%s)", engine, mode)
+	if estimatedSize >= 0 {
+		reason += fmt.Sprintf(", about %s", util.FormatBytes(float64(estimatedSize)))
+	} else {
+		reason += ", size unknown"
+	}
+	return accel.Recommendation{Dataset: activity.Dataset, Engine: engine, Mode: mode, EstimatedSize: estimatedSize, Reason: reason}
+}
+
+func init() {
+	adviseCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	adviseCmd.Flags().Int(lastFlag, 1000, "Analyze the most recent N queries")
+	adviseCmd.Flags().Int(minQueriesFlag, 5, "Queries a dataset or column needs before it is recommended")
+	adviseCmd.Flags().String(memoryBudgetFlag, "1GiB", "Largest estimated size to recommend an in-memory Arrow acceleration for")
+	adviseCmd.Flags().String(outputFileFlag, "", "Write the spicepod patch to this file")
+	RootCmd.AddCommand(adviseCmd)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/aws"
+	"github.com/spiceai/spiceai/bin/spice/pkg/docker"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+)
+
+const (
+	nameFlag             = "name"
+	imageFlag            = "image"
+	cpuFlag              = "cpu"
+	memoryFlag           = "memory"
+	regionFlag           = "region"
+	clusterFlag          = "cluster"
+	executionRoleArnFlag = "execution-role-arn"
+	taskRoleArnFlag      = "task-role-arn"
+	efsFileSystemIdFlag  = "efs-file-system-id"
+	efsAccessPointIdFlag = "efs-access-point-id"
+	secretFlag           = "secret"
+	subnetsFlag          = "subnets"
+	securityGroupsFlag   = "security-groups"
+	desiredCountFlag     = "desired-count"
+	assignPublicIpFlag   = "assign-public-ip"
+)
+
+var awsCmd = &cobra.Command{
+	Use:   "aws",
+	Short: "Deploy the Spice runtime on AWS",
+	Example: `
+spice aws ecs generate
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var awsEcsCmd = &cobra.Command{
+	Use:   "ecs",
+	Short: "Run the Spice runtime on Amazon ECS",
+	Example: `
+spice aws ecs generate --cluster spice --efs-file-system-id fs-0123456789abcdef0
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var awsEcsGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a Fargate task definition and service for the Spice runtime",
+	Example: `
+spice aws ecs generate
+spice aws ecs generate --image 123456789012.dkr.ecr.us-east-1.amazonaws.com/my-app:latest \
+  --execution-role-arn arn:aws:iam::123456789012:role/ecsTaskExecutionRole \
+  --efs-file-system-id fs-0123456789abcdef0 --cluster spice --subnets subnet-a,subnet-b \
+  --secret SPICE_SECRET_POSTGRES_PASSWORD=arn:aws:secretsmanager:us-east-1:123456789012:secret:pg
+
+aws ecs register-task-definition --cli-input-json file://ecs-task-definition.json
+aws ecs create-service --cli-input-json file://ecs-service.json
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spicepodDir, _ := cmd.Flags().GetString(spicepodFlag)
+		dir, _ := cmd.Flags().GetString(dirFlag)
+		force, _ := cmd.Flags().GetBool(forceFlag)
+
+		options := aws.EcsOptions{}
+		options.Name, _ = cmd.Flags().GetString(nameFlag)
+		options.Image, _ = cmd.Flags().GetString(imageFlag)
+		options.Cpu, _ = cmd.Flags().GetString(cpuFlag)
+		options.Memory, _ = cmd.Flags().GetString(memoryFlag)
+		options.Region, _ = cmd.Flags().GetString(regionFlag)
+		options.Cluster, _ = cmd.Flags().GetString(clusterFlag)
+		options.ExecutionRoleArn, _ = cmd.Flags().GetString(executionRoleArnFlag)
+		options.TaskRoleArn, _ = cmd.Flags().GetString(taskRoleArnFlag)
+		options.EfsFileSystemId, _ = cmd.Flags().GetString(efsFileSystemIdFlag)
+		options.EfsAccessPointId, _ = cmd.Flags().GetString(efsAccessPointIdFlag)
+		options.Secrets, _ = cmd.Flags().GetStringArray(secretFlag)
+		options.Subnets, _ = cmd.Flags().GetStringSlice(subnetsFlag)
+		options.SecurityGroups, _ = cmd.Flags().GetStringSlice(securityGroupsFlag)
+		options.DesiredCount, _ = cmd.Flags().GetInt(desiredCountFlag)
+		options.AssignPublicIp, _ = cmd.Flags().GetBool(assignPublicIpFlag)
+
+		if options.Name == "" {
+			options.Name = "spiceai"
+			if manifest, err := spicepod.LoadManifest(spicepodDir); err == nil && manifest.Name != "" {
+				options.Name = manifest.Name
+			}
+		}
+		officialImage := options.Image == ""
+		if officialImage {
+			options.Image = fmt.Sprintf("%s:%s", docker.IMAGE_REPOSITORY, docker.ImageTag(version.Version()))
+		}
+		if options.Region == "" {
+			options.Region = os.Getenv("AWS_REGION")
+		}
+
+		taskDefinition, err := aws.NewTaskDefinition(options)
+		if err != nil {
+			return err
+		}
+		service := aws.NewService(options)
+
+		files, err := aws.WriteEcsFiles(dir, taskDefinition, service, force)
+		if err != nil {
+			if errors.Is(err, aws.ErrFileExists) {
+				return fmt.Errorf("Error writing ECS files: %s\nUse --%s to overwrite existing files", err.Error(), forceFlag)
+			}
+			return fmt.Errorf("Error writing ECS files: %s", err.Error())
+		}
+
+		for _, file := range files {
+			cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Wrote %s", file)))
+		}
+
+		if placeholders := aws.Placeholders(taskDefinition, service); len(placeholders) > 0 {
+			cmd.Println(colors(cmd).Yellow(fmt.Sprintf("Replace the placeholders before registering: %v", placeholders)))
+		}
+		if officialImage {
+			cmd.Println("The official image does not contain your Spicepod: build one with spice docker init, push it to ECR and pass it with --image.")
+		}
+		if len(options.Secrets) > 0 {
+			cmd.Println("The execution role needs secretsmanager:GetSecretValue on the referenced secrets.")
+		}
+		return nil
+	},
+}
+
+func init() {
+	awsEcsGenerateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	awsEcsGenerateCmd.Flags().String(spicepodFlag, ".", "Directory of the Spicepod to deploy, used for the default name")
+	awsEcsGenerateCmd.Flags().String(dirFlag, ".", "Directory to write the task definition and service JSON to")
+	awsEcsGenerateCmd.Flags().String(nameFlag, "", "Task definition family and service name, the Spicepod name when empty")
+	awsEcsGenerateCmd.Flags().String(imageFlag, "", "Runtime image, e.g. one built with spice docker init and pushed to ECR")
+	awsEcsGenerateCmd.Flags().String(cpuFlag, "1024", "Fargate task CPU units")
+	awsEcsGenerateCmd.Flags().String(memoryFlag, "4096", "Fargate task memory in MiB")
+	awsEcsGenerateCmd.Flags().String(regionFlag, "", "AWS region for the runtime's logs (default: $AWS_REGION)")
+	awsEcsGenerateCmd.Flags().String(clusterFlag, "", "ECS cluster to create the service in")
+	awsEcsGenerateCmd.Flags().String(executionRoleArnFlag, "", "Task execution role, used to pull the image, write logs and read secrets")
+	awsEcsGenerateCmd.Flags().String(taskRoleArnFlag, "", "Task role for the runtime's own AWS access, e.g. to S3")
+	awsEcsGenerateCmd.Flags().String(efsFileSystemIdFlag, "", "EFS file system storing acceleration files")
+	awsEcsGenerateCmd.Flags().String(efsAccessPointIdFlag, "", "EFS access point, mounted with IAM authorization")
+	awsEcsGenerateCmd.Flags().StringArray(secretFlag, []string{}, "Secrets Manager secret for the runtime as NAME=arn")
+	awsEcsGenerateCmd.Flags().StringSlice(subnetsFlag, []string{}, "Subnets of the service")
+	awsEcsGenerateCmd.Flags().StringSlice(securityGroupsFlag, []string{}, "Security groups of the service")
+	awsEcsGenerateCmd.Flags().Int(desiredCountFlag, 1, "Number of runtime tasks")
+	awsEcsGenerateCmd.Flags().Bool(assignPublicIpFlag, false, "Assign public IPs to the tasks")
+	awsEcsGenerateCmd.Flags().Bool(forceFlag, false, "Overwrite existing files")
+	awsEcsCmd.AddCommand(awsEcsGenerateCmd)
+
+	awsEcsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	awsCmd.AddCommand(awsEcsCmd)
+
+	awsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(awsCmd)
+}
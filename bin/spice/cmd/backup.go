@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/snapshot"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+const (
+	toFlag    = "to"
+	fromFlag  = "from"
+	forceFlag = "force"
+)
+
+var backupCmd = &cobra.Command{
+	Use:               "backup <dataset>",
+	Short:             "Back up a dataset's DuckDB or SQLite acceleration file",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice backup orders --to ./backups/orders
+spice backup orders --to s3://my-bucket/backups/orders
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		destination, _ := cmd.Flags().GetString(toFlag)
+		if destination == "" {
+			return fmt.Errorf("--%s is required", toFlag)
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), args[0])
+		if err != nil {
+			return err
+		}
+
+		runtimeRunning := rtcontext.IsRuntimeHealthy(2*time.Second) == nil
+
+		manifest, err := snapshot.Backup(rtcontext.AppDir(), definition, destination)
+		if err != nil {
+			return err
+		}
+
+		if err := printManifest(cmd, manifest); err != nil {
+			return err
+		}
+		cmd.Printf("\nBacked up dataset %s to %s\n", manifest.Dataset, destination)
+		if runtimeRunning {
+			cmd.Println("The runtime was running during the backup, writes in progress may not be included")
+		}
+		return nil
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:               "restore <dataset>",
+	Short:             "Restore a dataset's acceleration file from a backup",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice restore orders --from ./backups/orders
+spice restore orders --from s3://my-bucket/backups/orders
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, _ := cmd.Flags().GetString(fromFlag)
+		force, _ := cmd.Flags().GetBool(forceFlag)
+		if source == "" {
+			return fmt.Errorf("--%s is required", fromFlag)
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), args[0])
+		if err != nil {
+			return err
+		}
+
+		if !force && rtcontext.IsRuntimeHealthy(2*time.Second) == nil {
+			return fmt.Errorf("The runtime is running at %s and may have the acceleration file open. Stop it before restoring, or use --%s.", rtcontext.HttpEndpoint(), forceFlag)
+		}
+
+		manifest, err := snapshot.Restore(rtcontext.AppDir(), definition, source)
+		if err != nil {
+			return err
+		}
+
+		if err := printManifest(cmd, manifest); err != nil {
+			return err
+		}
+		cmd.Printf("\nRestored dataset %s from the backup taken at %s\n", manifest.Dataset, manifest.CreatedAt.Format(time.RFC3339))
+		return nil
+	},
+}
+
+func printManifest(cmd *cobra.Command, manifest *snapshot.Manifest) error {
+	table := make([]interface{}, len(manifest.Files))
+	for i, file := range manifest.Files {
+		table[i] = file
+	}
+	return output(cmd).WriteTable(table)
+}
+
+func init() {
+	backupCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	backupCmd.Flags().String(toFlag, "", "Backup location, a local directory or s3:// URL")
+	RootCmd.AddCommand(backupCmd)
+
+	restoreCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	restoreCmd.Flags().String(fromFlag, "", "Backup location, a local directory or s3:// URL")
+	restoreCmd.Flags().Bool(forceFlag, false, "Restore even if the runtime is running")
+	RootCmd.AddCommand(restoreCmd)
+}
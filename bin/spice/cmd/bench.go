@@ -0,0 +1,258 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+)
+
+const (
+	iterationsFlag = "iterations"
+	warmupFlag     = "warmup"
+	queriesDirFlag = "queries-dir"
+	refreshFlag    = "refresh"
+	outputFileFlag = "output-file"
+	compareFlag    = "compare"
+	assertFlag     = "assert"
+	assertOutFlag  = "assert-output"
+	noSaveFlag     = "no-save"
+	cacheSplitFlag = "cache-split"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench <suite>",
+	Short: "Run a benchmark suite (tpch, tpcds) against the Spice runtime",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice bench tpch
+spice bench tpch --iterations 5 --output-file run.json
+spice bench tpch --compare baseline.json
+spice bench tpch --warmup 0 --cache-split
+spice bench tpcds --queries-dir ./tpcds-queries
+spice bench tpch --assert 'p95<250ms' --assert 'error_rate<0.1%' --assert-output results.xml
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		suite, err := bench.GetSuite(args[0])
+		if err != nil {
+			return err
+		}
+
+		iterations, _ := cmd.Flags().GetInt(iterationsFlag)
+		warmup, _ := cmd.Flags().GetInt(warmupFlag)
+		queriesDir, _ := cmd.Flags().GetString(queriesDirFlag)
+		refresh, _ := cmd.Flags().GetBool(refreshFlag)
+		outputFile, _ := cmd.Flags().GetString(outputFileFlag)
+		compareFile, _ := cmd.Flags().GetString(compareFlag)
+		rawAssertions, _ := cmd.Flags().GetStringArray(assertFlag)
+		assertOutput, _ := cmd.Flags().GetString(assertOutFlag)
+		noSave, _ := cmd.Flags().GetBool(noSaveFlag)
+		cacheSplit, _ := cmd.Flags().GetBool(cacheSplitFlag)
+
+		assertions, err := bench.ParseAssertions(rawAssertions)
+		if err != nil {
+			return err
+		}
+
+		queries, err := suite.Queries(queriesDir)
+		if err != nil {
+			return err
+		}
+
+		var baseline *bench.Report
+		if compareFile != "" {
+			baseline, err = bench.LoadReport(compareFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+
+		err = prepareSuiteDatasets(cmd, rtcontext, suite, refresh)
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Running %d %s queries (%d iterations each) ...\n", len(queries), suite.Name, iterations)
+		bar := progress.NewBar(progressOutput(cmd), "Queries", len(queries))
+		report := bench.Run(rtcontext, suite.Name, queries, bench.RunOptions{
+			Iterations: iterations,
+			Warmup:     warmup,
+			OnQueryComplete: func(result bench.QueryResult) {
+				if result.Error != "" {
+					bar.Printf("  %s failed\n", result.Name)
+				}
+				bar.Add(1)
+			},
+		})
+		bar.Finish()
+		report.Metadata = bench.CollectMetadata(rtcontext)
+
+		cmd.Println()
+		if err := output(cmd).WriteTable(report.Summaries()); err != nil {
+			return err
+		}
+
+		if cacheSplit {
+			if err := printCacheSummaries(cmd, report); err != nil {
+				return err
+			}
+		}
+
+		if baseline != nil {
+			cmd.Printf("\nComparison against %s (median):\n\n", compareFile)
+			comparisons := bench.Compare(baseline, report)
+			table := make([]interface{}, len(comparisons))
+			for i, comparison := range comparisons {
+				table[i] = comparison
+			}
+			if err := output(cmd).WriteTable(table); err != nil {
+				return err
+			}
+		}
+
+		if outputFile != "" {
+			err = report.Save(outputFile)
+			if err != nil {
+				return fmt.Errorf("Error saving benchmark report: %s", err.Error())
+			}
+			cmd.Printf("\nSaved benchmark report to %s\n", outputFile)
+		}
+
+		if !noSave {
+			historyFile, err := bench.SaveToHistory(bench.HistoryDir(rtcontext), report)
+			if err != nil {
+				cmd.PrintErrf("Error saving benchmark history: %s\n", err.Error())
+			} else {
+				cmd.Printf("\nRecorded run in %s, see trends with: spice bench report %s\n", historyFile, suite.Name)
+			}
+		}
+
+		if len(assertions) == 0 {
+			return nil
+		}
+
+		results := bench.EvaluateReport(report, assertions)
+		cmd.Println("\nAssertions:")
+		table := make([]interface{}, len(results))
+		for i, result := range results {
+			table[i] = result
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+
+		if assertOutput != "" {
+			err = bench.SaveAssertionResults(assertOutput, suite.Name, results)
+			if err != nil {
+				return fmt.Errorf("Error saving assertion results: %s", err.Error())
+			}
+			cmd.Printf("\nSaved assertion results to %s\n", assertOutput)
+		}
+
+		if !bench.AssertionsPassed(results) {
+			return errors.New("One or more performance assertions failed")
+		}
+		return nil
+	},
+}
+
+func printCacheSummaries(cmd *cobra.Command, report *bench.Report) error {
+	summaries := report.CacheSummaries()
+	reported := false
+	for _, summary := range summaries {
+		cacheSummary := summary.(bench.CacheSummary)
+		if cacheSummary.ColdRuns+cacheSummary.WarmRuns > 0 {
+			reported = true
+			break
+		}
+	}
+
+	if !reported {
+		cmd.Println("\nThe runtime did not report results cache status, is the results cache enabled?")
+		return nil
+	}
+
+	cmd.Println("\nResults cache misses (cold) vs hits (warm):")
+	return output(cmd).WriteTable(summaries)
+}
+
+// prepareSuiteDatasets verifies the runtime has a dataset for every table of the suite,
+// optionally triggering an acceleration refresh so the data is loaded before timing.
+func prepareSuiteDatasets(cmd *cobra.Command, rtcontext *context.RuntimeContext, suite bench.Suite, refresh bool) error {
+	datasets, err := api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string]api.Dataset, len(datasets))
+	for _, dataset := range datasets {
+		loaded[strings.ToLower(dataset.Name)] = dataset
+	}
+
+	var missing []string
+	for _, table := range suite.Tables {
+		if _, ok := loaded[table]; !ok {
+			missing = append(missing, table)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the runtime is missing %s datasets: %s", suite.Name, strings.Join(missing, ", "))
+	}
+
+	if !refresh {
+		return nil
+	}
+
+	for _, table := range suite.Tables {
+		if !loaded[table].AccelerationEnabled {
+			continue
+		}
+		cmd.Printf("Refreshing dataset %s ...\n", table)
+		_, err := api.PostRuntime[DatasetRefreshApiResponse](rtcontext, fmt.Sprintf("/v1/datasets/%s/acceleration/refresh", table))
+		if err != nil {
+			return fmt.Errorf("error refreshing dataset %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	benchCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	benchCmd.Flags().Int(iterationsFlag, 3, "Number of timed executions per query")
+	benchCmd.Flags().Int(warmupFlag, 1, "Number of untimed executions per query before timing")
+	benchCmd.Flags().String(queriesDirFlag, "", "Directory of .sql files to run instead of the bundled query set")
+	benchCmd.Flags().Bool(refreshFlag, false, "Refresh the suite's accelerated datasets before running")
+	benchCmd.Flags().String(outputFileFlag, "", "Write the benchmark report as JSON to this file")
+	benchCmd.Flags().String(compareFlag, "", "Compare the run against a previously saved JSON report")
+	benchCmd.Flags().Bool(cacheSplitFlag, false, "Report results cache misses (cold) and hits (warm) separately, use with --warmup 0 to capture cold runs")
+	benchCmd.Flags().Bool(noSaveFlag, false, "Do not record the run in the benchmark history")
+	benchCmd.Flags().StringArray(assertFlag, nil, "Fail if a budget is not met, e.g. p95<250ms or error_rate<0.1% (repeatable)")
+	benchCmd.Flags().String(assertOutFlag, "", "Write assertion results to this file as JUnit XML (.xml) or JSON")
+	RootCmd.AddCommand(benchCmd)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const (
+	datasetFlag      = "dataset"
+	enginesFlag      = "engines"
+	queryFlag        = "query"
+	readyTimeoutFlag = "ready-timeout"
+)
+
+type AccelComparison struct {
+	Query  string
+	Engine string
+	Rows   int
+	P50    time.Duration
+	P95    time.Duration
+	Change string
+	Status string
+}
+
+var benchAccelCmd = &cobra.Command{
+	Use:   "accel",
+	Short: "Compare query performance of a dataset across acceleration engines",
+	Example: `
+spice bench accel --dataset taxi_trips --engines arrow,duckdb,sqlite
+spice bench accel --dataset taxi_trips --query "SELECT COUNT(*) FROM taxi_trips WHERE fare_amount > 10"
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dataset, _ := cmd.Flags().GetString(datasetFlag)
+		engines, _ := cmd.Flags().GetStringSlice(enginesFlag)
+		queryStrings, _ := cmd.Flags().GetStringArray(queryFlag)
+		queriesDir, _ := cmd.Flags().GetString(queriesDirFlag)
+		iterations, _ := cmd.Flags().GetInt(iterationsFlag)
+		warmup, _ := cmd.Flags().GetInt(warmupFlag)
+		readyTimeout, _ := cmd.Flags().GetDuration(readyTimeoutFlag)
+
+		if dataset == "" {
+			return fmt.Errorf("No dataset provided, use --%s to provide a dataset", datasetFlag)
+		}
+
+		var queries []bench.Query
+		var err error
+		switch {
+		case queriesDir != "":
+			queries, err = bench.LoadQueriesFromDir(queriesDir)
+			if err != nil {
+				return err
+			}
+		case len(queryStrings) > 0:
+			for i, sql := range queryStrings {
+				queries = append(queries, bench.Query{Name: fmt.Sprintf("query_%d", i+1), Sql: sql})
+			}
+		default:
+			queries = bench.DefaultDatasetQueries(dataset)
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), dataset)
+		if err != nil {
+			return err
+		}
+
+		original, err := os.ReadFile(definition.FilePath)
+		if err != nil {
+			return err
+		}
+		// Saving an engine's configuration and restoring the original spicepod take turns, so an
+		// interrupt never restores it halfway through a save, and nothing is saved once restored.
+		var mu sync.Mutex
+		restored := false
+		restore := func() {
+			mu.Lock()
+			defer mu.Unlock()
+			if restored {
+				return
+			}
+			restored = true
+			if err := util.WriteToExistingFile(definition.FilePath, original); err != nil {
+				cmd.PrintErrf("Error restoring %s: %s\n", definition.FilePath, err.Error())
+				return
+			}
+			cmd.Printf("Restored %s\n", definition.FilePath)
+		}
+		save := func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			if restored {
+				return fmt.Errorf("%s was restored, the benchmark was interrupted", definition.FilePath)
+			}
+			return definition.Save()
+		}
+
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt)
+		done := make(chan struct{})
+		defer func() {
+			signal.Stop(sigCh)
+			close(done)
+			restore()
+		}()
+		go func() {
+			select {
+			case <-sigCh:
+				restore()
+				os.Exit(1)
+			case <-done:
+			}
+		}()
+
+		reports := make(map[string]*bench.Report, len(engines))
+		for _, engine := range engines {
+			cmd.Printf("Configuring %s with the %s acceleration engine ...\n", definition.Name, engine)
+			definition.Set("acceleration.enabled", true)
+			definition.Set("acceleration.engine", engine)
+			if err := save(); err != nil {
+				return err
+			}
+
+			spinner := progress.NewSpinner(progressOutput(cmd), fmt.Sprintf("Waiting for %s to load", definition.Name))
+			spinner.Start()
+			err = bench.WaitForDataset(rtcontext, metricsEndpoint(cmd), definition.Name, readyTimeout)
+			if err != nil {
+				spinner.Stop("failed")
+				cmd.PrintErrf("Skipping engine %s: %s\n", engine, err.Error())
+				continue
+			}
+			spinner.Stop("ready")
+			cmd.Printf("Running %d queries ...\n", len(queries))
+
+			reports[engine] = bench.Run(rtcontext, fmt.Sprintf("accel_%s", engine), queries, bench.RunOptions{
+				Iterations: iterations,
+				Warmup:     warmup,
+			})
+		}
+
+		restore()
+
+		var baseline *bench.Report
+		var table []interface{}
+		for _, engine := range engines {
+			report, ok := reports[engine]
+			if !ok {
+				continue
+			}
+			if baseline == nil {
+				baseline = report
+			}
+			comparisons := bench.Compare(baseline, report)
+			for i, result := range report.Results {
+				summary := result.Summary()
+				change := comparisons[i].Change
+				if report == baseline {
+					change = "baseline"
+				}
+				table = append(table, AccelComparison{
+					Query:  result.Name,
+					Engine: engine,
+					Rows:   summary.Rows,
+					P50:    summary.P50,
+					P95:    summary.P95,
+					Change: change,
+					Status: summary.Status,
+				})
+			}
+		}
+
+		if len(table) == 0 {
+			return fmt.Errorf("No engine could be benchmarked for %s (tried %s)", dataset, strings.Join(engines, ", "))
+		}
+
+		cmd.Println()
+		return output(cmd).WriteTable(table)
+	},
+}
+
+func init() {
+	benchAccelCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	benchAccelCmd.Flags().String(datasetFlag, "", "Dataset to benchmark")
+	_ = benchAccelCmd.RegisterFlagCompletionFunc(datasetFlag, completeDatasetFlag)
+	benchAccelCmd.Flags().StringSlice(enginesFlag, []string{"arrow", "duckdb", "sqlite"}, "Acceleration engines to compare")
+	benchAccelCmd.Flags().StringArray(queryFlag, []string{}, "Query to run (can be repeated)")
+	benchAccelCmd.Flags().String(queriesDirFlag, "", "Directory of .sql files to run")
+	benchAccelCmd.Flags().Int(iterationsFlag, 3, "Number of timed executions per query")
+	benchAccelCmd.Flags().Int(warmupFlag, 1, "Number of untimed executions per query before timing")
+	benchAccelCmd.Flags().Duration(readyTimeoutFlag, 5*time.Minute, "How long to wait for the dataset to load with each engine")
+	benchCmd.AddCommand(benchAccelCmd)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+)
+
+const (
+	modelFlag      = "model"
+	batchSizesFlag = "batch-sizes"
+	inputFileFlag  = "input-file"
+)
+
+var benchEmbeddingsCmd = &cobra.Command{
+	Use:   "embeddings",
+	Short: "Measure embedding latency and throughput per batch size",
+	Example: `
+spice bench embeddings --model embed --batch-sizes 1,8,32
+spice bench embeddings --model openai_embeddings --input-file chunks.txt --iterations 10
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		model, _ := cmd.Flags().GetString(modelFlag)
+		batchSizes, _ := cmd.Flags().GetIntSlice(batchSizesFlag)
+		iterations, _ := cmd.Flags().GetInt(iterationsFlag)
+		warmup, _ := cmd.Flags().GetInt(warmupFlag)
+		inputFile, _ := cmd.Flags().GetString(inputFileFlag)
+
+		for _, batchSize := range batchSizes {
+			if batchSize < 1 {
+				return fmt.Errorf("Invalid batch size %d, batch sizes must be positive", batchSize)
+			}
+		}
+
+		var inputs []string
+		if inputFile != "" {
+			content, err := os.ReadFile(inputFile)
+			if err != nil {
+				return fmt.Errorf("Error reading input file: %s", err.Error())
+			}
+			for _, line := range strings.Split(string(content), "\n") {
+				if strings.TrimSpace(line) != "" {
+					inputs = append(inputs, line)
+				}
+			}
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+
+		cmd.Printf("Embedding with model %s at batch sizes %v (%d iterations each) ...\n", model, batchSizes, iterations)
+		results := bench.RunEmbeddings(rtcontext, bench.EmbeddingsOptions{
+			Model:      model,
+			BatchSizes: batchSizes,
+			Iterations: iterations,
+			Warmup:     warmup,
+			Inputs:     inputs,
+		})
+
+		failed := false
+		table := make([]interface{}, len(results))
+		for i, result := range results {
+			if result.Error != "" {
+				failed = true
+			}
+			table[i] = result.Summary()
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+
+		if failed {
+			return errReported
+		}
+		return nil
+	},
+}
+
+func init() {
+	benchEmbeddingsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	benchEmbeddingsCmd.Flags().String(modelFlag, "embed", "Name of the embedding model in the spicepod")
+	_ = benchEmbeddingsCmd.RegisterFlagCompletionFunc(modelFlag, completeModelNames)
+	benchEmbeddingsCmd.Flags().IntSlice(batchSizesFlag, []int{1, 8, 32}, "Comma-separated number of inputs per request")
+	benchEmbeddingsCmd.Flags().Int(iterationsFlag, 5, "Number of timed requests per batch size")
+	benchEmbeddingsCmd.Flags().Int(warmupFlag, 1, "Number of untimed requests per batch size before timing")
+	benchEmbeddingsCmd.Flags().String(inputFileFlag, "", "File with one input text per line, used instead of sample sentences")
+	benchCmd.AddCommand(benchEmbeddingsCmd)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+)
+
+const runsFlag = "runs"
+
+var benchRefreshCmd = &cobra.Command{
+	Use:               "refresh <dataset>",
+	Short:             "Profile acceleration refreshes of a dataset (requires spiced --metrics)",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice bench refresh taxi_trips
+spice bench refresh taxi_trips --runs 3 --ready-timeout 30m
+
+# With refresh_mode: append, runs after the first load incrementally
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dataset := args[0]
+		runs, _ := cmd.Flags().GetInt(runsFlag)
+		timeout, _ := cmd.Flags().GetDuration(readyTimeoutFlag)
+
+		rtcontext := newRuntimeContext(cmd)
+
+		var table []interface{}
+		for run := 1; run <= runs; run++ {
+			cmd.Printf("Refreshing dataset %s (run %d of %d) ...\n", dataset, run, runs)
+			profile, err := bench.ProfileRefresh(rtcontext, metricsEndpoint(cmd), dataset, timeout)
+			if err != nil {
+				return err
+			}
+			profile.Run = run
+			table = append(table, *profile)
+		}
+
+		return output(cmd).WriteTable(table)
+	},
+}
+
+func init() {
+	benchRefreshCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	benchRefreshCmd.Flags().Int(runsFlag, 2, "Number of consecutive refreshes to profile")
+	benchRefreshCmd.Flags().Duration(readyTimeoutFlag, 10*time.Minute, "How long to wait for each refresh to complete")
+	benchCmd.AddCommand(benchRefreshCmd)
+}
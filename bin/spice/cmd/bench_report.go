@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+)
+
+const (
+	thresholdFlag = "threshold"
+	lastFlag      = "last"
+)
+
+var benchReportCmd = &cobra.Command{
+	Use:   "report <suite>",
+	Short: "Show benchmark trends across recorded runs and flag regressions",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice bench report tpch
+spice bench report tpch --last 5 --threshold 20
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threshold, _ := cmd.Flags().GetFloat64(thresholdFlag)
+		last, _ := cmd.Flags().GetInt(lastFlag)
+
+		rtcontext := newRuntimeContext(cmd)
+		historyDir := bench.HistoryDir(rtcontext)
+
+		reports, err := bench.LoadHistory(historyDir, args[0])
+		if err != nil {
+			return err
+		}
+		if len(reports) == 0 {
+			cmd.Printf("No recorded %s runs in %s. Run spice bench %s first.\n", args[0], historyDir, args[0])
+			return nil
+		}
+		if last > 0 && len(reports) > last {
+			reports = reports[len(reports)-last:]
+		}
+
+		latest := reports[len(reports)-1]
+		cmd.Printf("%d %s runs from %s to %s\n", len(reports), args[0], reports[0].StartedAt.Format("2006-01-02 15:04"), latest.StartedAt.Format("2006-01-02 15:04"))
+		if latest.Metadata != nil {
+			runtimeVersion := latest.Metadata.RuntimeVersion
+			if runtimeVersion == "" {
+				runtimeVersion = "unknown"
+			}
+			cmd.Printf("Latest run: runtime %s, %s/%s, %d CPUs\n", runtimeVersion, latest.Metadata.OS, latest.Metadata.Arch, latest.Metadata.CPUs)
+		}
+
+		trends := bench.Trends(reports, threshold)
+		regressions := 0
+		table := make([]interface{}, len(trends))
+		for i, trend := range trends {
+			if trend.Status == bench.TREND_STATUS_REGRESSION {
+				regressions++
+			}
+			table[i] = trend
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+
+		if regressions > 0 {
+			return fmt.Errorf("%d queries regressed by more than %.0f%% since the previous run", regressions, threshold)
+		}
+		return nil
+	},
+}
+
+func init() {
+	benchReportCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	benchReportCmd.Flags().Float64(thresholdFlag, 10, "Percentage slowdown of a query's median that counts as a regression")
+	benchReportCmd.Flags().Int(lastFlag, 0, "Only include the most recent N runs (0 for all)")
+	benchCmd.AddCommand(benchReportCmd)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	resultsCachePath = "runtime.results_cache"
+
+	cacheEnabledFlag        = "enabled"
+	cacheMaxSizeFlag        = "max-size"
+	cacheItemTtlFlag        = "item-ttl"
+	cacheEvictionPolicyFlag = "eviction-policy"
+)
+
+// Runtime defaults applied when a results cache setting is not in the spicepod.
+var resultsCacheSettings = []struct {
+	key          string
+	flag         string
+	defaultValue string
+}{
+	{key: "enabled", flag: cacheEnabledFlag, defaultValue: "true"},
+	{key: "cache_max_size", flag: cacheMaxSizeFlag, defaultValue: "128MiB"},
+	{key: "item_ttl", flag: cacheItemTtlFlag, defaultValue: "1s"},
+	{key: "eviction_policy", flag: cacheEvictionPolicyFlag, defaultValue: "lru"},
+}
+
+type cacheSetting struct {
+	Setting string
+	Value   string
+	Source  string
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and configure the runtime's results cache",
+	Example: `
+spice cache status
+spice cache config --item-ttl 30s --max-size 1GiB
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var cacheStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show results cache hits, misses and size from the runtime's metrics",
+	Example: `
+spice cache status
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		stats, err := api.GetCacheStats(rtcontext, metricsEndpoint(cmd))
+		if err != nil {
+			return err
+		}
+		if stats == nil {
+			return errors.New("Unable to read runtime metrics. Is the runtime running? Start it with spice run.")
+		}
+
+		return output(cmd).WriteTable([]interface{}{*stats})
+	},
+}
+
+var cacheConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or change the results cache settings in spicepod.yaml",
+	Example: `
+spice cache config
+spice cache config --item-ttl 30s
+spice cache config --max-size 1GiB --eviction-policy lru
+spice cache config --enabled=false
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+
+		var values yaml.MapSlice
+		for _, setting := range resultsCacheSettings {
+			if !cmd.Flags().Changed(setting.flag) {
+				continue
+			}
+			var value interface{}
+			if setting.flag == cacheEnabledFlag {
+				enabled, err := cmd.Flags().GetBool(setting.flag)
+				if err != nil {
+					return err
+				}
+				value = enabled
+			} else {
+				s, err := cmd.Flags().GetString(setting.flag)
+				if err != nil {
+					return err
+				}
+				value = s
+			}
+			values = append(values, yaml.MapItem{Key: fmt.Sprintf("%s.%s", resultsCachePath, setting.key), Value: value})
+		}
+
+		if policy, _ := cmd.Flags().GetString(cacheEvictionPolicyFlag); cmd.Flags().Changed(cacheEvictionPolicyFlag) && policy != "lru" {
+			return fmt.Errorf("Unsupported eviction policy '%s'. The runtime only supports lru.", policy)
+		}
+
+		if len(values) > 0 {
+			err := spicepod.SetManifestValues(rtcontext.AppDir(), values)
+			if err != nil {
+				return err
+			}
+		}
+
+		var settings []interface{}
+		for _, setting := range resultsCacheSettings {
+			value, err := spicepod.ManifestValue(rtcontext.AppDir(), fmt.Sprintf("%s.%s", resultsCachePath, setting.key))
+			if err != nil {
+				return err
+			}
+			row := cacheSetting{Setting: setting.key, Value: setting.defaultValue, Source: "default"}
+			switch v := value.(type) {
+			case nil:
+			case bool:
+				row.Value, row.Source = strconv.FormatBool(v), "spicepod"
+			default:
+				row.Value, row.Source = fmt.Sprintf("%v", v), "spicepod"
+			}
+			settings = append(settings, row)
+		}
+		if err := output(cmd).WriteTable(settings); err != nil {
+			return err
+		}
+
+		if len(values) > 0 {
+			cmd.Println("Updated spicepod.yaml. The results cache is configured when the runtime starts, restart spice run to apply the new settings.")
+		}
+		return nil
+	},
+}
+
+func init() {
+	cacheStatusCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	cacheCmd.AddCommand(cacheStatusCmd)
+
+	cacheConfigCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	cacheConfigCmd.Flags().Bool(cacheEnabledFlag, true, "Enable or disable the results cache")
+	cacheConfigCmd.Flags().String(cacheMaxSizeFlag, "", "Maximum size of the results cache, e.g. 128MiB")
+	cacheConfigCmd.Flags().String(cacheItemTtlFlag, "", "Time to live of cached results, e.g. 30s")
+	cacheConfigCmd.Flags().String(cacheEvictionPolicyFlag, "", "Cache eviction policy, only lru is supported")
+	cacheCmd.AddCommand(cacheConfigCmd)
+
+	cacheCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+)
+
+var catalogsCmd = &cobra.Command{
+	Use:   "catalogs [catalog] [schema]",
+	Short: "List the catalogs of the Spice runtime and drill into their schemas and tables",
+	Args:  cobra.MaximumNArgs(2),
+	Example: `
+spice catalogs
+spice catalogs spice
+spice catalogs spice public
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+
+		var table []interface{}
+		switch len(args) {
+		case 0:
+			catalogs, err := api.GetCatalogs(rtcontext)
+			if err != nil {
+				return err
+			}
+			for _, catalog := range catalogs {
+				table = append(table, catalog)
+			}
+		case 1:
+			schemas, err := api.GetCatalogSchemas(rtcontext, args[0])
+			if err != nil {
+				return err
+			}
+			for _, schema := range schemas {
+				table = append(table, schema)
+			}
+		default:
+			tables, err := api.GetCatalogTables(rtcontext, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			for _, t := range tables {
+				table = append(table, t)
+			}
+		}
+		return output(cmd).WriteTable(table)
+	},
+}
+
+func init() {
+	catalogsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(catalogsCmd)
+}
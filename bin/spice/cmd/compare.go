@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+)
+
+const (
+	baselineFlag  = "baseline"
+	candidateFlag = "candidate"
+	queriesFlag   = "queries"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Run the same queries against two runtimes and diff their results",
+	Example: `
+spice compare --baseline http://old:8090 --candidate http://new:8090 --queries q.sql
+spice compare --baseline http://localhost:3000 --candidate http://localhost:3001 --queries ./queries
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baselineEndpoint, _ := cmd.Flags().GetString(baselineFlag)
+		candidateEndpoint, _ := cmd.Flags().GetString(candidateFlag)
+		queriesPath, _ := cmd.Flags().GetString(queriesFlag)
+
+		if baselineEndpoint == "" || candidateEndpoint == "" || queriesPath == "" {
+			return fmt.Errorf("--%s, --%s and --%s are required", baselineFlag, candidateFlag, queriesFlag)
+		}
+
+		queries, err := bench.LoadQueries(queriesPath)
+		if err != nil {
+			return err
+		}
+
+		baseline := newRuntimeContext(cmd)
+		baseline.SetHttpEndpoint(baselineEndpoint)
+		candidate := newRuntimeContext(cmd)
+		candidate.SetHttpEndpoint(candidateEndpoint)
+
+		cmd.Printf("Comparing %d queries between %s and %s ...\n", len(queries), baseline.HttpEndpoint(), candidate.HttpEndpoint())
+		diffs := bench.DiffQueries(baseline, candidate, queries)
+
+		failed := false
+		table := make([]interface{}, len(diffs))
+		for i, diff := range diffs {
+			if diff.Status != bench.DIFF_STATUS_MATCH {
+				failed = true
+			}
+			table[i] = diff
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+
+		if failed {
+			return errors.New("Results differ between the baseline and candidate runtimes")
+		}
+		return nil
+	},
+}
+
+func init() {
+	compareCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	compareCmd.Flags().String(baselineFlag, "", "HTTP endpoint of the baseline runtime")
+	compareCmd.Flags().String(candidateFlag, "", "HTTP endpoint of the candidate runtime")
+	compareCmd.Flags().String(queriesFlag, "", "SQL file (statements separated by ;) or directory of .sql files")
+	RootCmd.AddCommand(compareCmd)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+// Completions only ask the runtime if it answers quickly, so a stopped runtime never stalls the shell.
+const completionProbeTimeout = 500 * time.Millisecond
+
+var completionCmd = &cobra.Command{
+	Use:                   "completion [bash|zsh|fish|powershell]",
+	Short:                 "Generate the autocompletion script for a shell",
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	Example: `
+# Load completions in the current bash session
+source <(spice completion bash)
+
+# Load completions for every zsh session
+spice completion zsh > "${fpath[1]}/_spice"
+
+spice completion fish > ~/.config/fish/completions/spice.fish
+spice completion powershell | Out-String | Invoke-Expression
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = cmd.Root().GenBashCompletionV2(cmd.OutOrStdout(), true)
+		case "zsh":
+			err = cmd.Root().GenZshCompletion(cmd.OutOrStdout())
+		case "fish":
+			err = cmd.Root().GenFishCompletion(cmd.OutOrStdout(), true)
+		case "powershell":
+			err = cmd.Root().GenPowerShellCompletionWithDesc(cmd.OutOrStdout())
+		}
+		if err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+// completeDatasetNames completes the first argument with the datasets in spicepod.yaml and,
+// if it is running, the datasets loaded by the runtime.
+func completeDatasetNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeDatasetFlag(cmd, args, toComplete)
+}
+
+func completeDatasetFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	rtcontext := newRuntimeContext(cmd)
+	var names []string
+	if datasets, err := spicepod.LoadDatasets(rtcontext.AppDir()); err == nil {
+		for _, dataset := range datasets {
+			names = append(names, dataset.Name)
+		}
+	}
+	if rtcontext.IsRuntimeHealthy(completionProbeTimeout) == nil {
+		if datasets, err := api.GetData[api.Dataset](rtcontext, "/v1/datasets"); err == nil {
+			for _, dataset := range datasets {
+				names = append(names, dataset.Name)
+			}
+		}
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeModelNames completes with the models loaded by the runtime.
+func completeModelNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	rtcontext := newRuntimeContext(cmd)
+	if rtcontext.IsRuntimeHealthy(completionProbeTimeout) != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	models, err := api.GetData[api.Model](rtcontext, "/v1/models")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, len(models))
+	for i, model := range models {
+		names[i] = model.Name
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRegistryNames completes the first argument with the registries in the CLI config.
+func completeRegistryNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cliConfig, err := loadConfig(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, len(cliConfig.Registries))
+	for i, registry := range cliConfig.Registries {
+		names[i] = registry.Name
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfileNames completes the first argument with the profiles in the CLI config.
+func completeProfileNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cliConfig, err := loadConfig(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := []string{}
+	if cmd.Name() == "use" {
+		names = append(names, context.LOCAL_PROFILE)
+	}
+	for _, profile := range cliConfig.Profiles {
+		names = append(names, profile.Name)
+	}
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func filterCompletions(values []string, toComplete string) []string {
+	var completions []string
+	for _, value := range values {
+		if strings.HasPrefix(value, toComplete) && !slices.Contains(completions, value) {
+			completions = append(completions, value)
+		}
+	}
+	return completions
+}
+
+func init() {
+	// Replaces cobra's default completion command, which does not follow the CLI's help conventions
+	RootCmd.CompletionOptions.DisableDefaultCmd = true
+	completionCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(completionCmd)
+}
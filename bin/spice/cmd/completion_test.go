@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicCompletion(t *testing.T) {
+	mock := testutils.NewMockRuntime(t)
+	mock.SetDatasets(api.Dataset{Name: "orders"}, api.Dataset{Name: "order_items"}, api.Dataset{Name: "users"})
+	mock.SetModels(api.Model{Name: "nql"}, api.Model{Name: "embed"})
+	ctx := WithDependencies(gocontext.Background(), Dependencies{
+		NewRuntimeContext: func() *context.RuntimeContext { return mock.Context() },
+		DotSpiceDir:       mock.DotSpiceDir,
+	})
+
+	output := testutils.RunCommandContext(t, ctx, RootCmd, "__complete", "retention", "show", "ord")
+	assert.NoError(t, output.Err)
+	assert.Equal(t, "orders\norder_items\n:4\n", output.Stdout)
+
+	output = testutils.RunCommandContext(t, ctx, RootCmd, "__complete", "retention", "show", "orders", "")
+	assert.Equal(t, ":4\n", output.Stdout)
+
+	output = testutils.RunCommandContext(t, ctx, RootCmd, "__complete", "nsql", "--model", "")
+	assert.Equal(t, "nql\nembed\n:4\n", output.Stdout)
+
+	output = testutils.RunCommandContext(t, ctx, RootCmd, "completion", "bash")
+	assert.NoError(t, output.Err)
+	assert.Contains(t, output.Stdout, "bash completion V2 for spice")
+}
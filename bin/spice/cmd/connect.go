@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/diagnostics"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+// sourceStages are the stages of a data source probe, in the order they are checked.
+var sourceStages = []string{diagnostics.STAGE_DNS, diagnostics.STAGE_TCP, diagnostics.STAGE_AUTH, diagnostics.STAGE_PERMISSIONS, diagnostics.STAGE_SCHEMA}
+
+var connectCmd = &cobra.Command{
+	Use:   "connect",
+	Short: "Check the connections of datasets to their data sources",
+	Example: `
+spice connect test taxi_trips
+spice connect test postgres
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var connectTestCmd = &cobra.Command{
+	Use:   "test <dataset|connector>",
+	Short: "Probe the data source of a dataset, or of every dataset using a connector, stage by stage",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice connect test taxi_trips
+spice connect test postgres --probe-timeout 10s
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout, err := cmd.Flags().GetDuration("probe-timeout")
+		if err != nil {
+			return err
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		datasets, err := spicepod.LoadDatasets(rtcontext.AppDir())
+		if err != nil {
+			return err
+		}
+
+		var targets []*spec.DatasetSpec
+		for _, dataset := range datasets {
+			if strings.EqualFold(dataset.Name, args[0]) {
+				targets = []*spec.DatasetSpec{dataset}
+				break
+			}
+			if diagnostics.SourceConnector(dataset) == args[0] {
+				targets = append(targets, dataset)
+			}
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("No dataset named %s or loaded with the %s connector in spicepod.yaml", args[0], args[0])
+		}
+
+		var results []interface{}
+		failed := false
+		for _, dataset := range targets {
+			for _, check := range probeSource(rtcontext, dataset, timeout) {
+				if check.Status == checkStatusFailed {
+					failed = true
+				}
+				results = append(results, check)
+			}
+		}
+
+		if err := output(cmd).WriteTable(results); err != nil {
+			return err
+		}
+
+		if failed {
+			return errReported
+		}
+		return nil
+	},
+}
+
+// probeSource checks the network path from this machine to the dataset's source, then asks
+// the runtime to read the dataset's schema through its connector. The runtime is asked even
+// when the network checks fail, since it may run on a different network.
+func probeSource(rtcontext *context.RuntimeContext, dataset *spec.DatasetSpec, timeout time.Duration) []diagnostics.CheckResult {
+	checks := make([]diagnostics.CheckResult, len(sourceStages))
+	for i, stage := range sourceStages {
+		checks[i] = diagnostics.CheckResult{Check: fmt.Sprintf("%s %s", dataset.Name, stage), Endpoint: dataset.From, Status: checkStatusSkipped}
+	}
+
+	host, port, ok := diagnostics.SourceAddress(dataset)
+	if ok {
+		checks[0].Endpoint = host
+		checks[1].Endpoint = net.JoinHostPort(host, port)
+		setStageResults(checks[:2], sourceStages[:2], diagnostics.ProbeSourceNetwork(host, port, timeout))
+	} else {
+		checks[0].Detail = "no source host in the dataset params, the runtime resolves it"
+		checks[1].Detail = checks[0].Detail
+	}
+
+	columns, err := diagnostics.ProbeSourceQuery(rtcontext, dataset.Name)
+	setStageResults(checks[2:], sourceStages[2:], err)
+	if err == nil {
+		checks[4].Detail = fmt.Sprintf("%d columns", len(columns))
+	}
+	return checks
+}
+
+// setStageResults marks the stages before the one err failed at as ok, that stage as failed
+// and the rest as skipped. Errors without a stage, such as an unreachable runtime, fail the
+// first stage.
+func setStageResults(checks []diagnostics.CheckResult, stages []string, err error) {
+	failedAt := len(checks)
+	if err != nil {
+		failedAt = 0
+		var probeErr *diagnostics.ProbeError
+		if errors.As(err, &probeErr) && slices.Contains(stages, probeErr.Stage) {
+			failedAt = slices.Index(stages, probeErr.Stage)
+		}
+	}
+
+	for i := range checks {
+		switch {
+		case i < failedAt:
+			checks[i].Status = checkStatusOk
+		case i == failedAt:
+			checks[i].Status = checkStatusFailed
+			checks[i].Detail = describeProbeError(err)
+		default:
+			checks[i].Status = checkStatusSkipped
+			checks[i].Detail = fmt.Sprintf("%s failed", stages[failedAt])
+		}
+	}
+}
+
+func init() {
+	connectCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	connectTestCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	connectTestCmd.Flags().Duration("probe-timeout", 5*time.Second, "Timeout for the DNS and TCP probes")
+	connectCmd.AddCommand(connectTestCmd)
+	RootCmd.AddCommand(connectCmd)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/connectors"
+)
+
+type connectorSummary struct {
+	Name        string `json:"name" csv:"name" yaml:"name"`
+	Description string `json:"description" csv:"description" yaml:"description"`
+	From        string `json:"from" csv:"from" yaml:"from"`
+}
+
+var connectorsCmd = &cobra.Command{
+	Use:   "connectors",
+	Short: "Discover the data connectors datasets can be loaded from",
+	Example: `
+spice connectors list
+spice connectors describe postgres
+
+# See more at: https://docs.spiceai.org/data-connectors/
+`,
+}
+
+var connectorsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available data connectors",
+	Example: `
+spice connectors list
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		table := make([]interface{}, len(connectors.Builtin))
+		for i, connector := range connectors.Builtin {
+			table[i] = connectorSummary{Name: connector.Name, Description: connector.Description, From: connector.From}
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+		cmd.Println("Show the parameters of a connector with: spice connectors describe <name>")
+		return nil
+	},
+}
+
+var connectorsDescribeCmd = &cobra.Command{
+	Use:   "describe <name>",
+	Short: "Show the parameters and secrets a data connector reads",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice connectors describe postgres
+spice connectors describe s3
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connector := connectors.Find(connectors.Builtin, args[0])
+		if connector == nil {
+			names := make([]string, len(connectors.Builtin))
+			for i, c := range connectors.Builtin {
+				names[i] = c.Name
+			}
+			return fmt.Errorf("Unknown connector '%s', available connectors: %s", args[0], strings.Join(names, ", "))
+		}
+
+		cmd.Printf("%s: %s\n", connector.Name, connector.Description)
+
+		if len(connector.Parameters) > 0 {
+			table := make([]interface{}, len(connector.Parameters))
+			for i, param := range connector.Parameters {
+				table[i] = param
+			}
+			if err := output(cmd).WriteTable(table); err != nil {
+				return err
+			}
+		}
+
+		cmd.Printf("Example dataset:\n\n%s\n", connectorExample(connector))
+
+		for _, param := range connector.Parameters {
+			if param.Secret {
+				secret := strings.ToUpper(connector.Name)
+				cmd.Printf("Secret parameters are read from the '%s' secret, e.g. SPICE_SECRET_%s_%s with the env secret store.\n", connector.Name, secret, strings.ToUpper(param.Name))
+				break
+			}
+		}
+		return nil
+	},
+}
+
+// connectorExample renders a spicepod.yaml dataset using the connector with its required, non-secret params.
+func connectorExample(connector *connectors.Connector) string {
+	var example strings.Builder
+	example.WriteString("datasets:\n")
+	example.WriteString(fmt.Sprintf("  - from: %s\n", connector.From))
+	example.WriteString("    name: my_dataset\n")
+
+	var params []string
+	for _, param := range connector.Parameters {
+		if param.Required && !param.Secret {
+			params = append(params, fmt.Sprintf("      %s: <%s>\n", param.Name, param.Name))
+		}
+	}
+	if len(params) > 0 {
+		example.WriteString("    params:\n")
+		example.WriteString(strings.Join(params, ""))
+	}
+	return example.String()
+}
+
+func init() {
+	connectorsListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	connectorsCmd.AddCommand(connectorsListCmd)
+
+	connectorsDescribeCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	connectorsCmd.AddCommand(connectorsDescribeCmd)
+
+	connectorsCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(connectorsCmd)
+}
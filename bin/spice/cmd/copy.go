@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/export"
+	"github.com/spiceai/spiceai/bin/spice/pkg/ingest"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+const asFlag = "as"
+
+var copyCmd = &cobra.Command{
+	Use:   "copy",
+	Short: "Copy a dataset's data from another runtime into this app as a local dataset",
+	Example: `
+spice copy --from http://prod:8090 --dataset orders
+spice copy --from http://prod:8090 --dataset orders --as orders_prod --to http://localhost:8090
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString(fromFlag)
+		to, _ := cmd.Flags().GetString(toFlag)
+		dataset, _ := cmd.Flags().GetString(datasetFlag)
+		localName, _ := cmd.Flags().GetString(asFlag)
+
+		if from == "" || dataset == "" {
+			return fmt.Errorf("--%s and --%s are required", fromFlag, datasetFlag)
+		}
+		if localName == "" {
+			localName = dataset
+		}
+		if !datasetNamePattern.MatchString(localName) {
+			return errors.New("Dataset name can only contain letters, numbers, underscores, and hyphens")
+		}
+		if fi, err := os.Stat("spicepod.yaml"); os.IsNotExist(err) || fi.IsDir() {
+			return errors.New("No spicepod.yaml found. Run spice init <app> first.")
+		}
+
+		source := newRuntimeContext(cmd)
+		source.SetHttpEndpoint(from)
+		target := newRuntimeContext(cmd)
+		if to != "" {
+			target.SetHttpEndpoint(to)
+		}
+
+		dataDir := ingest.DataDir(target.AppDir(), localName)
+		definition, _ := spicepod.FindDatasetDefinition(target.AppDir(), localName)
+		if definition != nil {
+			if err := checkImportedDataset(definition, dataDir, ingest.FORMAT_CSV); err != nil {
+				return fmt.Errorf("%s, use --%s to copy into a different local dataset", err.Error(), asFlag)
+			}
+		}
+
+		cmd.Printf("Copying dataset %s from %s ...\n", dataset, source.HttpEndpoint())
+		tmpFile := filepath.Join(dataDir, fmt.Sprintf(".%s.csv.tmp", localName))
+		files, err := export.Query(source, fmt.Sprintf("SELECT * FROM %s", dataset), export.Options{
+			Format: export.FORMAT_CSV,
+			Output: tmpFile,
+		})
+		if err != nil {
+			os.Remove(tmpFile)
+			return err
+		}
+
+		// Replace the data of a previous copy only once the new copy is complete
+		previous, _ := filepath.Glob(filepath.Join(dataDir, "*.csv"))
+		for _, file := range previous {
+			os.Remove(file)
+		}
+		err = os.Rename(tmpFile, filepath.Join(dataDir, fmt.Sprintf("%s.csv", localName)))
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Copied %d rows to %s\n", files[0].Rows, target.GetSpiceAppRelativePath(dataDir))
+
+		if definition == nil {
+			spec, err := ingest.NewDatasetSpec(localName, dataDir, ingest.FORMAT_CSV)
+			if err == nil {
+				var filePath string
+				filePath, err = ingest.WriteDataset(target.AppDir(), spec)
+				if err == nil {
+					cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Saved %s", target.GetSpiceAppRelativePath(filePath))))
+				}
+			}
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+
+		res, err := api.PostRuntime[DatasetRefreshApiResponse](target, fmt.Sprintf("/v1/datasets/%s/acceleration/refresh", localName))
+		if err != nil {
+			cmd.Printf("Could not refresh dataset %s at %s (%s), the new data loads on its next refresh\n", localName, target.HttpEndpoint(), err.Error())
+			return nil
+		}
+		cmd.Println(res.Message)
+		return nil
+	},
+}
+
+func init() {
+	copyCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	copyCmd.Flags().String(fromFlag, "", "HTTP endpoint of the runtime to copy from")
+	copyCmd.Flags().String(toFlag, "", "HTTP endpoint of the runtime serving this app, refreshed after the copy (default: the local runtime)")
+	copyCmd.Flags().String(datasetFlag, "", "Dataset to copy")
+	copyCmd.Flags().String(asFlag, "", "Name of the local dataset (default: the source dataset name)")
+	RootCmd.AddCommand(copyCmd)
+}
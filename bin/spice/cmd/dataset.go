@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -25,7 +26,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
 	"gopkg.in/yaml.v2"
@@ -44,26 +44,23 @@ spice dataset configure
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if fi, err := os.Stat("spicepod.yaml"); os.IsNotExist(err) || fi.IsDir() {
-			cmd.Println(aurora.BrightRed("No spicepod.yaml found. Run spice init <app> first."))
-			os.Exit(1)
+			return errors.New("No spicepod.yaml found. Run spice init <app> first.")
 		}
 
 		reader := bufio.NewReader(os.Stdin)
 
 		cwd, err := os.Getwd()
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		defaultDatasetName := path.Base(cwd)
 		cmd.Printf("dataset name: (%s) ", defaultDatasetName)
 		datasetName, err := reader.ReadString('\n')
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 		datasetName = strings.TrimSpace(strings.TrimSuffix(datasetName, "\n"))
 		if datasetName == "" {
@@ -72,33 +69,29 @@ spice dataset configure
 
 		match, err := regexp.MatchString("^[a-zA-Z0-9_-]+$", datasetName)
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 
 		if !match {
-			cmd.Println(aurora.BrightRed("Dataset name can only contain letters, numbers, underscores, and hyphens"))
-			os.Exit(1)
+			return errors.New("Dataset name can only contain letters, numbers, underscores, and hyphens")
 		}
 
 		if strings.Contains(datasetName, "-") {
 			// warn that dataset name with hyphen should be quoted in queries
-			cmd.Println(aurora.BrightYellow(fmt.Sprintf("Dataset names with hyphens should be quoted in queries:\ni.e. SELECT * FROM \"%s\"", datasetName)))
+			cmd.Println(colors(cmd).BrightYellow(fmt.Sprintf("Dataset names with hyphens should be quoted in queries:\ni.e. SELECT * FROM \"%s\"", datasetName)))
 		}
 
 		cmd.Print("description: ")
 		description, err := reader.ReadString('\n')
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 		description = strings.TrimSuffix(description, "\n")
 
 		cmd.Print("from: ")
 		from, err := reader.ReadString('\n')
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 		from = strings.TrimSpace(strings.TrimSuffix(from, "\n"))
 
@@ -108,8 +101,7 @@ spice dataset configure
 			cmd.Print("endpoint: ")
 			endpoint, err := reader.ReadString('\n')
 			if err != nil {
-				cmd.Println(err.Error())
-				os.Exit(1)
+				return err
 			}
 			endpoint = strings.TrimSuffix(endpoint, "\n")
 
@@ -123,8 +115,7 @@ spice dataset configure
 				cmd.Print("file_format (parquet/csv) (parquet) ")
 				file_format, err := reader.ReadString('\n')
 				if err != nil {
-					cmd.Println(err.Error())
-					os.Exit(1)
+					return err
 				}
 				file_format = strings.TrimSuffix(file_format, "\n")
 
@@ -133,8 +124,7 @@ spice dataset configure
 				}
 
 				if file_format != "parquet" && file_format != "csv" {
-					cmd.Println(aurora.BrightRed("file_format must be either parquet or csv"))
-					os.Exit(1)
+					return errors.New("file_format must be either parquet or csv")
 				}
 
 				params["file_format"] = file_format
@@ -144,8 +134,7 @@ spice dataset configure
 		cmd.Print("locally accelerate (y/n)? (y) ")
 		locallyAccelerateStr, err := reader.ReadString('\n')
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 		locallyAccelerateStr = strings.TrimSuffix(locallyAccelerateStr, "\n")
 		accelerateDataset := locallyAccelerateStr == "" || strings.ToLower(locallyAccelerateStr) == "y"
@@ -167,35 +156,30 @@ spice dataset configure
 
 		datasetBytes, err := yaml.Marshal(dataset)
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		dirPath := fmt.Sprintf("datasets/%s", dataset.Name)
 		err = os.MkdirAll(dirPath, 0766)
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		filePath := fmt.Sprintf("%s/dataset.yaml", dirPath)
 		err = os.WriteFile(filePath, datasetBytes, 0766)
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		spicepodBytes, err := os.ReadFile("spicepod.yaml")
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		var spicePod spec.SpicepodSpec
 		err = yaml.Unmarshal(spicepodBytes, &spicePod)
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		var datasetReferenced bool
@@ -212,18 +196,17 @@ spice dataset configure
 			})
 			spicepodBytes, err = yaml.Marshal(spicePod)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(1)
+				return err
 			}
 
 			err = os.WriteFile("spicepod.yaml", spicepodBytes, 0766)
 			if err != nil {
-				cmd.Println(err)
-				os.Exit(1)
+				return err
 			}
 		}
 
-		cmd.Println(aurora.BrightGreen(fmt.Sprintf("Saved %s", filePath)))
+		cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Saved %s", filePath)))
+		return nil
 	},
 }
 
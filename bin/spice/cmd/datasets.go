@@ -19,8 +19,6 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 	"github.com/spiceai/spiceai/bin/spice/pkg/api"
-	"github.com/spiceai/spiceai/bin/spice/pkg/context"
-	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
 var datasetsCmd = &cobra.Command{
@@ -29,16 +27,17 @@ var datasetsCmd = &cobra.Command{
 	Example: `
 spice datasets
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		rtcontext := context.NewContext()
-		_, dataset_statuses, err := api.GetComponentStatuses(PROM_ENDPOINT)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		// Datasets are listed without their status when the metrics are unavailable
+		_, dataset_statuses, err := api.GetComponentStatuses(rtcontext, metricsEndpoint(cmd))
 		if err != nil {
 			cmd.PrintErrln(err.Error())
 		}
 
 		datasets, err := api.GetData[api.Dataset](rtcontext, "/v1/datasets?status=true")
 		if err != nil {
-			cmd.PrintErrln(err.Error())
+			return err
 		}
 		table := make([]interface{}, len(datasets))
 		for i, dataset := range datasets {
@@ -48,7 +47,7 @@ spice datasets
 			}
 			table[i] = dataset
 		}
-		util.WriteTable(table)
+		return output(cmd).WriteTable(table)
 	},
 }
 
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/profile"
+)
+
+const (
+	sampleFlag  = "sample"
+	bucketsFlag = "buckets"
+)
+
+var datasetsProfileCmd = &cobra.Command{
+	Use:               "profile <dataset>",
+	Short:             "Compute column statistics for a dataset: null %, distinct counts, min/max and histograms",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice datasets profile taxi_trips
+spice datasets profile taxi_trips --sample 0 --buckets 20
+spice datasets profile taxi_trips --output-file taxi_trips.html
+spice datasets profile taxi_trips --output-file taxi_trips.json
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sampleSize, _ := cmd.Flags().GetInt(sampleFlag)
+		buckets, _ := cmd.Flags().GetInt(bucketsFlag)
+		outputFile, _ := cmd.Flags().GetString(outputFileFlag)
+
+		rtcontext := newRuntimeContext(cmd)
+		result, err := profile.Run(rtcontext, args[0], profile.Options{
+			SampleSize: sampleSize,
+			Buckets:    buckets,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.SampleSize > 0 && result.Rows >= int64(result.SampleSize) {
+			cmd.Printf("Profiled a sample of %d rows from %s, use --%s 0 to profile every row\n", result.Rows, result.Dataset, sampleFlag)
+		} else {
+			cmd.Printf("Profiled %d rows from %s\n", result.Rows, result.Dataset)
+		}
+
+		summaries := result.Summaries()
+		table := make([]interface{}, len(summaries))
+		for i, summary := range summaries {
+			table[i] = summary
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+
+		if outputFile != "" {
+			if err = result.Save(outputFile); err != nil {
+				return err
+			}
+			cmd.Printf("\nSaved profile to %s\n", outputFile)
+		}
+		return nil
+	},
+}
+
+func init() {
+	datasetsProfileCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	datasetsProfileCmd.Flags().Int(sampleFlag, 100000, "Number of rows to sample, or 0 to profile the whole dataset")
+	datasetsProfileCmd.Flags().Int(bucketsFlag, 10, "Number of histogram buckets for numeric columns")
+	datasetsProfileCmd.Flags().String(outputFileFlag, "", "Also save the profile to this file, as HTML when it ends in .html, otherwise as JSON")
+	datasetsCmd.AddCommand(datasetsProfileCmd)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const sinceFlag = "since"
+
+type datasetQueriesRow struct {
+	Dataset      string
+	Window       string
+	Queries      int
+	CacheHits    int
+	AvgLatency   string
+	P95Latency   string
+	BytesScanned string
+}
+
+var datasetsQueriesCmd = &cobra.Command{
+	Use:               "queries <dataset>",
+	Short:             "Summarize the queries that read a dataset, from the runtime's query history",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice datasets queries taxi_trips
+spice datasets queries taxi_trips --since 1h
+spice datasets queries taxi_trips --since 168h -o json
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, _ := cmd.Flags().GetDuration(sinceFlag)
+		if since <= 0 {
+			return fmt.Errorf("Invalid --%s: must be a positive duration, e.g. 24h", sinceFlag)
+		}
+		dataset := args[0]
+
+		rtcontext := newRuntimeContext(cmd)
+		if err := rtcontext.IsRuntimeHealthy(2 * time.Second); err != nil {
+			return errors.New("The runtime must be running to read its query history. Start it with spice run.")
+		}
+
+		records, err := api.Sql[accel.QueryRecord](rtcontext, accel.DatasetQueryHistorySql(dataset, since, recordsBytesScanned(rtcontext)))
+		if err != nil {
+			return err
+		}
+
+		row := datasetQueriesRow{
+			Dataset:      dataset,
+			Window:       since.String(),
+			AvgLatency:   "-",
+			P95Latency:   "-",
+			BytesScanned: "-",
+		}
+		activities := accel.AnalyzeQueryHistory(records, []string{dataset})
+		if len(activities) == 1 {
+			activity := activities[0]
+			row.Queries = activity.Queries
+			row.CacheHits = activity.CacheHits
+			if len(activity.Latencies) > 0 {
+				row.AvgLatency = activity.AverageLatency().Round(time.Millisecond).String()
+				row.P95Latency = activity.P95Latency().Round(time.Millisecond).String()
+			}
+			if activity.BytesScanned != nil {
+				row.BytesScanned = util.FormatBytes(float64(*activity.BytesScanned))
+			}
+		}
+
+		return output(cmd).WriteTable([]interface{}{row})
+	},
+}
+
+// recordsBytesScanned reports whether the runtime's query history has a bytes_scanned column.
+func recordsBytesScanned(rtcontext *context.RuntimeContext) bool {
+	columns, err := api.GetDatasetColumns(rtcontext, "runtime.query_history")
+	if err != nil {
+		return false
+	}
+	for _, column := range columns {
+		if column.Name == "bytes_scanned" {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	datasetsQueriesCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	datasetsQueriesCmd.Flags().Duration(sinceFlag, 24*time.Hour, "Only include queries started within this window")
+	datasetsCmd.AddCommand(datasetsQueriesCmd)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/schema"
+)
+
+var datasetsSchemaCmd = &cobra.Command{
+	Use:               "schema <dataset>",
+	Short:             "Export the schema of a dataset as SQL DDL, JSON Schema, or Go and Python types",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice datasets schema taxi_trips
+spice datasets schema taxi_trips --format jsonschema > taxi_trips.schema.json
+spice datasets schema taxi_trips --format go
+spice datasets schema taxi_trips --format python
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString(formatFlag)
+		format = strings.ToLower(format)
+		if err := schema.ValidateFormat(format); err != nil {
+			return err
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		columns, err := api.GetDatasetColumns(rtcontext, args[0])
+		if err != nil {
+			return err
+		}
+
+		output, err := schema.Generate(args[0], columns, format)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), output)
+		return nil
+	},
+}
+
+func init() {
+	datasetsSchemaCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	datasetsSchemaCmd.Flags().String(formatFlag, schema.FORMAT_SQL, fmt.Sprintf("Output format, one of: %s", strings.Join(schema.Formats, ", ")))
+	datasetsCmd.AddCommand(datasetsSchemaCmd)
+}
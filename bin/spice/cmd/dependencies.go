@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	gocontext "context"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+// Dependencies are the services commands reach the runtime through. They are carried by the
+// Go context a command is executed with, so tests can run many invocations in one process,
+// each against its own runtime.
+type Dependencies struct {
+	// Creates the runtime context for an invocation. Commands comparing two runtimes call it twice.
+	NewRuntimeContext func() *context.RuntimeContext
+	// The runtime's Prometheus metrics endpoint.
+	MetricsEndpoint string
+	// Where the CLI keeps its configuration, credentials and other state, ~/.spice when empty.
+	DotSpiceDir string
+}
+
+type dependenciesKey struct{}
+
+// WithDependencies returns a Go context carrying deps, e.g. for RootCmd.ExecuteContext. Unset
+// fields fall back to the defaults.
+func WithDependencies(ctx gocontext.Context, deps Dependencies) gocontext.Context {
+	return gocontext.WithValue(ctx, dependenciesKey{}, deps)
+}
+
+func dependencies(cmd *cobra.Command) Dependencies {
+	var deps Dependencies
+	if ctx := cmd.Context(); ctx != nil {
+		deps, _ = ctx.Value(dependenciesKey{}).(Dependencies)
+	}
+	if deps.NewRuntimeContext == nil {
+		dotSpiceDir := deps.DotSpiceDir
+		deps.NewRuntimeContext = func() *context.RuntimeContext {
+			return context.NewContextWithDotSpiceDir(dotSpiceDir)
+		}
+	}
+	if deps.MetricsEndpoint == "" {
+		deps.MetricsEndpoint = PROM_ENDPOINT
+	}
+	return deps
+}
+
+// newRuntimeContext creates the runtime context for an invocation, whose requests are bound to
+// the command's Go context and limited by --timeout.
+func newRuntimeContext(cmd *cobra.Command) *context.RuntimeContext {
+	rtcontext := dependencies(cmd).NewRuntimeContext()
+	rtcontext.SetContext(cmd.Context())
+	rtcontext.SetOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+	rtcontext.SetProgressOutput(progressOutput(cmd))
+	timeout, _ := cmd.Flags().GetDuration(timeoutFlag)
+	rtcontext.SetRequestTimeout(timeout)
+	// Validated by applyRetries before the command runs.
+	retries, _ := cmd.Flags().GetInt(retriesFlag)
+	_ = rtcontext.SetRetries(retries)
+	return rtcontext
+}
+
+func metricsEndpoint(cmd *cobra.Command) string {
+	return dependencies(cmd).MetricsEndpoint
+}
+
+// dotSpiceDir returns the directory the CLI keeps its state in for an invocation.
+func dotSpiceDir(cmd *cobra.Command) (string, error) {
+	if dir := dependencies(cmd).DotSpiceDir; dir != "" {
+		return dir, nil
+	}
+	return constants.DotSpiceDir()
+}
+
+// loadConfig reads the CLI configuration of an invocation.
+func loadConfig(cmd *cobra.Command) (*config.CliConfig, error) {
+	spiceDir, err := dotSpiceDir(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(spiceDir)
+}
+
+// loadAuthConfig reads the credentials saved by spice login for an invocation.
+func loadAuthConfig(cmd *cobra.Command) (map[string]*api.Auth, error) {
+	spiceDir, err := dotSpiceDir(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return api.LoadAuthConfig(spiceDir)
+}
+
+func saveConfig(cmd *cobra.Command, cliConfig *config.CliConfig) error {
+	spiceDir, err := dotSpiceDir(cmd)
+	if err != nil {
+		return err
+	}
+	return cliConfig.Save(spiceDir)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	gocontext "context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockRuntimeDependencies(t *testing.T, dataset string, status api.ComponentStatus) gocontext.Context {
+	mock := testutils.NewMockRuntime(t)
+	mock.SetDatasets(api.Dataset{Name: dataset, From: fmt.Sprintf("postgres:%s", dataset)})
+	mock.Handle("GET", "/metrics", func(testutils.MockRequest) (int, interface{}) {
+		return http.StatusOK, fmt.Sprintf("# TYPE dataset_status gauge\ndataset_status{dataset=\"%s\"} %d\n", dataset, status)
+	})
+
+	return WithDependencies(gocontext.Background(), Dependencies{
+		NewRuntimeContext: func() *context.RuntimeContext { return mock.Context() },
+		MetricsEndpoint:   mock.Server.URL,
+		DotSpiceDir:       mock.DotSpiceDir,
+	})
+}
+
+func TestDependenciesAreScopedToInvocations(t *testing.T) {
+	orders := mockRuntimeDependencies(t, "orders", api.Ready)
+	trips := mockRuntimeDependencies(t, "trips", api.Refreshing)
+
+	output := testutils.RunCommandContext(t, orders, RootCmd, "datasets")
+	assert.NoError(t, output.Err)
+	assert.Contains(t, output.Stdout, "postgres:orders")
+	assert.Contains(t, output.Stdout, "Ready")
+
+	output = testutils.RunCommandContext(t, trips, RootCmd, "datasets")
+	assert.NoError(t, output.Err)
+	assert.Contains(t, output.Stdout, "postgres:trips")
+	assert.Contains(t, output.Stdout, "Refreshing")
+	assert.NotContains(t, output.Stdout, "orders")
+
+	output = testutils.RunCommandContext(t, orders, RootCmd, "datasets")
+	assert.NoError(t, output.Err)
+	assert.Contains(t, output.Stdout, "postgres:orders")
+	assert.NotContains(t, output.Stdout, "trips")
+}
+
+func TestRequestsAreBoundToInvocations(t *testing.T) {
+	mock := testutils.NewMockRuntime(t)
+	mock.SetLatency("GET", "/v1/datasets", time.Second)
+	ctx := WithDependencies(gocontext.Background(), Dependencies{
+		NewRuntimeContext: func() *context.RuntimeContext { return mock.Context() },
+		MetricsEndpoint:   mock.Server.URL,
+		DotSpiceDir:       mock.DotSpiceDir,
+	})
+
+	start := time.Now()
+	output := testutils.RunCommandContext(t, ctx, RootCmd, "datasets", "--timeout", "50ms")
+	assert.ErrorContains(t, output.Err, fmt.Sprintf("The Spice runtime at %s did not respond within 50ms", mock.Server.URL))
+	assert.Empty(t, output.Stdout)
+	assert.Less(t, time.Since(start), time.Second)
+
+	canceled, cancel := gocontext.WithCancel(ctx)
+	cancel()
+	output = testutils.RunCommandContext(t, canceled, RootCmd, "datasets")
+	assert.ErrorContains(t, output.Err, "context canceled")
+	assert.Equal(t, 1, mock.Requests("GET", "/v1/datasets"))
+}
+
+func TestMetricsRequestsAreBoundToTimeout(t *testing.T) {
+	mock := testutils.NewMockRuntime(t)
+	mock.SetLatency("GET", "/metrics", time.Second)
+	ctx := WithDependencies(gocontext.Background(), Dependencies{
+		NewRuntimeContext: func() *context.RuntimeContext { return mock.Context() },
+		MetricsEndpoint:   mock.Server.URL,
+		DotSpiceDir:       mock.DotSpiceDir,
+	})
+
+	start := time.Now()
+	output := testutils.RunCommandContext(t, ctx, RootCmd, "datasets", "--timeout", "50ms")
+	assert.NoError(t, output.Err, "datasets are listed without their status")
+	assert.Contains(t, output.Stderr, "did not respond within 50ms")
+	assert.Less(t, time.Since(start), time.Second)
+}
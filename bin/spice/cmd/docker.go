@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/docker"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+)
+
+const sidecarsFlag = "sidecars"
+
+var dockerCmd = &cobra.Command{
+	Use:   "docker",
+	Short: "Containerize the Spice runtime and Spicepod with Docker",
+	Example: `
+spice docker init
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var dockerInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate a Dockerfile and docker-compose.yml for the local Spicepod",
+	Example: `
+spice docker init
+spice docker init --sidecars postgres
+spice docker init --sidecars "" --image-tag 0.13.1-alpha
+spice docker init --force
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spicepodDir, _ := cmd.Flags().GetString(spicepodFlag)
+
+		options := docker.InitOptions{}
+		options.ImageTag, _ = cmd.Flags().GetString(imageTagFlag)
+		options.Sidecars, _ = cmd.Flags().GetStringSlice(sidecarsFlag)
+		options.Force, _ = cmd.Flags().GetBool(forceFlag)
+
+		if options.ImageTag == "" {
+			options.ImageTag = docker.ImageTag(version.Version())
+		}
+
+		files, err := docker.Init(spicepodDir, options)
+		if err != nil {
+			if errors.Is(err, docker.ErrFileExists) {
+				return fmt.Errorf("Error generating Docker files: %s\nUse --%s to overwrite existing files", err.Error(), forceFlag)
+			}
+			return fmt.Errorf("Error generating Docker files: %s", err.Error())
+		}
+
+		for _, file := range files {
+			cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Wrote %s", file)))
+		}
+		cmd.Println("Start the runtime and sidecars with: docker compose up --build")
+		return nil
+	},
+}
+
+func init() {
+	dockerInitCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	dockerInitCmd.Flags().String(spicepodFlag, ".", "Directory of the Spicepod to containerize")
+	dockerInitCmd.Flags().String(imageTagFlag, "", "Runtime image tag, the one matching this CLI's version when empty")
+	dockerInitCmd.Flags().StringSlice(sidecarsFlag, docker.Sidecars, "Sidecar services to add to docker-compose.yml: postgres, minio")
+	dockerInitCmd.Flags().Bool(forceFlag, false, "Overwrite existing Docker files")
+	dockerCmd.AddCommand(dockerInitCmd)
+
+	dockerCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(dockerCmd)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/diagnostics"
+)
+
+const (
+	checkStatusOk      = "ok"
+	checkStatusFailed  = "failed"
+	checkStatusSkipped = "skipped"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose connectivity to the Spice runtime and Spice.ai cloud endpoints",
+	Example: `
+spice doctor
+spice doctor --probe-timeout 10s
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout, err := cmd.Flags().GetDuration("probe-timeout")
+		if err != nil {
+			return err
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		var results []interface{}
+
+		runtimeCheck := diagnostics.CheckResult{Check: "runtime", Endpoint: rtcontext.HttpEndpoint(), Status: checkStatusOk}
+		if err := rtcontext.IsRuntimeHealthy(timeout); err != nil {
+			runtimeCheck.Status = checkStatusFailed
+			runtimeCheck.Detail = err.Error()
+		}
+		results = append(results, runtimeCheck)
+
+		var apiKey string
+		authConfig, err := loadAuthConfig(cmd)
+		if err != nil {
+			cmd.PrintErrf("Error reading auth config: %s\n", err.Error())
+		} else if spiceAuth, ok := authConfig[api.AUTH_TYPE_SPICE_AI]; ok && spiceAuth.Params != nil {
+			apiKey = spiceAuth.Params[api.AUTH_PARAM_KEY]
+		}
+
+		failed := runtimeCheck.Status == checkStatusFailed
+		for _, endpoint := range []struct {
+			name     string
+			endpoint string
+			httpAuth bool
+		}{
+			{name: "cloud http", endpoint: diagnostics.CloudHttpEndpoint(), httpAuth: true},
+			{name: "cloud flight", endpoint: diagnostics.CloudFlightEndpoint()},
+		} {
+			checks := probeCloudEndpoint(endpoint.name, endpoint.endpoint, endpoint.httpAuth, apiKey, timeout)
+			for _, check := range checks {
+				if check.Status == checkStatusFailed {
+					failed = true
+				}
+				results = append(results, check)
+			}
+		}
+
+		if err := output(cmd).WriteTable(results); err != nil {
+			return err
+		}
+
+		if failed {
+			return errReported
+		}
+		return nil
+	},
+}
+
+func probeCloudEndpoint(name string, endpoint string, httpAuth bool, apiKey string, timeout time.Duration) []diagnostics.CheckResult {
+	connectivity := diagnostics.CheckResult{Check: fmt.Sprintf("%s connectivity", name), Endpoint: endpoint, Status: checkStatusOk}
+	auth := diagnostics.CheckResult{Check: fmt.Sprintf("%s auth", name), Endpoint: endpoint, Status: checkStatusSkipped}
+
+	cert, err := diagnostics.ProbeTLSEndpoint(endpoint, timeout)
+	if err != nil {
+		connectivity.Status = checkStatusFailed
+		connectivity.Detail = describeProbeError(err)
+		auth.Detail = "endpoint unreachable"
+		return []diagnostics.CheckResult{connectivity, auth}
+	}
+	if cert != nil {
+		connectivity.Detail = fmt.Sprintf("TLS certificate for %s issued by %s, valid until %s", cert.Subject.CommonName, cert.Issuer.CommonName, cert.NotAfter.Format(time.RFC3339))
+	}
+
+	switch {
+	case !httpAuth:
+		auth.Detail = "not supported for Flight endpoints"
+	case apiKey == "":
+		auth.Detail = "not logged in, run spice login"
+	default:
+		err = diagnostics.ProbeHttpAuth(endpoint, apiKey, timeout)
+		if err != nil {
+			auth.Status = checkStatusFailed
+			auth.Detail = describeProbeError(err)
+		} else {
+			auth.Status = checkStatusOk
+		}
+	}
+
+	return []diagnostics.CheckResult{connectivity, auth}
+}
+
+func describeProbeError(err error) string {
+	var probeErr *diagnostics.ProbeError
+	if !errors.As(err, &probeErr) {
+		return err.Error()
+	}
+
+	switch probeErr.Stage {
+	case diagnostics.STAGE_DNS:
+		return fmt.Sprintf("DNS resolution failed: %s", probeErr.Err.Error())
+	case diagnostics.STAGE_NETWORK, diagnostics.STAGE_TCP:
+		return fmt.Sprintf("network connection failed: %s", probeErr.Err.Error())
+	case diagnostics.STAGE_TLS:
+		return fmt.Sprintf("TLS verification failed: %s", probeErr.Err.Error())
+	case diagnostics.STAGE_AUTH:
+		return fmt.Sprintf("authentication failed: %s", probeErr.Err.Error())
+	case diagnostics.STAGE_PERMISSIONS:
+		return fmt.Sprintf("permission denied: %s", probeErr.Err.Error())
+	case diagnostics.STAGE_SCHEMA:
+		return fmt.Sprintf("schema could not be read: %s", probeErr.Err.Error())
+	}
+
+	return probeErr.Error()
+}
+
+func init() {
+	doctorCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	doctorCmd.Flags().Duration("probe-timeout", 5*time.Second, "Timeout for each connectivity probe")
+	RootCmd.AddCommand(doctorCmd)
+}
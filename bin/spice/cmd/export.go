@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/export"
+)
+
+const (
+	sqlFlag         = "sql"
+	formatFlag      = "format"
+	partitionByFlag = "partition-by"
+)
+
+var exportCmd = &cobra.Command{
+	Use:               "export [dataset]",
+	Short:             "Export a dataset or query result to local CSV or JSON files",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice export taxi_trips
+spice export taxi_trips --format jsonl --output-file trips.jsonl
+spice export taxi_trips --partition-by pickup_date --output-file ./trips
+spice export --sql "SELECT * FROM taxi_trips WHERE fare_amount > 100" --output-file expensive.csv
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sql, _ := cmd.Flags().GetString(sqlFlag)
+		format, _ := cmd.Flags().GetString(formatFlag)
+		outputFile, _ := cmd.Flags().GetString(outputFileFlag)
+		partitionBy, _ := cmd.Flags().GetString(partitionByFlag)
+
+		format = strings.ToLower(format)
+		if err := export.ValidateFormat(format); err != nil {
+			return err
+		}
+
+		name := "query"
+		switch {
+		case len(args) == 1 && sql != "":
+			return fmt.Errorf("Provide either a dataset or --%s, not both", sqlFlag)
+		case len(args) == 1:
+			name = args[0]
+			sql = fmt.Sprintf("SELECT * FROM %s", name)
+		case sql == "":
+			return fmt.Errorf("Provide a dataset to export or a query with --%s", sqlFlag)
+		}
+
+		if outputFile == "" {
+			outputFile = name
+			if partitionBy == "" {
+				outputFile = fmt.Sprintf("%s.%s", name, format)
+			}
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+
+		files, err := export.Query(rtcontext, sql, export.Options{
+			Format:      format,
+			Output:      outputFile,
+			PartitionBy: partitionBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		rows := 0
+		table := make([]interface{}, len(files))
+		for i, file := range files {
+			rows += file.Rows
+			table[i] = file
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+		cmd.Printf("\nExported %d rows to %d files\n", rows, len(files))
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	exportCmd.Flags().String(sqlFlag, "", "Export the results of this query instead of a dataset")
+	exportCmd.Flags().String(formatFlag, export.FORMAT_CSV, fmt.Sprintf("Output format, one of: %s", strings.Join(export.Formats, ", ")))
+	exportCmd.Flags().String(outputFileFlag, "", "Output file, or directory when partitioning (default: the dataset name)")
+	exportCmd.Flags().String(partitionByFlag, "", "Write one file per distinct value of this column")
+	RootCmd.AddCommand(exportCmd)
+}
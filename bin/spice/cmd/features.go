@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/features"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+type featureRow struct {
+	Name        string
+	Available   bool
+	Description string
+	RequiredBy  string
+}
+
+var featuresCmd = &cobra.Command{
+	Use:   "features",
+	Short: "List the optional features the runtime is built with, and check those the spicepod needs",
+	Example: `
+spice features
+spice features -o json
+
+# Exits with an error if spicepod.yaml uses a connector, accelerator or secret store the
+# runtime is built without
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		available, err := features.List(rtcontext)
+		if err != nil {
+			return err
+		}
+
+		// Outside of an app directory there is no spicepod to check
+		var datasets []*spec.DatasetSpec
+		pod, err := spicepod.LoadManifest(rtcontext.AppDir())
+		if err == nil {
+			datasets, err = spicepod.LoadDatasets(rtcontext.AppDir())
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		required := features.Required(pod, datasets)
+
+		var missing []string
+		table := make([]interface{}, len(available))
+		for i, feature := range available {
+			table[i] = featureRow{
+				Name:        feature.Name,
+				Available:   feature.Enabled,
+				Description: feature.Description,
+				RequiredBy:  strings.Join(required[feature.Name], ", "),
+			}
+			if !feature.Enabled && len(required[feature.Name]) > 0 {
+				missing = append(missing, feature.Name)
+			}
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf("The runtime is built without %s, which spicepod.yaml needs. Install a runtime build with these features.", strings.Join(missing, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	featuresCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(featuresCmd)
+}
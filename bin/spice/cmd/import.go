@@ -0,0 +1,303 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/ingest"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+const (
+	appendFlag        = "append"
+	dryRunFlag        = "dry-run"
+	streamFlag        = "stream"
+	batchSizeFlag     = "batch-size"
+	flushIntervalFlag = "flush-interval"
+)
+
+var datasetNamePattern = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>...",
+	Short: "Import local CSV, Parquet or JSON files as an accelerated dataset",
+	Example: `
+spice import trips.csv
+spice import events.jsonl --dataset events --dry-run
+spice import trips-2024-06.csv --dataset trips --append
+tail -f app.log | spice import --dataset logs --format jsonl --stream
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if fi, err := os.Stat("spicepod.yaml"); os.IsNotExist(err) || fi.IsDir() {
+			return errors.New("No spicepod.yaml found. Run spice init <app> first.")
+		}
+
+		datasetName, _ := cmd.Flags().GetString(datasetFlag)
+		format, _ := cmd.Flags().GetString(formatFlag)
+		appendData, _ := cmd.Flags().GetBool(appendFlag)
+		dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+		stream, _ := cmd.Flags().GetBool(streamFlag)
+
+		if stream {
+			if len(args) > 0 {
+				return fmt.Errorf("--%s reads from stdin and does not take files", streamFlag)
+			}
+			return streamImport(cmd, datasetName, format)
+		}
+		if len(args) == 0 {
+			return errors.New("Provide one or more files to import, or --stream to read from stdin")
+		}
+
+		formats := make([]string, len(args))
+		for i, file := range args {
+			formats[i] = strings.ToLower(format)
+			if formats[i] == "" {
+				detected, err := ingest.DetectFormat(file)
+				if err != nil {
+					return err
+				}
+				formats[i] = detected
+			}
+			if ingest.StorageFormat(formats[i]) != ingest.StorageFormat(formats[0]) {
+				return errors.New("All imported files must be Parquet, or all CSV and JSON")
+			}
+		}
+		storageFormat := ingest.StorageFormat(formats[0])
+
+		if datasetName == "" {
+			base := filepath.Base(args[0])
+			datasetName = strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(strings.TrimSuffix(base, filepath.Ext(base)))
+		}
+		if !datasetNamePattern.MatchString(datasetName) {
+			return errors.New("Dataset name can only contain letters, numbers, underscores, and hyphens")
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		dataDir := ingest.DataDir(rtcontext.AppDir(), datasetName)
+
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), datasetName)
+		switch {
+		case definition != nil && !appendData:
+			return fmt.Errorf("Dataset %s already exists, use --%s to add the files to it", datasetName, appendFlag)
+		case definition == nil && appendData:
+			return fmt.Errorf("Dataset %s does not exist, omit --%s to create it", datasetName, appendFlag)
+		case definition != nil:
+			err = checkImportedDataset(definition, dataDir, storageFormat)
+			if err != nil {
+				return err
+			}
+		}
+
+		var columns []string
+		if appendData {
+			columns, err = ingest.ExistingColumns(dataDir)
+			if err != nil {
+				return err
+			}
+		}
+
+		schema, err := ingest.InferSchema(args[0], formats[0])
+		switch {
+		case errors.Is(err, ingest.ErrSchemaInferenceUnsupported):
+			cmd.Println(err.Error())
+		case err != nil:
+			return err
+		default:
+			cmd.Printf("Inferred schema of %s:\n", args[0])
+			table := make([]interface{}, len(schema))
+			for i, column := range schema {
+				table[i] = column
+			}
+			if err := output(cmd).WriteTable(table); err != nil {
+				return err
+			}
+		}
+
+		if dryRun {
+			return nil
+		}
+
+		for i, file := range args {
+			copied, err := ingest.CopyInto(file, formats[i], dataDir, columns)
+			if err != nil {
+				return fmt.Errorf("Error importing %s: %s", file, err.Error())
+			}
+			cmd.Printf("Imported %s to %s\n", file, rtcontext.GetSpiceAppRelativePath(copied))
+
+			// Later files must match the columns of the first
+			if columns == nil && storageFormat == ingest.FORMAT_CSV {
+				columns, err = ingest.ExistingColumns(dataDir)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if !appendData {
+			dataset, err := ingest.NewDatasetSpec(datasetName, dataDir, storageFormat)
+			if err != nil {
+				return err
+			}
+			filePath, err := ingest.WriteDataset(rtcontext.AppDir(), dataset)
+			if err != nil {
+				return err
+			}
+			cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Saved %s", rtcontext.GetSpiceAppRelativePath(filePath))))
+			return nil
+		}
+
+		res, err := api.PostRuntime[DatasetRefreshApiResponse](rtcontext, fmt.Sprintf("/v1/datasets/%s/acceleration/refresh", datasetName))
+		if err != nil {
+			cmd.Printf("Could not refresh dataset %s (%s), the new data loads on its next refresh\n", datasetName, err.Error())
+			return nil
+		}
+		cmd.Println(res.Message)
+		return nil
+	},
+}
+
+// streamImport appends rows read from stdin to the dataset in batches, creating the
+// dataset from the first batch if needed and refreshing it after each batch.
+func streamImport(cmd *cobra.Command, datasetName string, format string) error {
+	batchSize, _ := cmd.Flags().GetInt(batchSizeFlag)
+	flushInterval, _ := cmd.Flags().GetDuration(flushIntervalFlag)
+
+	if datasetName == "" {
+		return fmt.Errorf("--%s is required with --%s", datasetFlag, streamFlag)
+	}
+	if !datasetNamePattern.MatchString(datasetName) {
+		return errors.New("Dataset name can only contain letters, numbers, underscores, and hyphens")
+	}
+	if format == "" {
+		format = ingest.FORMAT_JSONL
+	}
+	if batchSize < 1 || flushInterval <= 0 {
+		return fmt.Errorf("--%s and --%s must be positive", batchSizeFlag, flushIntervalFlag)
+	}
+
+	rtcontext := newRuntimeContext(cmd)
+	dataDir := ingest.DataDir(rtcontext.AppDir(), datasetName)
+
+	var columns []string
+	definition, _ := spicepod.FindDatasetDefinition(rtcontext.AppDir(), datasetName)
+	if definition != nil {
+		err := checkImportedDataset(definition, dataDir, ingest.FORMAT_CSV)
+		if err == nil {
+			columns, err = ingest.ExistingColumns(dataDir)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	stop := make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		close(stop)
+	}()
+
+	cmd.Printf("Streaming %s rows from stdin into dataset %s ...\n", format, datasetName)
+	err := ingest.Stream(os.Stdin, dataDir, ingest.StreamOptions{
+		Format:        format,
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+		Columns:       columns,
+	}, stop, func(batch ingest.Batch) error {
+		if batch.Invalid > 0 {
+			cmd.PrintErrf("Skipped %d invalid rows\n", batch.Invalid)
+		}
+		if batch.Rows == 0 {
+			return nil
+		}
+
+		if definition == nil {
+			dataset, err := ingest.NewDatasetSpec(datasetName, dataDir, ingest.FORMAT_CSV)
+			if err != nil {
+				return err
+			}
+			filePath, err := ingest.WriteDataset(rtcontext.AppDir(), dataset)
+			if err != nil {
+				return err
+			}
+			definition, err = spicepod.FindDatasetDefinition(rtcontext.AppDir(), datasetName)
+			if err != nil {
+				return err
+			}
+			cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Saved %s", rtcontext.GetSpiceAppRelativePath(filePath))))
+			cmd.Printf("Wrote %d rows\n", batch.Rows)
+			return nil
+		}
+
+		_, err := api.PostRuntime[DatasetRefreshApiResponse](rtcontext, fmt.Sprintf("/v1/datasets/%s/acceleration/refresh", datasetName))
+		if err != nil {
+			return fmt.Errorf("Wrote %d rows, refresh failed: %s", batch.Rows, err.Error())
+		}
+		cmd.Printf("Wrote %d rows\n", batch.Rows)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkImportedDataset verifies an existing dataset reads from the import directory, so
+// appended files are picked up on refresh.
+func checkImportedDataset(definition *spicepod.DatasetDefinition, dataDir string, storageFormat string) error {
+	absDataDir, err := filepath.Abs(dataDir)
+	if err != nil {
+		return err
+	}
+
+	from, _ := definition.Get("from").(string)
+	if strings.TrimSuffix(from, "/") != fmt.Sprintf("file:%s", filepath.ToSlash(absDataDir)) {
+		return fmt.Errorf("dataset %s was not created by spice import, its data cannot be appended to", definition.Name)
+	}
+
+	if fileFormat, _ := definition.Get("params.file_format").(string); fileFormat != storageFormat {
+		return fmt.Errorf("dataset %s holds %s files, cannot append %s data", definition.Name, fileFormat, storageFormat)
+	}
+
+	return nil
+}
+
+func init() {
+	importCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	importCmd.Flags().String(datasetFlag, "", "Dataset name (default: derived from the first file name)")
+	importCmd.Flags().String(formatFlag, "", "File format: csv, parquet, json or jsonl (default: from the file extension)")
+	importCmd.Flags().Bool(appendFlag, false, "Add the files to an existing imported dataset and refresh it")
+	importCmd.Flags().Bool(dryRunFlag, false, "Preview the inferred schema without importing")
+	importCmd.Flags().Bool(streamFlag, false, "Continuously import csv or jsonl rows from stdin")
+	importCmd.Flags().Int(batchSizeFlag, 1000, "Maximum rows per batch when streaming")
+	importCmd.Flags().Duration(flushIntervalFlag, 5*time.Second, "How often to write pending rows when streaming")
+	RootCmd.AddCommand(importCmd)
+}
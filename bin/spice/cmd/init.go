@@ -22,7 +22,6 @@ import (
 	"path"
 	"strings"
 
-	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
 )
@@ -35,15 +34,14 @@ spice init
 spice init <spice app name>
 spice init my_app
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var spicepodName string
 		spicepodDir := "."
 
 		if len(args) < 1 || args[0] == "." {
 			wd, err := os.Getwd()
 			if err != nil {
-				cmd.PrintErrf("Error getting current working directory: %s\n", err.Error())
-				return
+				return fmt.Errorf("Error getting current working directory: %w", err)
 			}
 			dirName := path.Base(wd)
 
@@ -63,17 +61,17 @@ spice init my_app
 			var confirm string
 			_, _ = fmt.Scanf("%s", &confirm)
 			if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
-				return
+				return nil
 			}
 		}
 
 		spicepodPath, err := spicepod.CreateManifest(spicepodName, spicepodDir)
 		if err != nil {
-			cmd.PrintErrf("Error creating spicepod.yaml: %s\n", err.Error())
-			return
+			return fmt.Errorf("Error creating spicepod.yaml: %w", err)
 		}
 
-		cmd.Println(aurora.BrightGreen(fmt.Sprintf("%s initialized!", spicepodPath)))
+		cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("%s initialized!", spicepodPath)))
+		return nil
 	},
 }
 
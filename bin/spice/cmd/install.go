@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/github"
+	"github.com/spiceai/spiceai/bin/spice/pkg/runtime"
+)
+
+const (
+	fromFileFlag  = "from-file"
+	checksumsFlag = "checksums"
+)
+
+var installCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the Spice.ai runtime, the latest release or a pinned version",
+	Example: `
+spice install
+spice install --version v0.14.0
+spice install --from-file ./spiced_linux_x86_64.tar.gz
+
+# --from-file installs without network access, verifying the tarball against the release's
+# checksums.txt, by default the one in the same directory
+
+# A pinned runtime is not upgraded by spice run, spice install without --version unpins it
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runtimeVersion, _ := cmd.Flags().GetString(versionFlag)
+		fromFile, _ := cmd.Flags().GetString(fromFileFlag)
+		checksumsFile, _ := cmd.Flags().GetString(checksumsFlag)
+		if fromFile != "" && runtimeVersion != "" {
+			return fmt.Errorf("--%s and --%s cannot be used together, the version is read from the file", fromFileFlag, versionFlag)
+		}
+		rtcontext := newRuntimeContext(cmd)
+
+		var err error
+		if fromFile != "" {
+			if checksumsFile == "" {
+				checksumsFile = filepath.Join(filepath.Dir(fromFile), github.CHECKSUMS_ASSET_NAME)
+			}
+			err = rtcontext.InstallRuntimeFromFile(fromFile, checksumsFile)
+		} else if runtimeVersion != "" {
+			err = runtime.EnsureInstalled(rtcontext, runtimeVersion)
+		} else {
+			err = rtcontext.InstallOrUpgradeRuntime()
+		}
+		if err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	installCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	installCmd.Flags().String(versionFlag, "", "Runtime release to install and pin, e.g. v0.14.0 (default: the latest release)")
+	installCmd.Flags().String(fromFileFlag, "", "Install from a local runtime release tarball instead of downloading from GitHub")
+	installCmd.Flags().String(checksumsFlag, "", "Checksums file to verify --from-file against (default: checksums.txt next to the tarball)")
+	RootCmd.AddCommand(installCmd)
+}
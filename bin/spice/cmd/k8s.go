@@ -0,0 +1,367 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/k8s"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	namespaceFlag    = "namespace"
+	releaseFlag      = "release"
+	chartFlag        = "chart"
+	chartVersionFlag = "chart-version"
+	imageTagFlag     = "image-tag"
+	replicasFlag     = "replicas"
+	envFlag          = "env"
+	podMonitorFlag   = "pod-monitor"
+	waitFlag         = "wait"
+	podFlag          = "pod"
+	httpPortFlag     = "http-port"
+	flightPortFlag   = "flight-port"
+	noRestartFlag    = "no-restart"
+)
+
+// Profile selected while spice k8s port-forward runs.
+const k8sProfile = "k8s"
+
+var k8sCmd = &cobra.Command{
+	Use:   "k8s",
+	Short: "Deploy and manage the Spice runtime on Kubernetes",
+	Example: `
+spice k8s install --namespace spice
+spice k8s status --namespace spice
+spice k8s port-forward --namespace spice
+spice k8s sync --namespace spice
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var k8sInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install or upgrade the Spice.ai Helm chart with the local Spicepod",
+	Example: `
+spice k8s install --namespace spice
+spice k8s install --namespace spice --replicas 2 --env SPICE_SECRET_POSTGRES_PASSWORD=...
+spice k8s install --chart ./deploy/chart --set resources.limits.memory=4Gi
+spice k8s install --dry-run
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spicepodDir, _ := cmd.Flags().GetString(spicepodFlag)
+		dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+
+		options := k8s.InstallOptions{}
+		options.Namespace, _ = cmd.Flags().GetString(namespaceFlag)
+		options.Release, _ = cmd.Flags().GetString(releaseFlag)
+		options.Chart, _ = cmd.Flags().GetString(chartFlag)
+		options.ChartVersion, _ = cmd.Flags().GetString(chartVersionFlag)
+		options.ImageTag, _ = cmd.Flags().GetString(imageTagFlag)
+		options.Replicas, _ = cmd.Flags().GetInt(replicasFlag)
+		options.Env, _ = cmd.Flags().GetStringArray(envFlag)
+		options.Set, _ = cmd.Flags().GetStringArray(setFlag)
+		options.PodMonitor, _ = cmd.Flags().GetBool(podMonitorFlag)
+		options.Wait, _ = cmd.Flags().GetBool(waitFlag)
+		options.Timeout, _ = cmd.Flags().GetDuration(readyTimeoutFlag)
+
+		if options.ImageTag == "" {
+			options.ImageTag = k8s.ImageTag(version.Version())
+		}
+
+		values, err := k8s.BuildValues(spicepodDir, options)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			valuesBytes, err := yaml.Marshal(values)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("# helm %s\n", strings.Join(k8s.HelmInstallArgs(options, "<values.yaml>"), " "))
+			cmd.Print(string(valuesBytes))
+			return nil
+		}
+
+		spinner := progress.NewSpinner(progressOutput(cmd), fmt.Sprintf("Installing release %s into namespace %s", options.Release, options.Namespace))
+		spinner.Start()
+		output, err := k8s.Install(options, values)
+		if err != nil {
+			spinner.Stop("failed")
+			return err
+		}
+		spinner.Stop("done")
+		cmd.Print(output)
+
+		cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Installed %s, check the rollout with: spice k8s status --namespace %s --release %s", options.Release, options.Namespace, options.Release)))
+		return nil
+	},
+}
+
+var k8sStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the rollout status and pods of a Spice deployment on Kubernetes",
+	Example: `
+spice k8s status --namespace spice
+spice k8s status --namespace spice --wait --ready-timeout 10m
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		namespace, _ := cmd.Flags().GetString(namespaceFlag)
+		release, _ := cmd.Flags().GetString(releaseFlag)
+		wait, _ := cmd.Flags().GetBool(waitFlag)
+		timeout, _ := cmd.Flags().GetDuration(readyTimeoutFlag)
+
+		// Without --wait, report the rollout as it is right now.
+		if !wait {
+			timeout = time.Second
+		}
+
+		rollout, rolloutErr := k8s.RolloutStatus(namespace, release, timeout)
+
+		pods, err := k8s.GetPods(namespace, release)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			return fmt.Errorf("No pods found for release %s in namespace %s", release, namespace)
+		}
+
+		table := make([]interface{}, len(pods))
+		for i, pod := range pods {
+			table[i] = pod
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+		cmd.Println()
+
+		if rolloutErr != nil {
+			return rolloutErr
+		}
+		cmd.Print(rollout)
+		return nil
+	},
+}
+
+var k8sPortForwardCmd = &cobra.Command{
+	Use:   "port-forward",
+	Short: "Forward the HTTP and Flight ports of a Spice runtime on Kubernetes to localhost",
+	Example: `
+spice k8s port-forward --namespace spice
+spice k8s port-forward --namespace spice --http-port 13000 --flight-port 15051
+spice k8s port-forward --namespace spice --pod spiceai-7d9c8b5f4-x2x9k
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		namespace, _ := cmd.Flags().GetString(namespaceFlag)
+		release, _ := cmd.Flags().GetString(releaseFlag)
+		pod, _ := cmd.Flags().GetString(podFlag)
+		httpPort, _ := cmd.Flags().GetInt(httpPortFlag)
+		flightPort, _ := cmd.Flags().GetInt(flightPortFlag)
+
+		if pod == "" {
+			pods, err := k8s.GetPods(namespace, release)
+			if err != nil {
+				return err
+			}
+			pod, err = k8s.SelectPod(pods)
+			if err != nil {
+				return fmt.Errorf("Error selecting a pod for release %s in namespace %s: %s", release, namespace, err.Error())
+			}
+		}
+
+		portForward, err := k8s.PortForwardCommand(namespace, pod, []k8s.PortMapping{
+			{Local: httpPort, Remote: k8s.RUNTIME_HTTP_PORT},
+			{Local: flightPort, Remote: k8s.RUNTIME_FLIGHT_PORT},
+		})
+		if err != nil {
+			return err
+		}
+		portForward.Stdout = cmd.OutOrStdout()
+		portForward.Stderr = cmd.ErrOrStderr()
+
+		// Point the CLI at the forwarded ports with a profile while they are open, and back to
+		// the profile it used before.
+		cliConfig, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		previousCurrentProfile := cliConfig.CurrentProfile
+		previousProfile := cliConfig.GetProfile(k8sProfile)
+		if previousProfile != nil {
+			saved := *previousProfile
+			previousProfile = &saved
+		}
+		profile := config.ProfileConfig{
+			Name:           k8sProfile,
+			Endpoint:       k8s.LocalEndpoint("http", httpPort),
+			FlightEndpoint: k8s.LocalEndpoint("grpc", flightPort),
+		}
+		cliConfig.SetProfile(profile)
+		cliConfig.CurrentProfile = k8sProfile
+		err = saveConfig(cmd, cliConfig)
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Forwarding pod %s: HTTP on %s, Flight on %s, using profile %s. Press Ctrl+C to stop.\n", pod, profile.Endpoint, profile.FlightEndpoint, k8sProfile)
+		runErr := util.RunCommand(portForward)
+
+		// Reload, so profiles changed while the ports were forwarded are kept
+		cliConfig, err = loadConfig(cmd)
+		if err == nil {
+			if previousProfile != nil {
+				cliConfig.SetProfile(*previousProfile)
+			} else {
+				cliConfig.RemoveProfile(k8sProfile)
+			}
+			if cliConfig.CurrentProfile == k8sProfile {
+				cliConfig.CurrentProfile = previousCurrentProfile
+			}
+			err = saveConfig(cmd, cliConfig)
+		}
+		if err != nil {
+			return fmt.Errorf("Error restoring the CLI profile: %s", err.Error())
+		}
+
+		if runErr != nil {
+			return fmt.Errorf("kubectl port-forward exited: %s", runErr.Error())
+		}
+		return nil
+	},
+}
+
+var k8sSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Update a Kubernetes deployment's Spicepod from the local spicepod.yaml and restart it",
+	Example: `
+spice k8s sync --namespace spice
+spice k8s sync --namespace spice --wait
+spice k8s sync --dry-run
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		namespace, _ := cmd.Flags().GetString(namespaceFlag)
+		release, _ := cmd.Flags().GetString(releaseFlag)
+		spicepodDir, _ := cmd.Flags().GetString(spicepodFlag)
+		noRestart, _ := cmd.Flags().GetBool(noRestartFlag)
+		dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+		wait, _ := cmd.Flags().GetBool(waitFlag)
+		timeout, _ := cmd.Flags().GetDuration(readyTimeoutFlag)
+
+		changed, err := k8s.SyncSpicepod(namespace, release, spicepodDir, dryRun)
+		if err != nil {
+			return fmt.Errorf("Error syncing ConfigMap %s: %s", k8s.ConfigMapName(release), err.Error())
+		}
+
+		if !changed {
+			cmd.Printf("ConfigMap %s is up to date\n", k8s.ConfigMapName(release))
+			return nil
+		}
+		if dryRun {
+			cmd.Printf("ConfigMap %s differs from the local Spicepod and would be updated\n", k8s.ConfigMapName(release))
+			return nil
+		}
+		cmd.Printf("Updated ConfigMap %s\n", k8s.ConfigMapName(release))
+
+		if noRestart {
+			cmd.Printf("Running pods keep the previous Spicepod until they restart: kubectl rollout restart deployment/%s --namespace %s\n", release, namespace)
+			return nil
+		}
+
+		err = k8s.RolloutRestart(namespace, release)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Restarting deployment %s\n", release)
+
+		if wait {
+			spinner := progress.NewSpinner(progressOutput(cmd), fmt.Sprintf("Waiting for the rollout of deployment %s", release))
+			spinner.Start()
+			rollout, err := k8s.RolloutStatus(namespace, release, timeout)
+			if err != nil {
+				spinner.Stop("failed")
+				return err
+			}
+			spinner.Stop("done")
+			cmd.Print(rollout)
+		}
+		return nil
+	},
+}
+
+func init() {
+	k8sInstallCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	k8sInstallCmd.Flags().StringP(namespaceFlag, "n", k8s.DEFAULT_NAMESPACE, "Kubernetes namespace to install into")
+	k8sInstallCmd.Flags().String(releaseFlag, k8s.DEFAULT_RELEASE, "Helm release name")
+	k8sInstallCmd.Flags().String(spicepodFlag, ".", "Directory of the Spicepod to deploy")
+	k8sInstallCmd.Flags().String(chartFlag, k8s.HELM_CHART, "Chart in the Spice.ai Helm repository, or the path of a local chart")
+	k8sInstallCmd.Flags().String(chartVersionFlag, "", "Chart version, the latest when empty")
+	k8sInstallCmd.Flags().String(imageTagFlag, "", "Runtime image tag, the one matching this CLI's version when empty")
+	k8sInstallCmd.Flags().Int(replicasFlag, 0, "Number of runtime replicas, the chart's default when 0")
+	k8sInstallCmd.Flags().StringArray(envFlag, []string{}, "Environment variable for the runtime as NAME=VALUE, e.g. for env secrets")
+	k8sInstallCmd.Flags().StringArray(setFlag, []string{}, "Additional chart value as key=value, passed to helm --set")
+	k8sInstallCmd.Flags().Bool(podMonitorFlag, false, "Create a Prometheus PodMonitor for the runtime's metrics")
+	k8sInstallCmd.Flags().Bool(waitFlag, false, "Wait for the rollout to complete")
+	k8sInstallCmd.Flags().Duration(readyTimeoutFlag, 5*time.Minute, "How long to wait for the rollout with --wait")
+	k8sInstallCmd.Flags().Bool(dryRunFlag, false, "Print the helm command and generated values without installing")
+	k8sCmd.AddCommand(k8sInstallCmd)
+
+	k8sStatusCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	k8sStatusCmd.Flags().StringP(namespaceFlag, "n", k8s.DEFAULT_NAMESPACE, "Kubernetes namespace of the deployment")
+	k8sStatusCmd.Flags().String(releaseFlag, k8s.DEFAULT_RELEASE, "Helm release name")
+	k8sStatusCmd.Flags().Bool(waitFlag, false, "Wait for the rollout to complete")
+	k8sStatusCmd.Flags().Duration(readyTimeoutFlag, 5*time.Minute, "How long to wait for the rollout with --wait")
+	k8sCmd.AddCommand(k8sStatusCmd)
+
+	k8sPortForwardCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	k8sPortForwardCmd.Flags().StringP(namespaceFlag, "n", k8s.DEFAULT_NAMESPACE, "Kubernetes namespace of the deployment")
+	k8sPortForwardCmd.Flags().String(releaseFlag, k8s.DEFAULT_RELEASE, "Helm release name")
+	k8sPortForwardCmd.Flags().String(podFlag, "", "Pod to forward to, the first ready pod of the release when empty")
+	k8sPortForwardCmd.Flags().Int(httpPortFlag, k8s.RUNTIME_HTTP_PORT, "Local port for the runtime's HTTP API")
+	k8sPortForwardCmd.Flags().Int(flightPortFlag, k8s.RUNTIME_FLIGHT_PORT, "Local port for the runtime's Arrow Flight endpoint")
+	k8sCmd.AddCommand(k8sPortForwardCmd)
+
+	k8sSyncCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	k8sSyncCmd.Flags().StringP(namespaceFlag, "n", k8s.DEFAULT_NAMESPACE, "Kubernetes namespace of the deployment")
+	k8sSyncCmd.Flags().String(releaseFlag, k8s.DEFAULT_RELEASE, "Helm release name")
+	k8sSyncCmd.Flags().String(spicepodFlag, ".", "Directory of the Spicepod to sync")
+	k8sSyncCmd.Flags().Bool(noRestartFlag, false, "Update the ConfigMap without restarting the deployment")
+	k8sSyncCmd.Flags().Bool(dryRunFlag, false, "Report whether the ConfigMap differs without updating it")
+	k8sSyncCmd.Flags().Bool(waitFlag, false, "Wait for the restart to complete")
+	k8sSyncCmd.Flags().Duration(readyTimeoutFlag, 5*time.Minute, "How long to wait for the restart with --wait")
+	k8sCmd.AddCommand(k8sSyncCmd)
+
+	k8sCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(k8sCmd)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+)
+
+const (
+	scriptFlag      = "script"
+	concurrencyFlag = "concurrency"
+	durationFlag    = "duration"
+	requestsFlag    = "requests"
+)
+
+var loadCmd = &cobra.Command{
+	Use:   "load",
+	Short: "Load test the Spice runtime with a scripted SQL and HTTP workload",
+	Example: `
+spice load --script workload.yaml --concurrency 32 --duration 5m
+
+# workload.yaml
+requests:
+  - name: top_trips
+    type: sql
+    weight: 3
+    sql: SELECT * FROM taxi_trips ORDER BY fare_amount DESC LIMIT 10
+  - name: ask
+    type: nsql
+    query: how many trips were there?
+  - name: status
+    type: http
+    method: GET
+    path: /v1/status
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script, _ := cmd.Flags().GetString(scriptFlag)
+		concurrency, _ := cmd.Flags().GetInt(concurrencyFlag)
+		duration, _ := cmd.Flags().GetDuration(durationFlag)
+		maxRequests, _ := cmd.Flags().GetInt(requestsFlag)
+		outputFile, _ := cmd.Flags().GetString(outputFileFlag)
+
+		if script == "" {
+			return fmt.Errorf("No workload provided, use --%s to provide a workload script", scriptFlag)
+		}
+
+		workload, err := bench.LoadWorkload(script)
+		if err != nil {
+			return err
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		if err := rtcontext.IsRuntimeHealthy(5 * time.Second); err != nil {
+			return rtcontext.RuntimeUnavailableError()
+		}
+
+		cmd.Printf("Running workload %s for %s with concurrency %d ...\n", script, duration, concurrency)
+		report, err := bench.RunLoad(rtcontext, workload, bench.LoadTestOptions{
+			Concurrency: concurrency,
+			Duration:    duration,
+			MaxRequests: maxRequests,
+		})
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("\n%d requests in %s, %.1f requests/sec, %d errors\n", report.Requests, report.Duration.Round(time.Millisecond), report.Throughput, report.Errors)
+		if err := output(cmd).WriteTable(report.Summaries()); err != nil {
+			return err
+		}
+
+		if report.Errors > 0 {
+			cmd.Println("\nErrors:")
+			if err := output(cmd).WriteTable(report.ErrorSummaries()); err != nil {
+				return err
+			}
+		}
+
+		if outputFile != "" {
+			reportBytes, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			err = os.WriteFile(outputFile, reportBytes, 0644)
+			if err != nil {
+				return fmt.Errorf("Error saving load test report: %s", err.Error())
+			}
+			cmd.Printf("\nSaved load test report to %s\n", outputFile)
+		}
+		return nil
+	},
+}
+
+func init() {
+	loadCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	loadCmd.Flags().String(scriptFlag, "", "Workload script (YAML) describing the requests to send")
+	loadCmd.Flags().Int(concurrencyFlag, 8, "Number of concurrent workers")
+	loadCmd.Flags().Duration(durationFlag, 30*time.Second, "How long to run the workload")
+	loadCmd.Flags().Int(requestsFlag, 0, "Stop after sending this many requests (0 for no limit)")
+	loadCmd.Flags().String(outputFileFlag, "", "Write the load test report as JSON to this file")
+	RootCmd.AddCommand(loadCmd)
+}
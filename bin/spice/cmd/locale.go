@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+// usageTemplate is cobra's default usage template with its headings looked up in the message
+// catalog.
+const usageTemplate = `{{T "help.usage"}}{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
+
+{{T "help.aliases"}}
+  {{.NameAndAliases}}{{end}}{{if .HasExample}}
+
+{{T "help.examples"}}
+{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
+
+{{T "help.available_commands"}}{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
+
+{{.Title}}{{range $cmds}}{{if (and (eq .GroupID $group.ID) (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if not .AllChildCommandsHaveGroup}}
+
+{{T "help.additional_commands"}}{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+
+{{T "help.flags"}}
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+{{T "help.global_flags"}}
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+
+{{T "help.additional_topics"}}{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+
+{{T "help.more_information" .CommandPath}}{{end}}
+`
+
+// localize selects the locale from the CLI config or environment and translates the help of
+// every command. Descriptions are translated by catalog keys of the form command.<path>,
+// e.g. command.accel enable.
+func localize(root *cobra.Command) {
+	locale := ""
+	if cliConfig, err := loadConfig(root); err == nil {
+		locale = cliConfig.Locale
+	}
+	i18n.SetLocale(i18n.DetectLocale(locale))
+
+	if flag := root.PersistentFlags().Lookup(progressFlag); flag != nil {
+		flag.Usage = i18n.T("help.progress_flag", strings.Join(progress.Modes, ", "))
+	}
+	if flag := root.PersistentFlags().Lookup(outputFlag); flag != nil {
+		flag.Usage = i18n.T("help.output_flag", strings.Join(util.OutputFormats, ", "))
+	}
+	if flag := root.PersistentFlags().Lookup(accessibleFlag); flag != nil {
+		flag.Usage = i18n.T("help.accessible_flag")
+	}
+	if flag := root.PersistentFlags().Lookup(retriesFlag); flag != nil {
+		flag.Usage = i18n.T("help.retries_flag")
+	}
+	if flag := root.PersistentFlags().Lookup(timeoutFlag); flag != nil {
+		flag.Usage = i18n.T("help.timeout_flag")
+	}
+	localizeCommand(root)
+}
+
+func localizeCommand(c *cobra.Command) {
+	if c.HasParent() {
+		path := strings.TrimPrefix(c.CommandPath(), c.Root().Name()+" ")
+		if short, ok := i18n.Lookup("command." + path); ok {
+			c.Short = short
+		}
+	}
+
+	c.InitDefaultHelpCmd()
+	c.InitDefaultHelpFlag()
+	if flag := c.Flags().Lookup("help"); flag != nil {
+		flag.Usage = i18n.T("help.flag")
+	}
+
+	for _, child := range c.Commands() {
+		localizeCommand(child)
+	}
+}
+
+func init() {
+	cobra.AddTemplateFunc("T", i18n.T)
+	RootCmd.SetUsageTemplate(usageTemplate)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandDescriptions(t *testing.T) {
+	i18n.SetLocale(i18n.DEFAULT_LOCALE)
+	defer i18n.SetLocale("")
+
+	for _, c := range RootCmd.Commands() {
+		short, ok := i18n.Lookup("command." + c.Name())
+		if assert.True(t, ok, "command.%s is not in the %s catalog", c.Name(), i18n.DEFAULT_LOCALE) {
+			assert.Equal(t, c.Short, short, "command.%s", c.Name())
+		}
+	}
+}
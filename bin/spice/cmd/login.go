@@ -18,12 +18,13 @@ package cmd
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
 	"time"
 
-	"github.com/logrusorgru/aurora"
 	toml "github.com/pelletier/go-toml"
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
@@ -57,14 +58,13 @@ spice login
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		authCode := generateAuthCode()
 
 		spiceApiClient := api.NewSpiceApiClient()
 		err := spiceApiClient.Init()
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 
 		spiceAuthUrl := spiceApiClient.GetAuthUrl(authCode)
@@ -83,15 +83,19 @@ spice login
 		for {
 			time.Sleep(time.Second)
 
-			authStatusResponse, err := spiceApiClient.ExchangeCode(authCode)
+			ctx, cancel := requestContext(cmd)
+			authStatusResponse, err := spiceApiClient.ExchangeCode(ctx, authCode)
+			cancel()
 			if err != nil {
+				if cmd.Context().Err() != nil {
+					return err
+				}
 				cmd.Println("Error:", err)
 				continue
 			}
 
 			if authStatusResponse.AccessDenied {
-				cmd.Println("Access denied")
-				os.Exit(1)
+				return errors.New("Access denied")
 			}
 
 			if authStatusResponse.AccessToken != "" {
@@ -115,21 +119,25 @@ spice login
 			}
 		}
 
-		spiceAuthContext, err := spiceApiClient.GetAuthContext(accessToken, &orgName, &appName)
+		ctx, cancel := requestContext(cmd)
+		defer cancel()
+		spiceAuthContext, err := spiceApiClient.GetAuthContext(ctx, accessToken, &orgName, &appName)
 		if err != nil {
-			cmd.Println("Error:", err)
-			os.Exit(1)
+			return err
 		}
 
-		mergeAuthConfig(cmd, api.AUTH_TYPE_SPICE_AI, &api.Auth{
+		if err := mergeAuthConfig(cmd, api.AUTH_TYPE_SPICE_AI, &api.Auth{
 			Params: map[string]string{
 				api.AUTH_PARAM_TOKEN: accessToken,
 				api.AUTH_PARAM_KEY:   spiceAuthContext.App.ApiKey,
 			},
-		})
+		}); err != nil {
+			return err
+		}
 
-		cmd.Println(aurora.BrightGreen(fmt.Sprintf("Successfully logged in to Spice.ai as %s (%s)", spiceAuthContext.Username, spiceAuthContext.Email)))
-		cmd.Println(aurora.BrightGreen(fmt.Sprintf("Using app %s/%s", spiceAuthContext.Org.Name, spiceAuthContext.App.Name)))
+		cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Successfully logged in to Spice.ai as %s (%s)", spiceAuthContext.Username, spiceAuthContext.Email)))
+		cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Using app %s/%s", spiceAuthContext.Org.Name, spiceAuthContext.App.Name)))
+		return nil
 	},
 }
 
@@ -141,7 +149,7 @@ spice login dremio --username <username> --password <password>
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: CreateLoginRunFunc(api.AUTH_TYPE_DREMIO, map[string]string{
+	RunE: CreateLoginRunFunc(api.AUTH_TYPE_DREMIO, map[string]string{
 		usernameFlag: fmt.Sprintf("No username provided, use --%s or -u to provide a username", usernameFlag),
 		passwordFlag: fmt.Sprintf("No password provided, use --%s or -p to provide a password", passwordFlag),
 	}, map[string]string{
@@ -158,7 +166,7 @@ spice login s3 --access-key <key> --access-secret <secret>
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: CreateLoginRunFunc(api.AUTH_TYPE_S3, map[string]string{
+	RunE: CreateLoginRunFunc(api.AUTH_TYPE_S3, map[string]string{
 		accessKeyFlag:    fmt.Sprintf("No access key provided, use --%s or -k to provide a key", accessKeyFlag),
 		accessSecretFlag: fmt.Sprintf("No access secret provided, use --%s or -s to provide a secret", accessSecretFlag),
 	}, map[string]string{
@@ -175,7 +183,7 @@ spice login postgres --password <password>
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: CreateLoginRunFunc(api.AUTH_TYPE_POSTGRES, map[string]string{
+	RunE: CreateLoginRunFunc(api.AUTH_TYPE_POSTGRES, map[string]string{
 		passwordFlag: fmt.Sprintf("No password provided, use --%s or -p to provide a password", passwordFlag),
 	}, map[string]string{
 		passwordFlag: api.AUTH_PARAM_PG_PASSWORD,
@@ -190,7 +198,7 @@ spice login postgres engine --password <password>
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: CreateLoginRunFunc(api.AUTH_TYPE_POSTGRES_ENGINE, map[string]string{
+	RunE: CreateLoginRunFunc(api.AUTH_TYPE_POSTGRES_ENGINE, map[string]string{
 		passwordFlag: fmt.Sprintf("No password provided, use --%s or -p to provide a password", passwordFlag),
 	}, map[string]string{
 		passwordFlag: api.AUTH_PARAM_PG_PASSWORD,
@@ -206,11 +214,11 @@ spice login snowflake --account <account-identifier> --username <username> --pri
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
 		if privateKeyPath, err := cmd.Flags().GetString(privateKeyPathFlag); err == nil && privateKeyPath != "" {
 
-			CreateLoginRunFunc(api.AUTH_TYPE_SNOWFLAKE, map[string]string{
+			return CreateLoginRunFunc(api.AUTH_TYPE_SNOWFLAKE, map[string]string{
 				accountFlag:        fmt.Sprintf("No account identifier provided, use --%s or -a to provide an account identifier", accountFlag),
 				usernameFlag:       fmt.Sprintf("No username provided, use --%s or -u to provide a username", usernameFlag),
 				privateKeyPathFlag: fmt.Sprintf("No private key path provided, use --%s or -k to provide a private key path", privateKeyPathFlag),
@@ -222,12 +230,10 @@ spice login snowflake --account <account-identifier> --username <username> --pri
 			}, []string{
 				passphraseFlag,
 			})(cmd, args)
-
-			return
 		}
 
 		// default username/password login
-		CreateLoginRunFunc(api.AUTH_TYPE_SNOWFLAKE, map[string]string{
+		return CreateLoginRunFunc(api.AUTH_TYPE_SNOWFLAKE, map[string]string{
 			accountFlag:  fmt.Sprintf("No account identifier provided, use --%s or -a to provide an account identifier", accountFlag),
 			usernameFlag: fmt.Sprintf("No username provided, use --%s or -u to provide a username", usernameFlag),
 			passwordFlag: fmt.Sprintf("No password provided, use --%s or -p to provide a password", passwordFlag),
@@ -236,7 +242,6 @@ spice login snowflake --account <account-identifier> --username <username> --pri
 			usernameFlag: api.AUTH_PARAM_USERNAME,
 			passwordFlag: api.AUTH_PARAM_PASSWORD,
 		}, []string{})(cmd, args)
-
 	},
 }
 
@@ -248,26 +253,24 @@ spice login spark --spark_remote <remote>
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: CreateLoginRunFunc(api.AUTH_TYPE_SPARK, map[string]string{
+	RunE: CreateLoginRunFunc(api.AUTH_TYPE_SPARK, map[string]string{
 		sparkRemoteFlag: "No spark_remote provided, use --spark_remote to provide",
 	}, map[string]string{
 		sparkRemoteFlag: api.AUTH_PARAM_SPARK_REMOTE,
 	}, []string{}),
 }
 
-func CreateLoginRunFunc(authName string, requiredFlags map[string]string, flagToTomlKeys map[string]string, optionalFlags []string) func(cmd *cobra.Command, args []string) {
-	return func(cmd *cobra.Command, args []string) {
+func CreateLoginRunFunc(authName string, requiredFlags map[string]string, flagToTomlKeys map[string]string, optionalFlags []string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
 
 		authParams := make(map[string]string)
 		for flag, errMsg := range requiredFlags {
 			value, err := cmd.Flags().GetString(flag)
 			if err != nil {
-				cmd.Println(err.Error())
-				os.Exit(1)
+				return err
 			}
 			if value == "" {
-				cmd.Println(errMsg)
-				os.Exit(1)
+				return errors.New(errMsg)
 			}
 			authParams[flag] = value
 		}
@@ -275,8 +278,7 @@ func CreateLoginRunFunc(authName string, requiredFlags map[string]string, flagTo
 		for _, flag := range optionalFlags {
 			value, err := cmd.Flags().GetString(flag)
 			if err != nil {
-				cmd.Println(err.Error())
-				os.Exit(1)
+				return err
 			}
 			if value != "" {
 				authParams[flag] = value
@@ -292,11 +294,14 @@ func CreateLoginRunFunc(authName string, requiredFlags map[string]string, flagTo
 				configParams[k] = v
 			}
 		}
-		mergeAuthConfig(cmd, authName, &api.Auth{
+		if err := mergeAuthConfig(cmd, authName, &api.Auth{
 			Params: configParams,
-		})
+		}); err != nil {
+			return err
+		}
 
-		cmd.Println(aurora.BrightGreen(fmt.Sprintf("Successfully logged in to %s", authName)))
+		cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Successfully logged in to %s", authName)))
+		return nil
 	}
 }
 
@@ -312,37 +317,32 @@ spice login databricks --token <access-token> --aws-region <aws-region> --aws-ac
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		token, err := cmd.Flags().GetString(token)
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 
 		if token == "" {
-			cmd.Println("No Databricks Access Token provided, use --token")
-			os.Exit(1)
+			return errors.New("No Databricks Access Token provided, use --token")
 		}
 
 		awsRegion, err := cmd.Flags().GetString(awsRegion)
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 
 		awsAccessKeyId, err := cmd.Flags().GetString(awsAccessKeyId)
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 
 		awsSecret, err := cmd.Flags().GetString(awsSecret)
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 
-		mergeAuthConfig(cmd, api.AUTH_TYPE_DATABRICKS, &api.Auth{
+		if err := mergeAuthConfig(cmd, api.AUTH_TYPE_DATABRICKS, &api.Auth{
 			Params: map[string]string{
 				api.AUTH_PARAM_TOKEN:                 token,
 				api.AUTH_PARAM_AWS_DEFAULT_REGION:    awsRegion,
@@ -350,54 +350,51 @@ spice login databricks --token <access-token> --aws-region <aws-region> --aws-ac
 				api.AUTH_PARAM_AWS_SECRET_ACCESS_KEY: awsSecret,
 			},
 		},
-		)
+		); err != nil {
+			return err
+		}
 
-		cmd.Println(aurora.BrightGreen("Successfully logged in to Databricks"))
+		cmd.Println(colors(cmd).BrightGreen("Successfully logged in to Databricks"))
+		return nil
 	},
 }
 
-func mergeAuthConfig(cmd *cobra.Command, updatedAuthName string, updatedAuthConfig *api.Auth) {
-	homeDir, err := os.UserHomeDir()
+func mergeAuthConfig(cmd *cobra.Command, updatedAuthName string, updatedAuthConfig *api.Auth) error {
+	spiceDir, err := dotSpiceDir(cmd)
 	if err != nil {
-		cmd.Println(err.Error())
-		os.Exit(1)
+		return err
 	}
-	spiceDir := fmt.Sprintf("%s/.spice", homeDir)
-	authFilePath := fmt.Sprintf("%s/auth", spiceDir)
+	authFilePath := filepath.Join(spiceDir, "auth")
 
 	err = os.MkdirAll(spiceDir, 0644)
 	if err != nil {
-		cmd.Println(err.Error())
-		os.Exit(1)
+		return err
 	}
 
 	authConfig := map[string]*api.Auth{}
 	if _, err := os.Stat(authFilePath); !os.IsNotExist(err) {
 		authConfigBytes, err := os.ReadFile(authFilePath)
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		err = toml.Unmarshal(authConfigBytes, &authConfig)
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 	}
 
 	authConfig[updatedAuthName] = updatedAuthConfig
 	updatedAuthConfigBytes, err := toml.Marshal(authConfig)
 	if err != nil {
-		cmd.Println(err.Error())
-		os.Exit(1)
+		return err
 	}
 
 	err = os.WriteFile(authFilePath, updatedAuthConfigBytes, 0644)
 	if err != nil {
-		cmd.Println(err.Error())
-		os.Exit(1)
+		return err
 	}
+	return nil
 }
 
 func init() {
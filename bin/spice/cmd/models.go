@@ -19,8 +19,6 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 	"github.com/spiceai/spiceai/bin/spice/pkg/api"
-	"github.com/spiceai/spiceai/bin/spice/pkg/context"
-	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
 var modelsCmd = &cobra.Command{
@@ -29,16 +27,16 @@ var modelsCmd = &cobra.Command{
 	Example: `
 spice models
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		rtcontext := context.NewContext()
-		model_statuses, _, err := api.GetComponentStatuses(PROM_ENDPOINT)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		model_statuses, _, err := api.GetComponentStatuses(rtcontext, metricsEndpoint(cmd))
 		if err != nil {
-			cmd.PrintErrln(err.Error())
+			return err
 		}
 
 		models, err := api.GetData[api.Model](rtcontext, "/v1/models?status=true")
 		if err != nil {
-			cmd.PrintErrln(err.Error())
+			return err
 		}
 
 		table := make([]interface{}, len(models))
@@ -49,7 +47,7 @@ spice models
 			}
 			table[i] = model
 		}
-		util.WriteTable(table)
+		return output(cmd).WriteTable(table)
 	},
 }
 
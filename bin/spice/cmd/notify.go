@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/notify"
+)
+
+const (
+	eventFlag = "event"
+	urlFlag   = "url"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Call webhooks when datasets refresh or fail to refresh, or the runtime started by spice run fails",
+	Example: `
+spice notify add --event refresh_failed --url https://hooks.slack.com/services/...
+spice notify add --event refresh_failed --dataset taxi_trips --url https://example.com/hooks/spice
+spice notify list
+spice notify test 1
+spice notify remove 1
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var notifyAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Subscribe a webhook to an event",
+	Example: `
+spice notify add --event refresh_failed --url https://hooks.slack.com/services/...
+spice notify add --event runtime_failed --url https://example.com/hooks/spice
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		event, _ := cmd.Flags().GetString(eventFlag)
+		webhookUrl, _ := cmd.Flags().GetString(urlFlag)
+		dataset, _ := cmd.Flags().GetString(datasetFlag)
+		if webhookUrl == "" {
+			return fmt.Errorf("--%s is required", urlFlag)
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		subscription, err := notify.Add(rtcontext.AppDir(), notify.Subscription{Event: event, Url: webhookUrl, Dataset: dataset})
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Added subscription %s, test it with: spice notify test %s\n", subscription.Id, subscription.Id)
+		return nil
+	},
+}
+
+var notifyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhook subscriptions of the app",
+	Example: `
+spice notify list
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		subscriptions, err := notify.Load(rtcontext.AppDir())
+		if err != nil {
+			return err
+		}
+
+		if len(subscriptions) == 0 {
+			cmd.Println("No webhook subscriptions, add one with: spice notify add --event <event> --url <url>")
+			return nil
+		}
+
+		table := make([]interface{}, len(subscriptions))
+		for i, subscription := range subscriptions {
+			table[i] = subscription
+		}
+		return output(cmd).WriteTable(table)
+	},
+}
+
+var notifyRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a webhook subscription",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice notify remove 1
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		err := notify.Remove(rtcontext.AppDir(), args[0])
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Removed subscription %s\n", args[0])
+		return nil
+	},
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test <id>",
+	Short: "Send a test notification to a subscribed webhook",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice notify test 1
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		subscriptions, err := notify.Load(rtcontext.AppDir())
+		if err != nil {
+			return err
+		}
+
+		index := slices.IndexFunc(subscriptions, func(s notify.Subscription) bool { return s.Id == args[0] })
+		if index < 0 {
+			return fmt.Errorf("No subscription with id %s, see: spice notify list", args[0])
+		}
+		subscription := subscriptions[index]
+
+		ctx, cancel := rtcontext.RequestContext()
+		defer cancel()
+		err = notify.Send(ctx, subscription.Url, notify.Notification{
+			Event:   notify.EVENT_TEST,
+			Time:    time.Now().UTC(),
+			Dataset: subscription.Dataset,
+			Text:    fmt.Sprintf("Test notification for the %s subscription %s", subscription.Event, subscription.Id),
+		})
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Sent a test notification to subscription %s\n", subscription.Id)
+		return nil
+	},
+}
+
+func init() {
+	notifyAddCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	notifyAddCmd.Flags().String(eventFlag, notify.EVENT_REFRESH_FAILED, fmt.Sprintf("Event to notify about, one of: %s", strings.Join(notify.Events, ", ")))
+	notifyAddCmd.Flags().String(urlFlag, "", "Webhook URL to post notifications to")
+	notifyAddCmd.Flags().String(datasetFlag, "", "Only notify about refreshes of this dataset")
+	_ = notifyAddCmd.RegisterFlagCompletionFunc(eventFlag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return notify.Events, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = notifyAddCmd.RegisterFlagCompletionFunc(datasetFlag, completeDatasetFlag)
+	notifyCmd.AddCommand(notifyAddCmd)
+
+	notifyListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	notifyCmd.AddCommand(notifyListCmd)
+
+	notifyRemoveCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	notifyCmd.AddCommand(notifyRemoveCmd)
+
+	notifyTestCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	notifyCmd.AddCommand(notifyTestCmd)
+
+	notifyCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(notifyCmd)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/export"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const yesFlag = "yes"
+
+var nsqlCmd = &cobra.Command{
+	Use:   "nsql <question>",
+	Short: "Ask the Spice runtime a question in natural language, confirm the generated SQL and show the results",
+	Args:  cobra.MinimumNArgs(1),
+	Example: `
+spice nsql "how many trips had more than 4 passengers?"
+spice nsql --model my_nql "top 5 customers by revenue"
+spice nsql --yes "average fare by month" -o csv
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		model, _ := cmd.Flags().GetString(modelFlag)
+		question := strings.Join(args, " ")
+
+		confirm := func(string) bool { return true }
+		if yes, _ := cmd.Flags().GetBool(yesFlag); !yes {
+			reader := bufio.NewReader(cmd.InOrStdin())
+			confirm = func(string) bool {
+				return promptYesNo(cmd, reader, "Run this query?", true)
+			}
+		}
+		return runNsql(cmd, rtcontext, model, question, confirm)
+	},
+}
+
+// runNsql has the runtime generate the SQL answering the question, prints it, and runs it and
+// prints the results once confirm accepts it.
+func runNsql(cmd *cobra.Command, rtcontext *context.RuntimeContext, model string, question string, confirm func(sql string) bool) error {
+	sql, err := api.NsqlGenerateSql(rtcontext, api.NsqlRequest{Query: question, Model: model})
+	if err != nil {
+		return err
+	}
+	// Other formats keep stdout for the results, e.g. to pipe them into jq
+	messages := cmd.OutOrStdout()
+	if output(cmd).Format() != util.OUTPUT_TABLE {
+		messages = cmd.ErrOrStderr()
+	}
+	fmt.Fprintln(messages, colors(cmd).BrightBlue(sql))
+	if !confirm(sql) {
+		fmt.Fprintln(messages, "The query was not run.")
+		return nil
+	}
+
+	rows, err := api.Sql[json.RawMessage](rtcontext, sql)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 && output(cmd).Format() == util.OUTPUT_TABLE {
+		cmd.Println("No results")
+		return nil
+	}
+
+	return export.WriteTable(output(cmd), rows)
+}
+
+func init() {
+	nsqlCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	nsqlCmd.Flags().String(modelFlag, api.DEFAULT_NSQL_MODEL, "Model to generate the SQL with")
+	nsqlCmd.Flags().BoolP(yesFlag, "y", false, "Run the generated SQL without asking for confirmation")
+	_ = nsqlCmd.RegisterFlagCompletionFunc(modelFlag, completeModelNames)
+	RootCmd.AddCommand(nsqlCmd)
+}
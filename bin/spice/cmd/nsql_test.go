@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNsqlConfirmsGeneratedSql(t *testing.T) {
+	mock := testutils.NewMockRuntime(t)
+	var request api.NsqlRequest
+	mock.Handle("POST", "/v1/nsql", func(r testutils.MockRequest) (int, interface{}) {
+		_ = json.Unmarshal(r.Body, &request)
+		return http.StatusOK, map[string]string{"sql": "SELECT count(*) AS trips FROM taxi_trips"}
+	})
+	var query string
+	mock.Handle("POST", "/v1/sql", func(r testutils.MockRequest) (int, interface{}) {
+		query = string(r.Body)
+		return http.StatusOK, []map[string]int{{"trips": 42}}
+	})
+	ctx := WithDependencies(gocontext.Background(), Dependencies{
+		NewRuntimeContext: func() *context.RuntimeContext { return mock.Context() },
+		DotSpiceDir:       mock.DotSpiceDir,
+	})
+	t.Cleanup(func() { RootCmd.SetIn(nil) })
+
+	RootCmd.SetIn(strings.NewReader("n\n"))
+	output := testutils.RunCommandContext(t, ctx, RootCmd, "nsql", "how many trips?")
+	assert.NoError(t, output.Err)
+	assert.True(t, request.SqlOnly)
+	assert.Equal(t, "how many trips?", request.Query)
+	assert.Contains(t, output.Stdout, "SELECT count(*) AS trips FROM taxi_trips")
+	assert.Contains(t, output.Stdout, "The query was not run.")
+	assert.Equal(t, 0, mock.Requests("POST", "/v1/sql"))
+
+	RootCmd.SetIn(strings.NewReader("y\n"))
+	output = testutils.RunCommandContext(t, ctx, RootCmd, "nsql", "how many trips?")
+	assert.NoError(t, output.Err)
+	assert.Equal(t, "SELECT count(*) AS trips FROM taxi_trips", query)
+	assert.Contains(t, output.Stdout, "42")
+
+	RootCmd.SetIn(strings.NewReader(""))
+	output = testutils.RunCommandContext(t, ctx, RootCmd, "nsql", "--yes", "how many trips?")
+	assert.NoError(t, output.Err)
+	assert.NotContains(t, output.Stdout, "Run this query?")
+	assert.Equal(t, 2, mock.Requests("POST", "/v1/sql"))
+}
+
+func TestNsqlRequiresSqlOnlySupport(t *testing.T) {
+	mock := testutils.NewMockRuntime(t)
+	mock.Handle("POST", "/v1/nsql", func(testutils.MockRequest) (int, interface{}) {
+		return http.StatusOK, []map[string]int{{"trips": 42}}
+	})
+	ctx := WithDependencies(gocontext.Background(), Dependencies{
+		NewRuntimeContext: func() *context.RuntimeContext { return mock.Context() },
+		DotSpiceDir:       mock.DotSpiceDir,
+	})
+
+	output := testutils.RunCommandContext(t, ctx, RootCmd, "nsql", "--yes", "how many trips?")
+	assert.ErrorContains(t, output.Err, "does not return the generated SQL for confirmation")
+	assert.Equal(t, 0, mock.Requests("POST", "/v1/sql"))
+}
+
+func TestNsqlWritesEmptyResults(t *testing.T) {
+	mock := testutils.NewMockRuntime(t)
+	mock.Handle("POST", "/v1/nsql", func(testutils.MockRequest) (int, interface{}) {
+		return http.StatusOK, map[string]string{"sql": "SELECT * FROM taxi_trips WHERE fare < 0"}
+	})
+	mock.Handle("POST", "/v1/sql", func(testutils.MockRequest) (int, interface{}) {
+		return http.StatusOK, []map[string]int{}
+	})
+	ctx := WithDependencies(gocontext.Background(), Dependencies{
+		NewRuntimeContext: func() *context.RuntimeContext { return mock.Context() },
+		DotSpiceDir:       mock.DotSpiceDir,
+	})
+
+	output := testutils.RunCommandContext(t, ctx, RootCmd, "nsql", "--yes", "negative fares?")
+	assert.NoError(t, output.Err)
+	assert.Contains(t, output.Stdout, "No results")
+
+	for format, expected := range map[string]string{"json": "[]\n", "yaml": "[]\n", "csv": ""} {
+		output = testutils.RunCommandContext(t, ctx, RootCmd, "nsql", "--yes", "-o", format, "negative fares?")
+		assert.NoError(t, output.Err, format)
+		assert.Equal(t, expected, output.Stdout, format)
+		assert.Contains(t, output.Stderr, "SELECT * FROM taxi_trips WHERE fare < 0", format)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const outputFlag = "output"
+
+// applyOutputFormat checks the format listing commands print in.
+func applyOutputFormat(cmd *cobra.Command) error {
+	format, _ := cmd.Flags().GetString(outputFlag)
+	_, err := util.NewOutput(cmd.OutOrStdout(), format)
+	return err
+}
+
+// output is where the command prints listings, in the format of --output.
+func output(cmd *cobra.Command) *util.Output {
+	format, _ := cmd.Flags().GetString(outputFlag)
+	out, err := util.NewOutput(cmd.OutOrStdout(), format)
+	if err != nil {
+		// Rejected by applyOutputFormat before the command runs
+		out, _ = util.NewOutput(cmd.OutOrStdout(), util.OUTPUT_TABLE)
+	}
+	return out
+}
+
+func init() {
+	RootCmd.PersistentFlags().StringP(outputFlag, "o", util.OUTPUT_TABLE, i18n.T("help.output_flag", strings.Join(util.OutputFormats, ", ")))
+}
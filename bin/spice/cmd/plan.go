@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/k8s"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	datasetsPerNodeFlag = "datasets-per-node"
+	readReplicasFlag    = "read-replicas"
+)
+
+type clusterNode struct {
+	Node     string `json:"node" csv:"node" yaml:"node"`
+	Datasets int    `json:"datasets" csv:"datasets" yaml:"datasets"`
+	Replicas int    `json:"replicas" csv:"replicas" yaml:"replicas"`
+	Dir      string `json:"dir" csv:"dir" yaml:"dir"`
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Plan deployments of the Spice runtime",
+	Example: `
+spice plan cluster --datasets-per-node 50 --read-replicas 2
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var planClusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Partition a large Spicepod across runtime nodes and generate their deployments",
+	Example: `
+spice plan cluster --datasets-per-node 50 --read-replicas 2
+spice plan cluster --datasets-per-node 20 --dir deploy/cluster --force
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spicepodDir, _ := cmd.Flags().GetString(spicepodFlag)
+		outputDir, _ := cmd.Flags().GetString(dirFlag)
+		datasetsPerNode, _ := cmd.Flags().GetInt(datasetsPerNodeFlag)
+		readReplicas, _ := cmd.Flags().GetInt(readReplicasFlag)
+		force, _ := cmd.Flags().GetBool(forceFlag)
+
+		if readReplicas < 1 {
+			return fmt.Errorf("--%s must be at least 1", readReplicasFlag)
+		}
+
+		manifest, err := spicepod.Flatten(spicepodDir)
+		if err != nil {
+			return fmt.Errorf("Error reading spicepod: %s", err.Error())
+		}
+
+		partitions, err := spicepod.PartitionDatasets(manifest, datasetsPerNode)
+		if err != nil {
+			return err
+		}
+		if len(partitions) == 0 {
+			return errors.New("The Spicepod has no datasets to partition")
+		}
+
+		if _, err := os.Stat(outputDir); err == nil && !force {
+			return fmt.Errorf("%s already exists, use --%s to overwrite it", outputDir, forceFlag)
+		}
+
+		var nodes []interface{}
+		for _, partition := range partitions {
+			nodeDir := filepath.Join(outputDir, partition.Name)
+			err = writeNodeDeployment(nodeDir, partition, readReplicas)
+			if err != nil {
+				return fmt.Errorf("Error writing %s: %s", nodeDir, err.Error())
+			}
+			if partition.Oversized {
+				cmd.Println(colors(cmd).Yellow(fmt.Sprintf("%s has %d related datasets, more than %d per node", partition.Name, len(partition.Datasets), datasetsPerNode)))
+			}
+			nodes = append(nodes, clusterNode{Node: partition.Name, Datasets: len(partition.Datasets), Replicas: readReplicas, Dir: nodeDir})
+		}
+
+		if err := output(cmd).WriteTable(nodes); err != nil {
+			return err
+		}
+		cmd.Println("Deploy each node with its own release, e.g.:")
+		cmd.Printf("  spice k8s install --spicepod %s --release %s --replicas %d\n", filepath.Join(outputDir, partitions[0].Name), partitions[0].Name, readReplicas)
+		cmd.Println("or with helm, using the generated values.yaml:")
+		cmd.Printf("  helm upgrade --install %s %s --repo %s --values %s\n", partitions[0].Name, k8s.HELM_CHART, k8s.HELM_REPO, filepath.Join(outputDir, partitions[0].Name, "values.yaml"))
+		return nil
+	},
+}
+
+// writeNodeDeployment writes a node's Spicepod and the Helm values to deploy it.
+func writeNodeDeployment(nodeDir string, partition spicepod.Partition, replicas int) error {
+	err := os.MkdirAll(nodeDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	spicepodBytes, err := yaml.Marshal(partition.Spicepod)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(nodeDir, "spicepod.yaml"), spicepodBytes, 0644)
+	if err != nil {
+		return err
+	}
+
+	values, err := k8s.BuildValues(nodeDir, k8s.InstallOptions{
+		Replicas: replicas,
+		ImageTag: k8s.ImageTag(version.Version()),
+	})
+	if err != nil {
+		return err
+	}
+	valuesBytes, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(nodeDir, "values.yaml"), valuesBytes, 0644)
+}
+
+func init() {
+	planClusterCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	planClusterCmd.Flags().String(spicepodFlag, ".", "Directory of the Spicepod to partition")
+	planClusterCmd.Flags().String(dirFlag, "cluster", "Directory to write the node Spicepods and Helm values to")
+	planClusterCmd.Flags().Int(datasetsPerNodeFlag, 50, "Maximum number of datasets per node")
+	planClusterCmd.Flags().Int(readReplicasFlag, 1, "Number of runtime replicas serving each node's datasets")
+	planClusterCmd.Flags().Bool(forceFlag, false, "Overwrite an existing output directory")
+	planCmd.AddCommand(planClusterCmd)
+
+	planCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(planCmd)
+}
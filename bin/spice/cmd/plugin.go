@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/plugin"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+)
+
+type pluginRow struct {
+	Command string
+	Path    string
+	Status  string
+}
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage CLI plugins, spice-<name> executables on PATH run as spice <name>",
+	Example: `
+spice plugin list
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the plugins found on PATH",
+	Example: `
+spice plugin list
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugins := plugin.List()
+		if len(plugins) == 0 {
+			cmd.Printf("No plugins found. Add an executable named %s<name> to PATH to run it as spice <name>.\n", plugin.PLUGIN_PREFIX)
+			return nil
+		}
+
+		table := make([]interface{}, len(plugins))
+		for i, p := range plugins {
+			row := pluginRow{Command: "spice " + p.Name, Path: p.Path, Status: "ok"}
+			if isBuiltinCommand(strings.Fields(p.Name)) {
+				row.Status = "shadowed by a built-in command"
+			}
+			table[i] = row
+		}
+		return output(cmd).WriteTable(table)
+	},
+}
+
+func isBuiltinCommand(args []string) bool {
+	found, _, err := RootCmd.Find(args)
+	return err == nil && found != RootCmd
+}
+
+// runPlugin runs the plugin named by args, if there is one and no built-in command of the same
+// name, and exits with its exit code.
+func runPlugin(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(args) {
+		return
+	}
+	p, pluginArgs := plugin.Find(args)
+	if p == nil {
+		return
+	}
+
+	rtcontext := dependencies(RootCmd).NewRuntimeContext()
+	env := map[string]string{
+		plugin.ENV_CLI_VERSION:     version.Version(),
+		plugin.ENV_APP_DIR:         rtcontext.AppDir(),
+		plugin.ENV_HTTP_ENDPOINT:   rtcontext.HttpEndpoint(),
+		plugin.ENV_FLIGHT_ENDPOINT: rtcontext.FlightEndpoint(),
+		plugin.ENV_TLS_CERT:        rtcontext.TlsCert(),
+		plugin.ENV_CONFIG_PATH:     config.ConfigPath(rtcontext.SpiceRuntimeDir()),
+	}
+	// The API key of a profile is only for its runtime, the Spice.ai key only for the local one
+	if rtcontext.Profile() != context.LOCAL_PROFILE {
+		env[plugin.ENV_API_KEY] = rtcontext.ApiKey()
+	} else if authConfig, err := api.LoadAuthConfig(rtcontext.SpiceRuntimeDir()); err == nil {
+		if spiceAuth, ok := authConfig[api.AUTH_TYPE_SPICE_AI]; ok && spiceAuth.Params != nil {
+			env[plugin.ENV_API_KEY] = spiceAuth.Params[api.AUTH_PARAM_KEY]
+		}
+	}
+
+	err := util.RunCommand(p.Command(pluginArgs, env))
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		RootCmd.PrintErrln(err.Error())
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func init() {
+	pluginListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	pluginCmd.AddCommand(pluginListCmd)
+
+	pluginCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(pluginCmd)
+}
@@ -19,8 +19,6 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 	"github.com/spiceai/spiceai/bin/spice/pkg/api"
-	"github.com/spiceai/spiceai/bin/spice/pkg/context"
-	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
 var podsCmd = &cobra.Command{
@@ -29,12 +27,12 @@ var podsCmd = &cobra.Command{
 	Example: `
 spice pods
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		rtcontext := context.NewContext()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
 
 		spicepods, err := api.GetData[api.Spicepod](rtcontext, "/v1/spicepods")
 		if err != nil {
-			cmd.PrintErrln(err.Error())
+			return err
 		}
 		table := make([]interface{}, len(spicepods))
 		for i, spicepod := range spicepods {
@@ -47,7 +45,7 @@ spice pods
 			}
 			table[i] = spicepodStatus
 		}
-		util.WriteTable(table)
+		return output(cmd).WriteTable(table)
 	},
 }
 
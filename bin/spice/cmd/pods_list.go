@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/registry"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+const podSourceApp = "app"
+
+type podSummary struct {
+	Name     string
+	Version  string
+	Source   string
+	Datasets int
+	Models   int
+	Path     string
+}
+
+type localPod struct {
+	dir        string
+	spec       *spec.SpicepodSpec
+	dependency string
+	version    string
+}
+
+var podsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the Spicepod in the current directory and the dependency Spicepods it has downloaded",
+	Example: `
+spice pods list
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pods, err := findLocalPods(cmd)
+		if err != nil {
+			return err
+		}
+
+		table := make([]interface{}, 0, len(pods))
+		for _, pod := range pods {
+			summary := podSummary{
+				Name:    pod.spec.Name,
+				Version: pod.version,
+				Source:  pod.dependency,
+				Path:    pod.dir,
+			}
+			components, err := spicepod.ListComponents(pod.dir)
+			if err != nil {
+				cmd.PrintErrln(err.Error())
+			}
+			for _, component := range components {
+				switch component.Kind {
+				case spicepod.COMPONENT_DATASET, spicepod.COMPONENT_VIEW:
+					summary.Datasets++
+				default:
+					summary.Models++
+				}
+			}
+			table = append(table, summary)
+		}
+		return output(cmd).WriteTable(table)
+	},
+}
+
+var podsShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show the datasets, views and models a Spicepod contributes",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice pods show quickstart
+spice pods show spiceai/quickstart
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pods, err := findLocalPods(cmd)
+		if err != nil {
+			return err
+		}
+
+		var pod *localPod
+		for i := range pods {
+			p := &pods[i]
+			if p.spec.Name == args[0] || p.dependency == args[0] || filepath.ToSlash(p.dir) == filepath.ToSlash(filepath.Clean(args[0])) ||
+				strings.HasSuffix(filepath.ToSlash(p.dir), "/"+args[0]) {
+				pod = p
+				break
+			}
+		}
+		if pod == nil {
+			return fmt.Errorf("No Spicepod named '%s' found, run spice pods list to see the available Spicepods", args[0])
+		}
+
+		components, err := spicepod.ListComponents(pod.dir)
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("%s (%s)\n", strings.TrimSpace(pod.spec.Name+" "+pod.version), pod.dir)
+		if pod.dependency != podSourceApp {
+			cmd.Printf("Dependency: %s\n", pod.dependency)
+		}
+		if len(pod.spec.Dependencies) > 0 {
+			cmd.Printf("Depends on: %s\n", strings.Join(pod.spec.Dependencies, ", "))
+		}
+
+		if len(components) == 0 {
+			cmd.Println("\nThe Spicepod does not define any datasets or models")
+			return nil
+		}
+		table := make([]interface{}, len(components))
+		for i, component := range components {
+			table[i] = component
+		}
+		return output(cmd).WriteTable(table)
+	},
+}
+
+// findLocalPods returns the Spicepod in the current directory, if any, followed by the Spicepods
+// downloaded to the spicepods directory, with the dependency each was downloaded for.
+func findLocalPods(cmd *cobra.Command) ([]localPod, error) {
+	var pods []localPod
+
+	app, err := spicepod.LoadManifest(".")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	dependencyDirs := map[string]string{}
+	if app != nil {
+		rtcontext := newRuntimeContext(cmd)
+		pods = append(pods, localPod{dir: ".", spec: app, dependency: podSourceApp, version: app.Metadata["version"]})
+		for _, dependency := range app.Dependencies {
+			if dir := registry.DependencyDir(rtcontext, dependency); dir != "" {
+				dependencyDirs[filepath.FromSlash(dir)] = dependency
+			}
+		}
+	}
+
+	lock, err := spicepod.LoadLockFile(".")
+	if err != nil {
+		return nil, err
+	}
+
+	podsDir := newRuntimeContext(cmd).PodsDir()
+	dirs, err := spicepod.FindPods(podsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		pod, err := spicepod.LoadManifest(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		relativeDir, _ := filepath.Rel(podsDir, dir)
+		local := localPod{
+			dir:        filepath.Join(filepath.Base(podsDir), relativeDir),
+			spec:       pod,
+			dependency: dependencyDirs[relativeDir],
+			version:    pod.Metadata["version"],
+		}
+		if locked := lock.Get(local.dependency); locked != nil && locked.Version != "" {
+			local.version = locked.Version
+		}
+		pods = append(pods, local)
+	}
+
+	return pods, nil
+}
+
+func init() {
+	podsListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	podsCmd.AddCommand(podsListCmd)
+
+	podsShowCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	podsCmd.AddCommand(podsShowCmd)
+}
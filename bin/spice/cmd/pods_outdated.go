@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/registry"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+type outdatedDependency struct {
+	Dependency string `json:"dependency" csv:"dependency" yaml:"dependency"`
+	Current    string `json:"current" csv:"current" yaml:"current"`
+	Wanted     string `json:"wanted" csv:"wanted" yaml:"wanted"`
+	Latest     string `json:"latest" csv:"latest" yaml:"latest"`
+	Changelog  string `json:"changelog" csv:"changelog" yaml:"changelog"`
+}
+
+var podsOutdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List Spicepod dependencies with newer published versions",
+	Example: `
+spice pods outdated
+
+# Exits with status 1 when a dependency is outdated
+spice pods outdated || spice pods update
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pod, err := spicepod.LoadManifest(".")
+		if err != nil {
+			return err
+		}
+
+		lock, err := spicepod.LoadLockFile(".")
+		if err != nil {
+			return err
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		var table []interface{}
+		failed := false
+		for _, dependency := range pod.Dependencies {
+			wanted, latest, err := registry.Available(rtcontext, dependency)
+			if errors.Is(err, registry.ErrUnversioned) {
+				continue
+			}
+			if err != nil {
+				cmd.PrintErrf("Error checking %s: %s\n", dependency, err.Error())
+				failed = true
+				continue
+			}
+
+			outdated := outdatedDependency{Dependency: dependency, Current: "missing", Wanted: wanted, Latest: latest}
+			if locked := lock.Get(dependency); locked != nil && locked.Version != "" {
+				outdated.Current = locked.Version
+			}
+			if outdated.Current == wanted && outdated.Current == latest {
+				continue
+			}
+
+			path, _ := registry.SplitDependency(rtcontext, dependency)
+			if linker, ok := registry.GetRegistry(rtcontext, path).(registry.ChangelogLinker); ok && latest != "" {
+				// Best effort, a missing changelog doesn't hide the update
+				outdated.Changelog, _ = linker.ChangelogUrl(path, latest)
+			}
+			table = append(table, outdated)
+		}
+
+		if len(table) == 0 {
+			if !failed {
+				cmd.Println("All dependencies are up to date")
+			}
+		} else {
+			if err := output(cmd).WriteTable(table); err != nil {
+				return err
+			}
+			cmd.Println("Update to the wanted versions with: spice pods update")
+			cmd.Println("Versions newer than wanted need a wider version constraint in spicepod.yaml")
+		}
+
+		if failed || len(table) > 0 {
+			return errReported
+		}
+		return nil
+	},
+}
+
+func init() {
+	podsOutdatedCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	podsCmd.AddCommand(podsOutdatedCmd)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/oci"
+	"github.com/spiceai/spiceai/bin/spice/pkg/registry"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/spiceai/spiceai/bin/spice/pkg/tempdir"
+)
+
+const (
+	versionFlag = "version"
+	pathFlag    = "path"
+)
+
+var podsPublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Validate, package and publish the Spicepod in the current directory to spicerack.org or an OCI registry",
+	Example: `
+spice pods publish --version v1.0.0 --dry-run
+spice pods publish --version v1.0.0
+spice pods publish --version v1.0.0 --path myorg/taxi --output-file taxi-v1.0.0.tar.gz
+spice pods publish --version v1.0.0 --to oci://ghcr.io/myorg/taxi
+spice pods publish --version v1.0.0 --path internal:myorg/taxi
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		podVersion, _ := cmd.Flags().GetString(versionFlag)
+		podPath, _ := cmd.Flags().GetString(pathFlag)
+		outputFile, _ := cmd.Flags().GetString(outputFileFlag)
+		dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+		to, _ := cmd.Flags().GetString(toFlag)
+
+		var ociRef *oci.Reference
+		if to != "" {
+			var err error
+			if ociRef, err = oci.ParseReference(to); err != nil || !oci.IsReference(to) {
+				return fmt.Errorf("--%s must be an OCI reference like oci://ghcr.io/myorg/pod", toFlag)
+			}
+		}
+
+		pod, err := spicepod.LoadManifest(".")
+		if err != nil {
+			return err
+		}
+
+		if podVersion == "" && pod.Metadata != nil {
+			podVersion = pod.Metadata["version"]
+		}
+		if podVersion != "" && !strings.HasPrefix(podVersion, "v") {
+			podVersion = "v" + podVersion
+		}
+		if podPath == "" && pod.Metadata != nil && pod.Metadata["org"] != "" {
+			podPath = fmt.Sprintf("%s/%s", pod.Metadata["org"], pod.Name)
+		}
+
+		if errs := spicepod.Validate(".", pod, podVersion); len(errs) > 0 {
+			for _, err := range errs {
+				cmd.PrintErrln(colors(cmd).BrightRed(err.Error()))
+			}
+			return fmt.Errorf("Set the version with --%s or metadata.version in spicepod.yaml", versionFlag)
+		}
+		apiKey := ""
+		var rack *registry.SpiceRackRegistry
+		if !dryRun && ociRef == nil {
+			if podPath == "" {
+				return fmt.Errorf("Set the registry path with --%s <org>/<name> or metadata.org in spicepod.yaml", pathFlag)
+			}
+			var ok bool
+			if rack, ok = registry.GetRegistry(newRuntimeContext(cmd), podPath).(*registry.SpiceRackRegistry); !ok {
+				return fmt.Errorf("--%s must be a registry path like <org>/<name> or <registry>:<org>/<name>", pathFlag)
+			}
+		}
+		if rack != nil && rack.Name == "" {
+			if authConfig, err := loadAuthConfig(cmd); err == nil {
+				if spiceAuth, ok := authConfig[api.AUTH_TYPE_SPICE_AI]; ok && spiceAuth.Params != nil {
+					apiKey = spiceAuth.Params[api.AUTH_PARAM_KEY]
+				}
+			}
+			if apiKey == "" {
+				return errors.New("Not logged in to Spice.ai, run spice login first")
+			}
+		}
+
+		if outputFile == "" {
+			dir, err := tempdir.CreateTempDir("publish")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+			outputFile = filepath.Join(dir, fmt.Sprintf("%s-%s.tar.gz", pod.Name, podVersion))
+		}
+
+		manifest, err := spicepod.Package(".", pod.Name, podVersion, outputFile)
+		if err != nil {
+			return fmt.Errorf("Error packaging Spicepod: %s", err.Error())
+		}
+		checksum, err := spicepod.FileSha256(outputFile)
+		if err != nil {
+			return err
+		}
+
+		table := make([]interface{}, len(manifest.Files))
+		for i, file := range manifest.Files {
+			file.Sha256 = file.Sha256[:12]
+			table[i] = file
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+		cmd.Printf("Packaged %s %s: %d files, sha256 %s\n", pod.Name, podVersion, len(manifest.Files), checksum)
+
+		if dryRun {
+			cmd.Println("Dry run, nothing was published")
+			return nil
+		}
+
+		if ociRef != nil {
+			if ociRef.Tag == "" {
+				ociRef.Tag = podVersion
+			}
+			content, err := os.ReadFile(outputFile)
+			if err != nil {
+				return err
+			}
+			annotations := map[string]string{
+				oci.ANNOTATION_TITLE:   pod.Name,
+				oci.ANNOTATION_VERSION: podVersion,
+			}
+			if pod.Metadata != nil && pod.Metadata["repository"] != "" {
+				annotations[oci.ANNOTATION_SOURCE] = pod.Metadata["repository"]
+			}
+			ctx, cancel := newRuntimeContext(cmd).RequestContext()
+			defer cancel()
+			digest, err := oci.NewClient(ociRef.Registry).WithContext(ctx).Push(ociRef, content, annotations)
+			if err != nil {
+				return err
+			}
+			cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Published %s (%s)", ociRef, digest)))
+			return nil
+		}
+
+		if err = rack.Publish(podPath, podVersion, outputFile, apiKey); err != nil {
+			return err
+		}
+		cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Published %s@%s", podPath, podVersion)))
+		return nil
+	},
+}
+
+func init() {
+	podsPublishCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	podsPublishCmd.Flags().String(versionFlag, "", "Version to publish, e.g. v1.2.0 (default: metadata.version in spicepod.yaml)")
+	podsPublishCmd.Flags().String(pathFlag, "", "Registry path to publish to, prefixed with <registry>: for a private registry (default: <metadata.org>/<name>)")
+	podsPublishCmd.Flags().String(outputFileFlag, "", "Also keep the package at this path")
+	podsPublishCmd.Flags().String(toFlag, "", "Publish to an OCI registry instead of spicerack.org, e.g. oci://ghcr.io/myorg/pod (tag defaults to the version)")
+	podsPublishCmd.Flags().Bool(dryRunFlag, false, "Validate and package the Spicepod without publishing it")
+	podsCmd.AddCommand(podsPublishCmd)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/registry"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/spiceai/spiceai/bin/spice/pkg/tempdir"
+)
+
+const setFlag = "set"
+
+var podsTemplateCmd = &cobra.Command{
+	Use:   "template <spicepod>",
+	Short: "Create a ready-to-run Spicepod from a template, filling in its {{ placeholders }}",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice pods template spiceai/s3-lakehouse
+spice pods template spiceai/s3-lakehouse@^1.0 --set bucket=s3://my-bucket/data --dir lakehouse
+spice pods template ./templates/postgres-replica --set org=acme
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString(dirFlag)
+		force, _ := cmd.Flags().GetBool(forceFlag)
+		sets, _ := cmd.Flags().GetStringArray(setFlag)
+
+		values := map[string]string{}
+		for _, set := range sets {
+			name, value, ok := strings.Cut(set, "=")
+			if !ok {
+				return fmt.Errorf("Invalid --%s '%s', expected <placeholder>=<value>", setFlag, set)
+			}
+			values[name] = value
+		}
+
+		output, err := filepath.Abs(output)
+		if err != nil {
+			return err
+		}
+		if _, err = os.Stat(filepath.Join(output, "spicepod.yaml")); err == nil && !force {
+			return fmt.Errorf("%s already exists, use --%s to overwrite it", filepath.Join(output, "spicepod.yaml"), forceFlag)
+		}
+
+		if err = renderTemplate(cmd, args[0], output, values); err != nil {
+			return err
+		}
+		cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Created Spicepod in %s from %s", output, args[0])))
+		return nil
+	},
+}
+
+func renderTemplate(cmd *cobra.Command, source string, output string, values map[string]string) error {
+	defer func() {
+		_ = tempdir.RemoveAllCreatedTempDirectories()
+	}()
+
+	templateDir, err := fetchTemplate(cmd, source)
+	if err != nil {
+		return err
+	}
+
+	placeholders, err := spicepod.FindPlaceholders(templateDir)
+	if err != nil {
+		return err
+	}
+	if err = promptPlaceholders(cmd, placeholders, values); err != nil {
+		return err
+	}
+
+	written, err := spicepod.RenderTemplate(templateDir, output, values)
+	if err != nil {
+		return fmt.Errorf("error rendering template: %w", err)
+	}
+
+	for _, file := range written {
+		cmd.Printf("  %s\n", file)
+	}
+	return nil
+}
+
+// fetchTemplate returns the directory of a local template, or downloads a registry template to a
+// temporary directory so it isn't added to the app's spicepods directory.
+func fetchTemplate(cmd *cobra.Command, source string) (string, error) {
+	if _, err := os.Stat(filepath.Join(source, "spicepod.yaml")); err == nil {
+		return source, nil
+	}
+
+	rtcontext := newRuntimeContext(cmd)
+	resolution, err := registry.Resolve(rtcontext, source, "")
+	if err != nil {
+		return "", err
+	}
+	if resolution.Path != source {
+		cmd.Printf("Resolved %s to %s\n", source, resolution.Version)
+	}
+
+	dir, err := tempdir.CreateTempDir("template")
+	if err != nil {
+		return "", err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if err = os.Chdir(dir); err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	cmd.Printf("Getting Spicepod %s ...\n", resolution.Path)
+	templateDir, err := registry.GetRegistry(rtcontext, resolution.Path).GetPod(resolution.Path)
+	if err != nil {
+		var itemNotFound *registry.RegistryItemNotFound
+		if errors.As(err, &itemNotFound) {
+			return "", fmt.Errorf("no Spicepod found at '%s'. Find Spicepods with: spice registry search <term>", source)
+		}
+		return "", err
+	}
+	return templateDir, nil
+}
+
+// promptPlaceholders asks for each placeholder not already set with --set. Without input, the
+// placeholder's default is used.
+func promptPlaceholders(cmd *cobra.Command, placeholders []spicepod.Placeholder, values map[string]string) error {
+	reader := bufio.NewReader(os.Stdin)
+	prompted := false
+	for _, placeholder := range placeholders {
+		if _, ok := values[placeholder.Name]; ok {
+			continue
+		}
+
+		if !prompted {
+			cmd.Println("The Spicepod template needs the following values, press enter to keep the default.")
+			prompted = true
+		}
+		if placeholder.Default != "" {
+			cmd.Printf("%s (%s): ", placeholder.Name, placeholder.Default)
+		} else {
+			cmd.Printf("%s: ", placeholder.Name)
+		}
+
+		value, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			value = placeholder.Default
+		}
+		if value == "" {
+			if errors.Is(err, io.EOF) {
+				cmd.Println()
+			}
+			return fmt.Errorf("no value for '%s', set it with --%s %s=<value>", placeholder.Name, setFlag, placeholder.Name)
+		}
+		values[placeholder.Name] = value
+	}
+	return nil
+}
+
+func init() {
+	podsTemplateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	podsTemplateCmd.Flags().StringArray(setFlag, nil, "Value for a placeholder, as <placeholder>=<value>; can be repeated")
+	podsTemplateCmd.Flags().String(dirFlag, ".", "Directory to write the Spicepod to")
+	podsTemplateCmd.Flags().Bool(forceFlag, false, "Overwrite an existing spicepod.yaml")
+	podsCmd.AddCommand(podsTemplateCmd)
+}
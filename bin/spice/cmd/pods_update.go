@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+type dependencyUpdate struct {
+	Dependency string
+	Previous   string
+	Current    string
+}
+
+var podsUpdateCmd = &cobra.Command{
+	Use:   "update [dependency...]",
+	Short: "Update Spicepod dependencies to the newest versions allowed by their constraints",
+	Example: `
+spice pods update
+spice pods update spiceai/quickstart@^1.2
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pod, err := spicepod.LoadManifest(".")
+		if err != nil {
+			return err
+		}
+
+		dependencies := pod.Dependencies
+		if len(args) > 0 {
+			for _, arg := range args {
+				if !contains(pod.Dependencies, arg) {
+					return fmt.Errorf("'%s' is not a dependency in spicepod.yaml", arg)
+				}
+			}
+			dependencies = args
+		}
+		if len(dependencies) == 0 {
+			cmd.Println("No dependencies to update")
+			return nil
+		}
+
+		lock, err := spicepod.LoadLockFile(".")
+		if err != nil {
+			return err
+		}
+
+		var table []interface{}
+		failed := false
+		for _, dependency := range dependencies {
+			update := dependencyUpdate{Dependency: dependency}
+			if locked := lock.Get(dependency); locked != nil {
+				update.Previous = locked.Version
+			}
+
+			if _, err := installDependency(cmd, dependency, lock, true); err != nil {
+				cmd.PrintErrf("Error updating %s: %s\n", dependency, err.Error())
+				failed = true
+				continue
+			}
+			if locked := lock.Get(dependency); locked != nil {
+				update.Current = locked.Version
+			}
+			table = append(table, update)
+		}
+
+		if err = saveLockFile(lock); err != nil {
+			return fmt.Errorf("Error writing %s: %s", spicepod.LockFileName, err.Error())
+		}
+
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+		if failed {
+			return errReported
+		}
+		return nil
+	},
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	podsUpdateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	podsCmd.AddCommand(podsUpdateCmd)
+}
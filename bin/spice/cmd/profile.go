@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+const (
+	endpointFlag       = "endpoint"
+	flightEndpointFlag = "flight-endpoint"
+	tlsCertFlag        = "tls-cert"
+	profileApiKeyFlag  = "api-key"
+)
+
+type profileSummary struct {
+	Current  string
+	Name     string
+	Endpoint string
+	TlsCert  string
+	ApiKey   string
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Switch between named connections to Spice runtimes, e.g. local, staging and production",
+	Example: `
+spice profile set staging --endpoint https://spice.staging.example.com --tls-cert ca.pem
+spice profile set prod --endpoint https://data.spiceai.io --api-key <key>
+spice profile use staging
+spice profile list
+spice profile use local
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List connection profiles, marking the one in use",
+	Example: `
+spice profile list
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spiceDir, err := dotSpiceDir(cmd)
+		if err != nil {
+			return err
+		}
+		profiles, current, err := context.Profiles(spiceDir)
+		if err != nil {
+			return err
+		}
+
+		table := []interface{}{}
+		for _, profile := range append([]config.ProfileConfig{context.LocalProfile()}, profiles...) {
+			summary := profileSummary{Name: profile.Name, Endpoint: profile.Endpoint, TlsCert: profile.TlsCert, ApiKey: "-"}
+			if profile.Name == current {
+				summary.Current = "*"
+			}
+			if profile.ApiKey != "" {
+				summary.ApiKey = "set"
+			}
+			table = append(table, summary)
+		}
+		return output(cmd).WriteTable(table)
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:               "use <name>",
+	Short:             "Connect commands to the runtime of a profile, or to the local runtime with: spice profile use local",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileNames,
+	Example: `
+spice profile use staging
+spice profile use local
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spiceDir, err := dotSpiceDir(cmd)
+		if err != nil {
+			return err
+		}
+		err = context.UseProfile(spiceDir, args[0])
+		if err != nil {
+			return err
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		if err = rtcontext.SwitchProfile(args[0]); err != nil {
+			return err
+		}
+		cmd.Printf("Using profile %s, connected to %s\n", args[0], rtcontext.HttpEndpoint())
+		return nil
+	},
+}
+
+var profileSetCmd = &cobra.Command{
+	Use:               "set <name>",
+	Short:             "Add a connection profile or change the settings given for an existing one",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileNames,
+	Example: `
+spice profile set staging --endpoint https://spice.staging.example.com
+spice profile set staging --tls-cert ./staging-ca.pem
+spice profile set prod --endpoint https://data.spiceai.io --api-key <key>
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		endpoint, _ := cmd.Flags().GetString(endpointFlag)
+		flightEndpoint, _ := cmd.Flags().GetString(flightEndpointFlag)
+		tlsCert, _ := cmd.Flags().GetString(tlsCertFlag)
+		apiKey, _ := cmd.Flags().GetString(profileApiKeyFlag)
+
+		if tlsCert != "" {
+			absTlsCert, err := filepath.Abs(tlsCert)
+			if err != nil {
+				return err
+			}
+			tlsCert = absTlsCert
+		}
+
+		spiceDir, err := dotSpiceDir(cmd)
+		if err != nil {
+			return err
+		}
+		profile, err := context.SetProfile(spiceDir, config.ProfileConfig{
+			Name:           args[0],
+			Endpoint:       strings.TrimSuffix(endpoint, "/"),
+			FlightEndpoint: flightEndpoint,
+			TlsCert:        tlsCert,
+			ApiKey:         apiKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Profile %s saved with endpoint %s. Connect to it with: spice profile use %s\n", profile.Name, profile.Endpoint, profile.Name)
+		return nil
+	},
+}
+
+func init() {
+	profileListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	profileCmd.AddCommand(profileListCmd)
+
+	profileUseCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	profileCmd.AddCommand(profileUseCmd)
+
+	profileSetCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	profileSetCmd.Flags().String(endpointFlag, "", "HTTP endpoint of the runtime, e.g. https://spice.example.com")
+	profileSetCmd.Flags().String(flightEndpointFlag, "", "Arrow Flight endpoint of the runtime passed to plugins, e.g. grpc+tls://spice.example.com:443")
+	profileSetCmd.Flags().String(tlsCertFlag, "", "PEM certificate to trust for the endpoint, e.g. of a private CA")
+	profileSetCmd.Flags().String(profileApiKeyFlag, "", "API key sent with each request to the runtime")
+	profileCmd.AddCommand(profileSetCmd)
+
+	profileCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(profileCmd)
+}
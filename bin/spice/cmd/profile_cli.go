@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/timing"
+)
+
+const (
+	profileCliFlag = "profile-cli"
+	// The value of a bare --profile-cli, which only prints the timing report.
+	profileCliReportOnly = "-"
+)
+
+// startCliProfile looks for --profile-cli before flags are parsed, so the time spent before a
+// command runs, e.g. loading the CLI config, is measured too. The returned function prints the
+// report to stderr and finishes the CPU profile, if one was requested.
+func startCliProfile(args []string) func() {
+	profilePath := ""
+	found := false
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--"+profileCliFlag {
+			found = true
+			profilePath = profileCliReportOnly
+		} else if path, ok := strings.CutPrefix(arg, "--"+profileCliFlag+"="); ok {
+			found = true
+			profilePath = path
+		}
+	}
+	if !found {
+		return func() {}
+	}
+
+	timing.Enable()
+	timing.InstrumentHTTP()
+
+	var profileFile *os.File
+	if profilePath != profileCliReportOnly && profilePath != "" {
+		var err error
+		profileFile, err = os.Create(profilePath)
+		if err == nil {
+			err = pprof.StartCPUProfile(profileFile)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to start CPU profile: %s\n", err.Error())
+			profileFile = nil
+		}
+	}
+
+	return func() {
+		fmt.Fprintln(os.Stderr)
+		timing.Report(os.Stderr)
+		if profileFile != nil {
+			pprof.StopCPUProfile()
+			profileFile.Close()
+			fmt.Fprintf(os.Stderr, "Wrote CPU profile to %s, inspect it with: go tool pprof %s\n", profilePath, profilePath)
+		}
+	}
+}
+
+func init() {
+	RootCmd.PersistentFlags().String(profileCliFlag, "", "Print where CLI time went after the command completes, and write a CPU profile to the given file")
+	RootCmd.PersistentFlags().Lookup(profileCliFlag).NoOptDefVal = profileCliReportOnly
+	_ = RootCmd.PersistentFlags().MarkHidden(profileCliFlag)
+}
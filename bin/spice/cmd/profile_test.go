@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+)
+
+func TestProfileList(t *testing.T) {
+	ctx := WithDependencies(gocontext.Background(), Dependencies{DotSpiceDir: testutils.EnsureTestSpiceDirectory(t)})
+
+	output := testutils.RunCommandContext(t, ctx, RootCmd, "profile", "list")
+	testutils.AssertGolden(t, output.Stdout)
+
+	testutils.RunCommandContext(t, ctx, RootCmd, "profile", "set", "staging", "--endpoint", "https://spice.staging.example.com/")
+	testutils.RunCommandContext(t, ctx, RootCmd, "profile", "set", "prod", "--endpoint", "https://data.spiceai.io", "--api-key", "secret")
+	testutils.RunCommandContext(t, ctx, RootCmd, "profile", "use", "staging")
+
+	output = testutils.RunCommandContext(t, ctx, RootCmd, "profile", "list")
+	t.Run("configured", func(t *testing.T) {
+		testutils.AssertGolden(t, output.Stdout)
+	})
+}
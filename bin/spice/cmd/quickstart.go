@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/quickstart"
+	"github.com/spiceai/spiceai/bin/spice/pkg/runtime"
+)
+
+const dirFlag = "dir"
+
+var quickstartCmd = &cobra.Command{
+	Use:   "quickstart",
+	Short: "List and run the Spice.ai quickstarts",
+	Example: `
+spice quickstart list
+spice quickstart run federated-postgres
+
+# See more at: https://github.com/spiceai/quickstarts
+`,
+}
+
+var quickstartListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the published quickstarts",
+	Example: `
+spice quickstart list
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		quickstarts, err := quickstart.List()
+		if err != nil {
+			return err
+		}
+
+		table := make([]interface{}, len(quickstarts))
+		for i, q := range quickstarts {
+			table[i] = q
+		}
+		return output(cmd).WriteTable(table)
+	},
+}
+
+var quickstartRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Download a quickstart, prompt for its credentials and start the runtime with it",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice quickstart run federated-postgres
+spice quickstart run rag-decision-records --dir ./rag-demo
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		dir, _ := cmd.Flags().GetString(dirFlag)
+		if dir == "" {
+			dir = name
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "spicepod.yaml")); err == nil {
+			cmd.Printf("Using quickstart %s in %s\n", name, dir)
+		} else {
+			cmd.Printf("Downloading quickstart %s ...\n", name)
+			if err = quickstart.Download(name, dir); err != nil {
+				return err
+			}
+			cmd.Println(colors(cmd).BrightGreen(fmt.Sprintf("Quickstart %s downloaded to %s", name, dir)))
+		}
+
+		if err := promptQuickstartEnv(cmd, dir); err != nil {
+			return err
+		}
+
+		if readme := filepath.Join(dir, "README.md"); fileExists(readme) {
+			cmd.Printf("See %s for a walkthrough of the quickstart.\n\n", readme)
+		}
+
+		if err := os.Chdir(dir); err != nil {
+			return err
+		}
+
+		// Created after changing directory, so the quickstart is the runtime context's app.
+		if err := runtime.Run(newRuntimeContext(cmd)); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+// promptQuickstartEnv asks for each variable the quickstart needs that is not already set in its
+// .env file or the environment, saves the answers to .env, and exports them to the runtime.
+func promptQuickstartEnv(cmd *cobra.Command, dir string) error {
+	required, err := quickstart.RequiredEnv(dir)
+	if err != nil {
+		return err
+	}
+	saved, err := quickstart.LoadEnv(dir)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	prompted := false
+	for i, v := range required {
+		if value, ok := saved[v.Name]; ok {
+			required[i].Default = value
+			continue
+		}
+		if value, ok := os.LookupEnv(v.Name); ok {
+			required[i].Default = value
+			continue
+		}
+
+		if !prompted {
+			cmd.Println("The quickstart needs the following settings, press enter to keep the example value.")
+			prompted = true
+		}
+		cmd.Printf("%s (%s): ", v.Name, v.Default)
+		value, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			required[i].Default = value
+		}
+	}
+
+	if prompted {
+		if err = quickstart.SaveEnv(dir, required); err != nil {
+			return err
+		}
+		cmd.Printf("Saved settings to %s\n", filepath.Join(dir, ".env"))
+	}
+
+	for _, v := range required {
+		if err = os.Setenv(v.Name, v.Default); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && !stat.IsDir()
+}
+
+func init() {
+	quickstartListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	quickstartCmd.AddCommand(quickstartListCmd)
+
+	quickstartRunCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	quickstartRunCmd.Flags().String(dirFlag, "", "Directory to download the quickstart to (default: the quickstart name)")
+	quickstartCmd.AddCommand(quickstartRunCmd)
+
+	quickstartCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(quickstartCmd)
+}
@@ -18,10 +18,11 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
 	"github.com/spiceai/spiceai/bin/spice/pkg/api"
-	"github.com/spiceai/spiceai/bin/spice/pkg/context"
 )
 
 type DatasetRefreshApiResponse struct {
@@ -29,33 +30,56 @@ type DatasetRefreshApiResponse struct {
 }
 
 var refreshCmd = &cobra.Command{
-	Use:   "refresh",
-	Short: "Refresh a dataset",
-	Args:  cobra.MinimumNArgs(1),
+	Use:               "refresh",
+	Short:             "Refresh a dataset",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeDatasetNames,
 	Example: `
 spice refresh taxi_trips
+spice refresh taxi_trips --wait
+spice refresh taxi_trips --wait --progress json
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		dataset := args[0]
 
 		cmd.Printf("Refreshing dataset %s ...\n", dataset)
 
-		rtcontext := context.NewContext()
+		rtcontext := newRuntimeContext(cmd)
+		wait, _ := cmd.Flags().GetBool(waitFlag)
+
+		// Refreshes are recorded in the runtime log, count them before triggering another.
+		var previous []accel.RefreshEvent
+		if wait {
+			var err error
+			previous, err = refreshEvents(rtcontext, dataset)
+			if err != nil {
+				return err
+			}
+		}
 
 		url := fmt.Sprintf("/v1/datasets/%s/acceleration/refresh", dataset)
 		res, err := api.PostRuntime[DatasetRefreshApiResponse](rtcontext, url)
 		if err != nil {
-			cmd.PrintErrln(err.Error())
-			return
+			return err
 		}
 
 		cmd.Println(res.Message)
+
+		if wait {
+			timeout, _ := cmd.Flags().GetDuration(readyTimeoutFlag)
+			if err = waitForRefresh(cmd, rtcontext, dataset, len(previous), timeout); err != nil {
+				return err
+			}
+		}
+		return nil
 	},
 }
 
 func init() {
 	refreshCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	refreshCmd.Flags().Bool(waitFlag, false, "Wait for the refresh to finish and report its outcome, from the runtime log written under spice run")
+	refreshCmd.Flags().Duration(readyTimeoutFlag, 5*time.Minute, "How long to wait for the refresh to finish")
 	RootCmd.AddCommand(refreshCmd)
 }
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+var refreshExplainCmd = &cobra.Command{
+	Use:               "explain <dataset>",
+	Short:             "Show the query the next refresh of a dataset sends to its source",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice refresh explain taxi_trips
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), args[0])
+		if err != nil {
+			return err
+		}
+		if definition.Get("acceleration.enabled") != true {
+			return fmt.Errorf("Dataset %s is not accelerated, only accelerated datasets are refreshed.", definition.Name)
+		}
+		if err := rtcontext.IsRuntimeHealthy(2 * time.Second); err != nil {
+			return errors.New("The runtime must be running to read the time column and high-water mark. Start it with spice run.")
+		}
+
+		settings := accel.RefreshSettings{Dataset: definition.Name}
+		for path, value := range map[string]*string{
+			"acceleration.refresh_mode":        &settings.Mode,
+			"acceleration.refresh_data_window": &settings.DataWindow,
+			"acceleration.refresh_sql":         &settings.Sql,
+			"time_column":                      &settings.TimeColumn,
+			"time_format":                      &settings.TimeFormat,
+		} {
+			if v := definition.Get(path); v != nil {
+				*value = fmt.Sprintf("%v", v)
+			}
+		}
+
+		var highWaterMark *time.Time
+		var notes []string
+		if settings.TimeColumn != "" {
+			columns, err := api.GetDatasetColumns(rtcontext, definition.Name)
+			if err != nil {
+				return err
+			}
+			columnIndex := slices.IndexFunc(columns, func(c api.Column) bool { return c.Name == settings.TimeColumn })
+			if columnIndex < 0 {
+				return fmt.Errorf("Dataset %s has no column %s", definition.Name, settings.TimeColumn)
+			}
+			settings.TimeColumnType = columns[columnIndex].DataType
+
+			query := fmt.Sprintf(`SELECT MAX("%s") AS high_water_mark FROM "%s"`, settings.TimeColumn, definition.Name)
+			rows, err := api.Sql[map[string]interface{}](rtcontext, query)
+			if err != nil {
+				return err
+			}
+			if len(rows) > 0 && rows[0]["high_water_mark"] != nil {
+				value := rows[0]["high_water_mark"]
+				t, err := accel.ParseTimeValue(value, settings.TimeFormat)
+				if err != nil {
+					return err
+				}
+				highWaterMark = &t
+				if note := accel.TimeFormatWarning(settings.TimeColumn, value, settings.TimeFormat); note != "" {
+					notes = append(notes, note)
+				}
+			}
+		}
+
+		plan, err := accel.ExplainRefresh(settings, highWaterMark, time.Now())
+		if err != nil {
+			return err
+		}
+
+		formatTime := func(t *time.Time) string {
+			if t == nil {
+				return "-"
+			}
+			return t.UTC().Format(time.RFC3339)
+		}
+		orDash := func(s string) string {
+			if s == "" {
+				return "-"
+			}
+			return s
+		}
+		timeColumn := orDash(settings.TimeColumn)
+		if settings.TimeColumnType != "" {
+			timeColumn = fmt.Sprintf("%s (%s)", settings.TimeColumn, settings.TimeColumnType)
+		}
+		if err := output(cmd).WriteTable([]interface{}{
+			settingRow{Setting: "strategy", Value: plan.Strategy},
+			settingRow{Setting: "time_column", Value: timeColumn},
+			settingRow{Setting: "time_format", Value: orDash(settings.TimeFormat)},
+			settingRow{Setting: "refresh_data_window", Value: orDash(settings.DataWindow)},
+			settingRow{Setting: "high_water_mark", Value: formatTime(plan.HighWaterMark)},
+			settingRow{Setting: "window_start", Value: formatTime(plan.WindowStart)},
+			settingRow{Setting: "filter", Value: orDash(plan.Filter)},
+			settingRow{Setting: "pushdown", Value: orDash(plan.Pushdown)},
+		}); err != nil {
+			return err
+		}
+
+		if plan.Sql != "" {
+			cmd.Printf("Refresh query:\n  %s\n", plan.Sql)
+		}
+		notes = append(notes, plan.Notes...)
+		if len(notes) > 0 {
+			cmd.Println("Notes:")
+			for _, note := range notes {
+				cmd.Printf("  - %s\n", note)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	refreshExplainCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	refreshCmd.AddCommand(refreshExplainCmd)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/loggers"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+)
+
+const refreshHistoryPollInterval = time.Second
+
+type refreshHistoryRow struct {
+	Time     string
+	Status   string
+	Rows     string
+	Size     string
+	Duration string
+	Error    string
+}
+
+var refreshHistoryCmd = &cobra.Command{
+	Use:               "history <dataset>",
+	Short:             "Show recent loads and refreshes of a dataset from the runtime log",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice refresh history taxi_trips
+spice refresh history taxi_trips --last 50
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		events, err := readRefreshHistory(rtcontext, args[0])
+		if err != nil {
+			return err
+		}
+
+		last, _ := cmd.Flags().GetInt(lastFlag)
+		if last > 0 && len(events) > last {
+			events = events[len(events)-last:]
+		}
+
+		var rows []interface{}
+		for _, event := range events {
+			row := refreshHistoryRow{Time: "-", Status: event.Status, Rows: "-", Size: "-", Duration: "-", Error: "-"}
+			if !event.Time.IsZero() {
+				row.Time = event.Time.Local().Format(time.DateTime)
+			}
+			if event.Status == accel.REFRESH_STATUS_SUCCEEDED {
+				row.Rows = strconv.FormatInt(event.Rows, 10)
+				row.Duration = event.Duration
+				if event.Size != "" {
+					row.Size = event.Size
+				}
+			} else {
+				row.Error = event.Error
+			}
+			rows = append(rows, row)
+		}
+		return output(cmd).WriteTable(rows)
+	},
+}
+
+var refreshRetryCmd = &cobra.Command{
+	Use:               "retry <dataset>",
+	Short:             "Refresh a dataset again if its last refresh failed",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice refresh retry taxi_trips
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dataset := args[0]
+		rtcontext := newRuntimeContext(cmd)
+		events, err := readRefreshHistory(rtcontext, dataset)
+		if err != nil {
+			return err
+		}
+
+		lastEvent := events[len(events)-1]
+		if lastEvent.Status != accel.REFRESH_STATUS_FAILED {
+			cmd.Printf("The last refresh of %s succeeded, there is nothing to retry.\n", dataset)
+			return nil
+		}
+
+		cmd.Printf("Retrying the refresh of %s that failed with: %s\n", dataset, lastEvent.Error)
+		res, err := api.PostRuntime[DatasetRefreshApiResponse](rtcontext, fmt.Sprintf("/v1/datasets/%s/acceleration/refresh", dataset))
+		if err != nil {
+			return err
+		}
+		cmd.Println(res.Message)
+
+		if wait, _ := cmd.Flags().GetBool(waitFlag); !wait {
+			return nil
+		}
+
+		timeout, _ := cmd.Flags().GetDuration(readyTimeoutFlag)
+		if err = waitForRefresh(cmd, rtcontext, dataset, len(events), timeout); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+// waitForRefresh polls the runtime log until a refresh after the first previous ones is
+// recorded, returning an error if it failed or did not finish within timeout.
+func waitForRefresh(cmd *cobra.Command, rtcontext *context.RuntimeContext, dataset string, previous int, timeout time.Duration) error {
+	spinner := progress.NewSpinner(progressOutput(cmd), fmt.Sprintf("Waiting for the refresh of %s", dataset))
+	spinner.Start()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(refreshHistoryPollInterval)
+
+		latest, err := refreshEvents(rtcontext, dataset)
+		if err != nil {
+			spinner.Stop("failed")
+			return err
+		}
+		if len(latest) <= previous {
+			continue
+		}
+		event := latest[len(latest)-1]
+		if event.Status == accel.REFRESH_STATUS_FAILED {
+			spinner.Stop("failed")
+			return fmt.Errorf("the refresh of %s failed: %s", dataset, event.Error)
+		}
+		spinner.Stop("done")
+		cmd.Printf("Refreshed %s: loaded %d rows in %s.\n", dataset, event.Rows, event.Duration)
+		return nil
+	}
+
+	spinner.Stop("timed out")
+	return fmt.Errorf("timed out waiting for the refresh of %s to finish. Check its progress with spice refresh history %s", dataset, dataset)
+}
+
+func refreshEvents(rtcontext *context.RuntimeContext, dataset string) ([]accel.RefreshEvent, error) {
+	files, err := loggers.RuntimeLogFiles(rtcontext.AppDir())
+	if err != nil {
+		return nil, err
+	}
+	return accel.ReadRefreshHistory(files, dataset)
+}
+
+// readRefreshHistory fails when the dataset has no refreshes in the runtime log, which is only
+// written while the runtime runs under spice run.
+func readRefreshHistory(rtcontext *context.RuntimeContext, dataset string) ([]accel.RefreshEvent, error) {
+	events, err := refreshEvents(rtcontext, dataset)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("No refreshes of %s found in %s. The runtime log is recorded when the runtime is started with spice run.", dataset, rtcontext.GetSpiceAppRelativePath(loggers.RuntimeLogPath(rtcontext.AppDir())))
+	}
+	return events, nil
+}
+
+func init() {
+	refreshHistoryCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	refreshHistoryCmd.Flags().Int(lastFlag, 20, "Only show the most recent N refreshes (0 for all)")
+	refreshCmd.AddCommand(refreshHistoryCmd)
+
+	refreshRetryCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	refreshRetryCmd.Flags().Bool(waitFlag, true, "Wait for the refresh to finish and report its outcome")
+	refreshRetryCmd.Flags().Duration(readyTimeoutFlag, 5*time.Minute, "How long to wait for the refresh to finish")
+	refreshCmd.AddCommand(refreshRetryCmd)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+const (
+	intervalFlag = "interval"
+	cronFlag     = "cron"
+)
+
+type refreshScheduleRow struct {
+	Dataset     string
+	Accelerated bool
+	RefreshMode string
+	Interval    string
+	DataWindow  string
+}
+
+var refreshScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "View and change dataset refresh schedules",
+	Example: `
+spice refresh schedule list
+spice refresh schedule set taxi_trips --interval 10m
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var refreshScheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the refresh schedule of each dataset",
+	Example: `
+spice refresh schedule list
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		definitions, err := spicepod.ListDatasetDefinitions(rtcontext.AppDir())
+		if err != nil {
+			return err
+		}
+
+		var rows []interface{}
+		for _, definition := range definitions {
+			row := refreshScheduleRow{
+				Dataset:     definition.Name,
+				Accelerated: definition.Get("acceleration.enabled") == true,
+				RefreshMode: "full",
+				Interval:    "-",
+				DataWindow:  "-",
+			}
+			if mode, ok := definition.Get("acceleration.refresh_mode").(string); ok {
+				row.RefreshMode = mode
+			}
+			if interval := definition.Get("acceleration.refresh_check_interval"); interval != nil {
+				row.Interval = fmt.Sprintf("%v", interval)
+			}
+			if window := definition.Get("acceleration.refresh_data_window"); window != nil {
+				row.DataWindow = fmt.Sprintf("%v", window)
+			}
+			rows = append(rows, row)
+		}
+		return output(cmd).WriteTable(rows)
+	},
+}
+
+var refreshScheduleSetCmd = &cobra.Command{
+	Use:               "set <dataset>",
+	Short:             "Set how often an accelerated dataset is refreshed",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice refresh schedule set taxi_trips --interval 10m
+spice refresh schedule set taxi_trips --cron '0 * * * *'
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		interval, _ := cmd.Flags().GetString(intervalFlag)
+		cron, _ := cmd.Flags().GetString(cronFlag)
+		switch {
+		case interval != "" && cron != "":
+			return fmt.Errorf("Use either --%s or --%s", intervalFlag, cronFlag)
+		case cron != "":
+			var err error
+			interval, err = accel.CronInterval(cron)
+			if err != nil {
+				return fmt.Errorf("%w\nThe runtime refreshes at a fixed interval from when the dataset loads. Use --%s instead.", err, intervalFlag)
+			}
+		case interval == "":
+			return fmt.Errorf("Specify the schedule with --%s or --%s", intervalFlag, cronFlag)
+		default:
+			if _, err := accel.ParseInterval(interval); err != nil {
+				return err
+			}
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), args[0])
+		if err != nil {
+			return err
+		}
+		if definition.Get("acceleration.enabled") != true {
+			return fmt.Errorf("Dataset %s is not accelerated, only accelerated datasets are refreshed. Enable acceleration with spice accel enable %s", definition.Name, definition.Name)
+		}
+
+		before := "-"
+		if value := definition.Get("acceleration.refresh_check_interval"); value != nil {
+			before = fmt.Sprintf("%v", value)
+		}
+		definition.Set("acceleration.refresh_check_interval", interval)
+		err = definition.Save()
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("Updated %s\n", rtcontext.GetSpiceAppRelativePath(definition.FilePath))
+		if err := output(cmd).WriteTable([]interface{}{accelChange{Setting: "acceleration.refresh_check_interval", Before: before, After: interval}}); err != nil {
+			return err
+		}
+		cmd.Println("The new schedule applies when the runtime loads spicepod.yaml.")
+		return nil
+	},
+}
+
+func init() {
+	refreshScheduleListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	refreshScheduleCmd.AddCommand(refreshScheduleListCmd)
+
+	refreshScheduleSetCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	refreshScheduleSetCmd.Flags().String(intervalFlag, "", "Refresh interval, e.g. 10m")
+	refreshScheduleSetCmd.Flags().String(cronFlag, "", "Cron expression that fires at a fixed interval, e.g. '*/15 * * * *'")
+	refreshScheduleCmd.AddCommand(refreshScheduleSetCmd)
+
+	refreshScheduleCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	refreshCmd.AddCommand(refreshScheduleCmd)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/registry"
+)
+
+const (
+	maxDescriptionLength = 60
+	registryFlag         = "registry"
+)
+
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Browse Spicepods published to spicerack.org and configure private registries",
+	Example: `
+spice registry search taxi
+spice registry show spiceai/quickstart
+spice registry add internal https://spicepods.example.com/v0.1 --token-env INTERNAL_REGISTRY_TOKEN
+spice registry list
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var registrySearchCmd = &cobra.Command{
+	Use:   "search <term>",
+	Short: "Search spicerack.org or a private registry for Spicepods",
+	Args:  cobra.MinimumNArgs(1),
+	Example: `
+spice registry search taxi
+spice registry search "decision records"
+spice registry search taxi --registry internal
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		term := strings.Join(args, " ")
+		registryName, _ := cmd.Flags().GetString(registryFlag)
+
+		r, err := registry.NewSpiceRackRegistry(newRuntimeContext(cmd), registryName)
+		if err != nil {
+			return err
+		}
+		results, err := r.Search(term)
+		if err != nil {
+			return err
+		}
+
+		if len(results) == 0 {
+			cmd.Printf("No Spicepods found matching '%s'.\n", term)
+			return nil
+		}
+
+		table := make([]interface{}, len(results))
+		for i, result := range results {
+			if len(result.Description) > maxDescriptionLength {
+				result.Description = result.Description[:maxDescriptionLength-3] + "..."
+			}
+			table[i] = result
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+		if r.Name != "" {
+			cmd.Printf("Add a Spicepod to the current app with: spice add %s:<path>\n", r.Name)
+		} else {
+			cmd.Println("Add a Spicepod to the current app with: spice add <path>")
+		}
+		return nil
+	},
+}
+
+var registryShowCmd = &cobra.Command{
+	Use:   "show <path>",
+	Short: "Show the description and published versions of a Spicepod on spicerack.org or a private registry",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice registry show spiceai/quickstart
+spice registry show internal:data-platform/orders
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, ok := registry.GetRegistry(newRuntimeContext(cmd), args[0]).(*registry.SpiceRackRegistry)
+		if !ok {
+			return fmt.Errorf("'%s' is not a registry path, e.g. spiceai/quickstart", args[0])
+		}
+		details, err := r.GetPodDetails(args[0])
+		if err != nil {
+			var itemNotFound *registry.RegistryItemNotFound
+			if errors.As(err, &itemNotFound) {
+				return fmt.Errorf("No Spicepod found at '%s', try: spice registry search <term>", args[0])
+			}
+			return err
+		}
+
+		cmd.Printf("%s %s\n", details.Path, details.Version)
+		if details.Description != "" {
+			cmd.Printf("%s\n", details.Description)
+		}
+		cmd.Println()
+		if details.Author != "" {
+			cmd.Printf("Author:   %s\n", details.Author)
+		}
+		cmd.Printf("Installs: %d\n", details.Installs)
+		if len(details.Versions) > 0 {
+			cmd.Printf("Versions: %s\n", strings.Join(details.Versions, ", "))
+		}
+		if len(details.Datasets) > 0 {
+			cmd.Printf("Datasets: %s\n", strings.Join(details.Datasets, ", "))
+		}
+		if len(details.Models) > 0 {
+			cmd.Printf("Models:   %s\n", strings.Join(details.Models, ", "))
+		}
+		addPath := details.Path
+		if r.Name != "" && strings.HasPrefix(args[0], r.Name+":") {
+			addPath = fmt.Sprintf("%s:%s", r.Name, details.Path)
+		}
+		cmd.Printf("\nAdd it with: spice add %s\n", addPath)
+		return nil
+	},
+}
+
+func init() {
+	registrySearchCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	registrySearchCmd.Flags().String(registryFlag, "", "Name of a private registry to search instead of spicerack.org")
+	registryCmd.AddCommand(registrySearchCmd)
+
+	registryShowCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	registryCmd.AddCommand(registryShowCmd)
+
+	registryCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(registryCmd)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+)
+
+const (
+	tokenEnvFlag = "token-env"
+	defaultFlag  = "default"
+)
+
+type registrySummary struct {
+	Name     string `json:"name" csv:"name" yaml:"name"`
+	Endpoint string `json:"endpoint" csv:"endpoint" yaml:"endpoint"`
+	Token    string `json:"token" csv:"token" yaml:"token"`
+	Default  bool   `json:"default" csv:"default" yaml:"default"`
+}
+
+var registryAddCmd = &cobra.Command{
+	Use:   "add <name> <endpoint>",
+	Short: "Configure a private Spicepod registry, used in dependencies as <name>:<org>/<pod>",
+	Args:  cobra.ExactArgs(2),
+	Example: `
+spice registry add internal https://spicepods.example.com/v0.1 --token-env INTERNAL_REGISTRY_TOKEN
+spice registry add internal https://spicepods.example.com/v0.1 --token <token> --default
+spice add internal:data-platform/orders@^1.2
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, endpoint := args[0], args[1]
+		registryToken, _ := cmd.Flags().GetString(token)
+		tokenEnv, _ := cmd.Flags().GetString(tokenEnvFlag)
+		isDefault, _ := cmd.Flags().GetBool(defaultFlag)
+
+		if name == "" || strings.ContainsAny(name, ":/@") || name == "oci" || name == "file" {
+			return fmt.Errorf("Invalid registry name '%s', names cannot contain ':', '/' or '@' or be 'oci' or 'file'", name)
+		}
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("Invalid registry endpoint '%s', expected a URL like https://spicepods.example.com/v0.1", endpoint)
+		}
+		if registryToken != "" && tokenEnv != "" {
+			return fmt.Errorf("Set only one of --%s and --%s", token, tokenEnvFlag)
+		}
+
+		cliConfig, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		cliConfig.SetRegistry(config.RegistryConfig{
+			Name:     name,
+			Endpoint: strings.TrimSuffix(endpoint, "/"),
+			Token:    registryToken,
+			TokenEnv: tokenEnv,
+			Default:  isDefault,
+		})
+		if err = saveConfig(cmd, cliConfig); err != nil {
+			return fmt.Errorf("Error saving config: %s", err.Error())
+		}
+
+		cmd.Printf("Registry '%s' saved. Add Spicepods from it with: spice add %s:<org>/<pod>\n", name, name)
+		if isDefault {
+			cmd.Printf("Dependencies without a registry prefix are now fetched from '%s' instead of spicerack.org\n", name)
+		}
+		return nil
+	},
+}
+
+var registryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured private Spicepod registries",
+	Example: `
+spice registry list
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cliConfig, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(cliConfig.Registries) == 0 {
+			cmd.Println("No private registries configured, add one with: spice registry add <name> <endpoint>")
+			return nil
+		}
+
+		table := make([]interface{}, len(cliConfig.Registries))
+		for i, r := range cliConfig.Registries {
+			table[i] = registrySummary{Name: r.Name, Endpoint: r.Endpoint, Token: describeRegistryToken(r), Default: r.Default}
+		}
+		return output(cmd).WriteTable(table)
+	},
+}
+
+var registryRemoveCmd = &cobra.Command{
+	Use:               "remove <name>",
+	Short:             "Remove a configured private Spicepod registry",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRegistryNames,
+	Example: `
+spice registry remove internal
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cliConfig, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		if !cliConfig.RemoveRegistry(args[0]) {
+			return fmt.Errorf("No registry named '%s' is configured", args[0])
+		}
+		if err = saveConfig(cmd, cliConfig); err != nil {
+			return fmt.Errorf("Error saving config: %s", err.Error())
+		}
+		cmd.Printf("Registry '%s' removed\n", args[0])
+		return nil
+	},
+}
+
+// describeRegistryToken reports where a registry token comes from without revealing it.
+func describeRegistryToken(r config.RegistryConfig) string {
+	switch {
+	case r.TokenEnv != "" && os.Getenv(r.TokenEnv) != "":
+		return fmt.Sprintf("$%s", r.TokenEnv)
+	case r.TokenEnv != "":
+		return fmt.Sprintf("$%s (not set)", r.TokenEnv)
+	case r.Token != "":
+		return "config"
+	}
+	return "none"
+}
+
+func init() {
+	registryAddCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	registryAddCmd.Flags().String(token, "", "Token sent as a bearer token to the registry, stored in ~/.spice/config.yaml")
+	registryAddCmd.Flags().String(tokenEnvFlag, "", "Environment variable to read the registry token from")
+	registryAddCmd.Flags().Bool(defaultFlag, false, "Fetch dependencies without a registry prefix from this registry instead of spicerack.org")
+	registryCmd.AddCommand(registryAddCmd)
+
+	registryListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	registryCmd.AddCommand(registryListCmd)
+
+	registryRemoveCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	registryCmd.AddCommand(registryRemoveCmd)
+}
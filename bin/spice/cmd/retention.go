@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+const (
+	periodFlag        = "period"
+	checkIntervalFlag = "check-interval"
+	timeColumnFlag    = "time-column"
+	timeFormatFlag    = "time-format"
+	disableFlag       = "disable"
+
+	defaultRetentionCheckInterval = "1h"
+)
+
+// Retention settings in the order the runtime reads them. time_column and time_format are set
+// on the dataset, the rest on its acceleration.
+var settingRowPaths = []string{"time_column", "time_format", "acceleration.retention_period", "acceleration.retention_check_interval", "acceleration.retention_check_enabled"}
+
+type settingRow struct {
+	Setting string
+	Value   string
+}
+
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Show and change retention of accelerated datasets",
+	Example: `
+spice retention show taxi_trips
+spice retention set taxi_trips --period 7d --time-column pickup_time
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var retentionShowCmd = &cobra.Command{
+	Use:               "show <dataset>",
+	Short:             "Show a dataset's retention settings and how many rows the next check evicts",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice retention show taxi_trips
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), args[0])
+		if err != nil {
+			return err
+		}
+
+		var settings []interface{}
+		for _, path := range settingRowPaths {
+			value := "-"
+			if v := definition.Get(path); v != nil {
+				value = fmt.Sprintf("%v", v)
+			}
+			settings = append(settings, settingRow{Setting: path, Value: value})
+		}
+		if err := output(cmd).WriteTable(settings); err != nil {
+			return err
+		}
+
+		if reason := retentionInactiveReason(definition); reason != "" {
+			cmd.Printf("Retention is not active for %s: %s.\n", definition.Name, reason)
+			return nil
+		}
+		return reportRetentionEvictions(cmd, rtcontext, definition, "would be evicted at the next retention check")
+	},
+}
+
+var retentionSetCmd = &cobra.Command{
+	Use:               "set <dataset>",
+	Short:             "Change a dataset's retention settings",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice retention set taxi_trips --period 7d --time-column pickup_time
+spice retention set taxi_trips --period 30d --dry-run
+spice retention set taxi_trips --disable
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), args[0])
+		if err != nil {
+			return err
+		}
+		if definition.Get("acceleration.enabled") != true {
+			return fmt.Errorf("Dataset %s is not accelerated, retention only applies to accelerated datasets.", definition.Name)
+		}
+
+		values := map[string]interface{}{}
+		for _, flag := range []struct {
+			name string
+			path string
+		}{
+			{name: periodFlag, path: "acceleration.retention_period"},
+			{name: checkIntervalFlag, path: "acceleration.retention_check_interval"},
+			{name: timeColumnFlag, path: "time_column"},
+			{name: timeFormatFlag, path: "time_format"},
+		} {
+			if !cmd.Flags().Changed(flag.name) {
+				continue
+			}
+			value, _ := cmd.Flags().GetString(flag.name)
+			switch flag.name {
+			case periodFlag, checkIntervalFlag:
+				if _, err := accel.ParseInterval(value); err != nil {
+					return err
+				}
+			case timeFormatFlag:
+				if !slices.Contains(accel.TimeFormats, value) {
+					return fmt.Errorf("Unsupported time format '%s', use one of: %s", value, strings.Join(accel.TimeFormats, ", "))
+				}
+			}
+			values[flag.path] = value
+		}
+
+		disable, _ := cmd.Flags().GetBool(disableFlag)
+		values["acceleration.retention_check_enabled"] = !disable
+		if !disable && definition.Get("acceleration.retention_check_interval") == nil && values["acceleration.retention_check_interval"] == nil {
+			values["acceleration.retention_check_interval"] = defaultRetentionCheckInterval
+		}
+
+		var changes []interface{}
+		for _, path := range settingRowPaths {
+			value, ok := values[path]
+			if !ok {
+				continue
+			}
+			before := "-"
+			if v := definition.Get(path); v != nil {
+				before = fmt.Sprintf("%v", v)
+			}
+			if after := fmt.Sprintf("%v", value); before != after {
+				definition.Set(path, value)
+				changes = append(changes, accelChange{Setting: path, Before: before, After: after})
+			}
+		}
+
+		if reason := retentionInactiveReason(definition); reason != "" && !disable {
+			return fmt.Errorf("Retention cannot be enabled for %s: %s.", definition.Name, reason)
+		}
+
+		dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+		if len(changes) == 0 {
+			cmd.Printf("No changes, %s is already configured as requested.\n", definition.Name)
+		} else if !dryRun {
+			err = definition.Save()
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Updated %s\n", rtcontext.GetSpiceAppRelativePath(definition.FilePath))
+		}
+		if err := output(cmd).WriteTable(changes); err != nil {
+			return err
+		}
+
+		if disable {
+			return nil
+		}
+		return reportRetentionEvictions(cmd, rtcontext, definition, "would be evicted by the first retention check after the runtime loads spicepod.yaml")
+	},
+}
+
+// retentionInactiveReason returns why the runtime would not run retention checks for the
+// dataset, which needs a time column, a period and a check interval, or "" if it would.
+func retentionInactiveReason(definition *spicepod.DatasetDefinition) string {
+	switch {
+	case definition.Get("acceleration.enabled") != true:
+		return "the dataset is not accelerated"
+	case definition.Get("acceleration.retention_check_enabled") != true:
+		return "retention_check_enabled is not set"
+	case definition.Get("time_column") == nil:
+		return "time_column is not set"
+	case definition.Get("acceleration.retention_period") == nil:
+		return "retention_period is not set"
+	case definition.Get("acceleration.retention_check_interval") == nil:
+		return "retention_check_interval is not set"
+	}
+	return ""
+}
+
+// reportRetentionEvictions counts the rows older than the retention period in the running
+// runtime. Retention deletes them at its next check; the runtime does not report past evictions.
+func reportRetentionEvictions(cmd *cobra.Command, rtcontext *context.RuntimeContext, definition *spicepod.DatasetDefinition, outcome string) error {
+	if rtcontext.IsRuntimeHealthy(2*time.Second) != nil {
+		cmd.Println("Start the runtime with spice run to see how many rows retention evicts.")
+		return nil
+	}
+
+	period, err := accel.ParseInterval(fmt.Sprintf("%v", definition.Get("acceleration.retention_period")))
+	if err != nil {
+		return err
+	}
+	timeColumn := fmt.Sprintf("%v", definition.Get("time_column"))
+	timeFormat, _ := definition.Get("time_format").(string)
+
+	columns, err := api.GetDatasetColumns(rtcontext, definition.Name)
+	if err != nil {
+		return err
+	}
+	columnIndex := slices.IndexFunc(columns, func(c api.Column) bool { return c.Name == timeColumn })
+	if columnIndex < 0 {
+		return fmt.Errorf("Dataset %s has no column %s", definition.Name, timeColumn)
+	}
+
+	cutoff := time.Now().Add(-period)
+	query, err := accel.RetentionCountSql(definition.Name, timeColumn, columns[columnIndex].DataType, timeFormat, cutoff)
+	if err != nil {
+		return err
+	}
+	rows, err := api.Sql[struct {
+		Evicted int64 `json:"evicted"`
+	}](rtcontext, query)
+	if err != nil {
+		return err
+	}
+	var evicted int64
+	if len(rows) > 0 {
+		evicted = rows[0].Evicted
+	}
+
+	cmd.Printf("%d rows with %s before %s %s.\n", evicted, timeColumn, cutoff.UTC().Format(time.RFC3339), outcome)
+	return nil
+}
+
+func init() {
+	retentionShowCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	retentionCmd.AddCommand(retentionShowCmd)
+
+	retentionSetCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	retentionSetCmd.Flags().String(periodFlag, "", "How long rows are kept, e.g. 7d")
+	retentionSetCmd.Flags().String(checkIntervalFlag, "", fmt.Sprintf("How often expired rows are evicted (default %s when not already set)", defaultRetentionCheckInterval))
+	retentionSetCmd.Flags().String(timeColumnFlag, "", "Column compared against the retention period")
+	retentionSetCmd.Flags().String(timeFormatFlag, "", fmt.Sprintf("Format of numeric time columns, one of: %s", strings.Join(accel.TimeFormats, ", ")))
+	retentionSetCmd.Flags().Bool(disableFlag, false, "Disable retention checks")
+	retentionSetCmd.Flags().Bool(dryRunFlag, false, "Report the changes and evictions without updating spicepod.yaml")
+	retentionCmd.AddCommand(retentionSetCmd)
+
+	retentionCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(retentionCmd)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+)
+
+const retriesFlag = "retries"
+
+// applyRetries checks how often reads from the runtime are retried before a command gives up.
+// The runtime contexts of the command are given the value by newRuntimeContext.
+func applyRetries(cmd *cobra.Command) error {
+	retries, _ := cmd.Flags().GetInt(retriesFlag)
+	if retries < 0 {
+		return fmt.Errorf("invalid number of retries %d, expected 0 or more", retries)
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.PersistentFlags().Int(retriesFlag, api.DEFAULT_RETRIES, i18n.T("help.retries_flag"))
+}
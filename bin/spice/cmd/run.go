@@ -17,37 +17,54 @@ limitations under the License.
 package cmd
 
 import (
-	"os"
-
 	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
 	"github.com/spiceai/spiceai/bin/spice/pkg/runtime"
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
+const dockerFlag = "docker"
+
 var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Run Spice.ai - starts the Spice.ai runtime, installing if necessary",
 	Example: `
 spice run
+spice run --docker
+spice run --docker --image-tag 0.13.1-alpha
+
+# The default, native or docker, is chosen with spice setup
 
 # See more at: https://docs.spiceai.org/
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		err := checkLatestCliReleaseVersion()
+		err := checkLatestCliReleaseVersion(cmd)
 		if err != nil && util.IsDebug() {
 			cmd.PrintErrf("failed to check for latest CLI release version: %s\n", err.Error())
 		}
 
-		err = runtime.Run()
+		useDocker, _ := cmd.Flags().GetBool(dockerFlag)
+		if cliConfig, err := loadConfig(cmd); err == nil && !cmd.Flags().Changed(dockerFlag) {
+			useDocker = cliConfig.RuntimeFlavor == config.RUNTIME_FLAVOR_DOCKER
+		}
+		rtcontext := newRuntimeContext(cmd)
+		if useDocker {
+			imageTag, _ := cmd.Flags().GetString(imageTagFlag)
+			err = runtime.RunDocker(rtcontext, imageTag)
+		} else {
+			err = runtime.Run(rtcontext)
+		}
 		if err != nil {
-			cmd.PrintErrln(err.Error())
-			os.Exit(1)
+			return err
 		}
+		return nil
 	},
 }
 
 func init() {
 	runCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	runCmd.Flags().Bool(dockerFlag, false, "Run the runtime in the official Docker image instead of the native binary")
+	runCmd.Flags().String(imageTagFlag, "", "Runtime image tag for --docker, the one matching the installed runtime when empty")
 	RootCmd.AddCommand(runCmd)
 }
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/runtimeconfig"
+)
+
+const diffFlag = "diff"
+
+var runtimeCmd = &cobra.Command{
+	Use:   "runtime",
+	Short: "Inspect a running Spice runtime",
+	Example: `
+spice runtime config
+spice runtime config --diff spicepod.yaml
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var runtimeConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show the effective configuration of the runtime, or compare it with a local file",
+	Example: `
+spice runtime config
+spice runtime config --output yaml
+spice runtime config --diff spicepod.yaml
+spice runtime config --diff settings.yaml
+
+# settings.yaml
+# runtime.results_cache.item_ttl: 10s
+# datafusion.execution.batch_size: 8192
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		settings, err := runtimeconfig.Fetch(rtcontext)
+		if err != nil {
+			return err
+		}
+
+		diffFile, _ := cmd.Flags().GetString(diffFlag)
+		if diffFile == "" {
+			table := make([]interface{}, len(settings))
+			for i, setting := range settings {
+				table[i] = setting
+			}
+			return output(cmd).WriteTable(table)
+		}
+
+		local, err := runtimeconfig.LoadFile(diffFile)
+		if err != nil {
+			return err
+		}
+		differences := runtimeconfig.Diff(local, settings)
+		if len(differences) == 0 {
+			cmd.Printf("The runtime matches the %d settings in %s\n", len(local), diffFile)
+			return nil
+		}
+
+		table := make([]interface{}, len(differences))
+		for i, difference := range differences {
+			table[i] = difference
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+		return errReported
+	},
+}
+
+func init() {
+	runtimeCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	runtimeConfigCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	runtimeConfigCmd.Flags().String(diffFlag, "", "Compare with the settings in this file, a spicepod.yaml or a YAML or JSON file of settings, and exit 1 when they differ")
+	runtimeCmd.AddCommand(runtimeConfigCmd)
+	RootCmd.AddCommand(runtimeCmd)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/runtime"
+	"github.com/spiceai/spiceai/bin/spice/pkg/telemetry"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+// Commands that never trigger the first-run setup, as they are run by scripts and shells or
+// only print information.
+var setupExemptCommands = []string{"setup", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd, "help", "version", "-h", "--help"}
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Configure the Spice CLI: how to run the runtime, installing it, a connection profile and telemetry",
+	Example: `
+spice setup
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := runSetup(cmd, bufio.NewReader(os.Stdin)); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+// runFirstRunSetup offers the setup when the CLI is used interactively without a config file.
+// Declining still writes the config, so the offer is only made once.
+func runFirstRunSetup(args []string) error {
+	spiceDir, err := dotSpiceDir(RootCmd)
+	if err != nil || len(args) == 0 || slices.Contains(setupExemptCommands, args[0]) || config.Exists(spiceDir) ||
+		os.Getenv("CI") != "" || os.Getenv("SPICE_NO_SETUP") != "" ||
+		!util.IsTerminal(os.Stdin) || !util.IsTerminal(os.Stdout) {
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	RootCmd.Println("Welcome to Spice.ai! No CLI configuration was found.")
+	if !promptYesNo(RootCmd, reader, "Set up the Spice CLI now?", true) {
+		if err := (&config.CliConfig{}).Save(spiceDir); err != nil {
+			RootCmd.PrintErrln(err.Error())
+		}
+		RootCmd.Println("Skipped, run spice setup at any time.")
+		RootCmd.Println()
+		return nil
+	}
+
+	if err := runSetup(RootCmd, reader); err != nil {
+		return err
+	}
+	RootCmd.Println()
+	return nil
+}
+
+func runSetup(cmd *cobra.Command, reader *bufio.Reader) error {
+	spiceDir, err := dotSpiceDir(cmd)
+	if err != nil {
+		return err
+	}
+	cliConfig, err := config.Load(spiceDir)
+	if err != nil {
+		return err
+	}
+	cmd.Println("Press enter to keep the value in parentheses.")
+
+	flavor := cliConfig.RuntimeFlavor
+	if flavor == "" {
+		flavor = config.RUNTIME_FLAVOR_NATIVE
+	}
+	for {
+		flavor = promptValue(cmd, reader, "Run the Spice runtime as a native binary or in Docker? [native/docker]", flavor)
+		if flavor == config.RUNTIME_FLAVOR_NATIVE || flavor == config.RUNTIME_FLAVOR_DOCKER {
+			break
+		}
+		cmd.Println("Enter native or docker.")
+		flavor = config.RUNTIME_FLAVOR_NATIVE
+	}
+	cliConfig.RuntimeFlavor = flavor
+
+	rtcontext := newRuntimeContext(cmd)
+	switch {
+	case flavor == config.RUNTIME_FLAVOR_DOCKER:
+		if _, err := exec.LookPath("docker"); err != nil {
+			cmd.Println("Docker was not found on PATH, install it before running spice run.")
+		}
+	case rtcontext.IsRuntimeInstallRequired():
+		if promptYesNo(cmd, reader, "Install the Spice runtime now?", true) {
+			if err := runtime.EnsureInstalled(rtcontext, ""); err != nil {
+				return err
+			}
+		} else {
+			cmd.Println("The runtime will be installed by the first spice run.")
+		}
+	}
+
+	if promptYesNo(cmd, reader, "Create a connection profile for a Spice runtime not started by spice run?", false) {
+		promptProfile(cmd, reader, cliConfig)
+	}
+
+	// Nothing is recorded without an endpoint to send it to, so there is nothing to opt in to.
+	if endpoint := telemetry.Endpoint(); endpoint != "" {
+		cliConfig.TelemetryEnabled = promptYesNo(cmd, reader, fmt.Sprintf("Send anonymous usage telemetry (command names, durations and error classes) to %s?", endpoint), cliConfig.TelemetryEnabled)
+		if cliConfig.TelemetryEnabled {
+			cmd.Println("See what is recorded with spice telemetry show, disable it with spice telemetry off.")
+		}
+	}
+
+	if err := cliConfig.Save(spiceDir); err != nil {
+		return err
+	}
+	cmd.Printf("Saved settings to %s\n", config.ConfigPath(spiceDir))
+	return nil
+}
+
+// promptProfile asks for a profile's name and endpoint and selects it. An invalid answer skips the
+// profile rather than asking again, so setup ends when input ends.
+func promptProfile(cmd *cobra.Command, reader *bufio.Reader, cliConfig *config.CliConfig) {
+	profile := config.ProfileConfig{Name: promptValue(cmd, reader, "Profile name", "remote")}
+	if existing := cliConfig.GetProfile(profile.Name); existing != nil {
+		profile = *existing
+	}
+	profile.Endpoint = strings.TrimSuffix(promptValue(cmd, reader, "Spice runtime HTTP endpoint, e.g. https://spice.example.com", profile.Endpoint), "/")
+
+	if err := context.ValidateProfile(profile); err != nil {
+		cmd.Printf("%s, no profile was created. Add one later with spice profile set.\n", err.Error())
+		return
+	}
+	cliConfig.SetProfile(profile)
+	cliConfig.CurrentProfile = profile.Name
+	cmd.Printf("Using profile %s. Add a TLS certificate or API key with: spice profile set %s --tls-cert <file> --api-key <key>\n", profile.Name, profile.Name)
+}
+
+// promptValue asks for a value, returning current when the answer is empty or input ended.
+func promptValue(cmd *cobra.Command, reader *bufio.Reader, question string, current string) string {
+	cmd.Printf("%s (%s): ", question, current)
+	answer, err := reader.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		cmd.Println()
+	}
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer
+	}
+	return current
+}
+
+func promptYesNo(cmd *cobra.Command, reader *bufio.Reader, question string, defaultYes bool) bool {
+	current := "y"
+	if !defaultYes {
+		current = "n"
+	}
+	answer := strings.ToLower(promptValue(cmd, reader, question+" [y/n]", current))
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	setupCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(setupCmd)
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	gocontext "context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupProfile(t *testing.T) {
+	spiceDir := testutils.EnsureTestSpiceDirectory(t)
+	cmd := &cobra.Command{}
+	cmd.SetContext(WithDependencies(gocontext.Background(), Dependencies{DotSpiceDir: spiceDir}))
+	var output bytes.Buffer
+	cmd.SetOut(&output)
+
+	input := "docker\ny\nstaging\nhttps://spice.example.com/\nn\n"
+	assert.NoError(t, runSetup(cmd, bufio.NewReader(strings.NewReader(input))))
+	cliConfig, err := config.Load(spiceDir)
+	assert.NoError(t, err)
+	assert.Equal(t, config.RUNTIME_FLAVOR_DOCKER, cliConfig.RuntimeFlavor)
+	assert.Equal(t, []config.ProfileConfig{{Name: "staging", Endpoint: "https://spice.example.com"}}, cliConfig.Profiles)
+	assert.Equal(t, "staging", cliConfig.CurrentProfile)
+
+	output.Reset()
+	input = "docker\ny\nlocal\n\nn\n"
+	assert.NoError(t, runSetup(cmd, bufio.NewReader(strings.NewReader(input))))
+	assert.Contains(t, output.String(), `invalid profile name "local", no profile was created`)
+	cliConfig, err = config.Load(spiceDir)
+	assert.NoError(t, err)
+	assert.Len(t, cliConfig.Profiles, 1)
+	assert.Equal(t, "staging", cliConfig.CurrentProfile)
+}
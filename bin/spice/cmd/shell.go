@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/export"
+	"github.com/spiceai/spiceai/bin/spice/pkg/shell"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const (
+	shellHistoryLimit = 1000
+	noHistoryFlag     = "no-history"
+)
+
+// The modes the runtime has a backend for, search: and chat: input is reported as unsupported.
+var shellQueryModes = []string{shell.MODE_SQL, shell.MODE_NSQL}
+
+const shellHelp = `Input is routed by its prefix, unprefixed input goes to the current mode:
+  sql: <query>       run SQL
+  nsql: <question>   ask a question in natural language
+  chat: <message>    chat with a model
+  sql:, nsql:, ...   switch the current mode
+  ! <command>        run a command in the system shell
+
+Commands:
+  .nsql <question>     ask a question without switching to nsql: mode
+  .connect [endpoint]  show or switch the runtime HTTP endpoint
+  .profile [name]      show or switch the connection profile
+  .history             list the input of this and earlier sessions
+  .help                show this help
+  .exit                leave the shell`
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start an interactive shell for SQL, natural language and system commands against the Spice.ai runtime",
+	Example: `
+$ spice shell
+sql> SELECT count(*) FROM taxi_trips
+sql> nsql: how many trips had more than 4 passengers?
+sql> .nsql average fare by month
+sql> nsql:
+nsql> top 5 customers by revenue
+nsql> ! ls
+nsql> .connect http://localhost:8090
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		model, _ := cmd.Flags().GetString(modelFlag)
+		history := replHistory(cmd, "shell")
+		mode := shell.MODE_SQL
+
+		cmd.Println("Welcome to the Spice.ai shell! Type '.help' for help.")
+		reader := bufio.NewReader(cmd.InOrStdin())
+		confirm := func(string) bool {
+			return promptYesNo(cmd, reader, "Run this query?", true)
+		}
+		for {
+			cmd.Printf("%s> ", mode)
+			line, err := reader.ReadString('\n')
+			if line == "" && err != nil {
+				cmd.Println()
+				return nil
+			}
+			history.Add(line)
+
+			input := shell.Parse(line, mode)
+			switch input.Kind {
+			case shell.KIND_META:
+				switch input.Command {
+				case "exit", "quit":
+					return nil
+				case shell.MODE_NSQL:
+					if input.Text == "" {
+						cmd.PrintErrln("Usage: .nsql <question>")
+					} else if err := runNsql(cmd, rtcontext, model, input.Text, confirm); err != nil {
+						cmd.PrintErrln(err.Error())
+					}
+				default:
+					runShellMetaCommand(cmd, rtcontext, history, input)
+				}
+			case shell.KIND_SYSTEM:
+				if input.Text == "" {
+					continue
+				}
+				if err := runSystemCommand(cmd, input.Text); err != nil {
+					cmd.PrintErrln(err.Error())
+				}
+			case shell.KIND_QUERY:
+				if slices.Contains(shellQueryModes, input.Mode) {
+					mode = input.Mode
+				}
+				if input.Text == "" {
+					continue
+				}
+				if err := runShellQuery(cmd, rtcontext, model, input, confirm); err != nil {
+					cmd.PrintErrln(err.Error())
+				}
+			}
+		}
+	},
+}
+
+// replHistory loads the saved history of a REPL, or starts one that is not saved with
+// --no-history.
+func replHistory(cmd *cobra.Command, name string) *shell.History {
+	if noHistory, _ := cmd.Flags().GetBool(noHistoryFlag); noHistory {
+		return shell.NewHistory(shellHistoryLimit)
+	}
+
+	spiceDir, err := dotSpiceDir(cmd)
+	if err == nil {
+		var path string
+		if path, err = shell.HistoryPath(spiceDir, name); err == nil {
+			var history *shell.History
+			if history, err = shell.LoadHistory(path, shellHistoryLimit); err == nil {
+				return history
+			}
+		}
+	}
+	cmd.PrintErrf("History is not saved: %s\n", err.Error())
+	return shell.NewHistory(shellHistoryLimit)
+}
+
+// runShellQuery runs the input in its mode. Generated SQL only runs once confirm accepts it.
+func runShellQuery(cmd *cobra.Command, rtcontext *context.RuntimeContext, model string, input shell.Input, confirm func(sql string) bool) error {
+	switch input.Mode {
+	case shell.MODE_NSQL:
+		return runNsql(cmd, rtcontext, model, input.Text, confirm)
+	case shell.MODE_SQL:
+		start := time.Now()
+		rows, err := api.Sql[json.RawMessage](rtcontext, input.Text)
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			if err = export.WriteTable(output(cmd), rows); err != nil {
+				return err
+			}
+		}
+		cmd.Printf("%d rows in %s\n", len(rows), time.Since(start).Round(time.Millisecond))
+		return nil
+	}
+
+	return fmt.Errorf("The Spice runtime at %s does not support %s: input, use sql: or nsql:", rtcontext.HttpEndpoint(), input.Mode)
+}
+
+func runShellMetaCommand(cmd *cobra.Command, rtcontext *context.RuntimeContext, history *shell.History, input shell.Input) {
+	switch input.Command {
+	case "help":
+		cmd.Println(shellHelp)
+	case "connect":
+		if input.Text != "" {
+			rtcontext.SetHttpEndpoint(strings.TrimSuffix(input.Text, "/"))
+		}
+		cmd.Printf("Connected to %s\n", rtcontext.HttpEndpoint())
+		if err := rtcontext.IsRuntimeHealthy(5 * time.Second); err != nil {
+			cmd.PrintErrf("The runtime is not reachable: %s\n", err.Error())
+		}
+	case "profile":
+		if input.Text != "" {
+			if err := rtcontext.SwitchProfile(input.Text); err != nil {
+				cmd.PrintErrln(err.Error())
+				return
+			}
+		}
+		if rtcontext.Profile() != "" {
+			cmd.Printf("Profile: %s\n", rtcontext.Profile())
+		} else {
+			cmd.Println("Profile: none, the endpoint was set with .connect")
+		}
+		cmd.Printf("HTTP endpoint: %s\n", rtcontext.HttpEndpoint())
+		if rtcontext.TlsCert() != "" {
+			cmd.Printf("TLS certificate: %s\n", rtcontext.TlsCert())
+		}
+		if rtcontext.ApiKey() != "" {
+			cmd.Println("API key: set")
+		}
+	case "history":
+		for i, entry := range history.Entries() {
+			cmd.Printf("%5d  %s\n", i+1, entry)
+		}
+	default:
+		cmd.PrintErrf("Unknown command .%s, type '.help' for help\n", input.Command)
+	}
+}
+
+func runSystemCommand(cmd *cobra.Command, command string) error {
+	var execCmd *exec.Cmd
+	if util.IsWindows() {
+		execCmd = exec.Command("cmd", "/C", command)
+	} else {
+		execCmd = exec.Command("sh", "-c", command)
+	}
+	execCmd.Stdin = os.Stdin
+	execCmd.Stdout = cmd.OutOrStdout()
+	execCmd.Stderr = cmd.ErrOrStderr()
+	return execCmd.Run()
+}
+
+func init() {
+	shellCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	shellCmd.Flags().Bool(noHistoryFlag, false, "Do not load or save the input history in ~/.spice/history")
+	shellCmd.Flags().String(modelFlag, api.DEFAULT_NSQL_MODEL, "Model to answer nsql: questions with")
+	_ = shellCmd.RegisterFlagCompletionFunc(modelFlag, completeModelNames)
+	RootCmd.AddCommand(shellCmd)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/snapshot"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const (
+	locationFlag   = "location"
+	snapshotIdFlag = "id"
+)
+
+type snapshotRow struct {
+	ID        string
+	CreatedAt string
+	Engine    string
+	Size      string
+	Location  string
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Create, list and restore snapshots of a dataset's acceleration file",
+	Example: `
+spice snapshot create orders
+spice snapshot list orders --location s3://my-bucket/snapshots
+spice snapshot restore orders --dry-run
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:               "create <dataset>",
+	Short:             "Snapshot a dataset's DuckDB or SQLite acceleration file",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice snapshot create orders
+spice snapshot create orders --location s3://my-bucket/snapshots
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), args[0])
+		if err != nil {
+			return err
+		}
+
+		runtimeRunning := rtcontext.IsRuntimeHealthy(2*time.Second) == nil
+
+		entry, err := snapshot.Create(rtcontext.AppDir(), definition, snapshotLocation(cmd, rtcontext.AppDir()))
+		if err != nil {
+			return err
+		}
+
+		if err := printManifest(cmd, entry.Manifest); err != nil {
+			return err
+		}
+		cmd.Printf("\nCreated snapshot %s of dataset %s at %s\n", entry.ID, entry.Manifest.Dataset, entry.Location)
+		if runtimeRunning {
+			cmd.Println("The runtime was running during the snapshot, writes in progress may not be included")
+		}
+		return nil
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:               "list <dataset>",
+	Short:             "List a dataset's snapshots, newest first",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice snapshot list orders
+spice snapshot list orders --location s3://my-bucket/snapshots
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		location := snapshotLocation(cmd, rtcontext.AppDir())
+
+		entries, err := snapshot.List(location, args[0])
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			cmd.Printf("No snapshots of dataset %s found in %s\n", args[0], location)
+			return nil
+		}
+
+		table := make([]interface{}, len(entries))
+		for i, entry := range entries {
+			var size int64
+			for _, file := range entry.Manifest.Files {
+				size += file.Size
+			}
+			table[i] = snapshotRow{
+				ID:        entry.ID,
+				CreatedAt: entry.Manifest.CreatedAt.Format(time.RFC3339),
+				Engine:    entry.Manifest.Engine,
+				Size:      util.FormatBytes(float64(size)),
+				Location:  entry.Location,
+			}
+		}
+		return output(cmd).WriteTable(table)
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:               "restore <dataset>",
+	Short:             "Verify and restore a dataset's acceleration file from a snapshot",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDatasetNames,
+	Example: `
+spice snapshot restore orders
+spice snapshot restore orders --id 20240501T120000Z
+spice snapshot restore orders --dry-run
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, _ := cmd.Flags().GetString(snapshotIdFlag)
+		dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+		force, _ := cmd.Flags().GetBool(forceFlag)
+
+		rtcontext := newRuntimeContext(cmd)
+		definition, err := spicepod.FindDatasetDefinition(rtcontext.AppDir(), args[0])
+		if err != nil {
+			return err
+		}
+
+		entry, err := snapshot.Find(snapshotLocation(cmd, rtcontext.AppDir()), definition.Name, id)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			manifest, err := snapshot.Verify(rtcontext.AppDir(), definition, entry.Location)
+			if err != nil {
+				return fmt.Errorf("Snapshot %s cannot be restored: %s", entry.ID, err.Error())
+			}
+			if err := printManifest(cmd, manifest); err != nil {
+				return err
+			}
+			cmd.Printf("\nSnapshot %s of dataset %s is valid and can be restored\n", entry.ID, manifest.Dataset)
+			return nil
+		}
+
+		if !force && rtcontext.IsRuntimeHealthy(2*time.Second) == nil {
+			return fmt.Errorf("The runtime is running at %s and may have the acceleration file open. Stop it before restoring, or use --%s.", rtcontext.HttpEndpoint(), forceFlag)
+		}
+
+		manifest, err := snapshot.Restore(rtcontext.AppDir(), definition, entry.Location)
+		if err != nil {
+			return fmt.Errorf("Snapshot %s cannot be restored: %s", entry.ID, err.Error())
+		}
+
+		if err := printManifest(cmd, manifest); err != nil {
+			return err
+		}
+		cmd.Printf("\nRestored dataset %s from snapshot %s taken at %s\n", manifest.Dataset, entry.ID, manifest.CreatedAt.Format(time.RFC3339))
+		cmd.Println("Start the runtime to load the restored acceleration")
+		return nil
+	},
+}
+
+func snapshotLocation(cmd *cobra.Command, appDir string) string {
+	location, _ := cmd.Flags().GetString(locationFlag)
+	if location == "" {
+		return snapshot.DefaultLocation(appDir)
+	}
+	return location
+}
+
+func init() {
+	snapshotCreateCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	snapshotCreateCmd.Flags().String(locationFlag, "", "Snapshot location, a local directory or s3:// URL (default .spice/snapshots in the app directory)")
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+
+	snapshotListCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	snapshotListCmd.Flags().String(locationFlag, "", "Snapshot location, a local directory or s3:// URL (default .spice/snapshots in the app directory)")
+	snapshotCmd.AddCommand(snapshotListCmd)
+
+	snapshotRestoreCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	snapshotRestoreCmd.Flags().String(locationFlag, "", "Snapshot location, a local directory or s3:// URL (default .spice/snapshots in the app directory)")
+	snapshotRestoreCmd.Flags().String(snapshotIdFlag, "", "Snapshot to restore (default latest)")
+	snapshotRestoreCmd.Flags().Bool(dryRunFlag, false, "Verify the snapshot can be loaded without restoring it")
+	snapshotRestoreCmd.Flags().Bool(forceFlag, false, "Restore even if the runtime is running")
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+
+	snapshotCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(snapshotCmd)
+}
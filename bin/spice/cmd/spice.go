@@ -17,28 +17,77 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
 )
 
 const PROM_ENDPOINT = "http://localhost:9000"
 
+const progressFlag = "progress"
+
+// errReported fails a command that has already reported why, e.g. in a table of check results,
+// so only the exit code is set.
+var errReported = errors.New("command failed")
+
 var RootCmd = &cobra.Command{
 	Use:   "spice",
 	Short: "Spice.ai CLI",
+	// Errors are printed once by Execute.
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Usage is only printed for invalid arguments, which are rejected before this runs.
+		cmd.SilenceUsage = true
+		mode, _ := cmd.Flags().GetString(progressFlag)
+		if !slices.Contains(progress.Modes, mode) {
+			return errors.New(i18n.T("error.invalid_progress", progressFlag, mode, strings.Join(progress.Modes, ", ")))
+		}
+		if err := applyOutputFormat(cmd); err != nil {
+			return err
+		}
+		if err := applyRetries(cmd); err != nil {
+			return err
+		}
+		beginTelemetry(cmd)
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		endTelemetry(cmd, nil)
+	},
 }
 
 // Execute adds all child commands to the root command.
 func Execute() {
+	stopCliProfile := startCliProfile(os.Args[1:])
 	cobra.OnInitialize(initConfig)
-
-	if err := RootCmd.Execute(); err != nil {
-		RootCmd.Println(err)
-		os.Exit(-1)
+	localize(RootCmd)
+	err := runFirstRunSetup(os.Args[1:])
+	if err == nil {
+		runPlugin(os.Args[1:])
+		var cmd *cobra.Command
+		cmd, err = RootCmd.ExecuteC()
+		if err != nil {
+			// PersistentPostRun only runs for commands that succeed.
+			endTelemetry(cmd, err)
+		}
 	}
+	stopCliProfile()
+	if err != nil {
+		if !errors.Is(err, errReported) {
+			RootCmd.PrintErrln(err.Error())
+		}
+		os.Exit(1)
+	}
+}
+
+func init() {
+	RootCmd.PersistentFlags().String(progressFlag, progress.MODE_AUTO, i18n.T("help.progress_flag", strings.Join(progress.Modes, ", ")))
 }
 
 func initConfig() {
@@ -17,13 +17,22 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/export"
+	"github.com/spiceai/spiceai/bin/spice/pkg/shell"
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
+const (
+	fileFlag = "file"
+)
+
 var sqlCmd = &cobra.Command{
 	Use:   "sql",
 	Short: "Start an interactive SQL query session against the Spice.ai runtime",
@@ -42,30 +51,81 @@ sql> show tables
 | datafusion    | information_schema | columns       | VIEW       |
 | datafusion    | information_schema | df_settings   | VIEW       |
 +---------------+--------------------+---------------+------------+
+
+$ spice sql -q "SELECT * FROM taxi_trips" --file trips.csv
+$ spice sql -q "SELECT * FROM taxi_trips" --format jsonl > trips.jsonl
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		rtcontext := context.NewContext()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+
+		query, _ := cmd.Flags().GetString(queryFlag)
+		if query != "" {
+			return runSqlQuery(cmd, rtcontext, query)
+		}
+
 		execCmd, err := rtcontext.GetRunCmd()
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		execCmd.Args = append(execCmd.Args, "--repl")
+		if noHistory, _ := cmd.Flags().GetBool(noHistoryFlag); !noHistory {
+			historyPath, err := shell.HistoryPath(rtcontext.SpiceRuntimeDir(), "sql")
+			if err != nil {
+				cmd.PrintErrf("History is not saved: %s\n", err.Error())
+			} else {
+				// Runtimes without history support ignore the variable.
+				execCmd.Env = append(execCmd.Environ(), fmt.Sprintf("SPICE_REPL_HISTORY_FILE=%s", historyPath))
+			}
+		}
 
-		execCmd.Stderr = os.Stderr
-		execCmd.Stdout = os.Stdout
+		execCmd.Stderr = cmd.ErrOrStderr()
+		execCmd.Stdout = cmd.OutOrStdout()
 		execCmd.Stdin = os.Stdin
 
 		err = util.RunCommand(execCmd)
 		if err != nil {
-			cmd.Println(err)
-			os.Exit(1)
+			return err
 		}
+		return nil
 	},
 }
 
+// runSqlQuery streams the results of a single query to a file or stdout without starting the REPL.
+func runSqlQuery(cmd *cobra.Command, rtcontext *context.RuntimeContext, query string) error {
+	file, _ := cmd.Flags().GetString(fileFlag)
+	format, _ := cmd.Flags().GetString(formatFlag)
+
+	if file == "" {
+		file = export.STDOUT
+	}
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(file), ".")
+		if file == export.STDOUT || format == "" {
+			format = export.FORMAT_CSV
+		}
+	}
+	format = strings.ToLower(format)
+
+	files, err := export.Query(rtcontext, query, export.Options{
+		Format: format,
+		Output: file,
+	})
+	if err != nil {
+		return err
+	}
+
+	if file != export.STDOUT && len(files) > 0 {
+		cmd.Printf("Wrote %d rows to %s\n", files[0].Rows, files[0].File)
+	}
+	return nil
+}
+
 func init() {
 	sqlCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	sqlCmd.Flags().Bool(noHistoryFlag, false, "Do not load or save the REPL history in ~/.spice/history")
+	sqlCmd.Flags().StringP(queryFlag, "q", "", "Run this query and exit instead of starting the REPL")
+	sqlCmd.Flags().String(fileFlag, "", "Stream the results of --query to this file instead of stdout")
+	sqlCmd.Flags().String(formatFlag, "", fmt.Sprintf("Output format for --query, one of: %s (default: from the --file extension, else csv)", strings.Join(export.Formats, ", ")))
 	RootCmd.AddCommand(sqlCmd)
 }
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/queryplan"
+)
+
+var sqlAnalyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Show which filters, projections and joins of a query are pushed down to its sources",
+	Example: `
+spice sql analyze -q "SELECT * FROM taxi_trips WHERE fare_amount > 100"
+spice sql analyze -q "SELECT c.name, o.total FROM customers c JOIN orders o ON c.id = o.customer_id" --output json
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query, _ := cmd.Flags().GetString(queryFlag)
+		if query == "" {
+			return fmt.Errorf("--%s is required", queryFlag)
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		plan, err := api.PhysicalPlan(rtcontext, query)
+		if err != nil {
+			return err
+		}
+
+		findings := queryplan.Analyze(queryplan.Parse(plan))
+		table := make([]interface{}, len(findings))
+		fullScans := 0
+		for i, finding := range findings {
+			if finding.FullScan {
+				fullScans++
+			}
+			table[i] = finding
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+
+		if fullScans > 0 {
+			cmd.Println(colors(cmd).Yellow(fmt.Sprintf("%d full table scans: add a filter the source can apply, or accelerate the dataset", fullScans)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	sqlAnalyzeCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	sqlAnalyzeCmd.Flags().StringP(queryFlag, "q", "", "Query to analyze")
+	sqlCmd.AddCommand(sqlAnalyzeCmd)
+}
@@ -19,7 +19,6 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 	"github.com/spiceai/spiceai/bin/spice/pkg/api"
-	"github.com/spiceai/spiceai/bin/spice/pkg/context"
 )
 
 var statusCmd = &cobra.Command{
@@ -28,12 +27,9 @@ var statusCmd = &cobra.Command{
 	Example: `
 spice status
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		rtcontext := context.NewContext()
-		err := api.WriteDataTable(rtcontext, "/v1/status", api.Service{})
-		if err != nil {
-			cmd.PrintErrln(err.Error())
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtcontext := newRuntimeContext(cmd)
+		return api.WriteDataTable(rtcontext, output(cmd), "/v1/status", api.Service{})
 	},
 }
 
@@ -0,0 +1,231 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/telemetry"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+// Commands that are not recorded: they only inspect or change the telemetry setting, or run
+// on every shell completion.
+var telemetryExemptCommands = []string{"telemetry", "setup", "completion", "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+// telemetryStartKey carries when a recorded invocation began on the command's Go context.
+type telemetryStartKey struct{}
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Inspect or disable anonymous CLI usage telemetry",
+	Example: `
+spice telemetry show
+spice telemetry off
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var telemetryShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show whether telemetry is enabled and the recorded events exactly as they would be sent",
+	Args:  cobra.NoArgs,
+	Example: `
+spice telemetry show
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spiceDir, err := dotSpiceDir(cmd)
+		if err != nil {
+			return err
+		}
+		cliConfig, err := config.Load(spiceDir)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case telemetryDisabledByEnv():
+			cmd.Println("Telemetry: disabled by DO_NOT_TRACK")
+		case cliConfig.TelemetryEnabled:
+			cmd.Println("Telemetry: enabled, disable it with spice telemetry off")
+		default:
+			cmd.Println("Telemetry: disabled, opt in with spice setup")
+		}
+		if endpoint := telemetry.Endpoint(); endpoint != "" {
+			cmd.Printf("Endpoint: %s\n", endpoint)
+		} else {
+			cmd.Printf("Endpoint: not set, nothing is recorded (set %s to record and send events)\n", telemetry.ENDPOINT_ENV)
+		}
+
+		err = telemetry.RecoverPending(spiceDir)
+		if err != nil {
+			return err
+		}
+		events, err := telemetry.Queued(spiceDir)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			cmd.Println("No events recorded.")
+			return nil
+		}
+
+		eventsBytes, err := json.MarshalIndent(events, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Recorded events (%d):\n%s\n", len(events), string(eventsBytes))
+		return nil
+	},
+}
+
+var telemetryOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Disable telemetry and delete the recorded events",
+	Args:  cobra.NoArgs,
+	Example: `
+spice telemetry off
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spiceDir, err := dotSpiceDir(cmd)
+		if err != nil {
+			return err
+		}
+		cliConfig, err := config.Load(spiceDir)
+		if err != nil {
+			return err
+		}
+		cliConfig.TelemetryEnabled = false
+		if err = cliConfig.Save(spiceDir); err != nil {
+			return err
+		}
+		if err = telemetry.Clear(spiceDir); err != nil {
+			return err
+		}
+		cmd.Println("Telemetry is off and the recorded events were deleted.")
+		return nil
+	},
+}
+
+func telemetryDisabledByEnv() bool {
+	doNotTrack := os.Getenv("DO_NOT_TRACK")
+	return doNotTrack == "1" || strings.EqualFold(doNotTrack, "true")
+}
+
+// telemetryCommand returns the command path without the root, e.g. "accel enable", or "" when
+// the invocation is not recorded, e.g. because there is no endpoint to send it to.
+func telemetryCommand(cmd *cobra.Command) string {
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if !cmd.HasParent() || slices.Contains(telemetryExemptCommands, strings.Fields(command)[0]) || telemetryDisabledByEnv() || telemetry.Endpoint() == "" {
+		return ""
+	}
+	if cliConfig, err := loadConfig(cmd); err != nil || !cliConfig.TelemetryEnabled {
+		return ""
+	}
+	return command
+}
+
+func beginTelemetry(cmd *cobra.Command) {
+	command := telemetryCommand(cmd)
+	if command == "" {
+		return
+	}
+	spiceDir, err := dotSpiceDir(cmd)
+	if err == nil {
+		start := time.Now()
+		err = telemetry.Begin(spiceDir, command, start)
+		cmd.SetContext(gocontext.WithValue(cmd.Context(), telemetryStartKey{}, start))
+	}
+	if err != nil && util.IsDebug() {
+		cmd.PrintErrf("failed to record telemetry: %s\n", err.Error())
+	}
+}
+
+// endTelemetry records the invocation begun by beginTelemetry, which failed with cmdErr unless
+// it is nil, and sends the recorded events.
+func endTelemetry(cmd *cobra.Command, cmdErr error) {
+	command := telemetryCommand(cmd)
+	if command == "" || cmd.Context() == nil {
+		return
+	}
+	start, ok := cmd.Context().Value(telemetryStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	spiceDir, err := dotSpiceDir(cmd)
+	if err == nil {
+		err = telemetry.End(spiceDir, command, start, telemetryErrorClass(cmdErr))
+	}
+	if err == nil {
+		ctx, cancel := requestContext(cmd)
+		defer cancel()
+		err = telemetry.Flush(ctx, spiceDir, telemetry.Endpoint())
+	}
+	if err != nil && util.IsDebug() {
+		cmd.PrintErrf("failed to record telemetry: %s\n", err.Error())
+	}
+}
+
+// telemetryErrorClass tells what kind of error err is without recording anything about it, e.g.
+// its message.
+func telemetryErrorClass(err error) string {
+	var apiErr *api.RuntimeApiError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return telemetry.ERROR_CLASS_NONE
+	case errors.Is(err, context.ErrRequestTimeout), errors.Is(err, gocontext.DeadlineExceeded):
+		return telemetry.ERROR_CLASS_TIMEOUT
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return telemetry.ERROR_CLASS_TIMEOUT
+	case errors.Is(err, context.ErrRuntimeUnavailable), netErr != nil:
+		return telemetry.ERROR_CLASS_UNAVAILABLE
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		return telemetry.ERROR_CLASS_AUTH
+	case apiErr != nil:
+		return telemetry.ERROR_CLASS_API
+	}
+	return telemetry.ERROR_CLASS_FAILED
+}
+
+func init() {
+	telemetryShowCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	telemetryCmd.AddCommand(telemetryShowCmd)
+
+	telemetryOffCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	telemetryCmd.AddCommand(telemetryOffCmd)
+
+	telemetryCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(telemetryCmd)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/telemetry"
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTelemetryErrorClass(t *testing.T) {
+	rtcontext := context.NewContext()
+	for err, class := range map[error]string{
+		nil:                                 telemetry.ERROR_CLASS_NONE,
+		rtcontext.RuntimeUnavailableError(): telemetry.ERROR_CLASS_UNAVAILABLE,
+		rtcontext.RequestTimeoutError():     telemetry.ERROR_CLASS_TIMEOUT,
+		&url.Error{Op: "Get", Err: os.ErrDeadlineExceeded}:                                   telemetry.ERROR_CLASS_TIMEOUT,
+		&url.Error{Op: "Get", Err: errors.New("no such host")}:                               telemetry.ERROR_CLASS_UNAVAILABLE,
+		fmt.Errorf("loading: %w", &api.RuntimeApiError{StatusCode: http.StatusUnauthorized}): telemetry.ERROR_CLASS_AUTH,
+		&api.RuntimeApiError{StatusCode: http.StatusBadRequest}:                              telemetry.ERROR_CLASS_API,
+		errReported: telemetry.ERROR_CLASS_FAILED,
+	} {
+		assert.Equal(t, class, telemetryErrorClass(err), "%v", err)
+	}
+}
+
+func TestTelemetryIsRecordedPerInvocation(t *testing.T) {
+	var events []telemetry.Event
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var received []telemetry.Event
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		events = append(events, received...)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(collector.Close)
+	t.Setenv(telemetry.ENDPOINT_ENV, collector.URL)
+	t.Setenv("DO_NOT_TRACK", "")
+
+	ctx := mockRuntimeDependencies(t, "orders", api.Ready)
+	deps := ctx.Value(dependenciesKey{}).(Dependencies)
+	assert.NoError(t, (&config.CliConfig{TelemetryEnabled: true}).Save(deps.DotSpiceDir))
+
+	output := testutils.RunCommandContext(t, ctx, RootCmd, "datasets")
+	assert.NoError(t, output.Err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "datasets", events[0].Command)
+	assert.Equal(t, telemetry.ERROR_CLASS_NONE, events[0].ErrorClass)
+
+	// Failed commands are recorded by Execute, which PersistentPostRun doesn't run for.
+	cmd := &cobra.Command{Use: "sql"}
+	RootCmd.AddCommand(cmd)
+	t.Cleanup(func() { RootCmd.RemoveCommand(cmd) })
+	cmd.SetContext(ctx)
+	beginTelemetry(cmd)
+	endTelemetry(cmd, deps.NewRuntimeContext().RuntimeUnavailableError())
+	assert.Len(t, events, 2)
+	assert.Equal(t, "sql", events[1].Command)
+	assert.Equal(t, telemetry.ERROR_CLASS_UNAVAILABLE, events[1].ErrorClass)
+
+	// An invocation is recorded by the command it began on, never by the next one.
+	cmd.SetContext(ctx)
+	endTelemetry(cmd, nil)
+	assert.Len(t, events, 2)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/e2e"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+	"github.com/spiceai/spiceai/bin/spice/pkg/runtime"
+)
+
+const (
+	spicepodFlag = "spicepod"
+	noStartFlag  = "no-start"
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Test Spicepods against a running Spice runtime",
+	Example: `
+spice test e2e scenario.yaml
+
+# See more at: https://docs.spiceai.org/
+`,
+}
+
+var testE2eCmd = &cobra.Command{
+	Use:   "e2e <scenario.yaml>",
+	Short: "Boot the runtime with a Spicepod and run an end-to-end test scenario against it",
+	Args:  cobra.ExactArgs(1),
+	Example: `
+spice test e2e scenario.yaml
+spice test e2e scenario.yaml --spicepod ./my-app --ready-timeout 5m
+spice test e2e scenario.yaml --no-start
+spice test e2e scenario.yaml --output-file results.json
+
+# scenario.yaml
+name: taxi trips
+spicepod: .
+wait_for: [taxi_trips]
+steps:
+  - name: trips loaded
+    sql: SELECT COUNT(*) AS trips FROM taxi_trips
+    expect:
+      rows: [{trips: 2964624}]
+  - name: airport trips
+    sql: SELECT * FROM taxi_trips WHERE "RatecodeID" = 2 LIMIT 5
+    expect:
+      min_count: 1
+  - name: dataset registered
+    api: {method: GET, path: /v1/datasets}
+    expect:
+      status: 200
+      contains: taxi_trips
+
+# See more at: https://docs.spiceai.org/
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scenario, err := e2e.LoadScenario(args[0])
+		if err != nil {
+			return err
+		}
+
+		spicepodDir, _ := cmd.Flags().GetString(spicepodFlag)
+		noStart, _ := cmd.Flags().GetBool(noStartFlag)
+		outputFile, _ := cmd.Flags().GetString(outputFileFlag)
+
+		readyTimeout, _ := cmd.Flags().GetDuration(readyTimeoutFlag)
+		if !cmd.Flags().Changed(readyTimeoutFlag) && scenario.ReadyTimeout != "" {
+			readyTimeout, _ = time.ParseDuration(scenario.ReadyTimeout)
+		}
+
+		if spicepodDir == "" {
+			spicepodDir = scenario.SpicepodDir()
+		}
+
+		rtcontext := newRuntimeContext(cmd)
+		results, err := runE2eScenario(cmd, rtcontext, scenario, spicepodDir, noStart, readyTimeout)
+		if err != nil {
+			return err
+		}
+
+		var table []interface{}
+		passed := 0
+		for _, result := range results {
+			table = append(table, result)
+			if result.Status == e2e.STEP_PASSED {
+				passed++
+			}
+		}
+		if err := output(cmd).WriteTable(table); err != nil {
+			return err
+		}
+		cmd.Printf("%d of %d steps passed\n", passed, len(results))
+
+		if outputFile != "" {
+			resultBytes, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return err
+			}
+			err = os.WriteFile(outputFile, resultBytes, 0644)
+			if err != nil {
+				return fmt.Errorf("Error saving test results: %s", err.Error())
+			}
+			cmd.Printf("Saved test results to %s\n", outputFile)
+		}
+
+		if !e2e.Passed(results) {
+			return errReported
+		}
+		return nil
+	},
+}
+
+// runE2eScenario boots the runtime unless noStart is set, runs the scenario and always tears
+// the runtime down again before returning.
+func runE2eScenario(cmd *cobra.Command, rtcontext *context.RuntimeContext, scenario *e2e.Scenario, spicepodDir string, noStart bool, readyTimeout time.Duration) ([]e2e.StepResult, error) {
+	if noStart {
+		spinner := progress.NewSpinner(progressOutput(cmd), fmt.Sprintf("Waiting for the Spice runtime at %s", rtcontext.HttpEndpoint()))
+		spinner.Start()
+		err := e2e.WaitForReady(rtcontext, metricsEndpoint(cmd), scenario.WaitFor, readyTimeout)
+		if err != nil {
+			spinner.Stop("failed")
+			return nil, err
+		}
+		spinner.Stop("ready")
+	} else {
+		err := rtcontext.Init()
+		if err != nil {
+			return nil, err
+		}
+		if err = runtime.EnsureInstalled(rtcontext, ""); err != nil {
+			return nil, err
+		}
+
+		cmd.Printf("Starting the Spice runtime with the Spicepod in %s ...\n", spicepodDir)
+		runtime, err := e2e.StartRuntime(rtcontext, spicepodDir)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := runtime.Stop(); err != nil {
+				cmd.PrintErrf("Error stopping the Spice runtime: %s\n", err.Error())
+			}
+		}()
+
+		spinner := progress.NewSpinner(progressOutput(cmd), "Waiting for the Spice runtime to be ready")
+		spinner.Start()
+		err = runtime.WaitForReady(rtcontext, metricsEndpoint(cmd), scenario.WaitFor, readyTimeout)
+		if err != nil {
+			spinner.Stop("failed")
+			cmd.PrintErrln("Spice runtime output:")
+			cmd.PrintErrln(runtime.Output())
+			return nil, err
+		}
+		spinner.Stop("ready")
+	}
+
+	cmd.Printf("Running scenario %s (%d steps) ...\n", scenario.Name, len(scenario.Steps))
+	return e2e.Run(rtcontext, scenario), nil
+}
+
+func init() {
+	testE2eCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	testE2eCmd.Flags().String(spicepodFlag, "", "Directory of the Spicepod to boot, overriding the scenario's spicepod")
+	testE2eCmd.Flags().Duration(readyTimeoutFlag, 2*time.Minute, "How long to wait for the runtime and the scenario's datasets to be ready")
+	testE2eCmd.Flags().Bool(noStartFlag, false, "Run the scenario against an already running runtime instead of booting one")
+	testE2eCmd.Flags().String(outputFileFlag, "", "Write the step results as JSON to this file")
+	testCmd.AddCommand(testE2eCmd)
+
+	testCmd.Flags().BoolP("help", "h", false, "Print this help message")
+	RootCmd.AddCommand(testCmd)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	gocontext "context"
+
+	"github.com/spf13/cobra"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+)
+
+const timeoutFlag = "timeout"
+
+// requestContext returns the context for a single request made by cmd to a service other than
+// the runtime, e.g. the telemetry endpoint, limited by --timeout like requests to the runtime.
+func requestContext(cmd *cobra.Command) (gocontext.Context, gocontext.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = gocontext.Background()
+	}
+	if timeout, _ := cmd.Flags().GetDuration(timeoutFlag); timeout > 0 {
+		return gocontext.WithTimeout(ctx, timeout)
+	}
+	return gocontext.WithCancel(ctx)
+}
+
+func init() {
+	RootCmd.PersistentFlags().Duration(timeoutFlag, 0, i18n.T("help.timeout_flag"))
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -24,7 +25,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
-	"github.com/spiceai/spiceai/bin/spice/pkg/context"
 	"github.com/spiceai/spiceai/bin/spice/pkg/github"
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 	"github.com/spiceai/spiceai/bin/spice/pkg/version"
@@ -36,20 +36,19 @@ var upgradeCmd = &cobra.Command{
 	Example: `
 spice upgrade
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.Println("Checking for latest Spice CLI release...")
-		release, err := github.GetLatestCliRelease()
+		release, err := github.GetLatestCliRelease(cmd.Context())
 		if err != nil {
-			cmd.PrintErrln("Error checking for latest release:", err)
-			return
+			return fmt.Errorf("Error checking for latest release: %w", err)
 		}
 
-		rtcontext := context.NewContext()
+		rtcontext := newRuntimeContext(cmd)
 		cliVersion := version.Version()
 
 		if cliVersion == release.TagName {
 			cmd.Printf("Using the latest version %s. No upgrade required.\n", release.TagName)
-			return
+			return nil
 		}
 
 		assetName := github.GetAssetName(constants.SpiceCliFilename)
@@ -59,8 +58,7 @@ spice upgrade
 
 		stat, err := os.Stat(spiceBinDir)
 		if err != nil {
-			cmd.PrintErrln("Error upgrading the spice binary:", err)
-			return
+			return fmt.Errorf("Error upgrading the spice binary: %w", err)
 		}
 
 		tmpDirName := strconv.FormatInt(time.Now().Unix(), 16)
@@ -68,23 +66,20 @@ spice upgrade
 
 		err = os.Mkdir(tmpDir, stat.Mode())
 		if err != nil {
-			cmd.PrintErrln("Error upgrading the spice binary:", err)
-			return
+			return fmt.Errorf("Error upgrading the spice binary: %w", err)
 		}
 		defer os.RemoveAll(tmpDir)
 
-		err = github.DownloadAsset(release, tmpDir, assetName)
+		err = github.DownloadAsset(cmd.Context(), progressOutput(cmd), release, tmpDir, assetName)
 		if err != nil {
-			cmd.PrintErrln("Error downloading the spice binary:", err)
-			return
+			return fmt.Errorf("Error downloading the spice binary: %w", err)
 		}
 
 		tempFilePath := filepath.Join(tmpDir, constants.SpiceCliFilename)
 
 		err = util.MakeFileExecutable(tempFilePath)
 		if err != nil {
-			cmd.PrintErrln("Error upgrading the spice binary:", err)
-			return
+			return fmt.Errorf("Error upgrading the spice binary: %w", err)
 		}
 
 		releaseFilePath := filepath.Join(spiceBinDir, constants.SpiceCliFilename)
@@ -95,18 +90,17 @@ spice upgrade
 			runningCliTempLocation := filepath.Join(spiceBinDir, constants.SpiceCliFilename+".bak")
 			err = os.Rename(releaseFilePath, runningCliTempLocation)
 			if err != nil {
-				cmd.PrintErrln("Error upgrading the spice binary:", err)
-				return
+				return fmt.Errorf("Error upgrading the spice binary: %w", err)
 			}
 		}
 
 		err = os.Rename(tempFilePath, releaseFilePath)
 		if err != nil {
-			cmd.PrintErrln("Error upgrading the spice binary:", err)
-			return
+			return fmt.Errorf("Error upgrading the spice binary: %w", err)
 		}
 
 		cmd.Printf("Spice.ai CLI upgraded to %s successfully.\n", release.TagName)
+		return nil
 	},
 }
 
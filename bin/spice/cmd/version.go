@@ -18,15 +18,12 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
-	"github.com/spiceai/spiceai/bin/spice/pkg/context"
 	"github.com/spiceai/spiceai/bin/spice/pkg/github"
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 	"github.com/spiceai/spiceai/bin/spice/pkg/version"
@@ -39,17 +36,16 @@ var versionCmd = &cobra.Command{
 	Example: `
 spice version
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.Printf("CLI version:     %s\n", version.Version())
 
 		var rtversion string
 		var err error
 
-		rtcontext := context.NewContext()
+		rtcontext := newRuntimeContext(cmd)
 		err = rtcontext.Init()
 		if err != nil {
-			cmd.Println(err.Error())
-			os.Exit(1)
+			return err
 		}
 
 		if rtcontext.IsRuntimeInstallRequired() {
@@ -57,22 +53,22 @@ spice version
 		} else {
 			rtversion, err = rtcontext.Version()
 			if err != nil {
-				cmd.Printf("error getting runtime version: %s\n", err)
-				os.Exit(1)
+				return fmt.Errorf("error getting runtime version: %s", err)
 			}
 		}
 
 		cmd.Printf("Runtime version: %s\n", rtversion)
 
-		err = checkLatestCliReleaseVersion()
+		err = checkLatestCliReleaseVersion(cmd)
 		if err != nil && util.IsDebug() {
 			cmd.PrintErrf("failed to check for latest CLI release version: %s\n", err.Error())
 		}
+		return nil
 	},
 }
 
-func checkLatestCliReleaseVersion() error {
-	rtcontext := context.NewContext()
+func checkLatestCliReleaseVersion(cmd *cobra.Command) error {
+	rtcontext := newRuntimeContext(cmd)
 
 	err := rtcontext.Init()
 	if err != nil {
@@ -91,13 +87,13 @@ func checkLatestCliReleaseVersion() error {
 	}
 
 	if latestReleaseVersion == "" {
-		release, err := github.GetLatestCliRelease()
+		release, err := github.GetLatestCliRelease(cmd.Context())
 		if err != nil {
 			return err
 		}
 		err = os.WriteFile(versionFilePath, []byte(release.TagName+"\n"), 0644)
 		if err != nil && util.IsDebug() {
-			log.Printf("failed to write version file: %s\n", err.Error())
+			cmd.PrintErrf("failed to write version file: %s\n", err.Error())
 		}
 		latestReleaseVersion = release.TagName
 	}
@@ -107,7 +103,7 @@ func checkLatestCliReleaseVersion() error {
 	cliIsPreRelease := strings.HasPrefix(cliVersion, "local") || strings.Contains(cliVersion, "rc")
 
 	if !cliIsPreRelease && semver.Compare(cliVersion, latestReleaseVersion) < 0 {
-		fmt.Printf("\nCLI version %s is now available!\nTo upgrade, run \"spice upgrade\".\n", aurora.BrightGreen(latestReleaseVersion))
+		fmt.Fprintf(cmd.OutOrStdout(), "\nCLI version %s is now available!\nTo upgrade, run \"spice upgrade\".\n", colors(cmd).BrightGreen(latestReleaseVersion))
 	}
 
 	return nil
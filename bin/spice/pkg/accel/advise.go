@@ -0,0 +1,271 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	tableReferencePattern = regexp.MustCompile(`(?i)\b(?:from|join)\s+((?:"[^"]+"|[a-z_]\w*)(?:\.(?:"[^"]+"|[a-z_]\w*))*)`)
+	// A column compared to a literal, e.g. id = 42, t.city = 'Seattle' or region IN (...)
+	equalityPattern = regexp.MustCompile(`(?i)(?:(?:"[^"]+"|[a-z_]\w*)\.)?("[^"]+"|[a-z_]\w*)\s*(?:=\s*(?:'|-?\d)|\bin\s*\()`)
+)
+
+// QueryRecord is a row of the runtime's runtime.query_history table. ExecutionTime is in seconds.
+// BytesScanned is only set by runtimes that record it.
+type QueryRecord struct {
+	Sql             string  `json:"sql"`
+	ExecutionTime   float64 `json:"execution_time"`
+	ExecutionStatus int     `json:"execution_status"`
+	ResultsCacheHit bool    `json:"results_cache_hit"`
+	BytesScanned    *int64  `json:"bytes_scanned"`
+}
+
+type Recommendation struct {
+	Dataset       string
+	Engine        string
+	Mode          string
+	EstimatedSize int64
+	Reason        string
+}
+
+// DatasetActivity summarizes the queries that read a dataset. Latencies exclude results
+// cache hits, which say nothing about how fast the dataset itself is. BytesScanned is nil
+// when the query history does not record it, and counts a join's bytes for every dataset
+// it reads.
+type DatasetActivity struct {
+	Dataset       string
+	Queries       int
+	CacheHits     int
+	Latencies     []time.Duration
+	BytesScanned  *int64
+	FilterColumns map[string]int
+}
+
+func (a *DatasetActivity) AverageLatency() time.Duration {
+	if len(a.Latencies) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, latency := range a.Latencies {
+		total += latency
+	}
+	return total / time.Duration(len(a.Latencies))
+}
+
+func (a *DatasetActivity) P95Latency() time.Duration {
+	return bench.Percentile(a.Latencies, 95)
+}
+
+func QueryHistorySql(limit int) string {
+	return fmt.Sprintf("SELECT sql, execution_time, execution_status, results_cache_hit FROM runtime.query_history ORDER BY start_time DESC LIMIT %d", limit)
+}
+
+// DatasetQueryHistorySql returns the queries started within the window that mention dataset,
+// to narrow down with AnalyzeQueryHistory.
+func DatasetQueryHistorySql(dataset string, window time.Duration, bytesScanned bool) string {
+	columns := "sql, execution_time, execution_status, results_cache_hit"
+	if bytesScanned {
+		columns += ", bytes_scanned"
+	}
+	return fmt.Sprintf("SELECT %s FROM runtime.query_history WHERE start_time >= now() - INTERVAL '%d seconds' AND strpos(lower(sql), '%s') > 0",
+		columns, int64(window.Seconds()), strings.ReplaceAll(strings.ToLower(dataset), "'", "''"))
+}
+
+// AnalyzeQueryHistory attributes successful queries to the datasets they read, most queried
+// first. Filter columns are only counted for queries that read a single dataset, since
+// the CLI cannot tell which table an unqualified column belongs to in a join.
+func AnalyzeQueryHistory(records []QueryRecord, datasets []string) []*DatasetActivity {
+	activities := map[string]*DatasetActivity{}
+	for _, record := range records {
+		if record.ExecutionStatus != 0 {
+			continue
+		}
+
+		referenced := ReferencedDatasets(record.Sql, datasets)
+		for _, dataset := range referenced {
+			activity, ok := activities[dataset]
+			if !ok {
+				activity = &DatasetActivity{Dataset: dataset, FilterColumns: map[string]int{}}
+				activities[dataset] = activity
+			}
+			activity.Queries++
+			if record.ResultsCacheHit {
+				activity.CacheHits++
+			} else {
+				activity.Latencies = append(activity.Latencies, time.Duration(record.ExecutionTime*float64(time.Second)))
+			}
+			if record.BytesScanned != nil {
+				if activity.BytesScanned == nil {
+					activity.BytesScanned = new(int64)
+				}
+				*activity.BytesScanned += *record.BytesScanned
+			}
+			if len(referenced) == 1 {
+				for _, column := range EqualityColumns(record.Sql) {
+					activity.FilterColumns[column]++
+				}
+			}
+		}
+	}
+
+	result := make([]*DatasetActivity, 0, len(activities))
+	for _, activity := range activities {
+		result = append(result, activity)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Queries != result[j].Queries {
+			return result[i].Queries > result[j].Queries
+		}
+		return result[i].Dataset < result[j].Dataset
+	})
+	return result
+}
+
+// ReferencedDatasets returns the datasets a query reads from, out of the given names.
+func ReferencedDatasets(sql string, datasets []string) []string {
+	var referenced []string
+	for _, match := range tableReferencePattern.FindAllStringSubmatch(sql, -1) {
+		name := strings.ReplaceAll(match[1], `"`, "")
+		for _, dataset := range datasets {
+			if strings.EqualFold(name, dataset) && !slices.Contains(referenced, dataset) {
+				referenced = append(referenced, dataset)
+			}
+		}
+	}
+	return referenced
+}
+
+// EqualityColumns returns the columns a query compares to literals with = or IN.
+func EqualityColumns(sql string) []string {
+	var columns []string
+	for _, match := range equalityPattern.FindAllStringSubmatch(sql, -1) {
+		column := match[1]
+		if strings.EqualFold(column, "not") {
+			continue
+		}
+		column = strings.ReplaceAll(column, `"`, "")
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// SizeSample is the result of SizeEstimateSql. VariableBytes is null for an empty dataset.
+type SizeSample struct {
+	RowCount      int64  `json:"row_count"`
+	VariableBytes *int64 `json:"variable_bytes"`
+}
+
+// SizeEstimateSql returns a query for a dataset's row count and the bytes held by its
+// variable-width columns, to pass to EstimateSize.
+func SizeEstimateSql(dataset string, columns []api.Column) string {
+	var variable []string
+	for _, column := range columns {
+		if _, ok := fixedWidth(column.DataType); !ok {
+			variable = append(variable, fmt.Sprintf("COALESCE(octet_length(CAST(%s AS VARCHAR)), 0)", quoteIdentifier(column.Name)))
+		}
+	}
+	variableBytes := "0"
+	if len(variable) > 0 {
+		variableBytes = fmt.Sprintf("SUM(%s)", strings.Join(variable, " + "))
+	}
+	return fmt.Sprintf("SELECT COUNT(*) AS row_count, %s AS variable_bytes FROM %s", variableBytes, quoteIdentifier(dataset))
+}
+
+// EstimateSize approximates the in-memory Arrow size of a dataset. Variable-width values also
+// carry a 4 byte offset each.
+func EstimateSize(columns []api.Column, sample SizeSample) int64 {
+	var rowBytes int64
+	for _, column := range columns {
+		if width, ok := fixedWidth(column.DataType); ok {
+			rowBytes += width
+		} else {
+			rowBytes += 4
+		}
+	}
+	size := sample.RowCount * rowBytes
+	if sample.VariableBytes != nil {
+		size += *sample.VariableBytes
+	}
+	return size
+}
+
+func fixedWidth(dataType string) (int64, bool) {
+	switch {
+	case dataType == "Boolean" || dataType == "Int8" || dataType == "UInt8":
+		return 1, true
+	case dataType == "Int16" || dataType == "UInt16" || dataType == "Float16":
+		return 2, true
+	case dataType == "Int32" || dataType == "UInt32" || dataType == "Float32" || dataType == "Date32" || strings.HasPrefix(dataType, "Time32"):
+		return 4, true
+	case dataType == "Int64" || dataType == "UInt64" || dataType == "Float64" || dataType == "Date64" ||
+		strings.HasPrefix(dataType, "Time64") || strings.HasPrefix(dataType, "Timestamp") || strings.HasPrefix(dataType, "Duration") || strings.HasPrefix(dataType, "Interval"):
+		return 8, true
+	case strings.HasPrefix(dataType, "Decimal128"):
+		return 16, true
+	case strings.HasPrefix(dataType, "Decimal256"):
+		return 32, true
+	}
+	return 0, false
+}
+
+// RecommendEngine keeps datasets that fit the memory budget in Arrow and moves larger ones
+// to a DuckDB file, which also supports indexes.
+func RecommendEngine(estimatedSize int64, memoryBudget int64) (string, string) {
+	if estimatedSize >= 0 && estimatedSize <= memoryBudget {
+		return ENGINE_ARROW, MODE_MEMORY
+	}
+	return ENGINE_DUCKDB, MODE_FILE
+}
+
+func IndexSql(dataset string, column string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, fmt.Sprintf("idx_%s_%s", dataset, column))
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s)", name, quoteIdentifier(dataset), quoteIdentifier(column))
+}
+
+// AccelerationPatch renders a spicepod fragment that enables the recommended accelerations.
+func AccelerationPatch(recommendations []Recommendation) ([]byte, error) {
+	datasets := make([]yaml.MapSlice, len(recommendations))
+	for i, recommendation := range recommendations {
+		acceleration := yaml.MapSlice{{Key: "enabled", Value: true}}
+		if recommendation.Engine != ENGINE_ARROW {
+			acceleration = append(acceleration, yaml.MapItem{Key: "engine", Value: recommendation.Engine})
+		}
+		if recommendation.Mode != MODE_MEMORY {
+			acceleration = append(acceleration, yaml.MapItem{Key: "mode", Value: recommendation.Mode})
+		}
+		datasets[i] = yaml.MapSlice{{Key: "name", Value: recommendation.Dataset}, {Key: "acceleration", Value: acceleration}}
+	}
+	return yaml.Marshal(yaml.MapSlice{{Key: "datasets", Value: datasets}})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeQueryHistory(t *testing.T) {
+	bytesScanned := int64(2048)
+	records := []QueryRecord{
+		{Sql: "SELECT * FROM orders WHERE id = 42", ExecutionTime: 0.1, BytesScanned: &bytesScanned},
+		{Sql: `SELECT * FROM "orders" o WHERE o.region IN ('a') AND o.id = 7`, ExecutionTime: 0.3},
+		{Sql: "SELECT * FROM orders WHERE id = 1", ResultsCacheHit: true},
+		{Sql: "SELECT * FROM users u JOIN orders o ON u.id = o.user_id WHERE u.name = 'x'", ExecutionTime: 1},
+		{Sql: "SELECT * FROM users WHERE broken", ExecutionStatus: 1},
+		{Sql: "SELECT * FROM runtime.query_history"},
+	}
+
+	activities := AnalyzeQueryHistory(records, []string{"orders", "users"})
+	assert.Len(t, activities, 2)
+
+	orders := activities[0]
+	assert.Equal(t, "orders", orders.Dataset)
+	assert.Equal(t, 4, orders.Queries)
+	assert.Equal(t, 1, orders.CacheHits)
+	assert.Equal(t, map[string]int{"id": 3, "region": 1}, orders.FilterColumns)
+	assert.Equal(t, 467*time.Millisecond, orders.AverageLatency().Round(time.Millisecond))
+	assert.Equal(t, time.Second, orders.P95Latency())
+	assert.Equal(t, int64(2048), *orders.BytesScanned)
+
+	users := activities[1]
+	assert.Equal(t, 1, users.Queries)
+	assert.Empty(t, users.FilterColumns)
+	assert.Nil(t, users.BytesScanned)
+}
+
+func TestDatasetQueryHistorySql(t *testing.T) {
+	assert.Equal(t, "SELECT sql, execution_time, execution_status, results_cache_hit FROM runtime.query_history WHERE start_time >= now() - INTERVAL '86400 seconds' AND strpos(lower(sql), 'o''brien') > 0",
+		DatasetQueryHistorySql("O'Brien", 24*time.Hour, false))
+	assert.Contains(t, DatasetQueryHistorySql("orders", time.Hour, true), "results_cache_hit, bytes_scanned FROM")
+}
+
+func TestEqualityColumns(t *testing.T) {
+	assert.Equal(t, []string{"city", "fare amount"}, EqualityColumns(`SELECT * FROM t WHERE t.city = 'x' AND "fare amount" = -1 AND zone NOT IN (1) AND a > 3`))
+}
+
+func TestEstimateSize(t *testing.T) {
+	columns := []api.Column{
+		{Name: "id", DataType: "Int64"},
+		{Name: "name", DataType: "Utf8"},
+		{Name: "at", DataType: "Timestamp(Nanosecond, None)"},
+	}
+	assert.Equal(t, `SELECT COUNT(*) AS row_count, SUM(COALESCE(octet_length(CAST("name" AS VARCHAR)), 0)) AS variable_bytes FROM "users"`, SizeEstimateSql("users", columns))
+
+	variableBytes := int64(500)
+	assert.Equal(t, int64(100*20+500), EstimateSize(columns, SizeSample{RowCount: 100, VariableBytes: &variableBytes}))
+	assert.Equal(t, int64(0), EstimateSize(columns, SizeSample{}))
+}
+
+func TestAccelerationPatch(t *testing.T) {
+	engine, mode := RecommendEngine(2<<30, 1<<30)
+	patch, err := AccelerationPatch([]Recommendation{
+		{Dataset: "orders", Engine: engine, Mode: mode},
+		{Dataset: "users", Engine: ENGINE_ARROW, Mode: MODE_MEMORY},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `datasets:
+- name: orders
+  acceleration:
+    enabled: true
+    engine: duckdb
+    mode: file
+- name: users
+  acceleration:
+    enabled: true
+`, string(patch))
+	assert.Equal(t, `CREATE INDEX idx_orders_fare_amount ON "orders" ("fare amount")`, IndexSql("orders", "fare amount"))
+}
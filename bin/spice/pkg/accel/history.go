@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	REFRESH_STATUS_SUCCEEDED = "succeeded"
+	REFRESH_STATUS_FAILED    = "failed"
+)
+
+var (
+	ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	// Lines logged by the runtime when a dataset load or refresh finishes, after the timestamp,
+	// level and target, e.g. "Loaded 42 rows (1.2 kiB) for dataset taxi_trips in 310ms."
+	refreshLoadedPattern = regexp.MustCompile(`Loaded (\d+) rows(?: \(([^)]*)\))? for dataset (\S+) in (.+)\.$`)
+	refreshFailedPattern = regexp.MustCompile(`Failed to load data for dataset (\S+): (.*)$`)
+)
+
+// RefreshEvent is a completed dataset load or refresh read from the runtime log.
+type RefreshEvent struct {
+	Time     time.Time
+	Status   string
+	Rows     int64
+	Size     string
+	Duration string
+	Error    string
+}
+
+// ReadRefreshHistory returns the refreshes of a dataset logged in the given runtime log files,
+// oldest first. Files that do not exist are skipped.
+func ReadRefreshHistory(files []string, dataset string) ([]RefreshEvent, error) {
+	var events []RefreshEvent
+	for _, file := range files {
+		f, err := os.Open(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		fileEvents, err := ParseRefreshEvents(f, dataset)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, fileEvents...)
+	}
+	return events, nil
+}
+
+// ParseRefreshEvents reads the runtime's log output and returns the refreshes of a dataset.
+func ParseRefreshEvents(r io.Reader, dataset string) ([]RefreshEvent, error) {
+	var events []RefreshEvent
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		eventDataset, event := ParseRefreshEvent(scanner.Text())
+		if event != nil && strings.EqualFold(eventDataset, dataset) {
+			events = append(events, *event)
+		}
+	}
+
+	return events, scanner.Err()
+}
+
+// ParseRefreshEvent returns the dataset and refresh logged by a line of the runtime's output,
+// or nil if the line does not log a finished refresh.
+func ParseRefreshEvent(line string) (string, *RefreshEvent) {
+	line = strings.TrimSpace(ansiEscapePattern.ReplaceAllString(line, ""))
+
+	if match := refreshLoadedPattern.FindStringSubmatch(line); match != nil {
+		rows, _ := strconv.ParseInt(match[1], 10, 64)
+		return match[3], &RefreshEvent{Time: logLineTime(line), Status: REFRESH_STATUS_SUCCEEDED, Rows: rows, Size: match[2], Duration: match[4]}
+	}
+	if match := refreshFailedPattern.FindStringSubmatch(line); match != nil {
+		return match[1], &RefreshEvent{Time: logLineTime(line), Status: REFRESH_STATUS_FAILED, Error: match[2]}
+	}
+	return "", nil
+}
+
+// logLineTime parses the RFC 3339 timestamp the runtime starts each log line with.
+func logLineTime(line string) time.Time {
+	field, _, _ := strings.Cut(line, " ")
+	t, err := time.Parse(time.RFC3339Nano, field)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRefreshEvents(t *testing.T) {
+	log := strings.Join([]string{
+		"\x1b[2m2024-05-01T12:00:00.123456Z\x1b[0m \x1b[32m INFO\x1b[0m \x1b[2mruntime::accelerated_table::refresh\x1b[0m\x1b[2m:\x1b[0m Loaded 42 rows (1.2 kiB) for dataset taxi_trips in 310ms.",
+		"2024-05-01T12:00:01Z  INFO runtime::accelerated_table::refresh: Loaded 0 rows for dataset other in 5ms.",
+		"2024-05-01T13:00:00Z ERROR runtime::accelerated_table::refresh: Failed to load data for dataset taxi_trips: connection refused",
+		"2024-05-01T13:00:00Z  INFO runtime: Dataset taxi_trips registered (s3://bucket/), acceleration (arrow), results cache enabled.",
+	}, "\n")
+
+	events, err := ParseRefreshEvents(strings.NewReader(log), "TAXI_TRIPS")
+	assert.NoError(t, err)
+	assert.Equal(t, []RefreshEvent{
+		{Time: time.Date(2024, 5, 1, 12, 0, 0, 123456000, time.UTC), Status: REFRESH_STATUS_SUCCEEDED, Rows: 42, Size: "1.2 kiB", Duration: "310ms"},
+		{Time: time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC), Status: REFRESH_STATUS_FAILED, Error: "connection refused"},
+	}, events)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+)
+
+const (
+	INDEX_KIND_PRIMARY_KEY = "primary key"
+	INDEX_KIND_UNIQUE      = "unique"
+	INDEX_KIND_INDEX       = "index"
+)
+
+type Index struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Columns string `json:"columns"`
+}
+
+// KeyColumns returns the columns of the primary key, or of the first unique or plain index if
+// the table has no primary key.
+func KeyColumns(indexes []Index) []string {
+	sorted := append([]Index(nil), indexes...)
+	rank := map[string]int{INDEX_KIND_PRIMARY_KEY: 0, INDEX_KIND_UNIQUE: 1, INDEX_KIND_INDEX: 2}
+	sort.SliceStable(sorted, func(i, j int) bool { return rank[sorted[i].Kind] < rank[sorted[j].Kind] })
+	for _, index := range sorted {
+		if index.Columns != "" {
+			return strings.Split(index.Columns, ", ")
+		}
+	}
+	return nil
+}
+
+// ConflictBehavior describes what a refresh does when it writes a row whose key already
+// exists. The runtime inserts without an ON CONFLICT clause.
+func ConflictBehavior(refreshMode string, indexes []Index) string {
+	if refreshMode != REFRESH_MODE_APPEND {
+		return "none, each full refresh replaces the table's contents"
+	}
+	for _, index := range indexes {
+		if index.Kind == INDEX_KIND_PRIMARY_KEY || index.Kind == INDEX_KIND_UNIQUE {
+			return fmt.Sprintf("append refreshes fail if they write an existing (%s) key", index.Columns)
+		}
+	}
+	return "append refreshes insert every row, duplicates are kept"
+}
+
+// InspectIndexes reads the primary key, unique constraints and indexes of the dataset's
+// accelerated table from the engine itself, using the engine's command line client:
+// sqlite3, duckdb or psql. In-memory accelerations are only visible to the runtime.
+func InspectIndexes(appDir string, dataset *spec.DatasetSpec) ([]Index, error) {
+	engine := Engine(dataset.Acceleration)
+	switch {
+	case engine == ENGINE_POSTGRES:
+		output, err := runClient("psql", postgresConnInfo(dataset.Acceleration.Params), "-X", "-A", "-t", "-F", "\t", "-c", postgresIndexQuery(dataset.Name))
+		if err != nil {
+			return nil, err
+		}
+		return parseDelimitedIndexes(output), nil
+	case engine == ENGINE_ARROW:
+		return nil, fmt.Errorf("arrow accelerations do not support indexes or key constraints")
+	case Mode(dataset.Acceleration) != MODE_FILE:
+		return nil, fmt.Errorf("dataset %s is accelerated in memory, its %s tables are only visible to the runtime", dataset.Name, engine)
+	}
+
+	files := DataFiles(appDir, dataset)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("unsupported acceleration engine %s", engine)
+	}
+
+	var output []byte
+	var err error
+	switch engine {
+	case ENGINE_SQLITE:
+		output, err = runClient("sqlite3", "-readonly", "-json", files[0], sqliteIndexQuery(dataset.Name))
+	case ENGINE_DUCKDB:
+		output, err = runClient("duckdb", "-readonly", "-json", files[0], duckdbIndexQuery(dataset.Name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseJsonIndexes(output)
+}
+
+func sqliteIndexQuery(table string) string {
+	name := escapeSqlString(table)
+	return fmt.Sprintf(`SELECT 'primary key' AS kind, '' AS name, group_concat(name, ', ') AS columns FROM (SELECT name FROM pragma_table_info('%[1]s') WHERE pk > 0 ORDER BY pk) HAVING count(*) > 0
+UNION ALL
+SELECT CASE WHEN il."unique" THEN 'unique' ELSE 'index' END, il.name, (SELECT group_concat(name, ', ') FROM (SELECT name FROM pragma_index_info(il.name) ORDER BY seqno)) FROM pragma_index_list('%[1]s') il WHERE il.origin != 'pk'`, name)
+}
+
+func duckdbIndexQuery(table string) string {
+	name := escapeSqlString(table)
+	return fmt.Sprintf(`SELECT lower(constraint_type) AS kind, '' AS name, array_to_string(constraint_column_names, ', ') AS columns FROM duckdb_constraints() WHERE table_name = '%[1]s' AND constraint_type IN ('PRIMARY KEY', 'UNIQUE')
+UNION ALL
+SELECT CASE WHEN is_unique THEN 'unique' ELSE 'index' END, index_name, trim(expressions, '[]') FROM duckdb_indexes() WHERE table_name = '%[1]s'`, name)
+}
+
+func postgresIndexQuery(table string) string {
+	return fmt.Sprintf(`SELECT CASE WHEN ix.indisprimary THEN 'primary key' WHEN ix.indisunique THEN 'unique' ELSE 'index' END, i.relname, (SELECT string_agg(a.attname, ', ' ORDER BY k.ord) FROM unnest(ix.indkey) WITH ORDINALITY k(attnum, ord) JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum) FROM pg_index ix JOIN pg_class t ON t.oid = ix.indrelid JOIN pg_class i ON i.oid = ix.indexrelid WHERE t.relname = '%s'`, escapeSqlString(table))
+}
+
+// postgresConnInfo builds a libpq connection string from the same acceleration params the
+// runtime reads. Passwords stored as secrets are not resolved, set PGPASSWORD instead.
+func postgresConnInfo(params map[string]string) string {
+	if connectionString := params["pg_connection_string"]; connectionString != "" {
+		return connectionString
+	}
+
+	var parts []string
+	for _, key := range []string{"host", "port", "user", "db", "pass", "sslmode", "sslrootcert"} {
+		value := params["pg_"+key]
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "db":
+			key = "dbname"
+		case "pass":
+			key = "password"
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", key, value))
+	}
+	return strings.Join(parts, " ")
+}
+
+func runClient(name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, fmt.Errorf("the %s command line client is required to inspect the acceleration", name)
+	}
+	var stderr bytes.Buffer
+	command := exec.Command(name, args...)
+	command.Stderr = &stderr
+	output, err := command.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running %s: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+func parseJsonIndexes(output []byte) ([]Index, error) {
+	// sqlite3 prints nothing rather than an empty array when there are no rows
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+	var indexes []Index
+	if err := json.Unmarshal(output, &indexes); err != nil {
+		return nil, fmt.Errorf("error parsing index list: %w", err)
+	}
+	return indexes, nil
+}
+
+func parseDelimitedIndexes(output []byte) []Index {
+	var indexes []Index
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		indexes = append(indexes, Index{Kind: fields[0], Name: fields[1], Columns: fields[2]})
+	}
+	return indexes
+}
+
+func escapeSqlString(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+// KeySampleSql returns a query for the key columns of one row, to look up with LookupSql.
+func KeySampleSql(dataset string, keyColumns []string) string {
+	quoted := make([]string, len(keyColumns))
+	for i, column := range keyColumns {
+		quoted[i] = quoteIdentifier(column)
+	}
+	return fmt.Sprintf("SELECT %s FROM %s LIMIT 1", strings.Join(quoted, ", "), quoteIdentifier(dataset))
+}
+
+// LookupSql returns a point query for the row whose key columns hold the sample's values.
+func LookupSql(dataset string, keyColumns []string, sample map[string]interface{}) string {
+	predicates := make([]string, len(keyColumns))
+	for i, column := range keyColumns {
+		switch value := sample[column].(type) {
+		case nil:
+			predicates[i] = fmt.Sprintf("%s IS NULL", quoteIdentifier(column))
+		case string:
+			predicates[i] = fmt.Sprintf("%s = '%s'", quoteIdentifier(column), escapeSqlString(value))
+		case float64:
+			predicates[i] = fmt.Sprintf("%s = %s", quoteIdentifier(column), strconv.FormatFloat(value, 'f', -1, 64))
+		default:
+			predicates[i] = fmt.Sprintf("%s = %v", quoteIdentifier(column), value)
+		}
+	}
+	return fmt.Sprintf("SELECT * FROM %s WHERE %s", quoteIdentifier(dataset), strings.Join(predicates, " AND "))
+}
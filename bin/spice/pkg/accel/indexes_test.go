@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyColumns(t *testing.T) {
+	indexes := []Index{
+		{Kind: INDEX_KIND_INDEX, Name: "idx_city", Columns: "city"},
+		{Kind: INDEX_KIND_PRIMARY_KEY, Columns: "id, region"},
+	}
+	assert.Equal(t, []string{"id", "region"}, KeyColumns(indexes))
+	assert.Equal(t, []string{"city"}, KeyColumns(indexes[:1]))
+	assert.Nil(t, KeyColumns(nil))
+}
+
+func TestConflictBehavior(t *testing.T) {
+	keyed := []Index{{Kind: INDEX_KIND_PRIMARY_KEY, Columns: "id"}}
+	assert.Equal(t, "none, each full refresh replaces the table's contents", ConflictBehavior("", keyed))
+	assert.Equal(t, "append refreshes fail if they write an existing (id) key", ConflictBehavior(REFRESH_MODE_APPEND, keyed))
+	assert.Equal(t, "append refreshes insert every row, duplicates are kept", ConflictBehavior(REFRESH_MODE_APPEND, nil))
+}
+
+func TestLookupSql(t *testing.T) {
+	assert.Equal(t, `SELECT "id", "region" FROM "orders" LIMIT 1`, KeySampleSql("orders", []string{"id", "region"}))
+	sql := LookupSql("orders", []string{"id", "region", "note"}, map[string]interface{}{"id": float64(1234567), "region": "o'hare"})
+	assert.Equal(t, `SELECT * FROM "orders" WHERE "id" = 1234567 AND "region" = 'o''hare' AND "note" IS NULL`, sql)
+}
+
+func TestPostgresConnInfo(t *testing.T) {
+	assert.Equal(t, "host=localhost port=5432 dbname=accel password=secret", postgresConnInfo(map[string]string{
+		"pg_host": "localhost", "pg_port": "5432", "pg_db": "accel", "pg_pass": "secret",
+	}))
+	assert.Equal(t, "postgres://localhost/accel", postgresConnInfo(map[string]string{"pg_connection_string": "postgres://localhost/accel", "pg_host": "ignored"}))
+}
+
+func TestParseIndexes(t *testing.T) {
+	indexes, err := parseJsonIndexes([]byte(`[{"kind":"primary key","name":"","columns":"id"}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Index{{Kind: INDEX_KIND_PRIMARY_KEY, Columns: "id"}}, indexes)
+
+	indexes, err = parseJsonIndexes([]byte("\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, indexes)
+
+	assert.Equal(t, []Index{{Kind: INDEX_KIND_UNIQUE, Name: "orders_key", Columns: "id, region"}}, parseDelimitedIndexes([]byte("unique\torders_key\tid, region\n")))
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	REFRESH_MODE_FULL   = "full"
+	REFRESH_MODE_APPEND = "append"
+
+	STRATEGY_FULL        = "full"
+	STRATEGY_INCREMENTAL = "incremental append"
+	STRATEGY_STREAM      = "append stream"
+)
+
+// Numeric time values above this are more likely milliseconds than seconds: as seconds they
+// would be after the year 5000.
+const unixMillisThreshold = 100_000_000_000
+
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+// RefreshSettings are the dataset settings that decide what a refresh fetches.
+type RefreshSettings struct {
+	Dataset        string
+	Mode           string
+	TimeColumn     string
+	TimeColumnType string
+	TimeFormat     string
+	DataWindow     string
+	Sql            string
+}
+
+// RefreshPlan describes the query the runtime sends to the source on the next refresh.
+type RefreshPlan struct {
+	Strategy      string
+	Sql           string
+	Filter        string
+	WindowStart   *time.Time
+	HighWaterMark *time.Time
+	// How the filter reaches the source, empty without a filter.
+	Pushdown string
+	Notes    []string
+}
+
+// ExplainRefresh mirrors how the runtime builds a refresh: the refresh_sql, or a scan of the
+// dataset, filtered on the time column. Incremental appends filter on the latest time already
+// accelerated, the high-water mark; full refreshes, and appends into an empty acceleration,
+// filter on refresh_data_window when it is set.
+func ExplainRefresh(settings RefreshSettings, highWaterMark *time.Time, now time.Time) (*RefreshPlan, error) {
+	plan := &RefreshPlan{Strategy: STRATEGY_FULL, Sql: settings.Sql}
+	if plan.Sql == "" {
+		plan.Sql = fmt.Sprintf("SELECT * FROM %s", quoteIdentifier(settings.Dataset))
+	}
+
+	mode := settings.Mode
+	if mode == "" {
+		mode = REFRESH_MODE_FULL
+	}
+	if mode == REFRESH_MODE_APPEND {
+		if settings.TimeColumn == "" {
+			plan.Strategy = STRATEGY_STREAM
+			plan.Sql = ""
+			plan.Notes = append(plan.Notes, "append mode without a time_column appends changes streamed by the connector; set time_column to refresh incrementally")
+			return plan, nil
+		}
+		plan.Strategy = STRATEGY_INCREMENTAL
+	}
+
+	if settings.TimeColumn == "" {
+		if settings.DataWindow != "" {
+			plan.Notes = append(plan.Notes, "refresh_data_window has no effect without a time_column")
+		}
+		plan.Notes = append(plan.Notes, "every refresh fetches the whole dataset")
+		return plan, nil
+	}
+
+	var filterTime *time.Time
+	switch {
+	case plan.Strategy == STRATEGY_INCREMENTAL && highWaterMark != nil:
+		plan.HighWaterMark = highWaterMark
+		filterTime = highWaterMark
+	case settings.DataWindow != "":
+		window, err := ParseInterval(settings.DataWindow)
+		if err != nil {
+			return nil, err
+		}
+		windowStart := now.Add(-window)
+		plan.WindowStart = &windowStart
+		filterTime = &windowStart
+		if plan.Strategy == STRATEGY_INCREMENTAL {
+			plan.Notes = append(plan.Notes, "the acceleration is empty, so the refresh falls back to refresh_data_window")
+		}
+	case plan.Strategy == STRATEGY_INCREMENTAL:
+		plan.Notes = append(plan.Notes, "the acceleration is empty and refresh_data_window is not set, so the refresh fetches the whole dataset")
+	default:
+		plan.Notes = append(plan.Notes, "refresh_data_window is not set, so every refresh fetches the whole dataset")
+	}
+
+	if filterTime == nil {
+		return plan, nil
+	}
+
+	filter, err := timePredicate(settings.TimeColumn, settings.TimeColumnType, settings.TimeFormat, ">", *filterTime)
+	if err != nil {
+		return nil, fmt.Errorf("refresh cannot filter on %w", err)
+	}
+	plan.Filter = filter
+	if settings.Sql != "" {
+		plan.Sql = fmt.Sprintf("SELECT * FROM (%s) WHERE %s", settings.Sql, filter)
+	} else {
+		plan.Sql = fmt.Sprintf("%s WHERE %s", plan.Sql, filter)
+	}
+
+	plan.Pushdown = "column comparison, pushed down to connectors that support filters"
+	if isString(settings.TimeColumnType) {
+		plan.Pushdown = "cast to timestamp, generally not pushed down"
+		plan.Notes = append(plan.Notes, fmt.Sprintf("%s is a string, so the filter casts it to a timestamp; sources generally cannot push such a filter down and every row is fetched before filtering", settings.TimeColumn))
+	}
+
+	return plan, nil
+}
+
+// ParseTimeValue converts a time column value returned by the runtime, e.g. a high-water mark,
+// to a time. Numbers are Unix seconds, or milliseconds with the unix_millis time format.
+func ParseTimeValue(value interface{}, timeFormat string) (time.Time, error) {
+	switch v := value.(type) {
+	case float64:
+		if timeFormat == TIME_FORMAT_UNIX_MILLIS {
+			return time.UnixMilli(int64(v)).UTC(), nil
+		}
+		return time.Unix(int64(v), 0).UTC(), nil
+	case string:
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return ParseTimeValue(n, timeFormat)
+		}
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t.UTC(), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to read %v as a time", value)
+}
+
+// TimeFormatWarning flags a numeric time value that looks like Unix milliseconds while the
+// time format is seconds. refresh_data_window filters then compare against seconds and match
+// every row.
+func TimeFormatWarning(timeColumn string, value interface{}, timeFormat string) string {
+	v, ok := value.(float64)
+	if !ok || timeFormat == TIME_FORMAT_UNIX_MILLIS || v < unixMillisThreshold {
+		return ""
+	}
+	return fmt.Sprintf("the latest %s value %.0f looks like Unix milliseconds, but the time format is Unix seconds; set time_format: %s", timeColumn, v, TIME_FORMAT_UNIX_MILLIS)
+}
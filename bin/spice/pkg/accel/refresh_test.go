@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainRefresh(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	highWaterMark := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	settings := RefreshSettings{Dataset: "trips", Mode: REFRESH_MODE_APPEND, TimeColumn: "ts", TimeColumnType: "Int64", DataWindow: "1d"}
+
+	plan, err := ExplainRefresh(settings, &highWaterMark, now)
+	assert.NoError(t, err)
+	assert.Equal(t, STRATEGY_INCREMENTAL, plan.Strategy)
+	assert.Equal(t, `SELECT * FROM "trips" WHERE "ts" > 1714564800`, plan.Sql)
+	assert.Empty(t, plan.Notes)
+
+	plan, err = ExplainRefresh(settings, nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, &highWaterMark, plan.WindowStart)
+	assert.Len(t, plan.Notes, 1)
+
+	settings.Mode = REFRESH_MODE_FULL
+	settings.TimeColumnType = "Utf8"
+	settings.Sql = `SELECT * FROM trips WHERE fare > 0`
+	plan, err = ExplainRefresh(settings, &highWaterMark, now)
+	assert.NoError(t, err)
+	assert.Equal(t, STRATEGY_FULL, plan.Strategy)
+	assert.Nil(t, plan.HighWaterMark)
+	assert.Equal(t, `SELECT * FROM (SELECT * FROM trips WHERE fare > 0) WHERE CAST("ts" AS TIMESTAMP) > to_timestamp_millis(1714564800000)`, plan.Sql)
+	assert.Equal(t, "cast to timestamp, generally not pushed down", plan.Pushdown)
+
+	plan, err = ExplainRefresh(RefreshSettings{Dataset: "trips", Mode: REFRESH_MODE_APPEND}, nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, STRATEGY_STREAM, plan.Strategy)
+	assert.Empty(t, plan.Sql)
+}
+
+func TestParseTimeValue(t *testing.T) {
+	expected := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value      interface{}
+		timeFormat string
+	}{
+		{value: float64(1714564800), timeFormat: ""},
+		{value: float64(1714564800000), timeFormat: TIME_FORMAT_UNIX_MILLIS},
+		{value: "2024-05-01T12:00:00", timeFormat: ""},
+		{value: "2024-05-01T12:00:00Z", timeFormat: TIME_FORMAT_ISO8601},
+	} {
+		actual, err := ParseTimeValue(tc.value, tc.timeFormat)
+		assert.NoError(t, err, tc.value)
+		assert.Equal(t, expected, actual, tc.value)
+	}
+
+	assert.NotEmpty(t, TimeFormatWarning("ts", float64(1714564800000), ""))
+	assert.Empty(t, TimeFormatWarning("ts", float64(1714564800), ""))
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TIME_FORMAT_UNIX_SECONDS = "unix_seconds"
+	TIME_FORMAT_UNIX_MILLIS  = "unix_millis"
+	TIME_FORMAT_ISO8601      = "ISO8601"
+)
+
+var TimeFormats = []string{TIME_FORMAT_UNIX_SECONDS, TIME_FORMAT_UNIX_MILLIS, TIME_FORMAT_ISO8601}
+
+var intervalPattern = regexp.MustCompile(`^([0-9]+)(ms|s|m|h|d)$`)
+
+// ParseInterval parses an interval written with a single unit, e.g. 30s, 10m or 7d.
+func ParseInterval(interval string) (time.Duration, error) {
+	match := intervalPattern.FindStringSubmatch(interval)
+	if match == nil {
+		return 0, fmt.Errorf("invalid interval '%s', e.g. 30s, 10m or 7d", interval)
+	}
+	value, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval '%s': %w", interval, err)
+	}
+
+	unit := map[string]time.Duration{"ms": time.Millisecond, "s": time.Second, "m": time.Minute, "h": time.Hour, "d": 24 * time.Hour}[match[2]]
+	return time.Duration(value) * unit, nil
+}
+
+// RetentionCountSql returns a query counting the rows retention evicts at cutoff. It compares
+// the time column the way the runtime does for the column's Arrow data type: numbers as Unix
+// seconds or milliseconds, timestamps and dates directly, and strings cast to timestamps.
+func RetentionCountSql(dataset string, timeColumn string, dataType string, timeFormat string, cutoff time.Time) (string, error) {
+	predicate, err := timePredicate(timeColumn, dataType, timeFormat, "<", cutoff)
+	if err != nil {
+		return "", fmt.Errorf("retention does not support %w", err)
+	}
+
+	return fmt.Sprintf("SELECT COUNT(*) AS evicted FROM %s WHERE %s", quoteIdentifier(dataset), predicate), nil
+}
+
+// timePredicate compares a time column against t the way the runtime's timestamp filters do for
+// the column's Arrow data type.
+func timePredicate(timeColumn string, dataType string, timeFormat string, operator string, t time.Time) (string, error) {
+	column := quoteIdentifier(timeColumn)
+
+	switch {
+	case isIntegerOrFloat(dataType):
+		if timeFormat == TIME_FORMAT_UNIX_MILLIS {
+			return fmt.Sprintf("%s %s %d", column, operator, t.UnixMilli()), nil
+		}
+		return fmt.Sprintf("%s %s %d", column, operator, t.Unix()), nil
+	case isTemporal(dataType):
+		return fmt.Sprintf("%s %s to_timestamp_millis(%d)", column, operator, t.UnixMilli()), nil
+	case isString(dataType):
+		return fmt.Sprintf("CAST(%s AS TIMESTAMP) %s to_timestamp_millis(%d)", column, operator, t.UnixMilli()), nil
+	}
+	return "", fmt.Errorf("time column %s of type %s", timeColumn, dataType)
+}
+
+func isIntegerOrFloat(dataType string) bool {
+	for _, prefix := range []string{"Int", "UInt", "Float"} {
+		if strings.HasPrefix(dataType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTemporal(dataType string) bool {
+	return strings.HasPrefix(dataType, "Timestamp") || strings.HasPrefix(dataType, "Date") || strings.HasPrefix(dataType, "Time")
+}
+
+func isString(dataType string) bool {
+	return dataType == "Utf8" || dataType == "LargeUtf8"
+}
+
+func quoteIdentifier(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
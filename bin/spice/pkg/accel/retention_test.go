@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInterval(t *testing.T) {
+	interval, err := ParseInterval("7d")
+	assert.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, interval)
+
+	interval, err = ParseInterval("500ms")
+	assert.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, interval)
+
+	_, err = ParseInterval("1h30m")
+	assert.Error(t, err)
+}
+
+func TestRetentionCountSql(t *testing.T) {
+	cutoff := time.Unix(1714564800, 0)
+
+	query, err := RetentionCountSql("trips", "ts", "Int64", TIME_FORMAT_UNIX_MILLIS, cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT COUNT(*) AS evicted FROM "trips" WHERE "ts" < 1714564800000`, query)
+
+	query, err = RetentionCountSql("trips", "ts", "UInt32", "", cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT COUNT(*) AS evicted FROM "trips" WHERE "ts" < 1714564800`, query)
+
+	query, err = RetentionCountSql("trips", "pickup time", "Timestamp(Nanosecond, None)", "", cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT COUNT(*) AS evicted FROM "trips" WHERE "pickup time" < to_timestamp_millis(1714564800000)`, query)
+
+	query, err = RetentionCountSql("trips", "ts", "Utf8", TIME_FORMAT_ISO8601, cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT COUNT(*) AS evicted FROM "trips" WHERE CAST("ts" AS TIMESTAMP) < to_timestamp_millis(1714564800000)`, query)
+
+	_, err = RetentionCountSql("trips", "ts", "Decimal128(10, 2)", "", cutoff)
+	assert.Error(t, err)
+}
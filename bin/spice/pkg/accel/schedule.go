@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CronInterval converts a cron expression that fires at a fixed interval, such as
+// "*/15 * * * *" or "0 */6 * * *", to a refresh_check_interval. The runtime schedules refreshes
+// by interval from when the dataset loads, so expressions pinned to particular times of day or
+// days of the week cannot be represented and return an error.
+func CronInterval(expression string) (string, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return "", fmt.Errorf("invalid cron expression '%s': expected 5 fields", expression)
+	}
+	minute, hour, dayOfMonth, month, dayOfWeek := fields[0], fields[1], fields[2], fields[3], fields[4]
+	if month != "*" || dayOfWeek != "*" {
+		return "", fmt.Errorf("cron expression '%s' is not a fixed interval", expression)
+	}
+
+	switch {
+	case hour == "*" && dayOfMonth == "*":
+		if minute == "*" {
+			return "1m", nil
+		}
+		if step, ok := cronStep(minute, 60); ok {
+			return fmt.Sprintf("%dm", step), nil
+		}
+		if isCronNumber(minute) {
+			return "1h", nil
+		}
+	case isCronNumber(minute) && dayOfMonth == "*":
+		if step, ok := cronStep(hour, 24); ok {
+			return fmt.Sprintf("%dh", step), nil
+		}
+		if isCronNumber(hour) {
+			return "24h", nil
+		}
+	}
+
+	return "", fmt.Errorf("cron expression '%s' is not a fixed interval", expression)
+}
+
+// cronStep parses a "*/n" field. n must divide the field's period for the interval to stay
+// fixed across the wrap, e.g. */7 minutes fires at :56 and then :00.
+func cronStep(field string, period int) (int, bool) {
+	if !strings.HasPrefix(field, "*/") {
+		return 0, false
+	}
+	step, err := strconv.Atoi(strings.TrimPrefix(field, "*/"))
+	if err != nil || step <= 0 || period%step != 0 {
+		return 0, false
+	}
+	return step, true
+}
+
+func isCronNumber(field string) bool {
+	_, err := strconv.Atoi(field)
+	return err == nil
+}
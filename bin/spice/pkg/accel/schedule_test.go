@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronInterval(t *testing.T) {
+	for expression, expected := range map[string]string{
+		"* * * * *":    "1m",
+		"*/15 * * * *": "15m",
+		"0 * * * *":    "1h",
+		"30 */6 * * *": "6h",
+		"0 0 * * *":    "24h",
+	} {
+		interval, err := CronInterval(expression)
+		assert.NoError(t, err, expression)
+		assert.Equal(t, expected, interval, expression)
+	}
+
+	for _, expression := range []string{"*/7 * * * *", "0 9 * * 1", "0 0 1 * *", "0 *", "*/0 * * * *"} {
+		_, err := CronInterval(expression)
+		assert.Error(t, err, expression)
+	}
+}
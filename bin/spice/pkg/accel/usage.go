@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+)
+
+const (
+	ENGINE_ARROW    = "arrow"
+	ENGINE_DUCKDB   = "duckdb"
+	ENGINE_SQLITE   = "sqlite"
+	ENGINE_POSTGRES = "postgres"
+
+	MODE_MEMORY = "memory"
+	MODE_FILE   = "file"
+
+	USAGE_SNAPSHOT_FILENAME = "accel_usage.json"
+)
+
+var Engines = []string{ENGINE_ARROW, ENGINE_DUCKDB, ENGINE_SQLITE, ENGINE_POSTGRES}
+
+// Usage is the storage an accelerated dataset uses. Size is -1 when the CLI cannot measure it,
+// e.g. for in-memory or Postgres accelerations.
+type Usage struct {
+	Dataset string
+	Engine  string
+	Mode    string
+	Paths   []string
+	Size    int64
+}
+
+// UsageSnapshot records dataset sizes so later reports can show growth.
+type UsageSnapshot struct {
+	Time  time.Time        `json:"time"`
+	Sizes map[string]int64 `json:"sizes"`
+}
+
+func Engine(acceleration *spec.AccelerationSpec) string {
+	if acceleration == nil || acceleration.Engine == "" {
+		return ENGINE_ARROW
+	}
+	return acceleration.Engine
+}
+
+func Mode(acceleration *spec.AccelerationSpec) string {
+	if acceleration == nil || acceleration.Mode == "" {
+		return MODE_MEMORY
+	}
+	return acceleration.Mode
+}
+
+func IsAccelerated(dataset *spec.DatasetSpec) bool {
+	return dataset.Acceleration != nil && dataset.Acceleration.Enabled
+}
+
+// DataFiles returns the files a file-mode DuckDB or SQLite acceleration writes, using the same
+// defaults as the runtime, which resolves relative paths against the app directory.
+func DataFiles(appDir string, dataset *spec.DatasetSpec) []string {
+	if !IsAccelerated(dataset) || Mode(dataset.Acceleration) != MODE_FILE {
+		return nil
+	}
+
+	var path string
+	var suffixes []string
+	switch Engine(dataset.Acceleration) {
+	case ENGINE_DUCKDB:
+		path = dataset.Acceleration.Params["duckdb_file"]
+		if path == "" {
+			path = fmt.Sprintf("%s.db", dataset.Name)
+		}
+		suffixes = []string{"", ".wal"}
+	case ENGINE_SQLITE:
+		path = dataset.Acceleration.Params["sqlite_file"]
+		if path == "" {
+			path = fmt.Sprintf("%s_sqlite.db", dataset.Name)
+		}
+		suffixes = []string{"", "-wal", "-shm"}
+	default:
+		return nil
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(appDir, path)
+	}
+	files := make([]string, 0, len(suffixes))
+	for _, suffix := range suffixes {
+		files = append(files, path+suffix)
+	}
+	return files
+}
+
+// MeasureUsage returns the usage of each accelerated dataset. Files that do not exist, such as
+// an unused WAL, are left out of Paths.
+func MeasureUsage(appDir string, datasets []*spec.DatasetSpec) ([]Usage, error) {
+	var usages []Usage
+	for _, dataset := range datasets {
+		if !IsAccelerated(dataset) {
+			continue
+		}
+
+		usage := Usage{Dataset: dataset.Name, Engine: Engine(dataset.Acceleration), Mode: Mode(dataset.Acceleration), Size: -1}
+		for _, file := range DataFiles(appDir, dataset) {
+			stat, err := os.Stat(file)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if usage.Size < 0 {
+				usage.Size = 0
+			}
+			usage.Size += stat.Size()
+			usage.Paths = append(usage.Paths, file)
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// LoadUsageSnapshot reads the snapshot saved in the app's .spice directory, or returns nil if
+// none has been saved.
+func LoadUsageSnapshot(appDir string) (*UsageSnapshot, error) {
+	snapshotBytes, err := os.ReadFile(usageSnapshotPath(appDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot UsageSnapshot
+	err = json.Unmarshal(snapshotBytes, &snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", usageSnapshotPath(appDir), err)
+	}
+	return &snapshot, nil
+}
+
+func SaveUsageSnapshot(appDir string, usages []Usage, now time.Time) error {
+	snapshot := UsageSnapshot{Time: now.UTC(), Sizes: make(map[string]int64)}
+	for _, usage := range usages {
+		if usage.Size >= 0 {
+			snapshot.Sizes[usage.Dataset] = usage.Size
+		}
+	}
+
+	snapshotBytes, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Join(appDir, constants.DotSpice), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(usageSnapshotPath(appDir), snapshotBytes, 0o644)
+}
+
+func usageSnapshotPath(appDir string) string {
+	return filepath.Join(appDir, constants.DotSpice, USAGE_SNAPSHOT_FILENAME)
+}
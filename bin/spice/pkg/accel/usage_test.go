@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasureUsage(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "trips.db"), make([]byte, 100), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "trips.db.wal"), make([]byte, 10), 0o644))
+
+	datasets := []*spec.DatasetSpec{
+		{Name: "trips", Acceleration: &spec.AccelerationSpec{Enabled: true, Engine: ENGINE_DUCKDB, Mode: MODE_FILE}},
+		{Name: "orders", Acceleration: &spec.AccelerationSpec{Enabled: true, Engine: ENGINE_SQLITE, Mode: MODE_FILE, Params: map[string]string{"sqlite_file": "/data/orders.db"}}},
+		{Name: "users", Acceleration: &spec.AccelerationSpec{Enabled: true}},
+		{Name: "raw"},
+	}
+
+	assert.Equal(t, []string{"/data/orders.db", "/data/orders.db-wal", "/data/orders.db-shm"}, DataFiles(dir, datasets[1]))
+
+	usages, err := MeasureUsage(dir, datasets)
+	assert.NoError(t, err)
+	assert.Equal(t, []Usage{
+		{Dataset: "trips", Engine: ENGINE_DUCKDB, Mode: MODE_FILE, Paths: []string{filepath.Join(dir, "trips.db"), filepath.Join(dir, "trips.db.wal")}, Size: 110},
+		{Dataset: "orders", Engine: ENGINE_SQLITE, Mode: MODE_FILE, Size: -1},
+		{Dataset: "users", Engine: ENGINE_ARROW, Mode: MODE_MEMORY, Size: -1},
+	}, usages)
+
+	snapshot, err := LoadUsageSnapshot(dir)
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, SaveUsageSnapshot(dir, usages, now))
+	snapshot, err = LoadUsageSnapshot(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, &UsageSnapshot{Time: now, Sizes: map[string]int64{"trips": 110}}, snapshot)
+}
@@ -16,6 +16,14 @@ limitations under the License.
 
 package api
 
+import (
+	"os"
+	"path/filepath"
+
+	toml "github.com/pelletier/go-toml"
+	"github.com/spiceai/spiceai/bin/spice/pkg/timing"
+)
+
 const (
 	AUTH_TYPE_SPICE_AI        = "spiceai"
 	AUTH_TYPE_DREMIO          = "dremio"
@@ -45,3 +53,26 @@ const (
 type Auth struct {
 	Params map[string]string `json:"params,omitempty" csv:"params" toml:"params,omitempty"`
 }
+
+// LoadAuthConfig reads the credentials saved by `spice login` from the auth file in dotSpiceDir,
+// e.g. ~/.spice/auth. A missing auth file yields an empty config.
+func LoadAuthConfig(dotSpiceDir string) (map[string]*Auth, error) {
+	authConfigPath := filepath.Join(dotSpiceDir, "auth")
+	defer timing.Start(timing.PHASE_CONFIG, authConfigPath)()
+
+	authConfig := map[string]*Auth{}
+	authConfigBytes, err := os.ReadFile(authConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return authConfig, nil
+		}
+		return nil, err
+	}
+
+	err = toml.Unmarshal(authConfigBytes, &authConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return authConfig, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+type CacheStats struct {
+	Requests uint64 `json:"requests" csv:"requests" yaml:"requests"`
+	Hits     uint64 `json:"hits" csv:"hits" yaml:"hits"`
+	HitRate  string `json:"hit_rate" csv:"hit_rate" yaml:"hit_rate"`
+	Items    uint64 `json:"items" csv:"items" yaml:"items"`
+	Size     string `json:"size" csv:"size" yaml:"size"`
+	MaxSize  string `json:"max_size" csv:"max_size" yaml:"max_size"`
+}
+
+// GetCacheStats reads the results cache metrics from the runtime's metrics endpoint. It returns
+// nil when the runtime is not running. Size metrics are only refreshed by the runtime every few
+// seconds, so they may lag behind the request counters.
+func GetCacheStats(rtcontext *context.RuntimeContext, spiced_addr string) (*CacheStats, error) {
+	metricFamilies, err := GetMetricFamilies(rtcontext, spiced_addr)
+	if err != nil || metricFamilies == nil {
+		return nil, err
+	}
+	return extractCacheStats(metricFamilies), nil
+}
+
+func extractCacheStats(metricFamilies map[string]*dto.MetricFamily) *CacheStats {
+	stats := &CacheStats{
+		Requests: uint64(metricValue(metricFamilies["results_cache_request_count"])),
+		Hits:     uint64(metricValue(metricFamilies["results_cache_hit_count"])),
+		Items:    uint64(metricValue(metricFamilies["results_cache_item_count"])),
+		Size:     util.FormatBytes(metricValue(metricFamilies["results_cache_size"])),
+		MaxSize:  util.FormatBytes(metricValue(metricFamilies["results_cache_max_size"])),
+		HitRate:  "-",
+	}
+	if stats.Requests > 0 {
+		stats.HitRate = fmt.Sprintf("%.1f%%", float64(stats.Hits)/float64(stats.Requests)*100)
+	}
+	return stats
+}
+
+// metricValue sums a counter or gauge metric across its series.
+func metricValue(mf *dto.MetricFamily) float64 {
+	if mf == nil {
+		return 0
+	}
+	var total float64
+	for _, m := range mf.Metric {
+		switch {
+		case m.Counter != nil:
+			total += m.Counter.GetValue()
+		case m.Gauge != nil:
+			total += m.Gauge.GetValue()
+		case m.Untyped != nil:
+			total += m.Untyped.GetValue()
+		}
+	}
+	return total
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+)
+
+// Catalog summarizes a catalog registered in the runtime's SQL engine, as reported by
+// information_schema.tables.
+type Catalog struct {
+	Name    string `json:"catalog_name,omitempty" csv:"catalog_name" yaml:"catalog_name,omitempty"`
+	Schemas int    `json:"schemas" csv:"schemas" yaml:"schemas"`
+	Tables  int    `json:"tables" csv:"tables" yaml:"tables"`
+}
+
+type Schema struct {
+	Catalog string `json:"table_catalog,omitempty" csv:"table_catalog" yaml:"table_catalog,omitempty"`
+	Name    string `json:"table_schema,omitempty" csv:"table_schema" yaml:"table_schema,omitempty"`
+	Tables  int    `json:"tables" csv:"tables" yaml:"tables"`
+}
+
+type Table struct {
+	Catalog string `json:"table_catalog,omitempty" csv:"table_catalog" yaml:"table_catalog,omitempty"`
+	Schema  string `json:"table_schema,omitempty" csv:"table_schema" yaml:"table_schema,omitempty"`
+	Name    string `json:"table_name,omitempty" csv:"table_name" yaml:"table_name,omitempty"`
+	Type    string `json:"table_type,omitempty" csv:"table_type" yaml:"table_type,omitempty"`
+}
+
+func GetCatalogs(rtcontext *context.RuntimeContext) ([]Catalog, error) {
+	return Sql[Catalog](rtcontext, "SELECT table_catalog AS catalog_name, COUNT(DISTINCT table_schema) AS schemas, COUNT(*) AS tables FROM information_schema.tables GROUP BY table_catalog ORDER BY table_catalog")
+}
+
+func GetCatalogSchemas(rtcontext *context.RuntimeContext, catalog string) ([]Schema, error) {
+	query := fmt.Sprintf("SELECT table_catalog, table_schema, COUNT(*) AS tables FROM information_schema.tables WHERE table_catalog = '%s' GROUP BY table_catalog, table_schema ORDER BY table_schema", escapeSqlString(catalog))
+	schemas, err := Sql[Schema](rtcontext, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(schemas) == 0 {
+		return nil, errors.New(i18n.T("error.catalog_not_found", catalog))
+	}
+	return schemas, nil
+}
+
+func GetCatalogTables(rtcontext *context.RuntimeContext, catalog string, schema string) ([]Table, error) {
+	query := fmt.Sprintf("SELECT table_catalog, table_schema, table_name, table_type FROM information_schema.tables WHERE table_catalog = '%s' AND table_schema = '%s' ORDER BY table_name", escapeSqlString(catalog), escapeSqlString(schema))
+	tables, err := Sql[Table](rtcontext, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, errors.New(i18n.T("error.schema_not_found", fmt.Sprintf("%s.%s", catalog, schema)))
+	}
+	return tables, nil
+}
@@ -18,6 +18,7 @@ package api
 
 import (
 	"bytes"
+	gocontext "context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -77,12 +78,12 @@ func (s *SpiceApiClient) GetAuthUrl(authCode string) string {
 	return fmt.Sprintf("%s/auth/token?code=%s", s.baseUrl, authCode)
 }
 
-func (s *SpiceApiClient) GetAuthContext(accessToken string, orgName *string, appName *string) (SpiceAuthContext, error) {
+func (s *SpiceApiClient) GetAuthContext(ctx gocontext.Context, accessToken string, orgName *string, appName *string) (SpiceAuthContext, error) {
 	var spiceAuthContext SpiceAuthContext
 
 	url := fmt.Sprintf("%s/api/spice-cli/auth?org_name=%s&app_name=%s", s.baseUrl, *orgName, *appName)
 
-	request, err := http.NewRequest("GET", url, nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return spiceAuthContext, err
 	}
@@ -110,7 +111,7 @@ func (s *SpiceApiClient) GetAuthContext(accessToken string, orgName *string, app
 	return spiceAuthContext, nil
 }
 
-func (s *SpiceApiClient) ExchangeCode(authCode string) (AccessTokenResponse, error) {
+func (s *SpiceApiClient) ExchangeCode(ctx gocontext.Context, authCode string) (AccessTokenResponse, error) {
 	var authStatusResponse AccessTokenResponse
 
 	payload := map[string]interface{}{
@@ -122,7 +123,7 @@ func (s *SpiceApiClient) ExchangeCode(authCode string) (AccessTokenResponse, err
 		return authStatusResponse, err
 	}
 
-	request, err := http.NewRequest("POST", fmt.Sprintf("%s/auth/token/exchange", s.baseUrl), bytes.NewReader(jsonBody))
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/auth/token/exchange", s.baseUrl), bytes.NewReader(jsonBody))
 	if err != nil {
 		return authStatusResponse, err
 	}
@@ -16,6 +16,15 @@ limitations under the License.
 
 package api
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+)
+
 type Dataset struct {
 	From                string `json:"from,omitempty" csv:"from" yaml:"from,omitempty"`
 	Name                string `json:"name,omitempty" csv:"name" yaml:"name,omitempty"`
@@ -24,3 +33,42 @@ type Dataset struct {
 	DependsOn           string `json:"depends_on,omitempty" csv:"depends_on" yaml:"depends_on,omitempty"`
 	Status              string `json:"status,omitempty" csv:"status,omitempty" yaml:"status,omitempty"`
 }
+
+// Column describes a dataset column as reported by information_schema.columns.
+type Column struct {
+	Name       string `json:"column_name,omitempty" csv:"column_name" yaml:"column_name,omitempty"`
+	DataType   string `json:"data_type,omitempty" csv:"data_type" yaml:"data_type,omitempty"`
+	IsNullable string `json:"is_nullable,omitempty" csv:"is_nullable" yaml:"is_nullable,omitempty"`
+}
+
+func (c Column) Nullable() bool {
+	return !strings.EqualFold(c.IsNullable, "NO")
+}
+
+// GetDatasetColumns returns the columns of a dataset in ordinal order. The dataset may be
+// qualified with its schema, e.g. "public.taxi_trips".
+func GetDatasetColumns(rtcontext *context.RuntimeContext, dataset string) ([]Column, error) {
+	schema, table := "", dataset
+	if i := strings.LastIndex(dataset, "."); i >= 0 {
+		schema, table = dataset[:i], dataset[i+1:]
+	}
+
+	query := fmt.Sprintf("SELECT column_name, data_type, is_nullable FROM information_schema.columns WHERE table_name = '%s'", escapeSqlString(table))
+	if schema != "" {
+		query += fmt.Sprintf(" AND table_schema = '%s'", escapeSqlString(schema))
+	}
+	query += " ORDER BY ordinal_position"
+
+	columns, err := Sql[Column](rtcontext, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, errors.New(i18n.T("error.dataset_not_found", dataset))
+	}
+	return columns, nil
+}
+
+func escapeSqlString(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
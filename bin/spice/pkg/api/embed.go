@@ -0,0 +1,30 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import "github.com/spiceai/spiceai/bin/spice/pkg/context"
+
+// EmbedRequest embeds either a single text or the first column of a SQL query's results.
+type EmbedRequest struct {
+	Text  string `json:"text,omitempty"`
+	Sql   string `json:"sql,omitempty"`
+	Model string `json:"use,omitempty"`
+}
+
+func Embed(rtcontext *context.RuntimeContext, request EmbedRequest) ([][]float32, error) {
+	return PostRuntimeJson[[][]float32](rtcontext, "/v1/embed", request)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type RuntimeApiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *RuntimeApiError) Error() string {
+	return e.Message
+}
+
+// NewRuntimeApiError builds an error from a failed runtime response, preferring the
+// `message` field of JSON error bodies over the raw body text.
+func NewRuntimeApiError(resp *http.Response) *RuntimeApiError {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || len(strings.TrimSpace(string(body))) == 0 {
+		return &RuntimeApiError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("Runtime request failed: %s", resp.Status),
+		}
+	}
+
+	var messageResponse struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &messageResponse); err == nil && messageResponse.Message != "" {
+		return &RuntimeApiError{
+			StatusCode: resp.StatusCode,
+			Message:    messageResponse.Message,
+		}
+	}
+
+	return &RuntimeApiError{
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+	}
+}
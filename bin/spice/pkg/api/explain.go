@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+// PhysicalPlan returns the physical plan the runtime executes a query with, as printed by
+// EXPLAIN, without running the query.
+func PhysicalPlan(rtcontext *context.RuntimeContext, query string) (string, error) {
+	rows, err := Sql[struct {
+		PlanType string `json:"plan_type"`
+		Plan     string `json:"plan"`
+	}](rtcontext, fmt.Sprintf("EXPLAIN %s", query))
+	if err != nil {
+		return "", err
+	}
+	for _, row := range rows {
+		if row.PlanType == "physical_plan" {
+			return row.Plan, nil
+		}
+	}
+	return "", errors.New("the runtime returned no physical plan")
+}
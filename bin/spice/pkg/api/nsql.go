@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+)
+
+const DEFAULT_NSQL_MODEL = "nql"
+
+type NsqlRequest struct {
+	Query string `json:"query"`
+	Model string `json:"use,omitempty"`
+	// Return the generated SQL instead of running it and returning the result rows.
+	SqlOnly bool `json:"sql_only,omitempty"`
+}
+
+type nsqlSqlResponse struct {
+	Sql string `json:"sql"`
+}
+
+// NsqlGenerateSql asks the runtime to generate the SQL answering a natural-language question
+// with the model, without running it, so it can be confirmed before it runs with Sql.
+func NsqlGenerateSql(rtcontext *context.RuntimeContext, request NsqlRequest) (string, error) {
+	request.SqlOnly = true
+	response, err := PostRuntimeJson[json.RawMessage](rtcontext, "/v1/nsql", request)
+	if err != nil {
+		return "", err
+	}
+	// Runtimes without sql_only ignore it, run the query and return the rows.
+	if trimmed := bytes.TrimSpace(response); len(trimmed) > 0 && trimmed[0] == '[' {
+		return "", fmt.Errorf("The Spice runtime at %s does not return the generated SQL for confirmation, upgrade it to use nsql", rtcontext.HttpEndpoint())
+	}
+
+	var sqlResponse nsqlSqlResponse
+	if err = json.Unmarshal(response, &sqlResponse); err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("error.decoding_response"), err)
+	}
+	if sqlResponse.Sql == "" {
+		return "", errors.New("The model did not generate a query for the question")
+	}
+	return sqlResponse.Sql, nil
+}
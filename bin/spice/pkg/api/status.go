@@ -17,6 +17,8 @@ limitations under the License.
 package api
 
 import (
+	gocontext "context"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -26,6 +28,7 @@ import (
 	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
 )
 
 type ComponentStatus int
@@ -61,30 +64,65 @@ func (cs ComponentStatus) String() string {
 const acceptHeader = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3`
 
 // Get the status of all models and datasets (respectively).
-func GetComponentStatuses(spiced_addr string) (map[string]ComponentStatus, map[string]ComponentStatus, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/metrics", spiced_addr), nil)
-	if err != nil {
+func GetComponentStatuses(rtcontext *context.RuntimeContext, spiced_addr string) (map[string]ComponentStatus, map[string]ComponentStatus, error) {
+	metricFamilies, err := GetMetricFamilies(rtcontext, spiced_addr)
+	if err != nil || metricFamilies == nil {
 		return nil, nil, err
 	}
+	models, datasets := extractComponentStatuses(metricFamilies)
+	return models, datasets, nil
+}
+
+// GetMetricFamilies scrapes the runtime's metrics endpoint within the request timeout. It
+// returns nil without an error when the endpoint is not listening.
+func GetMetricFamilies(rtcontext *context.RuntimeContext, spiced_addr string) (map[string]*dto.MetricFamily, error) {
+	ctx, cancel := rtcontext.RequestContext()
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/metrics", spiced_addr), nil)
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Add("Accept", acceptHeader)
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		if strings.HasSuffix(err.Error(), "connection refused") {
-			return nil, nil, nil
+			return nil, nil
 		}
-		return nil, nil, err
+		if errors.Is(ctx.Err(), gocontext.DeadlineExceeded) && rtcontext.RequestTimeout() > 0 {
+			return nil, rtcontext.RequestTimeoutError()
+		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
 	}
-	metricFamilies, err := parseResponse(resp)
-	if err != nil {
-		return nil, nil, err
+	return parseResponse(resp)
+}
+
+// MetricTotals returns the sample sum and count of a summary or histogram metric
+// for the series carrying the given label value.
+func MetricTotals(mf *dto.MetricFamily, label string, value string) (float64, uint64) {
+	if mf == nil {
+		return 0, 0
 	}
-	models, datasets := extractComponentStatuses(metricFamilies)
-	return models, datasets, nil
+	for _, m := range mf.Metric {
+		for _, lp := range m.Label {
+			if lp.GetName() != label || lp.GetValue() != value {
+				continue
+			}
+			switch {
+			case m.Summary != nil:
+				return m.Summary.GetSampleSum(), m.Summary.GetSampleCount()
+			case m.Histogram != nil:
+				return m.Histogram.GetSampleSum(), m.Histogram.GetSampleCount()
+			}
+		}
+	}
+	return 0, 0
 }
+
 func parseResponse(resp *http.Response) (map[string]*dto.MetricFamily, error) {
 	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
 
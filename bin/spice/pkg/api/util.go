@@ -17,12 +17,20 @@ limitations under the License.
 package api
 
 import (
+	"bytes"
+	gocontext "context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
@@ -31,37 +39,145 @@ const (
 	POST = "POST"
 )
 
-func doRuntimeApiRequest[T interface{}](rtcontext *context.RuntimeContext, method, path string) (T, error) {
+const (
+	CACHE_STATUS_HIT  = "hit"
+	CACHE_STATUS_MISS = "miss"
+)
+
+const (
+	DEFAULT_RETRIES        = context.DEFAULT_RETRIES
+	DEFAULT_RETRY_WAIT_MIN = context.DEFAULT_RETRY_WAIT_MIN
+	DEFAULT_RETRY_WAIT_MAX = context.DEFAULT_RETRY_WAIT_MAX
+)
+
+// getWithRetries performs a GET request, retrying it as many times as rtcontext allows with
+// exponential backoff and jitter. After the last attempt the error or response of that attempt
+// is returned.
+func getWithRetries(ctx gocontext.Context, rtcontext *context.RuntimeContext, httpClient *http.Client, url string) (*http.Response, error) {
+	request, err := retryablehttp.NewRequestWithContext(ctx, GET, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	setApiKey(request.Header, rtcontext.ApiKey())
+
+	client := retryablehttp.NewClient()
+	client.HTTPClient = httpClient
+	client.Logger = nil
+	client.RetryMax = rtcontext.Retries()
+	client.RetryWaitMin, client.RetryWaitMax = rtcontext.RetryWait()
+	client.CheckRetry = retryPolicy
+	client.Backoff = jitterBackoff
+	client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	return client.Do(request)
+}
+
+func post(ctx gocontext.Context, httpClient *http.Client, apiKey string, url string, contentType string, body io.Reader) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, POST, url, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", contentType)
+	setApiKey(request.Header, apiKey)
+	return httpClient.Do(request)
+}
+
+// setApiKey authenticates a request with the API key of the connection profile, if it has one.
+func setApiKey(header http.Header, apiKey string) {
+	if apiKey != "" {
+		header.Set("X-API-Key", apiKey)
+	}
+}
+
+// requestError describes a failed request to the runtime, telling a runtime that is not
+// running and one that did not answer in time apart from other failures.
+func requestError(rtcontext *context.RuntimeContext, ctx gocontext.Context, url string, err error) error {
+	if errors.Is(ctx.Err(), gocontext.DeadlineExceeded) && rtcontext.RequestTimeout() > 0 {
+		return rtcontext.RequestTimeoutError()
+	}
+	if strings.HasSuffix(err.Error(), "connection refused") {
+		return rtcontext.RuntimeUnavailableError()
+	}
+	return fmt.Errorf("%s: %w", i18n.T("error.request_failed", url), err)
+}
+
+// decodingError describes a response that could not be decoded, e.g. because the request
+// timed out while the body was read.
+func decodingError(rtcontext *context.RuntimeContext, ctx gocontext.Context, err error) error {
+	if errors.Is(ctx.Err(), gocontext.DeadlineExceeded) && rtcontext.RequestTimeout() > 0 {
+		return rtcontext.RequestTimeoutError()
+	}
+	return fmt.Errorf("%s: %w", i18n.T("error.decoding_response"), err)
+}
+
+// retryPolicy retries connection errors and the statuses a runtime that is starting, restarting
+// or behind an overloaded proxy answers with. Other errors are not going to go away by retrying.
+func retryPolicy(ctx gocontext.Context, resp *http.Response, err error) (bool, error) {
+	if err != nil {
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, nil
+	}
+	return false, nil
+}
+
+// jitterBackoff doubles the wait with every attempt up to max, and waits a random time between
+// half of it and all of it so many CLIs retrying at once don't hit the runtime in lockstep.
+func jitterBackoff(min time.Duration, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	wait := min
+	for i := 0; i < attemptNum && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+func doRuntimeApiRequest[T interface{}](rtcontext *context.RuntimeContext, method, path string, contentType string, body io.Reader) (T, error) {
+	result, _, err := doRuntimeApiRequestWithHeaders[T](rtcontext, method, path, contentType, body)
+	return result, err
+}
+
+func doRuntimeApiRequestWithHeaders[T interface{}](rtcontext *context.RuntimeContext, method, path string, contentType string, body io.Reader) (T, http.Header, error) {
 	url := fmt.Sprintf("%s%s", rtcontext.HttpEndpoint(), path)
+	httpClient, err := rtcontext.HttpClient()
+	if err != nil {
+		return *new(T), nil, err
+	}
+	ctx, cancel := rtcontext.RequestContext()
+	defer cancel()
+
 	var resp *http.Response
-	var err error
 
 	switch method {
 	case GET:
-		resp, err = http.Get(url)
+		resp, err = getWithRetries(ctx, rtcontext, httpClient, url)
 	case POST:
-		resp, err = http.Post(url, "application/json", nil)
+		resp, err = post(ctx, httpClient, rtcontext.ApiKey(), url, contentType, body)
 	default:
-		return *new(T), fmt.Errorf("Unsupported method: %s", method)
+		return *new(T), nil, fmt.Errorf("Unsupported method: %s", method)
 	}
 
 	if err != nil {
-		if strings.HasSuffix(err.Error(), "connection refused") {
-			return *new(T), rtcontext.RuntimeUnavailableError()
-		}
-		return *new(T), fmt.Errorf("Error performing request to %s: %w", url, err)
+		return *new(T), nil, requestError(rtcontext, ctx, url, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= 400 {
+		return *new(T), nil, NewRuntimeApiError(resp)
+	}
+
 	var result T
 	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return *new(T), fmt.Errorf("Error decoding response: %w", err)
+		return *new(T), nil, decodingError(rtcontext, ctx, err)
 	}
-	return result, nil
+	return result, resp.Header, nil
 }
 
 func GetData[T interface{}](rtcontext *context.RuntimeContext, path string) ([]T, error) {
-	result, err := doRuntimeApiRequest[[]T](rtcontext, GET, path)
+	result, err := doRuntimeApiRequest[[]T](rtcontext, GET, path, "", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -69,12 +185,84 @@ func GetData[T interface{}](rtcontext *context.RuntimeContext, path string) ([]T
 }
 
 func PostRuntime[T interface{}](rtcontext *context.RuntimeContext, path string) (T, error) {
-	return doRuntimeApiRequest[T](rtcontext, POST, path)
+	return doRuntimeApiRequest[T](rtcontext, POST, path, "application/json", nil)
+}
+
+func PostRuntimeJson[T interface{}](rtcontext *context.RuntimeContext, path string, body interface{}) (T, error) {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return *new(T), fmt.Errorf("%s: %w", i18n.T("error.encoding_request"), err)
+	}
+	return doRuntimeApiRequest[T](rtcontext, POST, path, "application/json", bytes.NewReader(bodyBytes))
+}
+
+// Sql runs a query through the runtime's /v1/sql endpoint and decodes each result row into T.
+func Sql[T interface{}](rtcontext *context.RuntimeContext, query string) ([]T, error) {
+	return doRuntimeApiRequest[[]T](rtcontext, POST, "/v1/sql", "text/plain", strings.NewReader(query))
+}
+
+// SqlStream runs a query through the runtime's /v1/sql endpoint and calls onRow with each
+// result row as it is decoded, so large results are never held in memory at once.
+func SqlStream(rtcontext *context.RuntimeContext, query string, onRow func(row json.RawMessage) error) error {
+	url := fmt.Sprintf("%s/v1/sql", rtcontext.HttpEndpoint())
+	httpClient, err := rtcontext.HttpClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := rtcontext.RequestContext()
+	defer cancel()
+
+	resp, err := post(ctx, httpClient, rtcontext.ApiKey(), url, "text/plain", strings.NewReader(query))
+	if err != nil {
+		return requestError(rtcontext, ctx, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return NewRuntimeApiError(resp)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return fmt.Errorf("%s: expected a JSON array of rows", i18n.T("error.decoding_response"))
+	}
+	for decoder.More() {
+		var row json.RawMessage
+		if err = decoder.Decode(&row); err != nil {
+			return decodingError(rtcontext, ctx, err)
+		}
+		if err = onRow(row); err != nil {
+			return err
+		}
+	}
+	if _, err = decoder.Token(); err != nil {
+		return decodingError(rtcontext, ctx, err)
+	}
+
+	return nil
 }
 
-func WriteDataTable[T interface{}](rtcontext *context.RuntimeContext, path string, t T) error {
+// SqlWithCacheStatus runs a query like Sql and also reports whether the runtime served it
+// from its results cache: CACHE_STATUS_HIT, CACHE_STATUS_MISS or "" when caching is disabled.
+func SqlWithCacheStatus[T interface{}](rtcontext *context.RuntimeContext, query string) ([]T, string, error) {
+	result, headers, err := doRuntimeApiRequestWithHeaders[[]T](rtcontext, POST, "/v1/sql", "text/plain", strings.NewReader(query))
+	if err != nil {
+		return nil, "", err
+	}
 
-	items, err := doRuntimeApiRequest[[]T](rtcontext, GET, path)
+	cacheStatus := strings.ToLower(headers.Get("X-Cache"))
+	switch {
+	case strings.HasPrefix(cacheStatus, CACHE_STATUS_HIT):
+		return result, CACHE_STATUS_HIT, nil
+	case strings.HasPrefix(cacheStatus, CACHE_STATUS_MISS):
+		return result, CACHE_STATUS_MISS, nil
+	}
+	return result, "", nil
+}
+
+// WriteDataTable prints the items returned by the runtime for path, of type T, to out.
+func WriteDataTable[T interface{}](rtcontext *context.RuntimeContext, out *util.Output, path string, t T) error {
+	items, err := doRuntimeApiRequest[[]T](rtcontext, GET, path, "", nil)
 
 	if err != nil {
 		return fmt.Errorf("Error fetching runtime information: %w", err)
@@ -85,7 +273,5 @@ func WriteDataTable[T interface{}](rtcontext *context.RuntimeContext, path strin
 		table = append(table, s)
 	}
 
-	util.WriteTable(table)
-
-	return nil
+	return out.WriteTable(table)
 }
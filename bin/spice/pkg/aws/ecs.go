@@ -0,0 +1,316 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/docker"
+)
+
+const (
+	TASK_DEFINITION_FILE_NAME = "ecs-task-definition.json"
+	SERVICE_FILE_NAME         = "ecs-service.json"
+
+	CONTAINER_NAME = "spiceai"
+	// Acceleration files are written below the Spicepod directory, which the EFS volume is mounted over.
+	ACCELERATION_PATH = "/app/.spice/data"
+
+	accelerationVolume = "acceleration"
+)
+
+var ErrFileExists = errors.New("file already exists")
+
+type EcsOptions struct {
+	// Task definition family and service name.
+	Name  string
+	Image string
+	// Fargate task size, e.g. 1024 CPU units and 4096 MiB.
+	Cpu              string
+	Memory           string
+	Region           string
+	Cluster          string
+	ExecutionRoleArn string
+	TaskRoleArn      string
+	EfsFileSystemId  string
+	EfsAccessPointId string
+	// Secrets Manager secrets as NAME=arn, exposed to the runtime as environment variables.
+	Secrets        []string
+	Subnets        []string
+	SecurityGroups []string
+	DesiredCount   int
+	AssignPublicIp bool
+}
+
+type TaskDefinition struct {
+	Family                  string                `json:"family"`
+	RequiresCompatibilities []string              `json:"requiresCompatibilities"`
+	NetworkMode             string                `json:"networkMode"`
+	Cpu                     string                `json:"cpu"`
+	Memory                  string                `json:"memory"`
+	ExecutionRoleArn        string                `json:"executionRoleArn"`
+	TaskRoleArn             string                `json:"taskRoleArn,omitempty"`
+	RuntimePlatform         RuntimePlatform       `json:"runtimePlatform"`
+	ContainerDefinitions    []ContainerDefinition `json:"containerDefinitions"`
+	Volumes                 []Volume              `json:"volumes"`
+}
+
+type RuntimePlatform struct {
+	CpuArchitecture       string `json:"cpuArchitecture"`
+	OperatingSystemFamily string `json:"operatingSystemFamily"`
+}
+
+type ContainerDefinition struct {
+	Name             string           `json:"name"`
+	Image            string           `json:"image"`
+	Essential        bool             `json:"essential"`
+	EntryPoint       []string         `json:"entryPoint"`
+	Command          []string         `json:"command"`
+	WorkingDirectory string           `json:"workingDirectory"`
+	PortMappings     []PortMapping    `json:"portMappings"`
+	Secrets          []Secret         `json:"secrets,omitempty"`
+	MountPoints      []MountPoint     `json:"mountPoints"`
+	LogConfiguration LogConfiguration `json:"logConfiguration"`
+}
+
+type PortMapping struct {
+	Name          string `json:"name"`
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol"`
+}
+
+type Secret struct {
+	Name      string `json:"name"`
+	ValueFrom string `json:"valueFrom"`
+}
+
+type MountPoint struct {
+	SourceVolume  string `json:"sourceVolume"`
+	ContainerPath string `json:"containerPath"`
+	ReadOnly      bool   `json:"readOnly"`
+}
+
+type LogConfiguration struct {
+	LogDriver string            `json:"logDriver"`
+	Options   map[string]string `json:"options"`
+}
+
+type Volume struct {
+	Name                   string                 `json:"name"`
+	EfsVolumeConfiguration EfsVolumeConfiguration `json:"efsVolumeConfiguration"`
+}
+
+type EfsVolumeConfiguration struct {
+	FileSystemId        string                  `json:"fileSystemId"`
+	TransitEncryption   string                  `json:"transitEncryption"`
+	AuthorizationConfig *EfsAuthorizationConfig `json:"authorizationConfig,omitempty"`
+}
+
+type EfsAuthorizationConfig struct {
+	AccessPointId string `json:"accessPointId"`
+	Iam           string `json:"iam"`
+}
+
+type Service struct {
+	ServiceName          string               `json:"serviceName"`
+	Cluster              string               `json:"cluster"`
+	TaskDefinition       string               `json:"taskDefinition"`
+	DesiredCount         int                  `json:"desiredCount"`
+	LaunchType           string               `json:"launchType"`
+	PlatformVersion      string               `json:"platformVersion"`
+	NetworkConfiguration NetworkConfiguration `json:"networkConfiguration"`
+}
+
+type NetworkConfiguration struct {
+	AwsvpcConfiguration AwsvpcConfiguration `json:"awsvpcConfiguration"`
+}
+
+type AwsvpcConfiguration struct {
+	Subnets        []string `json:"subnets"`
+	SecurityGroups []string `json:"securityGroups"`
+	AssignPublicIp string   `json:"assignPublicIp"`
+}
+
+// Placeholder marks a value the options did not provide, to be filled in before registering.
+func Placeholder(name string) string {
+	return fmt.Sprintf("<%s>", name)
+}
+
+func orPlaceholder(value string, name string) string {
+	if value == "" {
+		return Placeholder(name)
+	}
+	return value
+}
+
+// NewTaskDefinition returns a Fargate task definition, the input of aws ecs register-task-definition,
+// running spiced with the same ports as the Helm chart and an EFS volume for acceleration files.
+func NewTaskDefinition(options EcsOptions) (*TaskDefinition, error) {
+	secrets := make([]Secret, 0, len(options.Secrets))
+	for _, secret := range options.Secrets {
+		name, arn, found := strings.Cut(secret, "=")
+		if !found || name == "" || arn == "" {
+			return nil, fmt.Errorf("invalid secret '%s', expected NAME=arn", secret)
+		}
+		secrets = append(secrets, Secret{Name: name, ValueFrom: arn})
+	}
+
+	efs := EfsVolumeConfiguration{
+		FileSystemId:      orPlaceholder(options.EfsFileSystemId, "efs-file-system-id"),
+		TransitEncryption: "ENABLED",
+	}
+	if options.EfsAccessPointId != "" {
+		efs.AuthorizationConfig = &EfsAuthorizationConfig{AccessPointId: options.EfsAccessPointId, Iam: "ENABLED"}
+	}
+
+	return &TaskDefinition{
+		Family:                  options.Name,
+		RequiresCompatibilities: []string{"FARGATE"},
+		NetworkMode:             "awsvpc",
+		Cpu:                     options.Cpu,
+		Memory:                  options.Memory,
+		ExecutionRoleArn:        orPlaceholder(options.ExecutionRoleArn, "execution-role-arn"),
+		TaskRoleArn:             options.TaskRoleArn,
+		RuntimePlatform:         RuntimePlatform{CpuArchitecture: "X86_64", OperatingSystemFamily: "LINUX"},
+		ContainerDefinitions: []ContainerDefinition{{
+			Name:      CONTAINER_NAME,
+			Image:     options.Image,
+			Essential: true,
+			// Set both, so images generated by spice docker init do not get the arguments twice.
+			EntryPoint:       []string{"/usr/local/bin/spiced"},
+			Command:          docker.RuntimeArgs,
+			WorkingDirectory: "/app",
+			PortMappings: []PortMapping{
+				{Name: "http", ContainerPort: 3000, Protocol: "tcp"},
+				{Name: "metrics", ContainerPort: 9000, Protocol: "tcp"},
+				{Name: "flight", ContainerPort: 50051, Protocol: "tcp"},
+			},
+			Secrets:     secrets,
+			MountPoints: []MountPoint{{SourceVolume: accelerationVolume, ContainerPath: ACCELERATION_PATH}},
+			LogConfiguration: LogConfiguration{
+				LogDriver: "awslogs",
+				Options: map[string]string{
+					"awslogs-group":         fmt.Sprintf("/ecs/%s", options.Name),
+					"awslogs-region":        orPlaceholder(options.Region, "region"),
+					"awslogs-stream-prefix": CONTAINER_NAME,
+					"awslogs-create-group":  "true",
+				},
+			},
+		}},
+		Volumes: []Volume{{Name: accelerationVolume, EfsVolumeConfiguration: efs}},
+	}, nil
+}
+
+// NewService returns the input of aws ecs create-service for the task definition family.
+func NewService(options EcsOptions) *Service {
+	subnets := options.Subnets
+	if len(subnets) == 0 {
+		subnets = []string{Placeholder("subnet-id")}
+	}
+	securityGroups := options.SecurityGroups
+	if len(securityGroups) == 0 {
+		securityGroups = []string{Placeholder("security-group-id")}
+	}
+	assignPublicIp := "DISABLED"
+	if options.AssignPublicIp {
+		assignPublicIp = "ENABLED"
+	}
+
+	return &Service{
+		ServiceName:     options.Name,
+		Cluster:         orPlaceholder(options.Cluster, "cluster"),
+		TaskDefinition:  options.Name,
+		DesiredCount:    options.DesiredCount,
+		LaunchType:      "FARGATE",
+		PlatformVersion: "LATEST",
+		NetworkConfiguration: NetworkConfiguration{AwsvpcConfiguration: AwsvpcConfiguration{
+			Subnets:        subnets,
+			SecurityGroups: securityGroups,
+			AssignPublicIp: assignPublicIp,
+		}},
+	}
+}
+
+// WriteEcsFiles writes the task definition and service JSON to dir and returns the files written.
+// Existing files are kept unless force is set.
+func WriteEcsFiles(dir string, taskDefinition *TaskDefinition, service *Service, force bool) ([]string, error) {
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: TASK_DEFINITION_FILE_NAME, value: taskDefinition},
+		{name: SERVICE_FILE_NAME, value: service},
+	}
+
+	if !force {
+		for _, file := range files {
+			path := filepath.Join(dir, file.name)
+			if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("%s: %w", path, ErrFileExists)
+			}
+		}
+	}
+
+	written := make([]string, 0, len(files))
+	for _, file := range files {
+		// Keep placeholders readable instead of escaping their angle brackets.
+		var fileBytes bytes.Buffer
+		encoder := json.NewEncoder(&fileBytes)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(file.value)
+		if err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, file.name)
+		err = os.WriteFile(path, fileBytes.Bytes(), 0644)
+		if err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// Placeholders returns the placeholders left in the generated JSON.
+func Placeholders(taskDefinition *TaskDefinition, service *Service) []string {
+	values := []string{taskDefinition.ExecutionRoleArn}
+	for _, volume := range taskDefinition.Volumes {
+		values = append(values, volume.EfsVolumeConfiguration.FileSystemId)
+	}
+	for _, container := range taskDefinition.ContainerDefinitions {
+		values = append(values, container.LogConfiguration.Options["awslogs-region"])
+	}
+	values = append(values, service.Cluster)
+	values = append(values, service.NetworkConfiguration.AwsvpcConfiguration.Subnets...)
+	values = append(values, service.NetworkConfiguration.AwsvpcConfiguration.SecurityGroups...)
+
+	var placeholders []string
+	for _, value := range values {
+		if strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">") {
+			placeholders = append(placeholders, value)
+		}
+	}
+	return placeholders
+}
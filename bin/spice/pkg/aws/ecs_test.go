@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTaskDefinition(t *testing.T) {
+	options := EcsOptions{
+		Name:             "app",
+		Image:            "spiceai/spiceai:0.13.1-alpha",
+		Cpu:              "1024",
+		Memory:           "4096",
+		Region:           "us-east-1",
+		ExecutionRoleArn: "arn:aws:iam::123456789012:role/ecsTaskExecutionRole",
+		EfsFileSystemId:  "fs-0123",
+		EfsAccessPointId: "fsap-0123",
+		Secrets:          []string{"SPICE_SECRET_PG_PASS=arn:aws:secretsmanager:us-east-1:123456789012:secret:pg"},
+	}
+	taskDefinition, err := NewTaskDefinition(options)
+	assert.NoError(t, err)
+
+	container := taskDefinition.ContainerDefinitions[0]
+	assert.Equal(t, []Secret{{Name: "SPICE_SECRET_PG_PASS", ValueFrom: "arn:aws:secretsmanager:us-east-1:123456789012:secret:pg"}}, container.Secrets)
+	assert.Equal(t, []MountPoint{{SourceVolume: accelerationVolume, ContainerPath: ACCELERATION_PATH}}, container.MountPoints)
+	assert.Equal(t, &EfsAuthorizationConfig{AccessPointId: "fsap-0123", Iam: "ENABLED"}, taskDefinition.Volumes[0].EfsVolumeConfiguration.AuthorizationConfig)
+	assert.Empty(t, Placeholders(taskDefinition, NewService(EcsOptions{Name: "app", Cluster: "spice", Subnets: []string{"subnet-a"}, SecurityGroups: []string{"sg-a"}})))
+
+	_, err = NewTaskDefinition(EcsOptions{Secrets: []string{"SPICE_SECRET_PG_PASS"}})
+	assert.EqualError(t, err, "invalid secret 'SPICE_SECRET_PG_PASS', expected NAME=arn")
+}
+
+func TestWriteEcsFiles(t *testing.T) {
+	dir := t.TempDir()
+	taskDefinition, err := NewTaskDefinition(EcsOptions{Name: "app", Image: "spiceai/spiceai:latest"})
+	assert.NoError(t, err)
+	service := NewService(EcsOptions{Name: "app", DesiredCount: 1})
+	assert.Equal(t, []string{"<execution-role-arn>", "<efs-file-system-id>", "<region>", "<cluster>", "<subnet-id>", "<security-group-id>"}, Placeholders(taskDefinition, service))
+
+	files, err := WriteEcsFiles(dir, taskDefinition, service, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, TASK_DEFINITION_FILE_NAME), filepath.Join(dir, SERVICE_FILE_NAME)}, files)
+
+	serviceBytes, err := os.ReadFile(files[1])
+	assert.NoError(t, err)
+	assert.Contains(t, string(serviceBytes), `"cluster": "<cluster>"`)
+	var parsed Service
+	assert.NoError(t, json.Unmarshal(serviceBytes, &parsed))
+	assert.Equal(t, *service, parsed)
+
+	_, err = WriteEcsFiles(dir, taskDefinition, service, false)
+	assert.ErrorIs(t, err, ErrFileExists)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+const datasetReadyPollInterval = time.Second
+
+// WaitForDataset waits for the runtime to report the dataset as Ready and to answer a
+// probe query, e.g. after the spicepod has been modified and the runtime reloads it.
+func WaitForDataset(rtcontext *context.RuntimeContext, metricsEndpoint string, dataset string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	probe := fmt.Sprintf(`SELECT 1 FROM "%s" LIMIT 1`, dataset)
+
+	var lastErr error
+	for time.Now().Before(deadline) {
+		time.Sleep(datasetReadyPollInterval)
+
+		_, datasetStatuses, err := api.GetComponentStatuses(rtcontext, metricsEndpoint)
+		if err == nil && datasetStatuses != nil {
+			status, ok := datasetStatuses[dataset]
+			if !ok || status != api.Ready {
+				lastErr = fmt.Errorf("dataset %s is %s", dataset, status.String())
+				continue
+			}
+		}
+
+		_, lastErr = api.Sql[map[string]interface{}](rtcontext, probe)
+		if lastErr == nil {
+			return nil
+		}
+	}
+
+	if lastErr != nil {
+		return fmt.Errorf("timed out waiting for dataset %s to be ready: %w", dataset, lastErr)
+	}
+	return fmt.Errorf("timed out waiting for dataset %s to be ready", dataset)
+}
+
+// DefaultDatasetQueries is the query set used when no queries are provided for a dataset.
+func DefaultDatasetQueries(dataset string) []Query {
+	return []Query{
+		{Name: "count", Sql: fmt.Sprintf(`SELECT COUNT(*) FROM "%s"`, dataset)},
+		{Name: "scan_limit_1000", Sql: fmt.Sprintf(`SELECT * FROM "%s" LIMIT 1000`, dataset)},
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	METRIC_ERROR_RATE = "error_rate"
+)
+
+var latencyMetrics = map[string]float64{
+	"min":  0,
+	"p50":  50,
+	"p90":  90,
+	"p95":  95,
+	"p99":  99,
+	"max":  100,
+	"mean": -1,
+}
+
+var assertionPattern = regexp.MustCompile(`^\s*([a-z0-9_]+)\s*(<=|>=|<|>)\s*(\S+)\s*$`)
+
+// Assertion is a performance budget such as `p95<250ms` or `error_rate<0.1%`.
+type Assertion struct {
+	Raw       string
+	Metric    string
+	Operator  string
+	Threshold float64
+	// Set for latency metrics, whose threshold is stored in nanoseconds.
+	IsDuration bool
+}
+
+type AssertionResult struct {
+	Assertion string `json:"assertion"`
+	Scope     string `json:"scope"`
+	Actual    string `json:"actual"`
+	Passed    bool   `json:"passed"`
+}
+
+func ParseAssertion(raw string) (Assertion, error) {
+	matches := assertionPattern.FindStringSubmatch(raw)
+	if matches == nil {
+		return Assertion{}, fmt.Errorf("invalid assertion '%s', expected <metric><op><value> such as p95<250ms", raw)
+	}
+
+	assertion := Assertion{Raw: strings.TrimSpace(raw), Metric: matches[1], Operator: matches[2]}
+	value := matches[3]
+
+	switch {
+	case assertion.Metric == METRIC_ERROR_RATE:
+		percent := strings.HasSuffix(value, "%")
+		threshold, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return Assertion{}, fmt.Errorf("invalid error rate in assertion '%s': %w", raw, err)
+		}
+		if percent {
+			threshold /= 100
+		}
+		assertion.Threshold = threshold
+	default:
+		if _, ok := latencyMetrics[assertion.Metric]; !ok {
+			return Assertion{}, fmt.Errorf("unknown metric '%s' in assertion '%s'", assertion.Metric, raw)
+		}
+		threshold, err := time.ParseDuration(value)
+		if err != nil {
+			return Assertion{}, fmt.Errorf("invalid duration in assertion '%s': %w", raw, err)
+		}
+		assertion.Threshold = float64(threshold)
+		assertion.IsDuration = true
+	}
+
+	return assertion, nil
+}
+
+func ParseAssertions(raw []string) ([]Assertion, error) {
+	assertions := make([]Assertion, 0, len(raw))
+	for _, r := range raw {
+		assertion, err := ParseAssertion(r)
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, assertion)
+	}
+	return assertions, nil
+}
+
+func (a Assertion) holds(actual float64) bool {
+	switch a.Operator {
+	case "<":
+		return actual < a.Threshold
+	case "<=":
+		return actual <= a.Threshold
+	case ">":
+		return actual > a.Threshold
+	case ">=":
+		return actual >= a.Threshold
+	}
+	return false
+}
+
+func (a Assertion) evaluateLatency(scope string, durations []time.Duration) AssertionResult {
+	var actual time.Duration
+	if p := latencyMetrics[a.Metric]; p < 0 {
+		actual = Mean(durations)
+	} else {
+		actual = Percentile(durations, p)
+	}
+	return AssertionResult{Assertion: a.Raw, Scope: scope, Actual: actual.String(), Passed: a.holds(float64(actual))}
+}
+
+func (a Assertion) evaluateRate(scope string, rate float64) AssertionResult {
+	return AssertionResult{Assertion: a.Raw, Scope: scope, Actual: fmt.Sprintf("%.3f%%", rate*100), Passed: a.holds(rate)}
+}
+
+// EvaluateReport checks latency assertions against each query and the error rate across the suite.
+func EvaluateReport(report *Report, assertions []Assertion) []AssertionResult {
+	var results []AssertionResult
+	for _, assertion := range assertions {
+		switch {
+		case assertion.IsDuration:
+			for _, result := range report.Results {
+				if result.Error != "" || len(result.Durations) == 0 {
+					continue
+				}
+				results = append(results, assertion.evaluateLatency(result.Name, result.Durations))
+			}
+		default:
+			failed := 0
+			for _, result := range report.Results {
+				if result.Error != "" {
+					failed++
+				}
+			}
+			rate := 0.0
+			if len(report.Results) > 0 {
+				rate = float64(failed) / float64(len(report.Results))
+			}
+			results = append(results, assertion.evaluateRate(report.Suite, rate))
+		}
+	}
+	return results
+}
+
+func AssertionsPassed(results []AssertionResult) bool {
+	for _, result := range results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+type junitTestSuite struct {
+	XMLName  xml.Name        `xml:"testsuite"`
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// SaveAssertionResults writes the results as JUnit XML when path ends in .xml and as JSON otherwise.
+func SaveAssertionResults(path string, suite string, results []AssertionResult) error {
+	var content []byte
+	var err error
+
+	if strings.EqualFold(filepath.Ext(path), ".xml") {
+		testSuite := junitTestSuite{Name: suite, Tests: len(results)}
+		for _, result := range results {
+			testCase := junitTestCase{Name: fmt.Sprintf("%s %s", result.Scope, result.Assertion), ClassName: suite}
+			if !result.Passed {
+				testSuite.Failures++
+				testCase.Failure = &junitFailure{Message: fmt.Sprintf("expected %s, got %s", result.Assertion, result.Actual)}
+			}
+			testSuite.Cases = append(testSuite.Cases, testCase)
+		}
+		content, err = xml.MarshalIndent(testSuite, "", "  ")
+		content = append([]byte(xml.Header), content...)
+	} else {
+		content, err = json.MarshalIndent(results, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0644)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAssertion(t *testing.T) {
+	a, err := ParseAssertion("p95<250ms")
+	assert.NoError(t, err)
+	assert.Equal(t, "p95", a.Metric)
+	assert.Equal(t, "<", a.Operator)
+	assert.True(t, a.IsDuration)
+	assert.Equal(t, float64(250*time.Millisecond), a.Threshold)
+
+	a, err = ParseAssertion("error_rate <= 0.1%")
+	assert.NoError(t, err)
+	assert.Equal(t, "<=", a.Operator)
+	assert.InDelta(t, 0.001, a.Threshold, 1e-9)
+
+	_, err = ParseAssertion("p42<1s")
+	assert.Error(t, err)
+	_, err = ParseAssertion("p95<fast")
+	assert.Error(t, err)
+}
+
+func TestEvaluateReport(t *testing.T) {
+	report := &Report{
+		Suite: "tpch",
+		Results: []QueryResult{
+			{Name: "q1", Durations: []time.Duration{100 * time.Millisecond, 120 * time.Millisecond}},
+			{Name: "q2", Durations: []time.Duration{300 * time.Millisecond}},
+			{Name: "q3", Error: "boom"},
+		},
+	}
+
+	assertions, err := ParseAssertions([]string{"p95<250ms", "error_rate<50%"})
+	assert.NoError(t, err)
+
+	results := EvaluateReport(report, assertions)
+	assert.Len(t, results, 3)
+	assert.True(t, results[0].Passed)
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "q2", results[1].Scope)
+	assert.True(t, results[2].Passed)
+	assert.False(t, AssertionsPassed(results))
+}
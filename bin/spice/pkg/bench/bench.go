@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+type QueryResult struct {
+	Name       string          `json:"name"`
+	Iterations int             `json:"iterations"`
+	Rows       int             `json:"rows"`
+	Durations  []time.Duration `json:"durations_ns,omitempty"`
+	// Results cache status of each timed iteration, parallel to Durations.
+	CacheStatuses []string `json:"cache_statuses,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+type Report struct {
+	Suite     string        `json:"suite"`
+	Endpoint  string        `json:"endpoint"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration_ns"`
+	Metadata  *RunMetadata  `json:"metadata,omitempty"`
+	Results   []QueryResult `json:"results"`
+}
+
+type CacheSummary struct {
+	Query    string
+	ColdRuns int
+	ColdP50  time.Duration
+	WarmRuns int
+	WarmP50  time.Duration
+	Speedup  string
+}
+
+// QuerySummary is the tabular view of a QueryResult.
+type QuerySummary struct {
+	Query  string
+	Rows   int
+	Min    time.Duration
+	Mean   time.Duration
+	P50    time.Duration
+	P95    time.Duration
+	Max    time.Duration
+	Status string
+}
+
+type RunOptions struct {
+	Iterations int
+	// Number of untimed executions per query before measuring.
+	Warmup int
+	// Called after each query completes, for progress reporting.
+	OnQueryComplete func(result QueryResult)
+}
+
+func Run(rtcontext *context.RuntimeContext, suite string, queries []Query, options RunOptions) *Report {
+	if options.Iterations < 1 {
+		options.Iterations = 1
+	}
+
+	report := &Report{
+		Suite:     suite,
+		Endpoint:  rtcontext.HttpEndpoint(),
+		StartedAt: time.Now().UTC(),
+	}
+
+	for _, query := range queries {
+		result := runQuery(rtcontext, query, options)
+		report.Results = append(report.Results, result)
+		if options.OnQueryComplete != nil {
+			options.OnQueryComplete(result)
+		}
+	}
+
+	report.Duration = time.Since(report.StartedAt)
+	return report
+}
+
+func runQuery(rtcontext *context.RuntimeContext, query Query, options RunOptions) QueryResult {
+	result := QueryResult{Name: query.Name}
+
+	for i := 0; i < options.Warmup; i++ {
+		if _, err := api.Sql[map[string]interface{}](rtcontext, query.Sql); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	for i := 0; i < options.Iterations; i++ {
+		start := time.Now()
+		rows, cacheStatus, err := api.SqlWithCacheStatus[map[string]interface{}](rtcontext, query.Sql)
+		elapsed := time.Since(start)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.Iterations++
+		result.Rows = len(rows)
+		result.Durations = append(result.Durations, elapsed)
+		result.CacheStatuses = append(result.CacheStatuses, cacheStatus)
+	}
+
+	return result
+}
+
+func (r QueryResult) Summary() QuerySummary {
+	summary := QuerySummary{
+		Query:  r.Name,
+		Rows:   r.Rows,
+		Status: "ok",
+	}
+	if r.Error != "" {
+		summary.Status = fmt.Sprintf("error: %s", r.Error)
+	}
+	if len(r.Durations) > 0 {
+		summary.Min = Percentile(r.Durations, 0)
+		summary.Mean = Mean(r.Durations)
+		summary.P50 = Percentile(r.Durations, 50)
+		summary.P95 = Percentile(r.Durations, 95)
+		summary.Max = Percentile(r.Durations, 100)
+	}
+	return summary
+}
+
+// CacheSummary splits a query's timed iterations into results cache misses (cold)
+// and hits (warm).
+func (r QueryResult) CacheSummary() CacheSummary {
+	summary := CacheSummary{Query: r.Name, Speedup: "n/a"}
+
+	var cold, warm []time.Duration
+	for i, duration := range r.Durations {
+		if i >= len(r.CacheStatuses) {
+			break
+		}
+		switch r.CacheStatuses[i] {
+		case api.CACHE_STATUS_HIT:
+			warm = append(warm, duration)
+		case api.CACHE_STATUS_MISS:
+			cold = append(cold, duration)
+		}
+	}
+
+	summary.ColdRuns, summary.WarmRuns = len(cold), len(warm)
+	summary.ColdP50, summary.WarmP50 = Percentile(cold, 50), Percentile(warm, 50)
+	if summary.ColdP50 > 0 && summary.WarmP50 > 0 {
+		summary.Speedup = fmt.Sprintf("%.1fx", float64(summary.ColdP50)/float64(summary.WarmP50))
+	}
+	return summary
+}
+
+func (r *Report) CacheSummaries() []interface{} {
+	summaries := make([]interface{}, 0, len(r.Results))
+	for _, result := range r.Results {
+		if result.Error != "" {
+			continue
+		}
+		summaries = append(summaries, result.CacheSummary())
+	}
+	return summaries
+}
+
+func (r *Report) Summaries() []interface{} {
+	summaries := make([]interface{}, len(r.Results))
+	for i, result := range r.Results {
+		summaries[i] = result.Summary()
+	}
+	return summaries
+}
+
+func (r *Report) Save(path string) error {
+	reportBytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, reportBytes, 0644)
+}
+
+func LoadReport(path string) (*Report, error) {
+	reportBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var report Report
+	err = json.Unmarshal(reportBytes, &report)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing benchmark report '%s': %w", path, err)
+	}
+	return &report, nil
+}
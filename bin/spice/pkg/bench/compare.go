@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"fmt"
+	"time"
+)
+
+type Comparison struct {
+	Query     string
+	Baseline  time.Duration
+	Candidate time.Duration
+	Change    string
+}
+
+// Compare matches queries by name and compares their median durations.
+func Compare(baseline *Report, candidate *Report) []Comparison {
+	baselineResults := make(map[string]QueryResult, len(baseline.Results))
+	for _, result := range baseline.Results {
+		baselineResults[result.Name] = result
+	}
+
+	var comparisons []Comparison
+	for _, result := range candidate.Results {
+		comparison := Comparison{
+			Query:     result.Name,
+			Candidate: Percentile(result.Durations, 50),
+		}
+
+		baselineResult, ok := baselineResults[result.Name]
+		switch {
+		case !ok:
+			comparison.Change = "new"
+		case baselineResult.Error != "" || result.Error != "":
+			comparison.Baseline = Percentile(baselineResult.Durations, 50)
+			comparison.Change = "n/a (error)"
+		default:
+			comparison.Baseline = Percentile(baselineResult.Durations, 50)
+			comparison.Change = formatChange(comparison.Baseline, comparison.Candidate)
+		}
+
+		comparisons = append(comparisons, comparison)
+	}
+
+	return comparisons
+}
+
+func formatChange(baseline time.Duration, candidate time.Duration) string {
+	if baseline == 0 {
+		return "n/a"
+	}
+	change := (float64(candidate) - float64(baseline)) / float64(baseline) * 100
+	return fmt.Sprintf("%+.1f%%", change)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+const (
+	DIFF_STATUS_MATCH    = "match"
+	DIFF_STATUS_MISMATCH = "mismatch"
+	DIFF_STATUS_ERROR    = "error"
+)
+
+type ResultDiff struct {
+	Query             string
+	BaselineRows      int
+	CandidateRows     int
+	BaselineChecksum  string
+	CandidateChecksum string
+	Status            string
+	Detail            string
+}
+
+// LoadQueries reads queries from a directory of .sql files, or from a single file
+// holding one or more statements separated by semicolons.
+func LoadQueries(path string) ([]Query, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading queries from '%s': %w", path, err)
+	}
+	if info.IsDir() {
+		return LoadQueriesFromDir(path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading queries from '%s': %w", path, err)
+	}
+
+	var queries []Query
+	for _, statement := range splitStatements(string(content)) {
+		queries = append(queries, Query{Name: fmt.Sprintf("query_%d", len(queries)+1), Sql: statement})
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("no queries found in '%s'", path)
+	}
+
+	return queries, nil
+}
+
+// splitStatements splits on semicolons outside of quoted strings and drops empty statements.
+func splitStatements(sql string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote rune
+
+	flush := func() {
+		statement := strings.TrimSpace(current.String())
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for _, r := range sql {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ';':
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+
+	return statements
+}
+
+// DiffQueries runs each query against both runtimes and compares row counts and
+// order-insensitive checksums of the results.
+func DiffQueries(baseline *context.RuntimeContext, candidate *context.RuntimeContext, queries []Query) []ResultDiff {
+	diffs := make([]ResultDiff, 0, len(queries))
+	for _, query := range queries {
+		diff := ResultDiff{Query: query.Name, Status: DIFF_STATUS_MATCH}
+
+		baselineRows, baselineErr := api.Sql[map[string]interface{}](baseline, query.Sql)
+		candidateRows, candidateErr := api.Sql[map[string]interface{}](candidate, query.Sql)
+
+		if baselineErr != nil || candidateErr != nil {
+			diff.Status = DIFF_STATUS_ERROR
+			var details []string
+			if baselineErr != nil {
+				details = append(details, fmt.Sprintf("baseline: %s", baselineErr.Error()))
+			}
+			if candidateErr != nil {
+				details = append(details, fmt.Sprintf("candidate: %s", candidateErr.Error()))
+			}
+			diff.Detail = strings.Join(details, "; ")
+			diffs = append(diffs, diff)
+			continue
+		}
+
+		diff.BaselineRows = len(baselineRows)
+		diff.CandidateRows = len(candidateRows)
+		diff.BaselineChecksum = checksumRows(baselineRows)
+		diff.CandidateChecksum = checksumRows(candidateRows)
+
+		switch {
+		case diff.BaselineRows != diff.CandidateRows:
+			diff.Status = DIFF_STATUS_MISMATCH
+			diff.Detail = "row counts differ"
+		case diff.BaselineChecksum != diff.CandidateChecksum:
+			diff.Status = DIFF_STATUS_MISMATCH
+			diff.Detail = "row contents differ"
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	return diffs
+}
+
+func checksumRows(rows []map[string]interface{}) string {
+	encoded := make([]string, 0, len(rows))
+	for _, row := range rows {
+		// encoding/json sorts map keys, so each row has a canonical encoding
+		rowBytes, err := json.Marshal(row)
+		if err != nil {
+			rowBytes = []byte(fmt.Sprintf("%v", row))
+		}
+		encoded = append(encoded, string(rowBytes))
+	}
+	sort.Strings(encoded)
+
+	hash := sha256.New()
+	for _, row := range encoded {
+		hash.Write([]byte(row))
+		hash.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
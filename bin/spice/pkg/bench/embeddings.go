@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+var sampleSentences = []string{
+	"Spice provides a unified SQL interface to materialize, accelerate, and query data from any database, data warehouse, or data lake.",
+	"Datasets can be accelerated locally in Arrow, DuckDB, SQLite or PostgreSQL for low-latency queries.",
+	"Embeddings turn text into vectors so that similar passages land close together in vector space.",
+	"A spicepod is a package of configuration that describes datasets, models and secrets for an application.",
+}
+
+type EmbeddingsOptions struct {
+	Model      string
+	BatchSizes []int
+	Iterations int
+	Warmup     int
+	// Inputs to embed, cycled to fill each batch. Sample sentences are used when empty.
+	Inputs []string
+}
+
+type EmbeddingsResult struct {
+	BatchSize  int             `json:"batch_size"`
+	Dimensions int             `json:"dimensions"`
+	Durations  []time.Duration `json:"durations_ns,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// EmbeddingsSummary is the tabular view of an EmbeddingsResult.
+type EmbeddingsSummary struct {
+	BatchSize    int
+	Dimensions   int
+	P50          time.Duration
+	P95          time.Duration
+	PerEmbedding time.Duration
+	Throughput   string
+	Status       string
+}
+
+// RunEmbeddings measures /v1/embed latency for each batch size. Batches are sent as a
+// VALUES query so the runtime embeds every row of the batch in a single model call.
+func RunEmbeddings(rtcontext *context.RuntimeContext, options EmbeddingsOptions) []EmbeddingsResult {
+	inputs := options.Inputs
+	if len(inputs) == 0 {
+		inputs = sampleSentences
+	}
+
+	results := make([]EmbeddingsResult, 0, len(options.BatchSizes))
+	for _, batchSize := range options.BatchSizes {
+		result := EmbeddingsResult{BatchSize: batchSize}
+		request := api.EmbedRequest{Sql: batchSql(inputs, batchSize), Model: options.Model}
+
+		for i := 0; i < options.Warmup+options.Iterations; i++ {
+			start := time.Now()
+			embeddings, err := api.Embed(rtcontext, request)
+			elapsed := time.Since(start)
+			if err != nil {
+				result.Error = err.Error()
+				break
+			}
+			if len(embeddings) != batchSize {
+				result.Error = fmt.Sprintf("expected %d embeddings, got %d", batchSize, len(embeddings))
+				break
+			}
+			if len(embeddings) > 0 {
+				result.Dimensions = len(embeddings[0])
+			}
+			if i >= options.Warmup {
+				result.Durations = append(result.Durations, elapsed)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func (r EmbeddingsResult) Summary() EmbeddingsSummary {
+	summary := EmbeddingsSummary{BatchSize: r.BatchSize, Dimensions: r.Dimensions, Status: "ok"}
+	if r.Error != "" {
+		summary.Status = r.Error
+		return summary
+	}
+
+	summary.P50 = Percentile(r.Durations, 50)
+	summary.P95 = Percentile(r.Durations, 95)
+	if r.BatchSize > 0 {
+		summary.PerEmbedding = summary.P50 / time.Duration(r.BatchSize)
+	}
+	if mean := Mean(r.Durations); mean > 0 {
+		summary.Throughput = fmt.Sprintf("%.1f/s", float64(r.BatchSize)/mean.Seconds())
+	}
+	return summary
+}
+
+func batchSql(inputs []string, batchSize int) string {
+	values := make([]string, batchSize)
+	for i := range values {
+		values[i] = fmt.Sprintf("('%s')", strings.ReplaceAll(inputs[i%len(inputs)], "'", "''"))
+	}
+	return fmt.Sprintf("SELECT column1 FROM (VALUES %s)", strings.Join(values, ", "))
+}
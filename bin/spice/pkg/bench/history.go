@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+)
+
+const (
+	TREND_STATUS_OK         = "ok"
+	TREND_STATUS_REGRESSION = "regression"
+	TREND_STATUS_IMPROVED   = "improved"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+type RunMetadata struct {
+	RuntimeVersion string `json:"runtime_version,omitempty"`
+	CliVersion     string `json:"cli_version"`
+	Hostname       string `json:"hostname,omitempty"`
+	OS             string `json:"os"`
+	Arch           string `json:"arch"`
+	CPUs           int    `json:"cpus"`
+}
+
+// Trend summarizes the median duration of one query across stored runs.
+type Trend struct {
+	Query    string
+	Runs     int
+	Trend    string
+	Best     time.Duration
+	Previous time.Duration
+	Latest   time.Duration
+	Change   string
+	Status   string
+}
+
+func CollectMetadata(rtcontext *context.RuntimeContext) *RunMetadata {
+	metadata := &RunMetadata{
+		CliVersion: version.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		CPUs:       runtime.NumCPU(),
+	}
+	if runtimeVersion, err := rtcontext.Version(); err == nil {
+		metadata.RuntimeVersion = runtimeVersion
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		metadata.Hostname = hostname
+	}
+	return metadata
+}
+
+func HistoryDir(rtcontext *context.RuntimeContext) string {
+	return filepath.Join(rtcontext.SpiceRuntimeDir(), "benchmarks")
+}
+
+// SaveToHistory stores the report in dir under a name derived from its suite and start time.
+func SaveToHistory(dir string, report *Report) (string, error) {
+	err := os.MkdirAll(dir, 0766)
+	if err != nil {
+		return "", fmt.Errorf("error creating benchmark history directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.json", report.Suite, report.StartedAt.UTC().Format("20060102T150405Z")))
+	return path, report.Save(path)
+}
+
+// LoadHistory returns the stored reports for suite, oldest first.
+func LoadHistory(dir string, suite string) ([]*Report, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var reports []*Report
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), suite+"-") || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		report, err := LoadReport(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if report.Suite == suite {
+			reports = append(reports, report)
+		}
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].StartedAt.Before(reports[j].StartedAt)
+	})
+	return reports, nil
+}
+
+// Trends compares each query's latest median against the previous run. A slowdown of
+// more than threshold percent is reported as a regression.
+func Trends(reports []*Report, threshold float64) []Trend {
+	var names []string
+	medians := make(map[string][]time.Duration)
+	for _, report := range reports {
+		for _, result := range report.Results {
+			if result.Error != "" || len(result.Durations) == 0 {
+				continue
+			}
+			if _, ok := medians[result.Name]; !ok {
+				names = append(names, result.Name)
+			}
+			medians[result.Name] = append(medians[result.Name], Percentile(result.Durations, 50))
+		}
+	}
+
+	trends := make([]Trend, 0, len(names))
+	for _, name := range names {
+		runs := medians[name]
+		trend := Trend{
+			Query:  name,
+			Runs:   len(runs),
+			Trend:  sparkline(runs),
+			Best:   Percentile(runs, 0),
+			Latest: runs[len(runs)-1],
+			Change: "n/a",
+			Status: TREND_STATUS_OK,
+		}
+
+		if len(runs) > 1 {
+			trend.Previous = runs[len(runs)-2]
+			trend.Change = formatChange(trend.Previous, trend.Latest)
+			if trend.Previous > 0 {
+				change := (float64(trend.Latest) - float64(trend.Previous)) / float64(trend.Previous) * 100
+				switch {
+				case change > threshold:
+					trend.Status = TREND_STATUS_REGRESSION
+				case change < -threshold:
+					trend.Status = TREND_STATUS_IMPROVED
+				}
+			}
+		}
+
+		trends = append(trends, trend)
+	}
+
+	return trends
+}
+
+func sparkline(durations []time.Duration) string {
+	min, max := Percentile(durations, 0), Percentile(durations, 100)
+	var line strings.Builder
+	for _, d := range durations {
+		index := 0
+		if max > min {
+			index = int(float64(d-min) / float64(max-min) * float64(len(sparkBlocks)-1))
+		}
+		line.WriteRune(sparkBlocks[index])
+	}
+	return line.String()
+}
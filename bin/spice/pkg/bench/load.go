@@ -0,0 +1,383 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	REQUEST_TYPE_SQL  = "sql"
+	REQUEST_TYPE_NSQL = "nsql"
+	REQUEST_TYPE_HTTP = "http"
+)
+
+type Workload struct {
+	Requests []WorkloadRequest `json:"requests,omitempty" yaml:"requests,omitempty"`
+}
+
+// WorkloadRequest is one entry of a load test script. Requests are picked at random
+// proportionally to their weight.
+type WorkloadRequest struct {
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+	Type   string `json:"type,omitempty" yaml:"type,omitempty"`
+	Weight int    `json:"weight,omitempty" yaml:"weight,omitempty"`
+	Sql    string `json:"sql,omitempty" yaml:"sql,omitempty"`
+	Query  string `json:"query,omitempty" yaml:"query,omitempty"`
+	Model  string `json:"model,omitempty" yaml:"model,omitempty"`
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	Path   string `json:"path,omitempty" yaml:"path,omitempty"`
+	Body   string `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+type LoadTestOptions struct {
+	Concurrency int
+	Duration    time.Duration
+	// Maximum number of requests to send in total, 0 for unlimited.
+	MaxRequests int
+}
+
+type LoadTestReport struct {
+	Endpoint    string                    `json:"endpoint"`
+	Concurrency int                       `json:"concurrency"`
+	Duration    time.Duration             `json:"duration_ns"`
+	Requests    int                       `json:"requests"`
+	Errors      int                       `json:"errors"`
+	Throughput  float64                   `json:"throughput"`
+	Latencies   map[string]*LatencyReport `json:"latencies"`
+	ErrorCounts map[string]int            `json:"error_counts"`
+}
+
+type LatencyReport struct {
+	Requests  int             `json:"requests"`
+	Errors    int             `json:"errors"`
+	Durations []time.Duration `json:"-"`
+	P50       time.Duration   `json:"p50_ns"`
+	P90       time.Duration   `json:"p90_ns"`
+	P99       time.Duration   `json:"p99_ns"`
+	Max       time.Duration   `json:"max_ns"`
+}
+
+type LoadTestSummary struct {
+	Request  string
+	Requests int
+	Errors   int
+	P50      time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+type ErrorSummary struct {
+	Error string
+	Count int
+}
+
+func LoadWorkload(path string) (*Workload, error) {
+	workloadBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var workload Workload
+	err = yaml.Unmarshal(workloadBytes, &workload)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing workload '%s': %w", path, err)
+	}
+
+	if len(workload.Requests) == 0 {
+		return nil, fmt.Errorf("workload '%s' defines no requests", path)
+	}
+
+	for i := range workload.Requests {
+		request := &workload.Requests[i]
+		if request.Type == "" {
+			request.Type = REQUEST_TYPE_SQL
+		}
+		if request.Weight <= 0 {
+			request.Weight = 1
+		}
+		if request.Name == "" {
+			request.Name = fmt.Sprintf("%s_%d", request.Type, i+1)
+		}
+		switch request.Type {
+		case REQUEST_TYPE_SQL:
+			if request.Sql == "" {
+				return nil, fmt.Errorf("request '%s' is missing sql", request.Name)
+			}
+		case REQUEST_TYPE_NSQL:
+			if request.Query == "" {
+				return nil, fmt.Errorf("request '%s' is missing query", request.Name)
+			}
+		case REQUEST_TYPE_HTTP:
+			if request.Path == "" {
+				return nil, fmt.Errorf("request '%s' is missing path", request.Name)
+			}
+			if request.Method == "" {
+				request.Method = http.MethodGet
+			}
+		default:
+			return nil, fmt.Errorf("request '%s' has unsupported type '%s', expected sql, nsql or http", request.Name, request.Type)
+		}
+	}
+
+	return &workload, nil
+}
+
+// RunLoad drives the workload against the runtime with the given concurrency until the
+// duration elapses, MaxRequests have been sent or the runtime context's Go context ends.
+func RunLoad(rtcontext *context.RuntimeContext, workload *Workload, options LoadTestOptions) (*LoadTestReport, error) {
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+
+	client, err := loadClient(rtcontext, options.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	totalWeight := 0
+	for _, request := range workload.Requests {
+		totalWeight += request.Weight
+	}
+
+	report := &LoadTestReport{
+		Endpoint:    rtcontext.HttpEndpoint(),
+		Concurrency: options.Concurrency,
+		Latencies:   make(map[string]*LatencyReport),
+		ErrorCounts: make(map[string]int),
+	}
+	for _, request := range workload.Requests {
+		report.Latencies[request.Name] = &LatencyReport{}
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	sent := 0
+	deadline := time.Now().Add(options.Duration)
+	start := time.Now()
+
+	for worker := 0; worker < options.Concurrency; worker++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for time.Now().Before(deadline) && rtcontext.Context().Err() == nil {
+				mutex.Lock()
+				if options.MaxRequests > 0 && sent >= options.MaxRequests {
+					mutex.Unlock()
+					return
+				}
+				sent++
+				mutex.Unlock()
+
+				request := pickRequest(workload.Requests, totalWeight, rng)
+				requestStart := time.Now()
+				err := sendWorkloadRequest(rtcontext, client, request)
+				elapsed := time.Since(requestStart)
+
+				mutex.Lock()
+				latency := report.Latencies[request.Name]
+				latency.Requests++
+				report.Requests++
+				if err != nil {
+					latency.Errors++
+					report.Errors++
+					report.ErrorCounts[classifyError(err)]++
+				} else {
+					latency.Durations = append(latency.Durations, elapsed)
+				}
+				mutex.Unlock()
+			}
+		}(time.Now().UnixNano() + int64(worker))
+	}
+
+	wg.Wait()
+	report.Duration = time.Since(start)
+	if report.Duration > 0 {
+		report.Throughput = float64(report.Requests) / report.Duration.Seconds()
+	}
+
+	for _, latency := range report.Latencies {
+		latency.P50 = Percentile(latency.Durations, 50)
+		latency.P90 = Percentile(latency.Durations, 90)
+		latency.P99 = Percentile(latency.Durations, 99)
+		latency.Max = Percentile(latency.Durations, 100)
+	}
+
+	return report, nil
+}
+
+// loadClient returns the runtime context's client with a connection pool sized for the workers,
+// so connections are reused rather than reopened for each request.
+func loadClient(rtcontext *context.RuntimeContext, concurrency int) (*http.Client, error) {
+	client, err := rtcontext.HttpClient()
+	if err != nil {
+		return nil, err
+	}
+	transport, ok := client.Transport.(*http.Transport)
+	if client.Transport == nil {
+		transport, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if !ok {
+		return client, nil
+	}
+	transport = transport.Clone()
+	transport.MaxIdleConns = concurrency
+	transport.MaxIdleConnsPerHost = concurrency
+	return &http.Client{Transport: transport}, nil
+}
+
+func (r *LoadTestReport) Summaries() []interface{} {
+	names := make([]string, 0, len(r.Latencies))
+	for name := range r.Latencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	summaries := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		latency := r.Latencies[name]
+		summaries = append(summaries, LoadTestSummary{
+			Request:  name,
+			Requests: latency.Requests,
+			Errors:   latency.Errors,
+			P50:      latency.P50,
+			P90:      latency.P90,
+			P99:      latency.P99,
+			Max:      latency.Max,
+		})
+	}
+	return summaries
+}
+
+func (r *LoadTestReport) ErrorSummaries() []interface{} {
+	var summaries []interface{}
+	for errorClass, count := range r.ErrorCounts {
+		summaries = append(summaries, ErrorSummary{Error: errorClass, Count: count})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].(ErrorSummary).Count > summaries[j].(ErrorSummary).Count
+	})
+	return summaries
+}
+
+func pickRequest(requests []WorkloadRequest, totalWeight int, rng *rand.Rand) WorkloadRequest {
+	n := rng.Intn(totalWeight)
+	for _, request := range requests {
+		if n < request.Weight {
+			return request
+		}
+		n -= request.Weight
+	}
+	return requests[len(requests)-1]
+}
+
+type httpStatusError struct {
+	statusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d", e.statusCode)
+}
+
+func sendWorkloadRequest(rtcontext *context.RuntimeContext, client *http.Client, request WorkloadRequest) error {
+	var method, path, contentType string
+	var body []byte
+	switch request.Type {
+	case REQUEST_TYPE_SQL:
+		method, path, contentType, body = http.MethodPost, "/v1/sql", "text/plain", []byte(request.Sql)
+	case REQUEST_TYPE_NSQL:
+		model := request.Model
+		if model == "" {
+			model = api.DEFAULT_NSQL_MODEL
+		}
+		nsqlBody, err := json.Marshal(api.NsqlRequest{Query: request.Query, Model: model})
+		if err != nil {
+			return err
+		}
+		method, path, contentType, body = http.MethodPost, "/v1/nsql", "application/json", nsqlBody
+	default:
+		method, path, contentType, body = request.Method, request.Path, "application/json", []byte(request.Body)
+	}
+
+	ctx, cancel := rtcontext.RequestContext()
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, rtcontext.HttpEndpoint()+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if apiKey := rtcontext.ApiKey(); apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Read the full body so the timing includes the transfer of the results.
+	_, err = io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return &httpStatusError{statusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+func classifyError(err error) string {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Error()
+	}
+
+	var netErr net.Error
+	if errors.Is(err, gocontext.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "timeout"
+	}
+
+	if strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection reset") {
+		return "connection"
+	}
+
+	return "other"
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunLoadNsqlBody(t *testing.T) {
+	dotSpiceDir := testutils.EnsureTestSpiceDirectory(t)
+
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("[]"))
+	}))
+	t.Cleanup(server.Close)
+
+	rtcontext := context.NewContextWithDotSpiceDir(dotSpiceDir)
+	rtcontext.SetHttpEndpoint(server.URL)
+	query := "fares \x00\a\v over \xff 10"
+	workload := &Workload{Requests: []WorkloadRequest{{Name: "nsql", Type: REQUEST_TYPE_NSQL, Weight: 1, Query: query}}}
+
+	report, err := RunLoad(rtcontext, workload, LoadTestOptions{Concurrency: 1, Duration: time.Minute, MaxRequests: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Requests)
+	assert.Equal(t, 0, report.Errors)
+
+	var request api.NsqlRequest
+	assert.NoError(t, json.Unmarshal(body, &request), "invalid JSON: %s", body)
+	assert.Equal(t, "fares \x00\a\v over � 10", request.Query)
+	assert.Equal(t, api.DEFAULT_NSQL_MODEL, request.Model)
+}
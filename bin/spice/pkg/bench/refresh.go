@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+const (
+	REFRESH_MODE_FULL   = "full"
+	REFRESH_MODE_APPEND = "append"
+
+	fullRefreshMetric   = "load_dataset_duration_ms"
+	appendRefreshMetric = "append_dataset_duration_ms"
+
+	refreshPollInterval = 250 * time.Millisecond
+)
+
+// RefreshProfile breaks a single acceleration refresh down into the time spent fetching
+// from the source and the remainder spent writing to the accelerator.
+type RefreshProfile struct {
+	Run        int
+	Mode       string
+	Rows       int64
+	Fetch      time.Duration
+	Write      time.Duration
+	Total      time.Duration
+	Throughput string
+}
+
+type refreshSnapshot struct {
+	fullSum, appendSum     float64
+	fullCount, appendCount uint64
+}
+
+// ProfileRefresh triggers a refresh of the dataset and waits for it to complete, using the
+// runtime's refresh duration metrics to tell which mode ran and how long fetching took.
+func ProfileRefresh(rtcontext *context.RuntimeContext, metricsEndpoint string, dataset string, timeout time.Duration) (*RefreshProfile, error) {
+	before, err := snapshotRefreshMetrics(rtcontext, metricsEndpoint, dataset)
+	if err != nil {
+		return nil, err
+	}
+	rowsBefore, err := countRows(rtcontext, dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	_, err = api.PostRuntime[map[string]interface{}](rtcontext, fmt.Sprintf("/v1/datasets/%s/acceleration/refresh", dataset))
+	if err != nil {
+		return nil, fmt.Errorf("error triggering refresh of dataset %s: %w", dataset, err)
+	}
+
+	deadline := start.Add(timeout)
+	var after *refreshSnapshot
+	for {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the refresh of dataset %s to complete", dataset)
+		}
+		time.Sleep(refreshPollInterval)
+
+		if after == nil {
+			snapshot, err := snapshotRefreshMetrics(rtcontext, metricsEndpoint, dataset)
+			if err != nil {
+				return nil, err
+			}
+			if snapshot.fullCount > before.fullCount || snapshot.appendCount > before.appendCount {
+				after = snapshot
+			} else {
+				continue
+			}
+		}
+
+		_, datasetStatuses, err := api.GetComponentStatuses(rtcontext, metricsEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		if status, ok := datasetStatuses[dataset]; ok && status == api.Refreshing {
+			continue
+		}
+		break
+	}
+
+	profile := &RefreshProfile{Total: time.Since(start), Mode: REFRESH_MODE_FULL}
+	fetchMs := after.fullSum - before.fullSum
+	if after.appendCount > before.appendCount {
+		profile.Mode = REFRESH_MODE_APPEND
+		fetchMs = after.appendSum - before.appendSum
+	}
+	profile.Fetch = time.Duration(fetchMs * float64(time.Millisecond))
+	if profile.Fetch < profile.Total {
+		profile.Write = profile.Total - profile.Fetch
+	}
+
+	rowsAfter, err := countRows(rtcontext, dataset)
+	if err != nil {
+		return nil, err
+	}
+	profile.Rows = rowsAfter
+	if profile.Mode == REFRESH_MODE_APPEND {
+		profile.Rows = rowsAfter - rowsBefore
+	}
+	if profile.Total > 0 {
+		profile.Throughput = fmt.Sprintf("%.0f rows/s", float64(profile.Rows)/profile.Total.Seconds())
+	}
+
+	return profile, nil
+}
+
+func snapshotRefreshMetrics(rtcontext *context.RuntimeContext, metricsEndpoint string, dataset string) (*refreshSnapshot, error) {
+	metricFamilies, err := api.GetMetricFamilies(rtcontext, metricsEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error reading runtime metrics: %w", err)
+	}
+	if metricFamilies == nil {
+		return nil, fmt.Errorf("the runtime metrics endpoint %s is unavailable, start the runtime with --metrics to profile refreshes", metricsEndpoint)
+	}
+
+	snapshot := &refreshSnapshot{}
+	snapshot.fullSum, snapshot.fullCount = api.MetricTotals(metricFamilies[fullRefreshMetric], "dataset", dataset)
+	snapshot.appendSum, snapshot.appendCount = api.MetricTotals(metricFamilies[appendRefreshMetric], "dataset", dataset)
+	return snapshot, nil
+}
+
+func countRows(rtcontext *context.RuntimeContext, dataset string) (int64, error) {
+	rows, err := api.Sql[map[string]int64](rtcontext, fmt.Sprintf(`SELECT COUNT(*) AS count FROM "%s"`, dataset))
+	if err != nil {
+		return 0, fmt.Errorf("error counting rows of dataset %s: %w", dataset, err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0]["count"], nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	durations := []time.Duration{
+		5 * time.Millisecond,
+		1 * time.Millisecond,
+		4 * time.Millisecond,
+		2 * time.Millisecond,
+		3 * time.Millisecond,
+	}
+
+	assert.Equal(t, time.Duration(0), Percentile(nil, 50))
+	assert.Equal(t, 1*time.Millisecond, Percentile(durations, 0))
+	assert.Equal(t, 3*time.Millisecond, Percentile(durations, 50))
+	assert.Equal(t, 5*time.Millisecond, Percentile(durations, 95))
+	assert.Equal(t, 5*time.Millisecond, Percentile(durations, 100))
+	assert.Equal(t, 3*time.Millisecond, Mean(durations))
+
+	// Input order is preserved
+	assert.Equal(t, 5*time.Millisecond, durations[0])
+}
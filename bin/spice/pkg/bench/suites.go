@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bench
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed queries
+var bundledQueries embed.FS
+
+type Query struct {
+	Name string `json:"name"`
+	Sql  string `json:"sql"`
+}
+
+type Suite struct {
+	Name   string
+	Tables []string
+	// Directory under queries/ holding the bundled query set, if any.
+	bundledDir string
+}
+
+var suites = map[string]Suite{
+	"tpch": {
+		Name:       "tpch",
+		Tables:     []string{"customer", "lineitem", "nation", "orders", "part", "partsupp", "region", "supplier"},
+		bundledDir: "queries/tpch",
+	},
+	"tpcds": {
+		Name: "tpcds",
+		Tables: []string{
+			"call_center", "catalog_page", "catalog_returns", "catalog_sales", "customer", "customer_address",
+			"customer_demographics", "date_dim", "household_demographics", "income_band", "inventory", "item",
+			"promotion", "reason", "ship_mode", "store", "store_returns", "store_sales", "time_dim", "warehouse",
+			"web_page", "web_returns", "web_sales", "web_site",
+		},
+	},
+}
+
+func GetSuite(name string) (Suite, error) {
+	suite, ok := suites[strings.ToLower(name)]
+	if !ok {
+		return Suite{}, fmt.Errorf("unknown benchmark suite '%s', expected one of: %s", name, strings.Join(SuiteNames(), ", "))
+	}
+	return suite, nil
+}
+
+func SuiteNames() []string {
+	names := make([]string, 0, len(suites))
+	for name := range suites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Queries returns the suite's query set, read from queriesDir when provided and
+// from the queries bundled with the CLI otherwise.
+func (s Suite) Queries(queriesDir string) ([]Query, error) {
+	if queriesDir != "" {
+		return LoadQueriesFromDir(queriesDir)
+	}
+
+	if s.bundledDir == "" {
+		return nil, fmt.Errorf("the %s query set is not bundled with the Spice CLI, provide the queries with --queries-dir", s.Name)
+	}
+
+	entries, err := bundledQueries.ReadDir(s.bundledDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []Query
+	for _, entry := range entries {
+		sql, err := bundledQueries.ReadFile(filepath.ToSlash(filepath.Join(s.bundledDir, entry.Name())))
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, Query{
+			Name: fmt.Sprintf("%s_%s", s.Name, strings.TrimSuffix(entry.Name(), ".sql")),
+			Sql:  string(sql),
+		})
+	}
+
+	sortQueries(queries)
+	return queries, nil
+}
+
+// LoadQueriesFromDir reads every .sql file in dir as a single query named after the file.
+func LoadQueriesFromDir(dir string) ([]Query, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading queries from '%s': %w", dir, err)
+	}
+
+	var queries []Query
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		sql, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, Query{
+			Name: strings.TrimSuffix(entry.Name(), ".sql"),
+			Sql:  string(sql),
+		})
+	}
+
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("no .sql files found in '%s'", dir)
+	}
+
+	sortQueries(queries)
+	return queries, nil
+}
+
+// sortQueries orders queries so that q2 sorts before q10.
+func sortQueries(queries []Query) {
+	sort.SliceStable(queries, func(i, j int) bool {
+		a, b := queryNumber(queries[i].Name), queryNumber(queries[j].Name)
+		if a != b {
+			return a < b
+		}
+		return queries[i].Name < queries[j].Name
+	})
+}
+
+func queryNumber(name string) int {
+	digits := strings.TrimLeftFunc(name, func(r rune) bool { return r < '0' || r > '9' })
+	end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' })
+	if end >= 0 {
+		digits = digits[:end]
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/timing"
+	"gopkg.in/yaml.v2"
+)
+
+const ConfigFileName = "config.yaml"
+
+const (
+	RUNTIME_FLAVOR_NATIVE = "native"
+	RUNTIME_FLAVOR_DOCKER = "docker"
+)
+
+// RegistryConfig is a private Spicepod registry, referenced in dependencies as <name>:<org>/<pod>.
+type RegistryConfig struct {
+	Name     string `json:"name" csv:"name" yaml:"name"`
+	Endpoint string `json:"endpoint" csv:"endpoint" yaml:"endpoint"`
+	Token    string `json:"token,omitempty" csv:"-" yaml:"token,omitempty"`
+	// TokenEnv names an environment variable holding the token, so it is not stored in the config.
+	TokenEnv string `json:"token_env,omitempty" csv:"token_env" yaml:"token_env,omitempty"`
+	// Default registries serve dependencies without a registry prefix in place of spicerack.org.
+	Default bool `json:"default,omitempty" csv:"default" yaml:"default,omitempty"`
+}
+
+// ProfileConfig is a named runtime connection, selected with spice profile use.
+type ProfileConfig struct {
+	Name     string `json:"name" csv:"name" yaml:"name"`
+	Endpoint string `json:"endpoint" csv:"endpoint" yaml:"endpoint"`
+	// Arrow Flight endpoint passed to plugins, the local runtime's when empty.
+	FlightEndpoint string `json:"flight_endpoint,omitempty" csv:"flight_endpoint" yaml:"flight_endpoint,omitempty"`
+	// PEM certificate trusted in addition to the system roots, e.g. a private CA of an https endpoint.
+	TlsCert string `json:"tls_cert,omitempty" csv:"tls_cert" yaml:"tls_cert,omitempty"`
+	// Sent as X-API-Key with each request to the runtime.
+	ApiKey string `json:"api_key,omitempty" csv:"-" yaml:"api_key,omitempty"`
+}
+
+// CliConfig is the spice CLI configuration read from ~/.spice/config.yaml.
+type CliConfig struct {
+	Registries []RegistryConfig `json:"registries,omitempty" yaml:"registries,omitempty"`
+	// How spice run starts the runtime, RUNTIME_FLAVOR_NATIVE when empty.
+	RuntimeFlavor string `json:"runtime_flavor,omitempty" yaml:"runtime_flavor,omitempty"`
+	// Usage telemetry is only recorded after opting in with spice setup.
+	TelemetryEnabled bool `json:"telemetry_enabled,omitempty" yaml:"telemetry_enabled,omitempty"`
+	// Locale of CLI messages, e.g. de. Taken from LC_ALL, LC_MESSAGES or LANG when empty.
+	Locale string `json:"locale,omitempty" yaml:"locale,omitempty"`
+	// Always use the output of --accessible.
+	Accessible bool `json:"accessible,omitempty" yaml:"accessible,omitempty"`
+	// Runtime connections, the one named CurrentProfile is used in place of the local runtime.
+	Profiles       []ProfileConfig `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+	CurrentProfile string          `json:"current_profile,omitempty" yaml:"current_profile,omitempty"`
+}
+
+// ConfigPath returns the CLI configuration file in the .spice directory dotSpiceDir.
+func ConfigPath(dotSpiceDir string) string {
+	return filepath.Join(dotSpiceDir, ConfigFileName)
+}
+
+// Exists reports whether the CLI configuration file has been written, e.g. by spice setup.
+func Exists(dotSpiceDir string) bool {
+	_, err := os.Stat(ConfigPath(dotSpiceDir))
+	return err == nil
+}
+
+// Load reads the CLI configuration from dotSpiceDir. A missing config file yields an empty config.
+func Load(dotSpiceDir string) (*CliConfig, error) {
+	configPath := ConfigPath(dotSpiceDir)
+	defer timing.Start(timing.PHASE_CONFIG, configPath)()
+
+	config := &CliConfig{}
+	configBytes, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(configBytes, config)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", configPath, err)
+	}
+
+	return config, nil
+}
+
+// Save writes the configuration to dotSpiceDir, readable only by the current user, as it may
+// hold tokens.
+func (c *CliConfig) Save(dotSpiceDir string) error {
+	configPath := ConfigPath(dotSpiceDir)
+	err := os.MkdirAll(filepath.Dir(configPath), 0700)
+	if err != nil {
+		return err
+	}
+
+	configBytes, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, configBytes, 0600)
+}
+
+func (c *CliConfig) GetRegistry(name string) *RegistryConfig {
+	for i := range c.Registries {
+		if c.Registries[i].Name == name {
+			return &c.Registries[i]
+		}
+	}
+	return nil
+}
+
+// DefaultRegistry returns the registry marked as default, or nil to use spicerack.org.
+func (c *CliConfig) DefaultRegistry() *RegistryConfig {
+	for i := range c.Registries {
+		if c.Registries[i].Default {
+			return &c.Registries[i]
+		}
+	}
+	return nil
+}
+
+// SetRegistry adds or replaces the registry with the same name. At most one registry is the default.
+func (c *CliConfig) SetRegistry(registry RegistryConfig) {
+	if registry.Default {
+		for i := range c.Registries {
+			c.Registries[i].Default = false
+		}
+	}
+
+	if existing := c.GetRegistry(registry.Name); existing != nil {
+		*existing = registry
+		return
+	}
+	c.Registries = append(c.Registries, registry)
+}
+
+func (c *CliConfig) RemoveRegistry(name string) bool {
+	for i := range c.Registries {
+		if c.Registries[i].Name == name {
+			c.Registries = append(c.Registries[:i], c.Registries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CliConfig) GetProfile(name string) *ProfileConfig {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			return &c.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// SetProfile adds or replaces the profile with the same name.
+func (c *CliConfig) SetProfile(profile ProfileConfig) {
+	if existing := c.GetProfile(profile.Name); existing != nil {
+		*existing = profile
+		return
+	}
+	c.Profiles = append(c.Profiles, profile)
+}
+
+func (c *CliConfig) RemoveProfile(name string) bool {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			c.Profiles = append(c.Profiles[:i], c.Profiles[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveProfile returns the profile selected with spice profile use, or nil for the local runtime.
+func (c *CliConfig) ActiveProfile() *ProfileConfig {
+	if c.CurrentProfile == "" {
+		return nil
+	}
+	return c.GetProfile(c.CurrentProfile)
+}
+
+// GetToken returns the registry token, preferring the environment variable named by TokenEnv.
+func (r *RegistryConfig) GetToken() string {
+	if r.TokenEnv != "" {
+		if token := os.Getenv(r.TokenEnv); token != "" {
+			return token
+		}
+	}
+	return r.Token
+}
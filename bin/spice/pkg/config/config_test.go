@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetRegistry(t *testing.T) {
+	config := &CliConfig{}
+	config.SetRegistry(RegistryConfig{Name: "a", Endpoint: "https://a.example.com", Default: true})
+	config.SetRegistry(RegistryConfig{Name: "b", Endpoint: "https://b.example.com"})
+	assert.Equal(t, "a", config.DefaultRegistry().Name)
+
+	config.SetRegistry(RegistryConfig{Name: "b", Endpoint: "https://b2.example.com", Default: true})
+	assert.Len(t, config.Registries, 2)
+	assert.Equal(t, "https://b2.example.com", config.GetRegistry("b").Endpoint)
+	assert.Equal(t, "b", config.DefaultRegistry().Name)
+	assert.False(t, config.GetRegistry("a").Default)
+
+	assert.True(t, config.RemoveRegistry("b"))
+	assert.False(t, config.RemoveRegistry("b"))
+	assert.Nil(t, config.DefaultRegistry())
+}
+
+func TestSetProfile(t *testing.T) {
+	config := &CliConfig{}
+	assert.Nil(t, config.ActiveProfile())
+
+	config.SetProfile(ProfileConfig{Name: "staging", Endpoint: "https://staging.example.com"})
+	config.SetProfile(ProfileConfig{Name: "prod", Endpoint: "https://prod.example.com"})
+	config.CurrentProfile = "prod"
+	assert.Equal(t, "https://prod.example.com", config.ActiveProfile().Endpoint)
+
+	config.SetProfile(ProfileConfig{Name: "prod", Endpoint: "https://prod2.example.com", ApiKey: "key"})
+	assert.Len(t, config.Profiles, 2)
+	assert.Equal(t, "https://prod2.example.com", config.ActiveProfile().Endpoint)
+
+	config.CurrentProfile = "missing"
+	assert.Nil(t, config.ActiveProfile())
+}
+
+func TestGetToken(t *testing.T) {
+	registry := RegistryConfig{Name: "a", Token: "stored", TokenEnv: "SPICE_TEST_REGISTRY_TOKEN"}
+	assert.Equal(t, "stored", registry.GetToken())
+
+	t.Setenv("SPICE_TEST_REGISTRY_TOKEN", "from-env")
+	assert.Equal(t, "from-env", registry.GetToken())
+}
+
+func TestExists(t *testing.T) {
+	dotSpiceDir := filepath.Join(t.TempDir(), ".spice")
+	assert.False(t, Exists(dotSpiceDir))
+
+	err := (&CliConfig{RuntimeFlavor: RUNTIME_FLAVOR_DOCKER}).Save(dotSpiceDir)
+	assert.NoError(t, err)
+	assert.True(t, Exists(dotSpiceDir))
+
+	config, err := Load(dotSpiceDir)
+	assert.NoError(t, err)
+	assert.Equal(t, RUNTIME_FLAVOR_DOCKER, config.RuntimeFlavor)
+}
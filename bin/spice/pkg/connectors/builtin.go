@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectors
+
+// Builtin lists the connectors shipped with this release of the runtime, sorted by name. The
+// runtime doesn't report its connectors, so this catalog is kept in step with it by hand.
+var Builtin = []Connector{
+	{
+		Name:        "clickhouse",
+		Description: "ClickHouse tables",
+		From:        "clickhouse:my_db.my_table",
+		Parameters: []Parameter{
+			{Name: "clickhouse_connection_string", Secret: true, Description: "Connection string, instead of host, port, user and password"},
+			{Name: "clickhouse_host", Description: "Host name"},
+			{Name: "clickhouse_tcp_port", Default: "9000", Description: "Native protocol port"},
+			{Name: "clickhouse_db", Description: "Database"},
+			{Name: "clickhouse_user", Description: "User"},
+			{Name: "clickhouse_pass", Secret: true, Description: "Password"},
+			{Name: "clickhouse_secure", Default: "true", Description: "Connect using TLS"},
+			{Name: "clickhouse_connection_timeout", Description: "Connection timeout in milliseconds"},
+		},
+	},
+	{
+		Name:        "databricks",
+		Description: "Databricks Delta tables through Spark Connect or Delta Lake",
+		From:        "databricks:spiceai.datasets.my_table",
+		Parameters: []Parameter{
+			{Name: "endpoint", Required: true, Description: "Databricks workspace endpoint, e.g. dbc-a1b2345c-d6e7.cloud.databricks.com"},
+			{Name: "mode", Default: "spark_connect", Description: "spark_connect or delta_lake"},
+			{Name: "databricks_cluster_id", Description: "Cluster ID, required for spark_connect mode"},
+			{Name: "databricks_use_ssl", Default: "true", Description: "Connect using TLS"},
+			{Name: "token", Required: true, Secret: true, Description: "Databricks personal access token"},
+		},
+	},
+	{
+		Name:        "dremio",
+		Description: "Dremio datasets over Arrow Flight",
+		From:        "dremio:datasets.my_table",
+		Parameters: []Parameter{
+			{Name: "endpoint", Required: true, Description: "Dremio Flight endpoint, e.g. grpc://127.0.0.1:32010"},
+			{Name: "username", Secret: true, Description: "Dremio user"},
+			{Name: "password", Secret: true, Description: "Dremio password"},
+		},
+	},
+	{
+		Name:        "duckdb",
+		Description: "DuckDB tables and table functions",
+		From:        "duckdb:database.schema.table",
+		Parameters: []Parameter{
+			{Name: "open", Required: true, Description: "Path to the DuckDB database file"},
+		},
+	},
+	{
+		Name:        "file",
+		Description: "Parquet and CSV files on the local filesystem",
+		From:        "file:data/my_file.parquet",
+		Parameters: []Parameter{
+			{Name: "file_format", Description: "parquet or csv, detected from the file extension when not set"},
+		},
+	},
+	{
+		Name:        "flightsql",
+		Description: "Any Arrow Flight SQL server",
+		From:        "flightsql:my_table",
+		Parameters: []Parameter{
+			{Name: "endpoint", Required: true, Description: "Flight SQL endpoint, e.g. grpc://127.0.0.1:50051"},
+			{Name: "username", Secret: true, Description: "User"},
+			{Name: "password", Secret: true, Description: "Password"},
+		},
+	},
+	{
+		Name:        "ftp",
+		Description: "Parquet and CSV files on an FTP server",
+		From:        "ftp://remote-ftp-server.com/path/to/folder/",
+		Parameters: []Parameter{
+			{Name: "file_format", Description: "parquet or csv"},
+			{Name: "ftp_port", Default: "21", Description: "Port"},
+			{Name: "ftp_user", Description: "User"},
+			{Name: "ftp_pass", Secret: true, Description: "Password"},
+		},
+	},
+	{
+		Name:        "localhost",
+		Description: "A table written to by the runtime itself, e.g. through Flight DoPut",
+		From:        "localhost",
+		Parameters: []Parameter{
+			{Name: "schema", Required: true, Description: "Arrow schema of the table, as SQL column definitions"},
+		},
+	},
+	{
+		Name:        "mysql",
+		Description: "MySQL tables",
+		From:        "mysql:my_table",
+		Parameters: []Parameter{
+			{Name: "mysql_connection_string", Secret: true, Description: "Connection string, instead of host, port, user and password"},
+			{Name: "mysql_host", Description: "Host name"},
+			{Name: "mysql_tcp_port", Default: "3306", Description: "Port"},
+			{Name: "mysql_db", Description: "Database"},
+			{Name: "mysql_user", Description: "User"},
+			{Name: "mysql_pass", Secret: true, Description: "Password"},
+			{Name: "mysql_sslmode", Default: "required", Description: "required, preferred or disabled"},
+			{Name: "mysql_sslrootcert", Description: "Path to a root certificate for TLS"},
+		},
+	},
+	{
+		Name:        "odbc",
+		Description: "Any database with an ODBC driver",
+		From:        "odbc:my_table",
+		Parameters: []Parameter{
+			{Name: "odbc_connection_string", Required: true, Secret: true, Description: "ODBC connection string, including the driver"},
+		},
+	},
+	{
+		Name:        "postgres",
+		Description: "PostgreSQL tables",
+		From:        "postgres:my_schema.my_table",
+		Parameters: []Parameter{
+			{Name: "pg_connection_string", Secret: true, Description: "Connection string, instead of host, port, user and password"},
+			{Name: "pg_host", Description: "Host name"},
+			{Name: "pg_port", Default: "5432", Description: "Port"},
+			{Name: "pg_db", Description: "Database"},
+			{Name: "pg_user", Description: "User"},
+			{Name: "pg_pass", Secret: true, Description: "Password"},
+			{Name: "pg_sslmode", Default: "verify-full", Description: "verify-full, verify-ca, require, prefer or disable"},
+			{Name: "pg_sslrootcert", Description: "Path to a root certificate for TLS"},
+		},
+	},
+	{
+		Name:        "s3",
+		Description: "Parquet and CSV files in S3 or S3 compatible storage",
+		From:        "s3://my-bucket/path/to/folder/",
+		Parameters: []Parameter{
+			{Name: "file_format", Description: "parquet or csv"},
+			{Name: "region", Description: "AWS region"},
+			{Name: "endpoint", Description: "Endpoint of S3 compatible storage, e.g. a MinIO server"},
+			{Name: "timeout", Description: "Request timeout, e.g. 30s"},
+			{Name: "key", Secret: true, Description: "Access key ID, anonymous access when not set"},
+			{Name: "secret", Secret: true, Description: "Secret access key"},
+		},
+	},
+	{
+		Name:        "sftp",
+		Description: "Parquet and CSV files on an SFTP server",
+		From:        "sftp://remote-sftp-server.com/path/to/folder/",
+		Parameters: []Parameter{
+			{Name: "file_format", Description: "parquet or csv"},
+			{Name: "sftp_port", Default: "22", Description: "Port"},
+			{Name: "sftp_user", Description: "User"},
+			{Name: "sftp_pass", Secret: true, Description: "Password"},
+		},
+	},
+	{
+		Name:        "snowflake",
+		Description: "Snowflake tables",
+		From:        "snowflake:DATABASE.SCHEMA.TABLE",
+		Parameters: []Parameter{
+			{Name: "account", Required: true, Secret: true, Description: "Snowflake account identifier, e.g. orgname-accountname"},
+			{Name: "username", Required: true, Secret: true, Description: "User"},
+			{Name: "password", Secret: true, Description: "Password, for snowflake auth"},
+			{Name: "snowflake_auth_type", Default: "snowflake", Description: "snowflake (password) or keypair"},
+			{Name: "snowflake_private_key_path", Secret: true, Description: "Path to the private key, for keypair auth"},
+			{Name: "snowflake_private_key_passphrase", Secret: true, Description: "Passphrase of the private key"},
+			{Name: "snowflake_warehouse", Description: "Warehouse"},
+			{Name: "snowflake_role", Description: "Role"},
+		},
+	},
+	{
+		Name:        "spark",
+		Description: "Spark tables through Spark Connect",
+		From:        "spark:spiceai.datasets.my_table",
+		Parameters: []Parameter{
+			{Name: "spark_remote", Required: true, Secret: true, Description: "Spark Connect remote, e.g. sc://localhost:15002"},
+		},
+	},
+	{
+		Name:        "spiceai",
+		Description: "Datasets on the Spice.ai platform",
+		From:        "spiceai:spiceai/quickstart/datasets/taxi_trips",
+		Parameters: []Parameter{
+			{Name: "key", Required: true, Secret: true, Description: "Spice.ai API key, set with spice login"},
+		},
+	},
+}
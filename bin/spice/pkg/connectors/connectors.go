@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectors
+
+// Connector describes a data connector that datasets can be loaded from.
+type Connector struct {
+	Name        string      `json:"name" csv:"name" yaml:"name"`
+	Description string      `json:"description" csv:"description" yaml:"description"`
+	From        string      `json:"from" csv:"from" yaml:"from"`
+	Parameters  []Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// Parameter is a dataset param read by a connector. Secret parameters are usually provided
+// through the secret store rather than written into spicepod.yaml.
+type Parameter struct {
+	Name        string `json:"name" csv:"name" yaml:"name"`
+	Required    bool   `json:"required" csv:"required" yaml:"required"`
+	Secret      bool   `json:"secret" csv:"secret" yaml:"secret"`
+	Default     string `json:"default,omitempty" csv:"default" yaml:"default,omitempty"`
+	Description string `json:"description" csv:"description" yaml:"description"`
+}
+
+func Find(connectors []Connector, name string) *Connector {
+	for i := range connectors {
+		if connectors[i].Name == name {
+			return &connectors[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltin(t *testing.T) {
+	for i, connector := range Builtin {
+		if i > 0 {
+			assert.Less(t, Builtin[i-1].Name, connector.Name, "connectors are sorted by name")
+		}
+		assert.NotEmpty(t, connector.From, connector.Name)
+		assert.NotEmpty(t, connector.Description, connector.Name)
+	}
+
+	assert.Equal(t, "postgres", Find(Builtin, "postgres").Name)
+	assert.Nil(t, Find(Builtin, "nope"))
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package constants
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
@@ -39,3 +42,12 @@ func init() {
 		SpiceCliFilename = "spice"
 	}
 }
+
+// DotSpiceDir returns the directory the CLI keeps its state in by default, ~/.spice.
+func DotSpiceDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, DotSpice), nil
+}
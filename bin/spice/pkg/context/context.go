@@ -17,35 +17,109 @@ limitations under the License.
 package context
 
 import (
+	gocontext "context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
 	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
 	"github.com/spiceai/spiceai/bin/spice/pkg/github"
+	"github.com/spiceai/spiceai/bin/spice/pkg/i18n"
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
 	"golang.org/x/mod/semver"
 )
 
+// DEFAULT_RETRIES is how many times GET requests to the runtime are retried on connection errors
+// and responses saying the runtime is temporarily unable to answer.
+const DEFAULT_RETRIES = 3
+
+// Bounds of the exponential wait between retries of requests to the runtime.
+const (
+	DEFAULT_RETRY_WAIT_MIN = 200 * time.Millisecond
+	DEFAULT_RETRY_WAIT_MAX = 3 * time.Second
+)
+
+var (
+	// ErrRuntimeUnavailable is matched by errors.Is for errors about a runtime that is not running.
+	ErrRuntimeUnavailable = errors.New("runtime unavailable")
+	// ErrRequestTimeout is matched by errors.Is for requests that did not finish within the
+	// request timeout.
+	ErrRequestTimeout = errors.New("request timed out")
+)
+
+// runtimeError is a localized message about the runtime matching one of the errors above.
+type runtimeError struct {
+	message string
+	kind    error
+}
+
+func (e *runtimeError) Error() string {
+	return e.message
+}
+
+func (e *runtimeError) Unwrap() error {
+	return e.kind
+}
+
 type RuntimeContext struct {
 	spiceRuntimeDir string
 	spiceBinDir     string
 	appDir          string
 	podsDir         string
 	httpEndpoint    string
+	// Go context of the command, canceled e.g. when it is interrupted.
+	ctx gocontext.Context
+	// Limit on each request to the runtime, none when zero.
+	requestTimeout time.Duration
+	// Number of times reads from the runtime are retried.
+	retries int
+	// Bounds of the wait between retries.
+	retryWaitMin time.Duration
+	retryWaitMax time.Duration
+	// Where messages, e.g. about installing the runtime, and spinners are written.
+	out            io.Writer
+	errOut         io.Writer
+	progressOutput io.Writer
+	// Connection profile the endpoint, TLS certificate and API key are taken from. The
+	// certificate and key are only used while httpEndpoint is the profile's endpoint.
+	profile    config.ProfileConfig
+	httpClient *http.Client
 }
 
+// NewContext creates a runtime context for the app in the working directory, connected to the
+// active profile or the local runtime.
 func NewContext() *RuntimeContext {
+	return NewContextWithDotSpiceDir("")
+}
+
+// NewContextWithDotSpiceDir is NewContext keeping the CLI's state, e.g. its configuration and the
+// installed runtime, in dotSpiceDir instead of ~/.spice.
+func NewContextWithDotSpiceDir(dotSpiceDir string) *RuntimeContext {
 	rtcontext := &RuntimeContext{
-		httpEndpoint: "http://127.0.0.1:3000",
+		spiceRuntimeDir: dotSpiceDir,
+		profile:         LocalProfile(),
+		httpEndpoint:    defaultHttpEndpoint,
+		retries:         DEFAULT_RETRIES,
+		retryWaitMin:    DEFAULT_RETRY_WAIT_MIN,
+		retryWaitMax:    DEFAULT_RETRY_WAIT_MAX,
+		out:             os.Stdout,
+		errOut:          os.Stderr,
+		progressOutput:  os.Stderr,
 	}
 	err := rtcontext.Init()
 	if err != nil {
 		panic(err)
 	}
+	rtcontext.loadActiveProfile()
 	return rtcontext
 }
 
@@ -65,13 +139,120 @@ func (c *RuntimeContext) HttpEndpoint() string {
 	return c.httpEndpoint
 }
 
-func (c *RuntimeContext) Init() error {
-	homeDir, err := os.UserHomeDir()
+func (c *RuntimeContext) SetHttpEndpoint(endpoint string) {
+	c.httpEndpoint = strings.TrimSuffix(endpoint, "/")
+}
+
+// Context returns the Go context requests made for this runtime context are bound to.
+func (c *RuntimeContext) Context() gocontext.Context {
+	if c.ctx == nil {
+		return gocontext.Background()
+	}
+	return c.ctx
+}
+
+func (c *RuntimeContext) SetContext(ctx gocontext.Context) {
+	c.ctx = ctx
+}
+
+func (c *RuntimeContext) RequestTimeout() time.Duration {
+	return c.requestTimeout
+}
+
+// SetRequestTimeout limits how long each request to the runtime may take, zero for no limit.
+func (c *RuntimeContext) SetRequestTimeout(timeout time.Duration) {
+	c.requestTimeout = timeout
+}
+
+func (c *RuntimeContext) Retries() int {
+	return c.retries
+}
+
+// SetRetries sets how many times idempotent requests to the runtime are retried, 0 disables retries.
+func (c *RuntimeContext) SetRetries(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid number of retries %d, expected 0 or more", n)
+	}
+	c.retries = n
+	return nil
+}
+
+// RetryWait returns the bounds of the wait between retries.
+func (c *RuntimeContext) RetryWait() (time.Duration, time.Duration) {
+	return c.retryWaitMin, c.retryWaitMax
+}
+
+// SetRetryWait replaces the bounds of the wait between retries, e.g. with shorter ones in
+// tests. Zero keeps the default.
+func (c *RuntimeContext) SetRetryWait(min time.Duration, max time.Duration) {
+	if min <= 0 {
+		min = DEFAULT_RETRY_WAIT_MIN
+	}
+	if max <= 0 {
+		max = DEFAULT_RETRY_WAIT_MAX
+	}
+	c.retryWaitMin, c.retryWaitMax = min, max
+}
+
+func (c *RuntimeContext) Out() io.Writer {
+	return c.out
+}
+
+func (c *RuntimeContext) ErrOut() io.Writer {
+	return c.errOut
+}
+
+// SetOutput replaces where messages and errors are printed, e.g. with the command's output.
+func (c *RuntimeContext) SetOutput(out io.Writer, errOut io.Writer) {
+	c.out = out
+	c.errOut = errOut
+}
+
+func (c *RuntimeContext) ProgressOutput() io.Writer {
+	return c.progressOutput
+}
+
+// SetProgressOutput replaces where spinners and download bars are rendered.
+func (c *RuntimeContext) SetProgressOutput(w io.Writer) {
+	c.progressOutput = w
+}
+
+// RequestContext returns the context for a single request to the runtime, which ends after the
+// request timeout. The request's resources are released by calling cancel.
+func (c *RuntimeContext) RequestContext() (gocontext.Context, gocontext.CancelFunc) {
+	if c.requestTimeout <= 0 {
+		return gocontext.WithCancel(c.Context())
+	}
+	return gocontext.WithTimeout(c.Context(), c.requestTimeout)
+}
+
+// RequestTimeoutError reports a request that did not finish within the request timeout.
+func (c *RuntimeContext) RequestTimeoutError() error {
+	return &runtimeError{message: i18n.T("error.request_timeout", c.httpEndpoint, c.requestTimeout), kind: ErrRequestTimeout}
+}
+
+// IsRuntimeHealthy checks the runtime's /health endpoint, giving up after probeTimeout or the
+// request timeout, whichever ends first.
+func (c *RuntimeContext) IsRuntimeHealthy(probeTimeout time.Duration) error {
+	httpClient, err := c.HttpClient()
 	if err != nil {
 		return err
 	}
+	ctx, cancel := c.RequestContext()
+	defer cancel()
+	ctx, cancelProbe := gocontext.WithTimeout(ctx, probeTimeout)
+	defer cancelProbe()
+	return util.IsRuntimeServerHealthy(ctx, c.httpEndpoint, httpClient)
+}
 
-	c.spiceRuntimeDir = filepath.Join(homeDir, constants.DotSpice)
+func (c *RuntimeContext) Init() error {
+	if c.spiceRuntimeDir == "" {
+		spiceRuntimeDir, err := constants.DotSpiceDir()
+		if err != nil {
+			return err
+		}
+		c.spiceRuntimeDir = spiceRuntimeDir
+	}
 	c.spiceBinDir = filepath.Join(c.spiceRuntimeDir, "bin")
 
 	cwd, err := os.Getwd()
@@ -96,7 +277,7 @@ func (c *RuntimeContext) Version() (string, error) {
 }
 
 func (c *RuntimeContext) RuntimeUnavailableError() error {
-	return fmt.Errorf("The Spice runtime is unavailable at %s. Is it running?", c.httpEndpoint)
+	return &runtimeError{message: i18n.T("error.runtime_unavailable", c.httpEndpoint), kind: ErrRuntimeUnavailable}
 }
 
 func (c *RuntimeContext) IsRuntimeInstallRequired() bool {
@@ -113,18 +294,144 @@ func (c *RuntimeContext) InstallOrUpgradeRuntime() error {
 		return err
 	}
 
-	release, err := github.GetLatestRuntimeRelease()
+	spinner := progress.NewSpinner(c.progressOutput, "Checking for the latest Spice.ai runtime release")
+	spinner.Start()
+	release, err := github.GetLatestRuntimeRelease(c.Context())
+	if err != nil {
+		spinner.Stop("failed")
+		return err
+	}
+
+	spinner.Stop(release.TagName)
+
+	err = c.installRuntimeRelease(release)
+	if err != nil {
+		return err
+	}
+
+	// Installing the latest release ends any pin to an older one
+	err = os.Remove(c.runtimePinFilePath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// InstallRuntimeVersion installs the runtime release of the given tag and pins it, so it is not
+// upgraded automatically. The release must be compatible with the CLI.
+func (c *RuntimeContext) InstallRuntimeVersion(tagName string) error {
+	err := version.CheckRuntimeCompatibility(version.Version(), tagName)
+	if err != nil {
+		return err
+	}
+
+	err = c.prepareInstallDir()
+	if err != nil {
+		return err
+	}
+
+	spinner := progress.NewSpinner(c.progressOutput, fmt.Sprintf("Checking for Spice.ai runtime release %s", tagName))
+	spinner.Start()
+	release, err := github.GetRuntimeRelease(c.Context(), tagName)
+	if err != nil {
+		spinner.Stop("failed")
+		return err
+	}
+
+	spinner.Stop(release.TagName)
+
+	err = c.installRuntimeRelease(release)
 	if err != nil {
 		return err
 	}
 
-	runtimeVersion := release.TagName
+	return os.WriteFile(c.runtimePinFilePath(), []byte(release.TagName+"\n"), 0644)
+}
 
-	fmt.Printf("Downloading and installing Spice.ai Runtime %s ...\n", runtimeVersion)
+// InstallRuntimeFromFile installs the runtime from a local release tarball without contacting
+// GitHub, for air-gapped machines. The tarball is verified against a checksums file in the
+// format of the release's checksums.txt, and the runtime version it holds is pinned.
+func (c *RuntimeContext) InstallRuntimeFromFile(tarballPath string, checksumsPath string) error {
+	checksumsBody, err := os.ReadFile(checksumsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no checksums file at %s, download %s from the same release as %s", checksumsPath, github.CHECKSUMS_ASSET_NAME, filepath.Base(tarballPath))
+		}
+		return err
+	}
+	checksums, err := github.ParseChecksums(checksumsBody)
+	if err != nil {
+		return err
+	}
+	err = github.VerifyFileChecksum(checksums, filepath.Base(tarballPath), tarballPath)
+	if err != nil {
+		return err
+	}
 
-	err = github.DownloadRuntimeAsset(release, c.spiceBinDir)
+	err = c.prepareInstallDir()
 	if err != nil {
-		fmt.Println("Error downloading Spice.ai runtime binaries.")
+		return err
+	}
+
+	// Unpack next to the installed runtime first, so an archive without a compatible runtime
+	// leaves the installation unchanged
+	stagingDir, err := os.MkdirTemp(c.spiceBinDir, ".install-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
+
+	err = util.ExtractTarGzFile(tarballPath, stagingDir)
+	if err != nil {
+		return err
+	}
+
+	stagedPath := filepath.Join(stagingDir, constants.SpiceRuntimeFilename)
+	err = util.MakeFileExecutable(stagedPath)
+	if err != nil {
+		return fmt.Errorf("%s does not contain the Spice.ai runtime (%s)", filepath.Base(tarballPath), constants.SpiceRuntimeFilename)
+	}
+	output, err := exec.Command(stagedPath, "--version").Output()
+	if err != nil {
+		return fmt.Errorf("the runtime in %s cannot run on this machine: %w", filepath.Base(tarballPath), err)
+	}
+	runtimeVersion := strings.TrimSpace(string(output))
+	err = version.CheckRuntimeCompatibility(version.Version(), runtimeVersion)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(stagedPath, c.binaryFilePath(constants.SpiceRuntimeFilename))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.out, "Spice runtime %s installed into %s successfully.\n", runtimeVersion, c.spiceBinDir)
+
+	return os.WriteFile(c.runtimePinFilePath(), []byte(runtimeVersion+"\n"), 0644)
+}
+
+// PinnedRuntimeVersion returns the runtime version installed with InstallRuntimeVersion, or
+// an empty string if the runtime follows the latest release.
+func (c *RuntimeContext) PinnedRuntimeVersion() string {
+	content, err := os.ReadFile(c.runtimePinFilePath())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// installRuntimeRelease downloads the runtime from release, showing the download's progress.
+func (c *RuntimeContext) installRuntimeRelease(release *github.RepoRelease) error {
+	err := github.DownloadRuntimeAsset(c.Context(), c.progressOutput, release, c.spiceBinDir)
+	if err != nil {
+		var checksumErr *github.ChecksumError
+		if errors.As(err, &checksumErr) {
+			fmt.Fprintf(c.out, "The downloaded Spice.ai runtime %s failed checksum verification; the existing installation was left unchanged.\n", release.TagName)
+		} else {
+			fmt.Fprintln(c.out, "Error downloading Spice.ai runtime binaries.")
+		}
 		return err
 	}
 
@@ -132,11 +439,11 @@ func (c *RuntimeContext) InstallOrUpgradeRuntime() error {
 
 	err = util.MakeFileExecutable(releaseFilePath)
 	if err != nil {
-		fmt.Println("Error downloading Spice runtime binaries.")
+		fmt.Fprintln(c.out, "Error downloading Spice runtime binaries.")
 		return err
 	}
 
-	fmt.Printf("Spice runtime installed into %s successfully.\n", c.spiceBinDir)
+	fmt.Fprintf(c.out, "Spice runtime %s installed into %s successfully.\n", release.TagName, c.spiceBinDir)
 
 	return nil
 }
@@ -147,11 +454,11 @@ func (c *RuntimeContext) IsRuntimeUpgradeAvailable() (string, error) {
 		return "", err
 	}
 
-	if strings.HasPrefix(currentVersion, "local") || strings.Contains(currentVersion, "rc") {
+	if strings.HasPrefix(currentVersion, "local") || strings.Contains(currentVersion, "rc") || c.PinnedRuntimeVersion() != "" {
 		return "", nil
 	}
 
-	release, err := github.GetLatestRuntimeRelease()
+	release, err := github.GetLatestRuntimeRelease(c.Context())
 	if err != nil {
 		return "", err
 	}
@@ -192,6 +499,10 @@ func (c *RuntimeContext) prepareInstallDir() error {
 	return nil
 }
 
+func (c *RuntimeContext) runtimePinFilePath() string {
+	return filepath.Join(c.spiceBinDir, "spiced.pin")
+}
+
 func (c *RuntimeContext) binaryFilePath(binaryFilePrefix string) string {
 	return filepath.Join(c.spiceBinDir, binaryFilePrefix)
 }
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context_test
+
+import (
+	gocontext "context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/github"
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func runtimeRelease(t *testing.T, tagName string) testutils.FakeRelease {
+	spiced := fmt.Sprintf("#!/bin/sh\necho %s\n", tagName)
+	return testutils.FakeRelease{
+		TagName: tagName,
+		Assets: map[string][]byte{
+			github.GetRuntimeAssetName(): testutils.TarGzAsset(t, map[string]string{"spiced": spiced}),
+		},
+	}
+}
+
+func TestInstallAndUpgradeRuntime(t *testing.T) {
+	if util.IsWindows() {
+		t.Skip("the fake runtime is a shell script")
+	}
+
+	dotSpiceDir := testutils.EnsureTestSpiceDirectory(t)
+	fake := testutils.NewFakeGitHub(t)
+	fake.AddRelease("spiceai", "spiceai", runtimeRelease(t, "v0.1.0"))
+
+	rtcontext := context.NewContextWithDotSpiceDir(dotSpiceDir)
+	rtcontext.SetContext(fake.Context(gocontext.Background()))
+	assert.True(t, rtcontext.IsRuntimeInstallRequired())
+
+	assert.NoError(t, rtcontext.InstallOrUpgradeRuntime())
+	assert.False(t, rtcontext.IsRuntimeInstallRequired())
+	version, err := rtcontext.Version()
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.1.0", version)
+
+	upgrade, err := rtcontext.IsRuntimeUpgradeAvailable()
+	assert.NoError(t, err)
+	assert.Equal(t, "", upgrade)
+
+	fake.AddRelease("spiceai", "spiceai", runtimeRelease(t, "v0.2.0"))
+	upgrade, err = rtcontext.IsRuntimeUpgradeAvailable()
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.2.0", upgrade)
+
+	assert.NoError(t, rtcontext.InstallOrUpgradeRuntime())
+	version, err = rtcontext.Version()
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.2.0", version)
+	assert.Equal(t, 2, fake.Downloads(github.GetRuntimeAssetName()))
+}
+
+func TestInstallRuntimeVersion(t *testing.T) {
+	if util.IsWindows() {
+		t.Skip("the fake runtime is a shell script")
+	}
+
+	dotSpiceDir := testutils.EnsureTestSpiceDirectory(t)
+	fake := testutils.NewFakeGitHub(t)
+	fake.AddRelease("spiceai", "spiceai", runtimeRelease(t, "v0.1.0"))
+	fake.AddRelease("spiceai", "spiceai", runtimeRelease(t, "v0.2.0"))
+	fake.AddRelease("spiceai", "spiceai", testutils.FakeRelease{TagName: "v0.3.0", Assets: map[string][]byte{"other.tar.gz": {}}})
+
+	rtcontext := context.NewContextWithDotSpiceDir(dotSpiceDir)
+	rtcontext.SetContext(fake.Context(gocontext.Background()))
+	assert.NoError(t, rtcontext.InstallRuntimeVersion("v0.1.0"))
+	version, err := rtcontext.Version()
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.1.0", version)
+	assert.Equal(t, "v0.1.0", rtcontext.PinnedRuntimeVersion())
+
+	upgrade, err := rtcontext.IsRuntimeUpgradeAvailable()
+	assert.NoError(t, err)
+	assert.Equal(t, "", upgrade, "a pinned runtime is not upgraded")
+
+	assert.EqualError(t, rtcontext.InstallRuntimeVersion("v9.9.9"), "release v9.9.9 not found")
+	assert.ErrorContains(t, rtcontext.InstallRuntimeVersion("v0.3.0"), "release v0.3.0 has no runtime build")
+	assert.ErrorContains(t, rtcontext.InstallRuntimeVersion("latest"), "invalid runtime version latest")
+
+	assert.NoError(t, rtcontext.InstallOrUpgradeRuntime())
+	version, err = rtcontext.Version()
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.2.0", version)
+	assert.Equal(t, "", rtcontext.PinnedRuntimeVersion())
+}
+
+func TestInstallRuntimeFromFile(t *testing.T) {
+	if util.IsWindows() {
+		t.Skip("the fake runtime is a shell script")
+	}
+
+	dotSpiceDir := testutils.EnsureTestSpiceDirectory(t)
+	dir := t.TempDir()
+	tarball := testutils.TarGzAsset(t, map[string]string{"spiced": "#!/bin/sh\necho v0.1.0\n"})
+	tarballPath := filepath.Join(dir, github.GetRuntimeAssetName())
+	assert.NoError(t, os.WriteFile(tarballPath, tarball, 0644))
+
+	rtcontext := context.NewContextWithDotSpiceDir(dotSpiceDir)
+	checksumsPath := filepath.Join(dir, github.CHECKSUMS_ASSET_NAME)
+	assert.ErrorContains(t, rtcontext.InstallRuntimeFromFile(tarballPath, checksumsPath), "no checksums file at")
+
+	tampered := testutils.TarGzAsset(t, map[string]string{"spiced": "#!/bin/sh\necho v0.2.0\n"})
+	assert.NoError(t, os.WriteFile(checksumsPath, testutils.ChecksumsAsset(map[string][]byte{github.GetRuntimeAssetName(): tampered}), 0644))
+	var checksumErr *github.ChecksumError
+	assert.ErrorAs(t, rtcontext.InstallRuntimeFromFile(tarballPath, checksumsPath), &checksumErr)
+	assert.True(t, rtcontext.IsRuntimeInstallRequired())
+
+	assert.NoError(t, os.WriteFile(checksumsPath, testutils.ChecksumsAsset(map[string][]byte{github.GetRuntimeAssetName(): tarball}), 0644))
+	assert.NoError(t, rtcontext.InstallRuntimeFromFile(tarballPath, checksumsPath))
+	version, err := rtcontext.Version()
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.1.0", version)
+	assert.Equal(t, "v0.1.0", rtcontext.PinnedRuntimeVersion())
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/timing"
+)
+
+// LOCAL_PROFILE names the connection to the runtime started by spice run, used when no other
+// profile is selected.
+const LOCAL_PROFILE = "local"
+
+const (
+	defaultHttpEndpoint   = "http://127.0.0.1:3000"
+	defaultFlightEndpoint = "grpc://127.0.0.1:50051"
+)
+
+// LocalProfile returns the connection to the runtime started by spice run.
+func LocalProfile() config.ProfileConfig {
+	return config.ProfileConfig{Name: LOCAL_PROFILE, Endpoint: defaultHttpEndpoint, FlightEndpoint: defaultFlightEndpoint}
+}
+
+// Profiles returns the runtime connections configured in dotSpiceDir and the name of the one in use.
+func Profiles(dotSpiceDir string) ([]config.ProfileConfig, string, error) {
+	cliConfig, err := config.Load(dotSpiceDir)
+	if err != nil {
+		return nil, "", err
+	}
+	current := LOCAL_PROFILE
+	if profile := cliConfig.ActiveProfile(); profile != nil {
+		current = profile.Name
+	}
+	return cliConfig.Profiles, current, nil
+}
+
+// SetProfile adds a runtime connection to the configuration in dotSpiceDir or updates the fields
+// given for an existing one.
+func SetProfile(dotSpiceDir string, profile config.ProfileConfig) (*config.ProfileConfig, error) {
+	cliConfig, err := config.Load(dotSpiceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing := cliConfig.GetProfile(profile.Name); existing != nil {
+		updated := *existing
+		if profile.Endpoint != "" {
+			updated.Endpoint = profile.Endpoint
+		}
+		if profile.FlightEndpoint != "" {
+			updated.FlightEndpoint = profile.FlightEndpoint
+		}
+		if profile.TlsCert != "" {
+			updated.TlsCert = profile.TlsCert
+		}
+		if profile.ApiKey != "" {
+			updated.ApiKey = profile.ApiKey
+		}
+		profile = updated
+	}
+
+	err = ValidateProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	cliConfig.SetProfile(profile)
+	err = cliConfig.Save(dotSpiceDir)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ValidateProfile checks that a profile can be connected to before it is saved.
+func ValidateProfile(profile config.ProfileConfig) error {
+	if profile.Name == "" || profile.Name == LOCAL_PROFILE {
+		return fmt.Errorf("invalid profile name %q", profile.Name)
+	}
+	endpoint, err := url.Parse(profile.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("invalid endpoint %q for profile %s, expected an http or https URL", profile.Endpoint, profile.Name)
+	}
+	if profile.TlsCert != "" {
+		if _, err = loadCertPool(profile.TlsCert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseProfile selects the runtime connection of new runtime contexts keeping their state in
+// dotSpiceDir, LOCAL_PROFILE for the runtime started by spice run.
+func UseProfile(dotSpiceDir string, name string) error {
+	cliConfig, err := config.Load(dotSpiceDir)
+	if err != nil {
+		return err
+	}
+
+	if name == LOCAL_PROFILE {
+		cliConfig.CurrentProfile = ""
+	} else if cliConfig.GetProfile(name) == nil {
+		return fmt.Errorf("no profile named %s, add it with: spice profile set %s --endpoint <url>", name, name)
+	} else {
+		cliConfig.CurrentProfile = name
+	}
+	return cliConfig.Save(dotSpiceDir)
+}
+
+// SwitchProfile connects the runtime context to the named profile.
+func (c *RuntimeContext) SwitchProfile(name string) error {
+	if name == LOCAL_PROFILE {
+		c.applyProfile(nil)
+		return nil
+	}
+
+	cliConfig, err := config.Load(c.spiceRuntimeDir)
+	if err != nil {
+		return err
+	}
+	profile := cliConfig.GetProfile(name)
+	if profile == nil {
+		return fmt.Errorf("no profile named %s", name)
+	}
+	c.applyProfile(profile)
+	return nil
+}
+
+// Profile returns the name of the runtime connection in use, or an empty string after the
+// endpoint was changed to one that is not the profile's.
+func (c *RuntimeContext) Profile() string {
+	if !c.onProfileEndpoint() {
+		return ""
+	}
+	return c.profile.Name
+}
+
+func (c *RuntimeContext) FlightEndpoint() string {
+	if !c.onProfileEndpoint() || c.profile.FlightEndpoint == "" {
+		return defaultFlightEndpoint
+	}
+	return c.profile.FlightEndpoint
+}
+
+// TlsCert returns the certificate of the profile, which is never trusted for another endpoint.
+func (c *RuntimeContext) TlsCert() string {
+	if !c.onProfileEndpoint() {
+		return ""
+	}
+	return c.profile.TlsCert
+}
+
+// ApiKey returns the API key of the profile, which is never sent to another endpoint.
+func (c *RuntimeContext) ApiKey() string {
+	if !c.onProfileEndpoint() {
+		return ""
+	}
+	return c.profile.ApiKey
+}
+
+// HttpClient returns the client for requests to the runtime, which trusts the profile's TLS
+// certificate in addition to the system roots.
+func (c *RuntimeContext) HttpClient() (*http.Client, error) {
+	tlsCert := c.TlsCert()
+	if tlsCert == "" {
+		return http.DefaultClient, nil
+	}
+	if c.httpClient != nil {
+		return c.httpClient, nil
+	}
+
+	roots, err := loadCertPool(tlsCert)
+	if err != nil {
+		return nil, err
+	}
+	// http.DefaultTransport is wrapped by --profile-cli, so the profile's transport is its own.
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       &tls.Config{RootCAs: roots},
+	}
+	c.httpClient = &http.Client{Transport: timing.Transport(transport)}
+	return c.httpClient, nil
+}
+
+// loadActiveProfile applies the profile selected with spice profile use, if any. A config that
+// cannot be read leaves the local runtime in place, as for other settings of the CLI config.
+func (c *RuntimeContext) loadActiveProfile() {
+	cliConfig, err := config.Load(c.spiceRuntimeDir)
+	if err != nil {
+		return
+	}
+	if profile := cliConfig.ActiveProfile(); profile != nil {
+		c.applyProfile(profile)
+	}
+}
+
+func (c *RuntimeContext) applyProfile(profile *config.ProfileConfig) {
+	c.httpClient = nil
+	if profile == nil {
+		c.profile = LocalProfile()
+	} else {
+		c.profile = *profile
+	}
+	c.SetHttpEndpoint(c.profile.Endpoint)
+}
+
+func (c *RuntimeContext) onProfileEndpoint() bool {
+	return c.httpEndpoint == strings.TrimSuffix(c.profile.Endpoint, "/")
+}
+
+func loadCertPool(certPath string) (*x509.CertPool, error) {
+	certBytes, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading TLS certificate: %w", err)
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(certBytes) {
+		return nil, fmt.Errorf("no PEM certificate found in %s", certPath)
+	}
+	return roots, nil
+}
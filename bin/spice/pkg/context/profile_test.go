@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context_test
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/spiceai/spiceai/bin/spice/pkg/timing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiles(t *testing.T) {
+	dotSpiceDir := testutils.EnsureTestSpiceDirectory(t)
+
+	var apiKey string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("X-API-Key")
+		_, _ = w.Write([]byte(`[{"n":1}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	certPath := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(certPath, cert, 0600))
+
+	_, err := context.SetProfile(dotSpiceDir, config.ProfileConfig{Name: context.LOCAL_PROFILE, Endpoint: server.URL})
+	assert.ErrorContains(t, err, "invalid profile name")
+	_, err = context.SetProfile(dotSpiceDir, config.ProfileConfig{Name: "staging", Endpoint: "127.0.0.1:3000"})
+	assert.ErrorContains(t, err, "expected an http or https URL")
+	_, err = context.SetProfile(dotSpiceDir, config.ProfileConfig{Name: "staging", Endpoint: server.URL, TlsCert: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.ErrorContains(t, err, "error reading TLS certificate")
+	assert.ErrorContains(t, context.UseProfile(dotSpiceDir, "staging"), "no profile named staging")
+
+	_, err = context.SetProfile(dotSpiceDir, config.ProfileConfig{Name: "staging", Endpoint: server.URL, ApiKey: "secret"})
+	assert.NoError(t, err)
+	profile, err := context.SetProfile(dotSpiceDir, config.ProfileConfig{Name: "staging", TlsCert: certPath})
+	assert.NoError(t, err)
+	assert.Equal(t, config.ProfileConfig{Name: "staging", Endpoint: server.URL, TlsCert: certPath, ApiKey: "secret"}, *profile)
+
+	rtcontext := context.NewContextWithDotSpiceDir(dotSpiceDir)
+	assert.Equal(t, context.LOCAL_PROFILE, rtcontext.Profile())
+	assert.Equal(t, "http://127.0.0.1:3000", rtcontext.HttpEndpoint())
+
+	assert.NoError(t, context.UseProfile(dotSpiceDir, "staging"))
+	profiles, current, err := context.Profiles(dotSpiceDir)
+	assert.NoError(t, err)
+	assert.Len(t, profiles, 1)
+	assert.Equal(t, "staging", current)
+
+	rtcontext = context.NewContextWithDotSpiceDir(dotSpiceDir)
+	assert.Equal(t, "staging", rtcontext.Profile())
+	assert.Equal(t, server.URL, rtcontext.HttpEndpoint())
+	rows, err := api.Sql[json.RawMessage](rtcontext, "SELECT 1 AS n")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "secret", apiKey)
+
+	rtcontext.SetHttpEndpoint("http://127.0.0.1:4000")
+	assert.Equal(t, "", rtcontext.Profile())
+	assert.Equal(t, "", rtcontext.ApiKey(), "the API key of a profile is not sent to other endpoints")
+	assert.Equal(t, "", rtcontext.TlsCert())
+	rtcontext.SetHttpEndpoint(server.URL + "/")
+	assert.Equal(t, "secret", rtcontext.ApiKey())
+
+	assert.NoError(t, rtcontext.SwitchProfile(context.LOCAL_PROFILE))
+	assert.Equal(t, "http://127.0.0.1:3000", rtcontext.HttpEndpoint())
+	assert.Equal(t, "", rtcontext.ApiKey())
+
+	assert.NoError(t, context.UseProfile(dotSpiceDir, context.LOCAL_PROFILE))
+	assert.Equal(t, context.LOCAL_PROFILE, context.NewContextWithDotSpiceDir(dotSpiceDir).Profile())
+}
+
+func TestProfileRequestsAreTimed(t *testing.T) {
+	dotSpiceDir := testutils.EnsureTestSpiceDirectory(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"n":1}]`))
+	}))
+	t.Cleanup(server.Close)
+	certPath := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(certPath, cert, 0600))
+	_, err := context.SetProfile(dotSpiceDir, config.ProfileConfig{Name: "staging", Endpoint: server.URL, TlsCert: certPath})
+	assert.NoError(t, err)
+	assert.NoError(t, context.UseProfile(dotSpiceDir, "staging"))
+
+	// --profile-cli wraps http.DefaultTransport, which the profile's transport must not assume
+	// is an *http.Transport.
+	defaultTransport := http.DefaultTransport
+	t.Cleanup(func() { http.DefaultTransport = defaultTransport })
+	timing.Enable()
+	timing.InstrumentHTTP()
+
+	rows, err := api.Sql[json.RawMessage](context.NewContextWithDotSpiceDir(dotSpiceDir), "SELECT 1 AS n")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	spans := timing.Spans()
+	assert.NotEmpty(t, spans)
+	assert.Equal(t, timing.PHASE_HTTP, spans[len(spans)-1].Phase)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+)
+
+const (
+	STAGE_DNS         = "dns"
+	STAGE_NETWORK     = "network"
+	STAGE_TCP         = "tcp"
+	STAGE_TLS         = "tls"
+	STAGE_AUTH        = "auth"
+	STAGE_PERMISSIONS = "permissions"
+	STAGE_SCHEMA      = "schema"
+)
+
+type CheckResult struct {
+	Check    string `json:"check,omitempty" csv:"check" yaml:"check,omitempty"`
+	Endpoint string `json:"endpoint,omitempty" csv:"endpoint" yaml:"endpoint,omitempty"`
+	Status   string `json:"status,omitempty" csv:"status" yaml:"status,omitempty"`
+	Detail   string `json:"detail,omitempty" csv:"detail" yaml:"detail,omitempty"`
+}
+
+// ProbeError reports the stage at which an endpoint probe failed.
+type ProbeError struct {
+	Stage string
+	Err   error
+}
+
+func (e *ProbeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Stage, e.Err.Error())
+}
+
+func (e *ProbeError) Unwrap() error {
+	return e.Err
+}
+
+func CloudHttpEndpoint() string {
+	if os.Getenv("SPICE_CLOUD_HTTP_ENDPOINT") != "" {
+		return os.Getenv("SPICE_CLOUD_HTTP_ENDPOINT")
+	}
+	if strings.HasSuffix(version.Version(), "-dev") {
+		return "https://dev-data.spiceai.io"
+	}
+	return "https://data.spiceai.io"
+}
+
+func CloudFlightEndpoint() string {
+	if os.Getenv("SPICE_CLOUD_FLIGHT_ENDPOINT") != "" {
+		return os.Getenv("SPICE_CLOUD_FLIGHT_ENDPOINT")
+	}
+	if strings.HasSuffix(version.Version(), "-dev") {
+		return "https://dev-flight.spiceai.io"
+	}
+	return "https://flight.spiceai.io"
+}
+
+// ProbeTLSEndpoint resolves, dials and performs a TLS handshake against the endpoint,
+// returning the leaf certificate on success.
+func ProbeTLSEndpoint(endpoint string, timeout time.Duration) (*x509.Certificate, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, &ProbeError{Stage: STAGE_NETWORK, Err: err}
+	}
+
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	conn, err := dial(host, port, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if u.Scheme != "https" && u.Scheme != "grpc+tls" {
+		return nil, nil
+	}
+
+	err = conn.SetDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return nil, &ProbeError{Stage: STAGE_NETWORK, Err: err}
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+	err = tlsConn.Handshake()
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			return nil, &ProbeError{Stage: STAGE_NETWORK, Err: err}
+		}
+		return nil, &ProbeError{Stage: STAGE_TLS, Err: err}
+	}
+
+	peerCertificates := tlsConn.ConnectionState().PeerCertificates
+	if len(peerCertificates) == 0 {
+		return nil, &ProbeError{Stage: STAGE_TLS, Err: errors.New("no peer certificates presented")}
+	}
+
+	return peerCertificates[0], nil
+}
+
+// dial resolves host and opens a TCP connection to it, failing with a ProbeError for the
+// stage that failed.
+func dial(host string, port string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, &ProbeError{Stage: STAGE_DNS, Err: err}
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), timeout)
+	if err != nil {
+		return nil, &ProbeError{Stage: STAGE_NETWORK, Err: err}
+	}
+	return conn, nil
+}
+
+// ProbeHttpAuth issues a trivial SQL query to a Spice.ai HTTP endpoint using the API key.
+func ProbeHttpAuth(endpoint string, apiKey string, timeout time.Duration) error {
+	request, err := http.NewRequest("POST", fmt.Sprintf("%s/v1/sql", strings.TrimSuffix(endpoint, "/")), strings.NewReader("SELECT 1"))
+	if err != nil {
+		return &ProbeError{Stage: STAGE_AUTH, Err: err}
+	}
+	request.Header.Set("Content-Type", "text/plain")
+	request.Header.Set("X-API-Key", apiKey)
+
+	client := &http.Client{Timeout: timeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return &ProbeError{Stage: STAGE_NETWORK, Err: err}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+		return &ProbeError{Stage: STAGE_AUTH, Err: fmt.Errorf("API key rejected (%s)", response.Status)}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+)
+
+// sourceHostParams maps connectors that reach a source over the network to the dataset params
+// holding its host and port, and the port used when the param is not set.
+var sourceHostParams = map[string]struct {
+	host        string
+	port        string
+	defaultPort string
+}{
+	"clickhouse": {host: "clickhouse_host", port: "clickhouse_tcp_port", defaultPort: "9000"},
+	"mysql":      {host: "mysql_host", port: "mysql_tcp_port", defaultPort: "3306"},
+	"postgres":   {host: "pg_host", port: "pg_port", defaultPort: "5432"},
+	"ftp":        {port: "ftp_port", defaultPort: "21"},
+	"sftp":       {port: "sftp_port", defaultPort: "22"},
+}
+
+// Error messages of the sources' drivers, as passed on by the runtime, that identify the
+// failing stage. Auth is matched first: MySQL reports bad credentials as "access denied".
+var (
+	authErrorPatterns        = []string{"authentication", "password", "unauthorized", "unauthenticated", "invalid credentials", "login failed", "invalid token", "401"}
+	permissionsErrorPatterns = []string{"permission denied", "access denied", "forbidden", "command denied", "insufficient privilege", "not authorized", "403"}
+)
+
+// SourceConnector returns the connector a dataset is loaded with, e.g. postgres for
+// postgres:public.orders and s3 for s3://bucket/orders/.
+func SourceConnector(dataset *spec.DatasetSpec) string {
+	connector, _, _ := strings.Cut(dataset.From, ":")
+	return connector
+}
+
+// SourceAddress returns the host and port the dataset's connector connects to. ok is false for
+// connectors without a network source, such as file and duckdb, and for hosts that are only
+// known to the runtime, e.g. from a connection string or a secret.
+func SourceAddress(dataset *spec.DatasetSpec) (host string, port string, ok bool) {
+	connector := SourceConnector(dataset)
+	switch connector {
+	case "dremio", "flightsql", "databricks":
+		return endpointAddress(dataset.Params["endpoint"], "443")
+	case "spiceai":
+		return endpointAddress(CloudFlightEndpoint(), "443")
+	case "s3":
+		if endpoint := dataset.Params["endpoint"]; endpoint != "" {
+			return endpointAddress(endpoint, "443")
+		}
+		if region := dataset.Params["region"]; region != "" {
+			return fmt.Sprintf("s3.%s.amazonaws.com", region), "443", true
+		}
+		return "s3.amazonaws.com", "443", true
+	}
+
+	params, ok := sourceHostParams[connector]
+	if !ok {
+		return "", "", false
+	}
+	if params.host != "" {
+		host = dataset.Params[params.host]
+	} else if u, err := url.Parse(dataset.From); err == nil {
+		host = u.Hostname()
+	}
+	port = dataset.Params[params.port]
+	if port == "" {
+		port = params.defaultPort
+	}
+	if host == "" || strings.Contains(host, "${") || strings.Contains(port, "${") {
+		return "", "", false
+	}
+	return host, port, true
+}
+
+func endpointAddress(endpoint string, defaultPort string) (string, string, bool) {
+	if endpoint == "" || strings.Contains(endpoint, "${") {
+		return "", "", false
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return "", "", false
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return u.Hostname(), port, true
+}
+
+// ProbeSourceNetwork resolves the source host and opens a TCP connection to it from this
+// machine, which should share the runtime's network for the result to be meaningful.
+func ProbeSourceNetwork(host string, port string, timeout time.Duration) error {
+	conn, err := dial(host, port, timeout)
+	if err != nil {
+		var probeErr *ProbeError
+		if errors.As(err, &probeErr) && probeErr.Stage == STAGE_NETWORK {
+			probeErr.Stage = STAGE_TCP
+		}
+		return err
+	}
+	return conn.Close()
+}
+
+// ProbeSourceQuery asks the runtime to read the dataset's schema through its connector
+// without fetching rows, returning the columns or a ProbeError for the stage that failed.
+func ProbeSourceQuery(rtcontext *context.RuntimeContext, dataset string) ([]api.Column, error) {
+	_, err := api.Sql[map[string]interface{}](rtcontext, fmt.Sprintf("SELECT * FROM %s LIMIT 0", quoteIdentifier(dataset)))
+	if err != nil {
+		var apiErr *api.RuntimeApiError
+		if !errors.As(err, &apiErr) {
+			return nil, err
+		}
+		return nil, &ProbeError{Stage: sourceErrorStage(apiErr.Message), Err: err}
+	}
+
+	columns, err := api.GetDatasetColumns(rtcontext, dataset)
+	if err != nil {
+		return nil, &ProbeError{Stage: STAGE_SCHEMA, Err: err}
+	}
+	return columns, nil
+}
+
+func sourceErrorStage(message string) string {
+	message = strings.ToLower(message)
+	for _, pattern := range authErrorPatterns {
+		if strings.Contains(message, pattern) {
+			return STAGE_AUTH
+		}
+	}
+	for _, pattern := range permissionsErrorPatterns {
+		if strings.Contains(message, pattern) {
+			return STAGE_PERMISSIONS
+		}
+	}
+	return STAGE_SCHEMA
+}
+
+func quoteIdentifier(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
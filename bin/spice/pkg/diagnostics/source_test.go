@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceAddress(t *testing.T) {
+	testCases := []struct {
+		from   string
+		params map[string]string
+		host   string
+		port   string
+		ok     bool
+	}{
+		{"postgres:public.orders", map[string]string{"pg_host": "db.internal"}, "db.internal", "5432", true},
+		{"mysql:orders", map[string]string{"mysql_host": "db", "mysql_tcp_port": "3307"}, "db", "3307", true},
+		{"postgres:orders", map[string]string{"pg_connection_string": "${secrets:pg}"}, "", "", false},
+		{"postgres:orders", map[string]string{"pg_host": "${secrets:pg_host}"}, "", "", false},
+		{"dremio:datasets.orders", map[string]string{"endpoint": "grpc://dremio:32010"}, "dremio", "32010", true},
+		{"databricks:spiceai.orders", map[string]string{"endpoint": "dbc-a1b2.cloud.databricks.com"}, "dbc-a1b2.cloud.databricks.com", "443", true},
+		{"s3://bucket/orders/", map[string]string{"region": "us-west-2"}, "s3.us-west-2.amazonaws.com", "443", true},
+		{"sftp://files.example.com/orders.csv", nil, "files.example.com", "22", true},
+		{"file:orders.parquet", nil, "", "", false},
+	}
+
+	for _, tc := range testCases {
+		host, port, ok := SourceAddress(&spec.DatasetSpec{From: tc.from, Params: tc.params})
+		assert.Equal(t, tc.ok, ok, tc.from)
+		assert.Equal(t, tc.host, host, tc.from)
+		assert.Equal(t, tc.port, port, tc.from)
+	}
+}
+
+func TestSourceErrorStage(t *testing.T) {
+	assert.Equal(t, STAGE_AUTH, sourceErrorStage(`password authentication failed for user "spice"`))
+	assert.Equal(t, STAGE_AUTH, sourceErrorStage("Access denied for user 'spice'@'10.0.0.1' (using password: YES)"))
+	assert.Equal(t, STAGE_PERMISSIONS, sourceErrorStage("permission denied for table orders"))
+	assert.Equal(t, STAGE_PERMISSIONS, sourceErrorStage("SELECT command denied to user 'spice' for table 'orders'"))
+	assert.Equal(t, STAGE_SCHEMA, sourceErrorStage("table 'spice.public.orders' not found"))
+}
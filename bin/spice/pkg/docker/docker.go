@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	IMAGE_REPOSITORY = "spiceai/spiceai"
+
+	DOCKERFILE_NAME   = "Dockerfile"
+	COMPOSE_FILE_NAME = "docker-compose.yml"
+	DOCKERIGNORE_NAME = ".dockerignore"
+
+	// Where the Spicepod is mounted or copied in the runtime container.
+	CONTAINER_APP_DIR = "/app"
+
+	SIDECAR_POSTGRES = "postgres"
+	SIDECAR_MINIO    = "minio"
+)
+
+var Sidecars = []string{SIDECAR_POSTGRES, SIDECAR_MINIO}
+
+var releaseVersionPattern = regexp.MustCompile(`^v?\d+\.\d+`)
+
+var ErrFileExists = errors.New("file already exists")
+
+// RuntimeArgs make the runtime listen on all interfaces with the same ports as the Helm chart.
+var RuntimeArgs = []string{
+	"--http", "0.0.0.0:3000",
+	"--metrics", "0.0.0.0:9000",
+	"--flight", "0.0.0.0:50051",
+}
+
+type InitOptions struct {
+	// Runtime image tag, latest when empty.
+	ImageTag string
+	Sidecars []string
+	// Overwrite existing files.
+	Force bool
+}
+
+func ValidateSidecars(sidecars []string) error {
+	for _, sidecar := range sidecars {
+		if sidecar != SIDECAR_POSTGRES && sidecar != SIDECAR_MINIO {
+			return fmt.Errorf("unknown sidecar '%s', use one of: %s", sidecar, strings.Join(Sidecars, ", "))
+		}
+	}
+	return nil
+}
+
+// Init writes a Dockerfile, .dockerignore and docker-compose.yml for the Spicepod in dir and
+// returns the files written. Existing files are kept unless options.Force is set.
+func Init(dir string, options InitOptions) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "spicepod.yaml")); err != nil {
+		return nil, fmt.Errorf("no spicepod.yaml found in %s, run spice init first", dir)
+	}
+
+	err := ValidateSidecars(options.Sidecars)
+	if err != nil {
+		return nil, err
+	}
+
+	compose, err := Compose(options.Sidecars)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{name: DOCKERFILE_NAME, content: Dockerfile(options.ImageTag)},
+		{name: DOCKERIGNORE_NAME, content: Dockerignore()},
+		{name: COMPOSE_FILE_NAME, content: compose},
+	}
+
+	if !options.Force {
+		for _, file := range files {
+			path := filepath.Join(dir, file.name)
+			if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("%s: %w", path, ErrFileExists)
+			}
+		}
+	}
+
+	written := make([]string, 0, len(files))
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		err := os.WriteFile(path, []byte(file.content), 0644)
+		if err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// ImageTag returns the runtime image tag for a version of the runtime or CLI, e.g. 0.13.1-alpha
+// for v0.13.1-alpha, or latest for local builds.
+func ImageTag(version string) string {
+	version = strings.TrimSpace(version)
+	if fields := strings.Fields(version); len(fields) > 0 {
+		// spiced --version prints the binary name before the version.
+		version = fields[len(fields)-1]
+	}
+	if !releaseVersionPattern.MatchString(version) {
+		return "latest"
+	}
+	return strings.TrimPrefix(version, "v")
+}
+
+// RunArgs are the docker run arguments to start the runtime image with appDir mounted as the
+// Spicepod directory. Ports are only published on the loopback interface, as for a native runtime.
+func RunArgs(appDir string, imageTag string) []string {
+	args := []string{
+		"run", "--rm", "--name", "spiceai",
+		"--volume", fmt.Sprintf("%s:%s", appDir, CONTAINER_APP_DIR),
+		"--workdir", CONTAINER_APP_DIR,
+		"--publish", "127.0.0.1:3000:3000",
+		"--publish", "127.0.0.1:9000:9000",
+		"--publish", "127.0.0.1:50051:50051",
+		fmt.Sprintf("%s:%s", IMAGE_REPOSITORY, imageTag),
+	}
+	return append(args, RuntimeArgs...)
+}
+
+// RunCommand returns the docker run command for the runtime image, pulling it when missing.
+func RunCommand(appDir string, imageTag string) (*exec.Cmd, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, fmt.Errorf("docker was not found on the PATH, install Docker to run the runtime in a container")
+	}
+	return exec.Command("docker", RunArgs(appDir, imageTag)...), nil
+}
+
+// Dockerfile builds an image of the runtime with the Spicepod and the files it references baked in.
+func Dockerfile(imageTag string) string {
+	if imageTag == "" {
+		imageTag = "latest"
+	}
+
+	quotedArgs := make([]string, len(RuntimeArgs))
+	for i, arg := range RuntimeArgs {
+		quotedArgs[i] = fmt.Sprintf("%q", arg)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by spice docker init.\n")
+	b.WriteString(fmt.Sprintf("FROM %s:%s\n\n", IMAGE_REPOSITORY, imageTag))
+	b.WriteString(fmt.Sprintf("WORKDIR %s\n", CONTAINER_APP_DIR))
+	b.WriteString(fmt.Sprintf("COPY . %s\n\n", CONTAINER_APP_DIR))
+	b.WriteString("EXPOSE 3000 9000 50051\n\n")
+	b.WriteString(fmt.Sprintf("ENTRYPOINT [\"/usr/local/bin/spiced\", %s]\n", strings.Join(quotedArgs, ", ")))
+	return b.String()
+}
+
+// Dockerignore keeps local state and the container definitions out of the build context.
+func Dockerignore() string {
+	return strings.Join([]string{
+		".git",
+		".spice",
+		DOCKERFILE_NAME,
+		DOCKERIGNORE_NAME,
+		COMPOSE_FILE_NAME,
+	}, "\n") + "\n"
+}
+
+// Compose returns a docker-compose.yml running the runtime image next to the sidecars. A header
+// comment lists the connection parameters a Spicepod uses to reach each sidecar.
+func Compose(sidecars []string) (string, error) {
+	runtime := yaml.MapSlice{
+		{Key: "build", Value: "."},
+		{Key: "ports", Value: []string{"3000:3000", "9000:9000", "50051:50051"}},
+	}
+	services := yaml.MapSlice{{Key: "spiceai", Value: runtime}}
+	volumes := yaml.MapSlice{}
+	header := []string{"# Generated by spice docker init."}
+
+	var dependsOn yaml.MapSlice
+	for _, sidecar := range sidecars {
+		switch sidecar {
+		case SIDECAR_POSTGRES:
+			header = append(header, "# postgres: pg_host: postgres, pg_port: 5432, pg_db: spice, pg_user: spice, pg_pass: spice, pg_sslmode: disable")
+			services = append(services, yaml.MapItem{Key: SIDECAR_POSTGRES, Value: yaml.MapSlice{
+				{Key: "image", Value: "postgres:16"},
+				{Key: "environment", Value: yaml.MapSlice{
+					{Key: "POSTGRES_DB", Value: "spice"},
+					{Key: "POSTGRES_USER", Value: "spice"},
+					{Key: "POSTGRES_PASSWORD", Value: "spice"},
+				}},
+				{Key: "ports", Value: []string{"5432:5432"}},
+				{Key: "volumes", Value: []string{"postgres-data:/var/lib/postgresql/data"}},
+				{Key: "healthcheck", Value: yaml.MapSlice{
+					{Key: "test", Value: []string{"CMD", "pg_isready", "-U", "spice"}},
+					{Key: "interval", Value: "5s"},
+					{Key: "retries", Value: 10},
+				}},
+			}})
+			volumes = append(volumes, yaml.MapItem{Key: "postgres-data", Value: yaml.MapSlice{}})
+			dependsOn = append(dependsOn, yaml.MapItem{Key: SIDECAR_POSTGRES, Value: yaml.MapSlice{{Key: "condition", Value: "service_healthy"}}})
+		case SIDECAR_MINIO:
+			// The runtime publishes its metrics on 9000, so MinIO is published on 9100 and 9101.
+			header = append(header, "# minio: s3_endpoint: http://minio:9000, s3_auth: key, s3_key: spice, s3_secret: spicespice, console on http://localhost:9101")
+			services = append(services, yaml.MapItem{Key: SIDECAR_MINIO, Value: yaml.MapSlice{
+				{Key: "image", Value: "minio/minio"},
+				{Key: "command", Value: "server /data --console-address :9001"},
+				{Key: "environment", Value: yaml.MapSlice{
+					{Key: "MINIO_ROOT_USER", Value: "spice"},
+					{Key: "MINIO_ROOT_PASSWORD", Value: "spicespice"},
+				}},
+				{Key: "ports", Value: []string{"9100:9000", "9101:9001"}},
+				{Key: "volumes", Value: []string{"minio-data:/data"}},
+			}})
+			volumes = append(volumes, yaml.MapItem{Key: "minio-data", Value: yaml.MapSlice{}})
+			dependsOn = append(dependsOn, yaml.MapItem{Key: SIDECAR_MINIO, Value: yaml.MapSlice{{Key: "condition", Value: "service_started"}}})
+		default:
+			return "", ValidateSidecars([]string{sidecar})
+		}
+	}
+
+	if len(dependsOn) > 0 {
+		services[0].Value = append(runtime, yaml.MapItem{Key: "depends_on", Value: dependsOn})
+	}
+
+	compose := yaml.MapSlice{{Key: "services", Value: services}}
+	if len(volumes) > 0 {
+		compose = append(compose, yaml.MapItem{Key: "volumes", Value: volumes})
+	}
+
+	composeBytes, err := yaml.Marshal(compose)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(header, "\n") + "\n" + string(composeBytes), nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestInit(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Init(dir, InitOptions{})
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "spicepod.yaml"), []byte("name: app\n"), 0644))
+	files, err := Init(dir, InitOptions{ImageTag: "0.13.1-alpha", Sidecars: Sidecars})
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	dockerfile, err := os.ReadFile(filepath.Join(dir, DOCKERFILE_NAME))
+	assert.NoError(t, err)
+	assert.Contains(t, string(dockerfile), "FROM spiceai/spiceai:0.13.1-alpha\n")
+
+	_, err = Init(dir, InitOptions{})
+	assert.ErrorIs(t, err, ErrFileExists)
+	_, err = Init(dir, InitOptions{Force: true})
+	assert.NoError(t, err)
+
+	_, err = Init(dir, InitOptions{Sidecars: []string{"mysql"}, Force: true})
+	assert.EqualError(t, err, "unknown sidecar 'mysql', use one of: postgres, minio")
+}
+
+func TestCompose(t *testing.T) {
+	compose, err := Compose([]string{SIDECAR_POSTGRES})
+	assert.NoError(t, err)
+
+	var parsed struct {
+		Services map[string]struct {
+			Build     string                 `yaml:"build"`
+			Image     string                 `yaml:"image"`
+			DependsOn map[string]interface{} `yaml:"depends_on"`
+		} `yaml:"services"`
+		Volumes map[string]interface{} `yaml:"volumes"`
+	}
+	assert.NoError(t, yaml.Unmarshal([]byte(compose), &parsed))
+	assert.Len(t, parsed.Services, 2)
+	assert.Equal(t, ".", parsed.Services["spiceai"].Build)
+	assert.Contains(t, parsed.Services["spiceai"].DependsOn, SIDECAR_POSTGRES)
+	assert.Equal(t, "postgres:16", parsed.Services[SIDECAR_POSTGRES].Image)
+	assert.Contains(t, parsed.Volumes, "postgres-data")
+
+	compose, err = Compose(nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, compose, "depends_on")
+	assert.NotContains(t, compose, "volumes")
+}
+
+func TestImageTag(t *testing.T) {
+	assert.Equal(t, "0.13.1-alpha", ImageTag("v0.13.1-alpha"))
+	assert.Equal(t, "0.13.1-alpha", ImageTag("spiced v0.13.1-alpha\n"))
+	assert.Equal(t, "0.13.1-alpha", ImageTag("0.13.1-alpha"))
+	assert.Equal(t, "latest", ImageTag("local-dev"))
+	assert.Equal(t, "latest", ImageTag(""))
+}
+
+func TestRunArgs(t *testing.T) {
+	args := RunArgs("/home/me/app", "0.13.1-alpha")
+	assert.Equal(t, []string{"run", "--rm", "--name", "spiceai", "--volume", "/home/me/app:/app", "--workdir", "/app"}, args[:8])
+	assert.Contains(t, args, "127.0.0.1:3000:3000")
+	assert.Equal(t, []string{"spiceai/spiceai:0.13.1-alpha", "--http", "0.0.0.0:3000", "--metrics", "0.0.0.0:9000", "--flight", "0.0.0.0:50051"}, args[len(args)-7:])
+}
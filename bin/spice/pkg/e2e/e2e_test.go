@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testScenario = `
+name: smoke
+spicepod: ../app
+steps:
+  - name: count
+    sql: SELECT COUNT(*) AS n FROM trips
+    expect:
+      count: 1
+      rows: [{n: 3}]
+  - name: broken query
+    sql: SELECT nope
+    expect:
+      error: not found
+  - sql: SELECT city FROM trips
+    expect:
+      min_count: 2
+      contains: JFK
+  - name: datasets
+    api: {path: /v1/datasets}
+    expect:
+      contains: trips
+`
+
+func writeScenario(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadScenario(t *testing.T) {
+	path := writeScenario(t, testScenario)
+	scenario, err := LoadScenario(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "smoke", scenario.Name)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "../app"), scenario.SpicepodDir())
+	assert.Len(t, scenario.Steps, 4)
+	assert.Equal(t, "step 3", scenario.Steps[2].Name)
+	assert.Equal(t, STEP_TYPE_SQL, scenario.Steps[2].Type())
+	assert.Equal(t, "GET", scenario.Steps[3].Api.Method)
+
+	_, err = LoadScenario(writeScenario(t, "steps:\n  - sql: SELECT 1\n    api: {path: /health}\n"))
+	assert.ErrorContains(t, err, "exactly one of sql or api")
+
+	_, err = LoadScenario(writeScenario(t, "name: empty\n"))
+	assert.ErrorContains(t, err, "defines no steps")
+}
+
+func TestRun(t *testing.T) {
+	mock := testutils.NewMockRuntime(t)
+	mock.Handle("POST", "/v1/sql", func(request testutils.MockRequest) (int, interface{}) {
+		if string(request.Body) == "SELECT nope" {
+			return http.StatusBadRequest, map[string]string{"message": "column nope not found"}
+		}
+		if string(request.Body) == "SELECT city FROM trips" {
+			return http.StatusOK, []map[string]interface{}{{"city": "JFK"}, {"city": "LGA"}}
+		}
+		return http.StatusOK, []map[string]interface{}{{"n": 3}}
+	})
+	mock.Handle("GET", "/v1/datasets", func(testutils.MockRequest) (int, interface{}) {
+		return http.StatusOK, []map[string]string{{"name": "trips"}}
+	})
+
+	scenario, err := LoadScenario(writeScenario(t, testScenario))
+	assert.NoError(t, err)
+
+	results := Run(mock.Context(), scenario)
+	assert.Len(t, results, 4)
+	for _, result := range results {
+		assert.Equal(t, STEP_PASSED, result.Status, "%s: %s", result.Step, result.Detail)
+	}
+	assert.True(t, Passed(results))
+
+	mock.Handle("POST", "/v1/sql", func(testutils.MockRequest) (int, interface{}) {
+		return http.StatusOK, []map[string]interface{}{{"n": 4}}
+	})
+	results = Run(mock.Context(), scenario)
+	assert.Equal(t, STEP_FAILED, results[0].Status)
+	assert.Equal(t, "row 1 column 'n': expected 3, got 4", results[0].Detail)
+	assert.Equal(t, STEP_FAILED, results[1].Status)
+	assert.False(t, Passed(results))
+}
+
+func TestWaitForReady(t *testing.T) {
+	mock := testutils.NewMockRuntime(t)
+	mock.Fail("GET", "/health", http.StatusServiceUnavailable, 2)
+
+	err := WaitForReady(mock.Context(), mock.Server.URL, nil, 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, mock.Requests("GET", "/health"))
+
+	mock.Fail("GET", "/health", http.StatusServiceUnavailable, -1)
+	err = WaitForReady(mock.Context(), mock.Server.URL, nil, 500*time.Millisecond)
+	assert.ErrorContains(t, err, "timed out waiting for the Spice runtime")
+}
+
+func TestValuesEqual(t *testing.T) {
+	assert.True(t, valuesEqual(3, 3.0))
+	assert.True(t, valuesEqual("a", "a"))
+	assert.True(t, valuesEqual(map[interface{}]interface{}{"k": 1}, map[string]interface{}{"k": 1.0}))
+	assert.False(t, valuesEqual(3, "3"))
+	assert.False(t, valuesEqual(nil, 0.0))
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+const (
+	STEP_PASSED = "passed"
+	STEP_FAILED = "failed"
+)
+
+// An API step fails after this long even without --timeout.
+const apiStepTimeout = 60 * time.Second
+
+type StepResult struct {
+	Step     string        `json:"step" csv:"step"`
+	Type     string        `json:"type" csv:"type"`
+	Status   string        `json:"status" csv:"status"`
+	Duration time.Duration `json:"duration_ns" csv:"duration"`
+	Detail   string        `json:"detail,omitempty" csv:"detail"`
+}
+
+// Run executes the scenario's steps in order against the runtime. Every step runs even if an
+// earlier one failed.
+func Run(rtcontext *context.RuntimeContext, scenario *Scenario) []StepResult {
+	results := make([]StepResult, 0, len(scenario.Steps))
+	for _, step := range scenario.Steps {
+		start := time.Now()
+		detail, err := runStep(rtcontext, step)
+
+		result := StepResult{
+			Step:     step.Name,
+			Type:     step.Type(),
+			Status:   STEP_PASSED,
+			Duration: time.Since(start).Round(time.Millisecond),
+			Detail:   detail,
+		}
+		if err != nil {
+			result.Status = STEP_FAILED
+			result.Detail = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func Passed(results []StepResult) bool {
+	for _, result := range results {
+		if result.Status != STEP_PASSED {
+			return false
+		}
+	}
+	return true
+}
+
+func runStep(rtcontext *context.RuntimeContext, step Step) (string, error) {
+	switch step.Type() {
+	case STEP_TYPE_SQL:
+		rows, err := api.Sql[map[string]interface{}](rtcontext, step.Sql)
+		if err != nil {
+			return checkError(step.Expect, err)
+		}
+		if err = checkUnexpectedSuccess(step.Expect); err != nil {
+			return "", err
+		}
+		return checkRows(step.Expect, rows)
+	case STEP_TYPE_API:
+		return runApiStep(rtcontext, step)
+	}
+	return "", fmt.Errorf("unsupported step type")
+}
+
+func runApiStep(rtcontext *context.RuntimeContext, step Step) (string, error) {
+	var body io.Reader
+	if step.Api.Body != "" {
+		body = strings.NewReader(step.Api.Body)
+	}
+
+	httpClient, err := rtcontext.HttpClient()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := rtcontext.RequestContext()
+	defer cancel()
+	ctx, cancelStep := gocontext.WithTimeout(ctx, apiStepTimeout)
+	defer cancelStep()
+
+	request, err := http.NewRequestWithContext(ctx, step.Api.Method, rtcontext.HttpEndpoint()+step.Api.Path, body)
+	if err != nil {
+		return "", err
+	}
+	if apiKey := rtcontext.ApiKey(); apiKey != "" {
+		request.Header.Set("X-API-Key", apiKey)
+	}
+	if step.Api.Body != "" {
+		contentType := "text/plain"
+		if json.Valid([]byte(step.Api.Body)) {
+			contentType = "application/json"
+		}
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return checkError(step.Expect, err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response: %w", err)
+	}
+
+	expectedStatus := step.Expect.Status
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+	if response.StatusCode != expectedStatus {
+		return "", fmt.Errorf("expected status %d, got %s", expectedStatus, response.Status)
+	}
+	if step.Expect.Contains != "" && !strings.Contains(string(responseBody), step.Expect.Contains) {
+		return "", fmt.Errorf("response does not contain '%s'", step.Expect.Contains)
+	}
+
+	return response.Status, nil
+}
+
+func checkError(expect Expectation, err error) (string, error) {
+	if expect.Error == "" {
+		return "", err
+	}
+	if !strings.Contains(err.Error(), expect.Error) {
+		return "", fmt.Errorf("expected error containing '%s', got: %s", expect.Error, err.Error())
+	}
+	return fmt.Sprintf("failed as expected: %s", err.Error()), nil
+}
+
+func checkUnexpectedSuccess(expect Expectation) error {
+	if expect.Error != "" {
+		return fmt.Errorf("expected error containing '%s', but the step succeeded", expect.Error)
+	}
+	return nil
+}
+
+func checkCount(expect Expectation, count int, noun string) error {
+	if expect.Count != nil && count != *expect.Count {
+		return fmt.Errorf("expected %d %s, got %d", *expect.Count, noun, count)
+	}
+	if expect.MinCount != nil && count < *expect.MinCount {
+		return fmt.Errorf("expected at least %d %s, got %d", *expect.MinCount, noun, count)
+	}
+	return nil
+}
+
+func checkRows(expect Expectation, rows []map[string]interface{}) (string, error) {
+	if err := checkCount(expect, len(rows), "rows"); err != nil {
+		return "", err
+	}
+
+	if len(expect.Rows) > len(rows) {
+		return "", fmt.Errorf("expected at least %d rows to compare, got %d", len(expect.Rows), len(rows))
+	}
+	for i, expectedRow := range expect.Rows {
+		for column, expectedValue := range expectedRow {
+			actualValue, ok := rows[i][column]
+			if !ok {
+				return "", fmt.Errorf("row %d has no column '%s'", i+1, column)
+			}
+			if !valuesEqual(expectedValue, actualValue) {
+				return "", fmt.Errorf("row %d column '%s': expected %v, got %v", i+1, column, expectedValue, actualValue)
+			}
+		}
+	}
+
+	if expect.Contains != "" {
+		rowBytes, err := json.Marshal(rows)
+		if err != nil {
+			return "", err
+		}
+		if !strings.Contains(string(rowBytes), expect.Contains) {
+			return "", fmt.Errorf("result does not contain '%s'", expect.Contains)
+		}
+	}
+
+	return fmt.Sprintf("%d rows", len(rows)), nil
+}
+
+// valuesEqual compares a value from the scenario YAML with one decoded from the runtime's
+// JSON response, so that e.g. the YAML integer 3 equals the JSON number 3.0.
+func valuesEqual(expected interface{}, actual interface{}) bool {
+	return reflect.DeepEqual(normalizeValue(expected), normalizeValue(actual))
+}
+
+func normalizeValue(value interface{}) interface{} {
+	valueBytes, err := json.Marshal(jsonCompatible(value))
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err = json.Unmarshal(valueBytes, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// jsonCompatible converts the map[interface{}]interface{} values produced by yaml.v2.
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = jsonCompatible(item)
+		}
+		return items
+	}
+	return value
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/bench"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+const (
+	healthPollInterval = 250 * time.Millisecond
+	stopTimeout        = 10 * time.Second
+)
+
+var errRuntimeExited = errors.New("the Spice runtime exited before becoming healthy")
+
+// Runtime is a spiced process booted for a scenario. Its output is kept in memory so it can be
+// shown when the runtime fails to become ready.
+type Runtime struct {
+	cmd     *exec.Cmd
+	output  *bytes.Buffer
+	exited  chan struct{}
+	exitErr error
+}
+
+// StartRuntime boots the installed runtime in spicepodDir. It refuses to start when another
+// runtime already answers on the context's HTTP endpoint, as the scenario would run against it.
+func StartRuntime(rtcontext *context.RuntimeContext, spicepodDir string) (*Runtime, error) {
+	if _, err := os.Stat(spicepodDir); err != nil {
+		return nil, fmt.Errorf("spicepod directory '%s' not found: %w", spicepodDir, err)
+	}
+
+	if rtcontext.IsRuntimeHealthy(time.Second) == nil {
+		return nil, fmt.Errorf("a Spice runtime is already running at %s, stop it or run the scenario against it with --no-start", rtcontext.HttpEndpoint())
+	}
+
+	cmd, err := rtcontext.GetRunCmd()
+	if err != nil {
+		return nil, err
+	}
+
+	output := &bytes.Buffer{}
+	cmd.Dir = spicepodDir
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("error starting the Spice runtime: %w", err)
+	}
+
+	runtime := &Runtime{cmd: cmd, output: output, exited: make(chan struct{})}
+	go func() {
+		runtime.exitErr = cmd.Wait()
+		close(runtime.exited)
+	}()
+
+	return runtime, nil
+}
+
+// WaitForReady waits for the runtime to become healthy and for each dataset to be ready.
+func (r *Runtime) WaitForReady(rtcontext *context.RuntimeContext, metricsEndpoint string, datasets []string, timeout time.Duration) error {
+	err := waitForReady(rtcontext, metricsEndpoint, datasets, timeout, r.exited)
+	if err == errRuntimeExited && r.exitErr != nil {
+		return fmt.Errorf("%s: %w", err.Error(), r.exitErr)
+	}
+	return err
+}
+
+// Output returns everything the runtime has written to stdout and stderr.
+func (r *Runtime) Output() string {
+	return r.output.String()
+}
+
+// Stop interrupts the runtime and kills it if it has not exited within stopTimeout.
+func (r *Runtime) Stop() error {
+	select {
+	case <-r.exited:
+		return nil
+	default:
+	}
+
+	if err := r.cmd.Process.Signal(os.Interrupt); err != nil {
+		return r.cmd.Process.Kill()
+	}
+
+	select {
+	case <-r.exited:
+		return nil
+	case <-time.After(stopTimeout):
+		return r.cmd.Process.Kill()
+	}
+}
+
+// WaitForReady waits for an already running runtime to become healthy and for each dataset
+// to be ready.
+func WaitForReady(rtcontext *context.RuntimeContext, metricsEndpoint string, datasets []string, timeout time.Duration) error {
+	return waitForReady(rtcontext, metricsEndpoint, datasets, timeout, nil)
+}
+
+func waitForReady(rtcontext *context.RuntimeContext, metricsEndpoint string, datasets []string, timeout time.Duration, exited <-chan struct{}) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		err := rtcontext.IsRuntimeHealthy(time.Second)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for the Spice runtime at %s to become healthy: %w", rtcontext.HttpEndpoint(), err)
+		}
+
+		select {
+		case <-exited:
+			return errRuntimeExited
+		case <-rtcontext.Context().Done():
+			return rtcontext.Context().Err()
+		case <-time.After(healthPollInterval):
+		}
+	}
+
+	for _, dataset := range datasets {
+		err := bench.WaitForDataset(rtcontext, metricsEndpoint, dataset, time.Until(deadline))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	STEP_TYPE_SQL = "sql"
+	STEP_TYPE_API = "api"
+)
+
+// Scenario is an end-to-end test: the spicepod to boot, the datasets to wait for and the
+// steps to run against the runtime once it is ready.
+type Scenario struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Directory containing the spicepod.yaml, relative to the scenario file.
+	Spicepod string `json:"spicepod,omitempty" yaml:"spicepod,omitempty"`
+	// Datasets that must be ready before the first step runs.
+	WaitFor      []string `json:"wait_for,omitempty" yaml:"wait_for,omitempty"`
+	ReadyTimeout string   `json:"ready_timeout,omitempty" yaml:"ready_timeout,omitempty"`
+	Steps        []Step   `json:"steps,omitempty" yaml:"steps,omitempty"`
+
+	dir string
+}
+
+type Step struct {
+	Name   string      `json:"name,omitempty" yaml:"name,omitempty"`
+	Sql    string      `json:"sql,omitempty" yaml:"sql,omitempty"`
+	Api    *ApiStep    `json:"api,omitempty" yaml:"api,omitempty"`
+	Expect Expectation `json:"expect,omitempty" yaml:"expect,omitempty"`
+}
+
+type ApiStep struct {
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	Path   string `json:"path" yaml:"path"`
+	Body   string `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// Expectation lists the assertions made on a step's outcome. Unset fields are not checked.
+type Expectation struct {
+	// Exact number of rows of a sql result.
+	Count *int `json:"count,omitempty" yaml:"count,omitempty"`
+	// Minimum number of rows of a sql result.
+	MinCount *int `json:"min_count,omitempty" yaml:"min_count,omitempty"`
+	// Leading rows of a sql result. Only the columns listed are compared.
+	Rows []map[string]interface{} `json:"rows,omitempty" yaml:"rows,omitempty"`
+	// HTTP status of an api step, 200 by default.
+	Status int `json:"status,omitempty" yaml:"status,omitempty"`
+	// Substring of the response body.
+	Contains string `json:"contains,omitempty" yaml:"contains,omitempty"`
+	// Substring of the error the step is expected to fail with.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func LoadScenario(path string) (*Scenario, error) {
+	scenarioBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenario Scenario
+	err = yaml.Unmarshal(scenarioBytes, &scenario)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing scenario '%s': %w", path, err)
+	}
+
+	if len(scenario.Steps) == 0 {
+		return nil, fmt.Errorf("scenario '%s' defines no steps", path)
+	}
+
+	for i := range scenario.Steps {
+		step := &scenario.Steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+		if step.Type() == "" {
+			return nil, fmt.Errorf("step '%s' of scenario '%s' must define exactly one of sql or api", step.Name, path)
+		}
+		if step.Api != nil {
+			if step.Api.Path == "" {
+				return nil, fmt.Errorf("api step '%s' of scenario '%s' has no path", step.Name, path)
+			}
+			if step.Api.Method == "" {
+				step.Api.Method = "GET"
+			}
+			step.Api.Method = strings.ToUpper(step.Api.Method)
+		}
+	}
+
+	if scenario.ReadyTimeout != "" {
+		if _, err := time.ParseDuration(scenario.ReadyTimeout); err != nil {
+			return nil, fmt.Errorf("invalid ready_timeout in scenario '%s': %w", path, err)
+		}
+	}
+
+	if scenario.Name == "" {
+		scenario.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	scenario.dir = filepath.Dir(path)
+
+	return &scenario, nil
+}
+
+// SpicepodDir returns the directory of the spicepod to boot, resolved against the scenario file.
+func (s *Scenario) SpicepodDir() string {
+	if filepath.IsAbs(s.Spicepod) {
+		return s.Spicepod
+	}
+	return filepath.Join(s.dir, s.Spicepod)
+}
+
+// Type returns the kind of the step, or "" unless exactly one kind is defined.
+func (s *Step) Type() string {
+	var types []string
+	if s.Sql != "" {
+		types = append(types, STEP_TYPE_SQL)
+	}
+	if s.Api != nil {
+		types = append(types, STEP_TYPE_API)
+	}
+	if len(types) != 1 {
+		return ""
+	}
+	return types[0]
+}
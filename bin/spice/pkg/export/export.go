@@ -0,0 +1,395 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const (
+	FORMAT_CSV   = "csv"
+	FORMAT_JSON  = "json"
+	FORMAT_JSONL = "jsonl"
+
+	// Output that writes to stdout instead of a file.
+	STDOUT = "-"
+
+	nullPartition = "__null__"
+)
+
+var Formats = []string{FORMAT_CSV, FORMAT_JSON, FORMAT_JSONL}
+
+type Options struct {
+	Format string
+	// File to write, or the root directory of the partitions when PartitionBy is set.
+	Output      string
+	PartitionBy string
+}
+
+type ExportedFile struct {
+	File string
+	Rows int
+}
+
+// Row is a result row with its columns in the order returned by the runtime.
+type Row struct {
+	Columns []string
+	Values  map[string]interface{}
+	Raw     json.RawMessage
+}
+
+type rowWriter interface {
+	WriteRow(row *Row) error
+	Close() error
+}
+
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	if format == "parquet" || format == "arrow" {
+		return fmt.Errorf("%s output requires Arrow support, which this build of the Spice CLI does not include; use one of: %s", format, strings.Join(Formats, ", "))
+	}
+	return fmt.Errorf("unsupported format '%s', expected one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// Query streams the results of sql to disk, returning a summary of the files written.
+func Query(rtcontext *context.RuntimeContext, sql string, options Options) ([]ExportedFile, error) {
+	if err := ValidateFormat(options.Format); err != nil {
+		return nil, err
+	}
+	if options.PartitionBy != "" && options.Output == STDOUT {
+		return nil, fmt.Errorf("partitioned results cannot be written to stdout")
+	}
+
+	writers := make(map[string]rowWriter)
+	counts := make(map[string]int)
+	closeAll := func() error {
+		var firstErr error
+		for _, writer := range writers {
+			if err := writer.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+
+	err := api.SqlStream(rtcontext, sql, func(raw json.RawMessage) error {
+		row, err := ParseRow(raw)
+		if err != nil {
+			return err
+		}
+
+		path := options.Output
+		if options.PartitionBy != "" {
+			value, ok := row.Values[options.PartitionBy]
+			if !ok {
+				return fmt.Errorf("partition column '%s' is not in the query results", options.PartitionBy)
+			}
+			path = filepath.Join(options.Output, fmt.Sprintf("%s=%s", options.PartitionBy, partitionValue(value)), fmt.Sprintf("part-0.%s", options.Format))
+		}
+
+		writer, ok := writers[path]
+		if !ok {
+			writer, err = newRowWriter(path, options.Format, rtcontext.Out())
+			if err != nil {
+				return err
+			}
+			writers[path] = writer
+		}
+		counts[path]++
+		return writer.WriteRow(row)
+	})
+	if closeErr := closeAll(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty, unpartitioned result still produces a file, e.g. a CSV with no rows.
+	if len(counts) == 0 && options.PartitionBy == "" {
+		writer, err := newRowWriter(options.Output, options.Format, rtcontext.Out())
+		if err != nil {
+			return nil, err
+		}
+		if err = writer.Close(); err != nil {
+			return nil, err
+		}
+		counts[options.Output] = 0
+	}
+
+	files := make([]ExportedFile, 0, len(counts))
+	for path, rows := range counts {
+		files = append(files, ExportedFile{File: path, Rows: rows})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].File < files[j].File })
+	return files, nil
+}
+
+func ParseRow(raw json.RawMessage) (*Row, error) {
+	row := &Row{Values: make(map[string]interface{}), Raw: raw}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("unexpected result row: %s", string(raw))
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		column, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected result row: %s", string(raw))
+		}
+		var value interface{}
+		if err = decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		row.Columns = append(row.Columns, column)
+		row.Values[column] = value
+	}
+
+	return row, nil
+}
+
+// WriteTable renders result rows as a table on out, with the columns of the first row.
+func WriteTable(out *util.Output, raws []json.RawMessage) error {
+	// JSON output keeps the rows as the runtime returned them, with their value types
+	if out.Format() == util.OUTPUT_JSON {
+		if raws == nil {
+			raws = []json.RawMessage{}
+		}
+		rawsBytes, err := json.MarshalIndent(raws, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out.Writer(), string(rawsBytes))
+		return err
+	}
+
+	if len(raws) == 0 {
+		// Other formats write an empty document, without columns as none are known
+		if out.Format() == util.OUTPUT_TABLE {
+			return nil
+		}
+		return out.WriteRowsTable(nil, nil)
+	}
+
+	var columns []string
+	rows := make([][]string, 0, len(raws))
+	for _, raw := range raws {
+		row, err := ParseRow(raw)
+		if err != nil {
+			return err
+		}
+		if columns == nil {
+			columns = row.Columns
+		}
+		values := make([]string, len(columns))
+		for i, column := range columns {
+			values[i] = tableValue(row.Values[column])
+		}
+		rows = append(rows, values)
+	}
+
+	return out.WriteRowsTable(columns, rows)
+}
+
+func tableValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		valueBytes, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(valueBytes)
+	}
+	return fmt.Sprint(value)
+}
+
+func partitionValue(value interface{}) string {
+	if value == nil {
+		return nullPartition
+	}
+	s := fmt.Sprint(value)
+	if s == "" {
+		return nullPartition
+	}
+	return strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(s)
+}
+
+// stdoutWriter is the runtime context's output, which rows written to STDOUT must not close.
+type stdoutWriter struct {
+	io.Writer
+}
+
+func (stdoutWriter) Close() error { return nil }
+
+func newRowWriter(path string, format string, stdout io.Writer) (rowWriter, error) {
+	var file io.WriteCloser = stdoutWriter{stdout}
+	if path != STDOUT {
+		if dir := filepath.Dir(path); dir != "." {
+			if err := os.MkdirAll(dir, 0766); err != nil {
+				return nil, err
+			}
+		}
+		created, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		file = created
+	}
+	buffered := bufio.NewWriter(file)
+
+	switch format {
+	case FORMAT_CSV:
+		return &csvRowWriter{file: file, buffered: buffered, writer: csv.NewWriter(buffered)}, nil
+	case FORMAT_JSON:
+		return &jsonRowWriter{file: file, buffered: buffered}, nil
+	default:
+		return &jsonlRowWriter{file: file, buffered: buffered}, nil
+	}
+}
+
+type csvRowWriter struct {
+	file     io.WriteCloser
+	buffered *bufio.Writer
+	writer   *csv.Writer
+	columns  []string
+}
+
+func (w *csvRowWriter) WriteRow(row *Row) error {
+	if w.columns == nil {
+		w.columns = row.Columns
+		if err := w.writer.Write(w.columns); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		record[i] = CsvValue(row.Values[column])
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvRowWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.file.Close()
+		return err
+	}
+	if err := w.buffered.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+func CsvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+type jsonlRowWriter struct {
+	file     io.WriteCloser
+	buffered *bufio.Writer
+}
+
+func (w *jsonlRowWriter) WriteRow(row *Row) error {
+	if _, err := w.buffered.Write(row.Raw); err != nil {
+		return err
+	}
+	return w.buffered.WriteByte('\n')
+}
+
+func (w *jsonlRowWriter) Close() error {
+	if err := w.buffered.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+type jsonRowWriter struct {
+	file     io.WriteCloser
+	buffered *bufio.Writer
+	rows     int
+}
+
+func (w *jsonRowWriter) WriteRow(row *Row) error {
+	separator := ",\n"
+	if w.rows == 0 {
+		separator = "[\n"
+	}
+	w.rows++
+	if _, err := w.buffered.WriteString(separator); err != nil {
+		return err
+	}
+	_, err := w.buffered.Write(row.Raw)
+	return err
+}
+
+func (w *jsonRowWriter) Close() error {
+	closing := "\n]\n"
+	if w.rows == 0 {
+		closing = "[]\n"
+	}
+	if _, err := w.buffered.WriteString(closing); err != nil {
+		w.file.Close()
+		return err
+	}
+	if err := w.buffered.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
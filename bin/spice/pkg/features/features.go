@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+)
+
+// Feature is an optional feature the runtime is compiled with, such as a data connector.
+type Feature struct {
+	Name        string `json:"name" csv:"name" yaml:"name"`
+	Description string `json:"description" csv:"description" yaml:"description"`
+	Enabled     bool   `json:"enabled" csv:"enabled" yaml:"enabled"`
+}
+
+// connectorFeatures maps the data connectors and accelerator engines that are only in some
+// runtime builds to the feature that provides them.
+var connectorFeatures = map[string]string{
+	"clickhouse": "clickhouse",
+	"databricks": "databricks",
+	"dremio":     "dremio",
+	"duckdb":     "duckdb",
+	"flightsql":  "flightsql",
+	"ftp":        "ftp",
+	"sftp":       "ftp",
+	"mysql":      "mysql",
+	"odbc":       "odbc",
+	"postgres":   "postgres",
+	"snowflake":  "snowflake",
+	"spark":      "spark",
+	"sqlite":     "sqlite",
+}
+
+var secretStoreFeatures = map[string]string{
+	"keyring":             "keyring-secret-store",
+	"aws_secrets_manager": "aws-secrets-manager",
+}
+
+// List returns the features reported by the runtime. Runtimes older than the features
+// endpoint fail with a message to upgrade.
+func List(rtcontext *context.RuntimeContext) ([]Feature, error) {
+	features, err := api.GetData[Feature](rtcontext, "/v1/features")
+	if err != nil {
+		var apiErr *api.RuntimeApiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("the runtime does not report its features, upgrade it with spice install")
+		}
+		return nil, err
+	}
+	return features, nil
+}
+
+// Required returns the features the spicepod's components need, mapped to the components
+// that need them, e.g. "postgres" -> ["dataset orders"].
+func Required(pod *spec.SpicepodSpec, datasets []*spec.DatasetSpec) map[string][]string {
+	required := map[string][]string{}
+	add := func(feature string, component string) {
+		for _, existing := range required[feature] {
+			if existing == component {
+				return
+			}
+		}
+		required[feature] = append(required[feature], component)
+	}
+
+	for _, dataset := range datasets {
+		component := fmt.Sprintf("dataset %s", dataset.Name)
+		source, _, _ := strings.Cut(dataset.From, ":")
+		if feature, ok := connectorFeatures[source]; ok {
+			add(feature, component)
+		}
+		if dataset.Acceleration != nil && dataset.Acceleration.Enabled {
+			if feature, ok := connectorFeatures[dataset.Acceleration.Engine]; ok {
+				add(feature, component)
+			}
+		}
+	}
+
+	if pod != nil {
+		if len(pod.Models) > 0 {
+			add("models", "models")
+		}
+		if feature, ok := secretStoreFeatures[pod.Secrets.Store]; ok {
+			add(feature, fmt.Sprintf("secret store %s", pod.Secrets.Store))
+		}
+	}
+
+	for _, components := range required {
+		sort.Strings(components)
+	}
+	return required
+}
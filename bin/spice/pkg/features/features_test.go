@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequired(t *testing.T) {
+	pod := &spec.SpicepodSpec{
+		Models:  []*spec.Reference{{}},
+		Secrets: spec.Secrets{Store: "keyring"},
+	}
+	datasets := []*spec.DatasetSpec{
+		{Name: "orders", From: "postgres:orders", Acceleration: &spec.AccelerationSpec{Enabled: true, Engine: "duckdb"}},
+		{Name: "files", From: "sftp://host/files/"},
+		{Name: "lake", From: "s3://bucket/lake/", Acceleration: &spec.AccelerationSpec{Enabled: false, Engine: "sqlite"}},
+		{Name: "customers", From: "postgres:customers", Acceleration: &spec.AccelerationSpec{Enabled: true, Engine: "postgres"}},
+	}
+
+	assert.Equal(t, map[string][]string{
+		"postgres":             {"dataset customers", "dataset orders"},
+		"duckdb":               {"dataset orders"},
+		"ftp":                  {"dataset files"},
+		"models":               {"models"},
+		"keyring-secret-store": {"secret store keyring"},
+	}, Required(pod, datasets))
+
+	assert.Empty(t, Required(nil, nil))
+}
@@ -20,10 +20,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path"
-	"path/filepath"
-
-	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
 type ReleaseAsset struct {
@@ -47,34 +43,60 @@ func DownloadReleaseAsset(gh *GitHubClient, release *RepoRelease, assetName stri
 		return errors.New("no release assets found")
 	}
 
-	var asset *ReleaseAsset
-	for _, a := range release.Assets {
-		if a.Name == assetName {
-			asset = &a
-			break
-		}
-	}
-
+	asset := findAsset(release, assetName)
 	if asset == nil {
 		return errors.New("no matching asset found")
 	}
 
-	assetUrl := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/assets/%d", gh.Owner, gh.Repo, asset.ID)
+	filePath, err := gh.downloadToFile(gh.assetUrl(asset), "application/octet-stream", downloadDir, assetName, asset.Size)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(filePath)
 
-	body, err := gh.call("GET", assetUrl, nil, "application/octet-stream")
+	if assetName != CHECKSUMS_ASSET_NAME {
+		err = verifyReleaseAsset(gh, release, assetName, filePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return extractFile(filePath, assetName, downloadDir)
+}
+
+// verifyReleaseAsset checks a downloaded asset against the release's checksums.txt. Releases
+// published before checksums were added have nothing to verify against, which is reported.
+func verifyReleaseAsset(gh *GitHubClient, release *RepoRelease, assetName string, filePath string) error {
+	if !release.HasAsset(CHECKSUMS_ASSET_NAME) {
+		warnUnverified(gh, release.TagName, assetName)
+		return nil
+	}
+
+	checksumsBody, err := gh.call("GET", gh.assetUrl(findAsset(release, CHECKSUMS_ASSET_NAME)), nil, "application/octet-stream")
+	if err != nil {
+		return fmt.Errorf("error downloading %s: %w", CHECKSUMS_ASSET_NAME, err)
+	}
+	checksums, err := ParseChecksums(checksumsBody)
 	if err != nil {
 		return err
 	}
+	return VerifyFileChecksum(checksums, assetName, filePath)
+}
 
-	ext := path.Ext(assetName)
+// warnUnverified reports a download of a release published before checksums were added.
+func warnUnverified(gh *GitHubClient, tagName string, assetName string) {
+	fmt.Fprintf(gh.output(), "Warning: release %s has no %s, the download of %s was not verified.\n", tagName, CHECKSUMS_ASSET_NAME, assetName)
+}
 
-	switch ext {
-	case ".zip":
-		return util.ExtractZip(body, downloadDir)
-	case ".gz":
-		return util.ExtractTarGz(body, downloadDir)
-	default:
-		filePath := filepath.Join(downloadDir, assetName)
-		return os.WriteFile(filePath, body, 0766)
+func findAsset(release *RepoRelease, assetName string) *ReleaseAsset {
+	for i := range release.Assets {
+		if release.Assets[i].Name == assetName {
+			return &release.Assets[i]
+		}
 	}
+	return nil
+}
+
+func (g *GitHubClient) assetUrl(asset *ReleaseAsset) string {
+	return g.RepoApiUrl(fmt.Sprintf("releases/assets/%d", asset.ID))
 }
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const CHECKSUMS_ASSET_NAME = "checksums.txt"
+
+// ChecksumError reports a downloaded asset whose SHA256 digest does not match the one
+// published with its release.
+type ChecksumError struct {
+	AssetName string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected sha256 %s, got %s. The download is corrupt or has been tampered with and was not installed", e.AssetName, e.Expected, e.Actual)
+}
+
+// ParseChecksums reads a checksums file in the format written by sha256sum, one
+// "<sha256>  <filename>" line per asset, into a map of filename to digest.
+func ParseChecksums(content []byte) (map[string]string, error) {
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid line in %s: %q", CHECKSUMS_ASSET_NAME, line)
+		}
+		digest := strings.ToLower(fields[0])
+		if _, err := hex.DecodeString(digest); err != nil || len(digest) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid sha256 digest in %s: %q", CHECKSUMS_ASSET_NAME, fields[0])
+		}
+		// sha256sum marks files hashed in binary mode with a leading '*'
+		checksums[strings.TrimPrefix(fields[1], "*")] = digest
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+// VerifyChecksum checks content against the digest published for assetName in checksums.
+func VerifyChecksum(checksums map[string]string, assetName string, content []byte) error {
+	hash := sha256.Sum256(content)
+	return verifyDigest(checksums, assetName, hash[:])
+}
+
+// VerifyFileChecksum checks the file at path against the digest published for assetName in
+// checksums, without reading the file into memory.
+func VerifyFileChecksum(checksums map[string]string, assetName string, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash, err := util.ComputeHash(file)
+	if err != nil {
+		return err
+	}
+	return verifyDigest(checksums, assetName, hash)
+}
+
+func verifyDigest(checksums map[string]string, assetName string, hash []byte) error {
+	expected, ok := checksums[assetName]
+	if !ok {
+		return fmt.Errorf("%s has no checksum for %s, refusing to install an unverified download", CHECKSUMS_ASSET_NAME, assetName)
+	}
+
+	actual := hex.EncodeToString(hash)
+	if actual != expected {
+		return &ChecksumError{AssetName: assetName, Expected: expected, Actual: actual}
+	}
+	return nil
+}
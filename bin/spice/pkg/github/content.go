@@ -41,7 +41,7 @@ type Links struct {
 }
 
 func GetContents(gh *GitHubClient, path string) ([]RepoContent, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", gh.Owner, gh.Repo, path)
+	url := gh.RepoApiUrl(fmt.Sprintf("contents/%s", path))
 	body, err := gh.Get(url, nil)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const maxDownloadAttempts = 5
+
+// downloadToFile streams url into a temporary file in dir, showing a progress bar for name. A
+// download interrupted by a network or server error resumes where it stopped with an HTTP
+// range request. size is the expected size, 0 if unknown. The caller removes the file.
+func (g *GitHubClient) downloadToFile(url string, accept string, dir string, name string, size int64) (string, error) {
+	file, err := os.CreateTemp(dir, fmt.Sprintf(".%s.*.part", name))
+	if err != nil {
+		return "", err
+	}
+
+	bar := progress.NewByteBar(g.output(), fmt.Sprintf("Downloading %s", name), int(size))
+	var written int64
+	for attempt := 1; ; attempt++ {
+		var transient bool
+		transient, err = g.downloadFrom(url, accept, file, &written, bar)
+		if err == nil || !transient || attempt == maxDownloadAttempts {
+			break
+		}
+		bar.Printf("Download of %s interrupted (%s), resuming from %s\n", name, err.Error(), util.FormatBytes(float64(written)))
+		time.Sleep(time.Duration(attempt) * g.settings().ResumeBackoff)
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	bar.Finish()
+	return file.Name(), nil
+}
+
+// downloadFrom requests url from the written offset onwards and appends the response to file,
+// reporting whether a failure is transient, so the download can resume.
+func (g *GitHubClient) downloadFrom(url string, accept string, file *os.File, written *int64, bar *progress.Bar) (bool, error) {
+	req, err := http.NewRequestWithContext(g.context(), "GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+	if accept != "" {
+		req.Header.Add("Accept", accept)
+	}
+	if *written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", *written))
+	}
+
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// A canceled download is not resumed
+		return g.context().Err() == nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		// Servers that don't support ranges send the whole file again
+		if err = restartDownload(file, written); err != nil {
+			return false, err
+		}
+		if response.ContentLength > 0 {
+			bar.SetTotal(int(response.ContentLength))
+		}
+	case http.StatusPartialContent:
+		// A server or proxy ignoring the requested offset would corrupt the file, so the
+		// download starts over
+		if start, ok := contentRangeStart(response.Header.Get("Content-Range")); !ok || start != *written {
+			resumed := *written
+			if err = restartDownload(file, written); err != nil {
+				return false, err
+			}
+			return true, fmt.Errorf("the server did not resume at byte %d, restarting", resumed)
+		}
+	default:
+		body, _ := io.ReadAll(response.Body)
+		return response.StatusCode >= http.StatusInternalServerError, NewGitHubCallError(fmt.Sprintf("Error calling GitHub: %s", string(body)), response.StatusCode)
+	}
+
+	_, err = io.Copy(&progressWriter{file: file, written: written, bar: bar}, response.Body)
+	return g.context().Err() == nil, err
+}
+
+// restartDownload discards what was written to file so far.
+func restartDownload(file *os.File, written *int64) error {
+	if *written == 0 {
+		return nil
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	*written = 0
+	return nil
+}
+
+// contentRangeStart returns the first byte of a Content-Range header like "bytes 100-999/1000".
+func contentRangeStart(contentRange string) (int64, bool) {
+	byteRange, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(byteRange, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	return n, err == nil
+}
+
+// progressWriter writes to file, counting the bytes written and advancing bar with them.
+type progressWriter struct {
+	file    *os.File
+	written *int64
+	bar     *progress.Bar
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	*w.written += int64(n)
+	w.bar.Set(int(*w.written))
+	return n, err
+}
+
+// extractFile unpacks a downloaded archive into downloadDir by its extension, or moves any
+// other file there as assetName.
+func extractFile(filePath string, assetName string, downloadDir string) error {
+	switch path.Ext(assetName) {
+	case ".zip":
+		return util.ExtractZipFile(filePath, downloadDir)
+	case ".gz":
+		return util.ExtractTarGzFile(filePath, downloadDir)
+	default:
+		target := filepath.Join(downloadDir, assetName)
+		if err := os.Rename(filePath, target); err != nil {
+			return err
+		}
+		return os.Chmod(target, 0766)
+	}
+}
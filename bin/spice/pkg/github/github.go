@@ -18,18 +18,45 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
+const (
+	GITHUB_API_URL = "https://api.github.com"
+	GITHUB_URL     = "https://github.com"
+)
+
+// Settings change where requests bound to a Go context are sent and how they are retried.
+type Settings struct {
+	// Where both the GitHub API and release downloads are sent, e.g. a local fake GitHub in
+	// tests. Empty for GitHub.
+	BaseUrl string
+	// Resuming an interrupted download waits this long times the number of failed attempts,
+	// a second when zero.
+	ResumeBackoff time.Duration
+}
+
+type settingsKey struct{}
+
+// WithSettings returns a copy of ctx whose GitHub requests use settings.
+func WithSettings(ctx context.Context, settings Settings) context.Context {
+	return context.WithValue(ctx, settingsKey{}, settings)
+}
+
 type GitHubClient struct {
 	Owner string
 	Repo  string
+
+	ctx            context.Context
+	progressOutput io.Writer
 }
 
 func NewGitHubClientFromPath(path string) (*GitHubClient, error) {
@@ -52,6 +79,62 @@ func NewGitHubClient(owner string, repo string) *GitHubClient {
 	}
 }
 
+// WithContext returns a copy of the client whose requests are bound to ctx.
+func (g *GitHubClient) WithContext(ctx context.Context) *GitHubClient {
+	client := *g
+	client.ctx = ctx
+	return &client
+}
+
+func (g *GitHubClient) context() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
+
+// WithProgressOutput returns a copy of the client rendering download progress and warnings
+// to w instead of stderr.
+func (g *GitHubClient) WithProgressOutput(w io.Writer) *GitHubClient {
+	client := *g
+	client.progressOutput = w
+	return &client
+}
+
+func (g *GitHubClient) settings() Settings {
+	settings, _ := g.context().Value(settingsKey{}).(Settings)
+	if settings.ResumeBackoff <= 0 {
+		settings.ResumeBackoff = time.Second
+	}
+	return settings
+}
+
+func (g *GitHubClient) apiBaseUrl() string {
+	if baseUrl := g.settings().BaseUrl; baseUrl != "" {
+		return strings.TrimSuffix(baseUrl, "/")
+	}
+	return GITHUB_API_URL
+}
+
+func (g *GitHubClient) webBaseUrl() string {
+	if baseUrl := g.settings().BaseUrl; baseUrl != "" {
+		return strings.TrimSuffix(baseUrl, "/")
+	}
+	return GITHUB_URL
+}
+
+func (g *GitHubClient) output() io.Writer {
+	if g.progressOutput == nil {
+		return os.Stderr
+	}
+	return g.progressOutput
+}
+
+// RepoApiUrl returns the URL of path under the repository in the GitHub API.
+func (g *GitHubClient) RepoApiUrl(path string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/%s", g.apiBaseUrl(), g.Owner, g.Repo, strings.TrimPrefix(path, "/"))
+}
+
 func (g *GitHubClient) Get(url string, payload []byte) ([]byte, error) {
 	return g.call("GET", url, payload, "application/vnd.github.v3+json")
 }
@@ -66,12 +149,13 @@ func (g *GitHubClient) DownloadFile(url string, downloadPath string) error {
 }
 
 func (g *GitHubClient) DownloadTarGzip(url string, downloadDir string) error {
-	body, err := g.Get(url, nil)
+	filePath, err := g.downloadToFile(url, "application/vnd.github.v3+json", downloadDir, fmt.Sprintf("%s.tar.gz", g.Repo), 0)
 	if err != nil {
 		return err
 	}
+	defer os.Remove(filePath)
 
-	return util.ExtractTarGz(body, downloadDir)
+	return util.ExtractTarGzFile(filePath, downloadDir)
 }
 
 func (g *GitHubClient) call(method string, url string, payload []byte, accept string) ([]byte, error) {
@@ -81,7 +165,7 @@ func (g *GitHubClient) call(method string, url string, payload []byte, accept st
 
 	payloadReader := bytes.NewReader(payload)
 
-	req, err := http.NewRequest(method, url, payloadReader)
+	req, err := http.NewRequestWithContext(g.context(), method, url, payloadReader)
 	if err != nil {
 		return nil, err
 	}
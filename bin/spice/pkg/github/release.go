@@ -18,10 +18,14 @@ package github
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
 	"sort"
 	"strings"
 
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 	"golang.org/x/mod/semver"
 )
 
@@ -78,7 +82,7 @@ func (r *RepoRelease) HasAsset(assetName string) bool {
 }
 
 func GetReleases(gh *GitHubClient) (RepoReleases, error) {
-	releasesURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", gh.Owner, gh.Repo)
+	releasesURL := gh.RepoApiUrl("releases")
 	body, err := gh.Get(releasesURL, nil)
 	if err != nil {
 		return nil, err
@@ -121,16 +125,56 @@ func GetLatestRelease(gh *GitHubClient, assetName string) (*RepoRelease, error)
 	return nil, fmt.Errorf("no releases")
 }
 
+// GetReleaseByTagName returns the release of the given tag, including drafts and prereleases.
+func GetReleaseByTagName(gh *GitHubClient, tagName string) (*RepoRelease, error) {
+	body, err := gh.Get(gh.RepoApiUrl(fmt.Sprintf("releases/tags/%s", tagName)), nil)
+	if err != nil {
+		var callErr *GitHubCallError
+		if errors.As(err, &callErr) && callErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("release %s not found", tagName)
+		}
+		return nil, err
+	}
+
+	var release RepoRelease
+	err = json.Unmarshal(body, &release)
+	if err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
 func DownloadReleaseByTagName(gh *GitHubClient, tagName string, downloadDir string, filename string) error {
 	archiveExt := "tar.gz"
+	assetName := fmt.Sprintf("%s.%s", filename, archiveExt)
 
-	releaseUrl := fmt.Sprintf(
-		"https://github.com/%s/%s/releases/download/%s/%s.%s",
-		gh.Owner,
-		gh.Repo,
-		tagName,
-		filename,
-		archiveExt)
+	filePath, err := gh.downloadToFile(gh.releaseDownloadUrl(tagName, assetName), "", downloadDir, assetName, 0)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(filePath)
+
+	checksumsBody, err := gh.Get(gh.releaseDownloadUrl(tagName, CHECKSUMS_ASSET_NAME), nil)
+	var callErr *GitHubCallError
+	switch {
+	case errors.As(err, &callErr) && callErr.StatusCode == http.StatusNotFound:
+		warnUnverified(gh, tagName, assetName)
+	case err != nil:
+		return fmt.Errorf("error downloading %s: %w", CHECKSUMS_ASSET_NAME, err)
+	default:
+		checksums, err := ParseChecksums(checksumsBody)
+		if err != nil {
+			return err
+		}
+		if err = VerifyFileChecksum(checksums, assetName, filePath); err != nil {
+			return err
+		}
+	}
+
+	return util.ExtractTarGzFile(filePath, downloadDir)
+}
 
-	return gh.DownloadTarGzip(releaseUrl, downloadDir)
+func (g *GitHubClient) releaseDownloadUrl(tagName string, assetName string) string {
+	return fmt.Sprintf("%s/%s/%s/releases/download/%s/%s", g.webBaseUrl(), g.Owner, g.Repo, tagName, assetName)
 }
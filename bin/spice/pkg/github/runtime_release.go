@@ -17,7 +17,9 @@ limitations under the License.
 package github
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"runtime"
 
 	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
@@ -32,19 +34,32 @@ const (
 	runtimeRepo  = "spiceai"
 )
 
-func GetLatestRuntimeRelease() (*RepoRelease, error) {
-	fmt.Println("Checking for latest Spice runtime release...")
+func GetLatestRuntimeRelease(ctx context.Context) (*RepoRelease, error) {
+	release, err := GetLatestRelease(githubClient.WithContext(ctx), GetAssetName(constants.SpiceRuntimeFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	return release, nil
+}
 
-	release, err := GetLatestRelease(githubClient, GetAssetName(constants.SpiceRuntimeFilename))
+// GetRuntimeRelease returns the runtime release of the given tag, failing if it has no
+// runtime build for this platform.
+func GetRuntimeRelease(ctx context.Context, tagName string) (*RepoRelease, error) {
+	release, err := GetReleaseByTagName(githubClient.WithContext(ctx), tagName)
 	if err != nil {
 		return nil, err
 	}
 
+	if !release.HasAsset(GetRuntimeAssetName()) {
+		return nil, fmt.Errorf("release %s has no runtime build for %s/%s", tagName, runtime.GOOS, runtime.GOARCH)
+	}
+
 	return release, nil
 }
 
-func GetLatestCliRelease() (*RepoRelease, error) {
-	release, err := GetLatestRelease(githubClient, GetAssetName(constants.SpiceCliFilename))
+func GetLatestCliRelease(ctx context.Context) (*RepoRelease, error) {
+	release, err := GetLatestRelease(githubClient.WithContext(ctx), GetAssetName(constants.SpiceCliFilename))
 	if err != nil {
 		return nil, err
 	}
@@ -52,14 +67,14 @@ func GetLatestCliRelease() (*RepoRelease, error) {
 	return release, nil
 }
 
-func DownloadRuntimeAsset(release *RepoRelease, downloadPath string) error {
-	assetName := GetRuntimeAssetName()
-	fmt.Println("Downloading the Spice runtime...", assetName)
-	return DownloadReleaseAsset(githubClient, release, assetName, downloadPath)
+// DownloadRuntimeAsset downloads the runtime of release into downloadPath, rendering the
+// download's progress to progressOutput.
+func DownloadRuntimeAsset(ctx context.Context, progressOutput io.Writer, release *RepoRelease, downloadPath string) error {
+	return DownloadReleaseAsset(githubClient.WithContext(ctx).WithProgressOutput(progressOutput), release, GetRuntimeAssetName(), downloadPath)
 }
 
-func DownloadAsset(release *RepoRelease, downloadPath string, assetName string) error {
-	return DownloadReleaseAsset(githubClient, release, assetName, downloadPath)
+func DownloadAsset(ctx context.Context, progressOutput io.Writer, release *RepoRelease, downloadPath string, assetName string) error {
+	return DownloadReleaseAsset(githubClient.WithContext(ctx).WithProgressOutput(progressOutput), release, assetName, downloadPath)
 }
 
 func GetRuntimeAssetName() string {
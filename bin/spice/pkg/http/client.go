@@ -17,36 +17,61 @@ limitations under the License.
 package http
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	net_http "net/http"
 	"runtime"
+	"sync"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/spiceai/spiceai/bin/spice/pkg/version"
 )
 
-var _userAgent string
-var client *retryablehttp.Client
+// The client and user agent are shared by concurrent requests, e.g. notifications sent to every
+// subscription at once, so they are set up once.
+var (
+	clientOnce sync.Once
+	client     *retryablehttp.Client
+	userAgent  = sync.OnceValue(func() string {
+		return fmt.Sprintf("Spice.ai/spice %s/%s (%s)", version.Version(), version.Version(), runtime.GOOS)
+	})
+)
 
 func RetryableClient() *retryablehttp.Client {
-	if client == nil {
+	clientOnce.Do(func() {
 		client = retryablehttp.NewClient()
 		client.Logger = log.New(io.Discard, "", 0)
-	}
+	})
 	return client
 }
 
-func Get(url string, accept string) (*net_http.Response, error) {
-	req, err := retryablehttp.NewRequest("GET", url, nil)
+func Get(ctx context.Context, url string, accept string, headers map[string]string) (*net_http.Response, error) {
+	req, err := retryablehttp.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
 	return do(req, accept)
 }
 
+func Post(ctx context.Context, url string, contentType string, body []byte, headers map[string]string) (*net_http.Response, error) {
+	req, err := retryablehttp.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return do(req, "application/json")
+}
+
 func do(req *retryablehttp.Request, accept string) (*net_http.Response, error) {
 	req.Header.Set("User-Agent", userAgent())
 	if accept != "" {
@@ -60,10 +85,3 @@ func do(req *retryablehttp.Request, accept string) (*net_http.Response, error) {
 
 	return resp, nil
 }
-
-func userAgent() string {
-	if _userAgent == "" {
-		_userAgent = fmt.Sprintf("Spice.ai/spice %s/%s (%s)", version.Version(), version.Version(), runtime.GOOS)
-	}
-	return _userAgent
-}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DEFAULT_LOCALE's catalog holds every message, other catalogs may translate a subset.
+const DEFAULT_LOCALE = "en"
+
+// Catalogs are locales/<locale>.yaml files mapping message keys to fmt format strings. A
+// language is added by adding its file, e.g. locales/pt_BR.yaml.
+//
+//go:embed locales
+var localeFiles embed.FS
+
+var (
+	localeMu sync.RWMutex
+	locale   = DEFAULT_LOCALE
+
+	loadOnce sync.Once
+	catalogs map[string]map[string]string
+)
+
+// SetLocale selects the catalog messages are looked up in, e.g. "de" or "pt_BR". Locales
+// without a catalog fall back to their language and then to DEFAULT_LOCALE.
+func SetLocale(l string) {
+	l = normalize(l)
+	if l == "" {
+		l = DEFAULT_LOCALE
+	}
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	locale = l
+}
+
+func Locale() string {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	return locale
+}
+
+// DetectLocale returns configured when set, otherwise the locale of the environment from
+// LC_ALL, LC_MESSAGES or LANG.
+func DetectLocale(configured string) string {
+	if configured != "" {
+		return normalize(configured)
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l := normalize(os.Getenv(env)); l != "" {
+			return l
+		}
+	}
+	return DEFAULT_LOCALE
+}
+
+// Locales lists the locales with a catalog.
+func Locales() []string {
+	load()
+	var locales []string
+	for l := range catalogs {
+		locales = append(locales, l)
+	}
+	return locales
+}
+
+// T formats the message key in the current locale. Keys missing from every catalog are
+// returned as they are, so a typo shows up instead of an empty message.
+func T(key string, args ...interface{}) string {
+	message, ok := Lookup(key)
+	if !ok {
+		message = key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Lookup returns the unformatted message key in the current locale or DEFAULT_LOCALE.
+func Lookup(key string) (string, bool) {
+	load()
+	for _, l := range fallbacks(Locale()) {
+		if message, ok := catalogs[l][key]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// fallbacks returns the catalogs to try for l, e.g. de_AT, de, en.
+func fallbacks(l string) []string {
+	locales := []string{l}
+	if language, _, ok := strings.Cut(l, "_"); ok {
+		locales = append(locales, language)
+	}
+	return append(locales, DEFAULT_LOCALE)
+}
+
+// normalize turns environment values like de_DE.UTF-8 or de-DE@euro into de_DE. The C and
+// POSIX locales are English.
+func normalize(l string) string {
+	l, _, _ = strings.Cut(l, ".")
+	l, _, _ = strings.Cut(l, "@")
+	l = strings.ReplaceAll(strings.TrimSpace(l), "-", "_")
+	if l == "C" || l == "POSIX" {
+		return DEFAULT_LOCALE
+	}
+	if language, region, ok := strings.Cut(l, "_"); ok {
+		return strings.ToLower(language) + "_" + strings.ToUpper(region)
+	}
+	return strings.ToLower(l)
+}
+
+func load() {
+	loadOnce.Do(func() {
+		catalogs = map[string]map[string]string{}
+		entries, err := localeFiles.ReadDir("locales")
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			catalogBytes, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+			if err != nil {
+				continue
+			}
+			catalog := map[string]string{}
+			if err = yaml.Unmarshal(catalogBytes, &catalog); err != nil {
+				continue
+			}
+			catalogs[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = catalog
+		}
+	})
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]`)
+
+func TestCatalogs(t *testing.T) {
+	load()
+	english := catalogs[DEFAULT_LOCALE]
+	assert.NotEmpty(t, english)
+
+	for l, catalog := range catalogs {
+		for key, message := range catalog {
+			englishMessage, ok := english[key]
+			if !assert.True(t, ok, "%s: %s is not in the %s catalog", l, key, DEFAULT_LOCALE) {
+				continue
+			}
+			assert.Equal(t, formatVerb.FindAllString(englishMessage, -1), formatVerb.FindAllString(message, -1), "%s: %s", l, key)
+		}
+	}
+}
+
+func TestDetectLocale(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	assert.Equal(t, "de_DE", DetectLocale(""))
+	assert.Equal(t, "pt_BR", DetectLocale("pt-br"))
+
+	t.Setenv("LC_ALL", "C")
+	assert.Equal(t, DEFAULT_LOCALE, DetectLocale(""))
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale("")
+
+	SetLocale("de_AT")
+	assert.Equal(t, "Verwendung:", T("help.usage"))
+	assert.Equal(t, "Die Spice-Runtime ist unter http://localhost:8090 nicht erreichbar. Läuft sie?", T("error.runtime_unavailable", "http://localhost:8090"))
+
+	SetLocale("fr")
+	assert.Equal(t, "Usage:", T("help.usage"))
+	assert.Equal(t, "help.missing", T("help.missing"))
+}
@@ -0,0 +1,427 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingest
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/export"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	FORMAT_CSV     = "csv"
+	FORMAT_PARQUET = "parquet"
+	FORMAT_JSON    = "json"
+	FORMAT_JSONL   = "jsonl"
+
+	schemaSampleRows = 100
+	maxSampleLength  = 32
+)
+
+var ErrSchemaInferenceUnsupported = errors.New("schema preview is not available for parquet files, the runtime reads the schema from the file")
+
+type ColumnInfo struct {
+	Column string
+	Type   string
+	Sample string
+}
+
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FORMAT_CSV, nil
+	case ".parquet":
+		return FORMAT_PARQUET, nil
+	case ".json":
+		return FORMAT_JSON, nil
+	case ".jsonl", ".ndjson":
+		return FORMAT_JSONL, nil
+	}
+	return "", fmt.Errorf("cannot detect the format of '%s', use --format csv, parquet, json or jsonl", path)
+}
+
+// StorageFormat is the file format the runtime reads the data in. JSON is not supported
+// by the file connector, so it is converted to CSV on import.
+func StorageFormat(format string) string {
+	if format == FORMAT_JSON || format == FORMAT_JSONL {
+		return FORMAT_CSV
+	}
+	return format
+}
+
+func DataDir(appDir string, dataset string) string {
+	return filepath.Join(appDir, "data", dataset)
+}
+
+// InferSchema samples the first rows of a file and infers a type for each column, as
+// the runtime would for the CSV it reads.
+func InferSchema(path string, format string) ([]ColumnInfo, error) {
+	var columns []string
+	samples := make(map[string][]string)
+
+	switch format {
+	case FORMAT_PARQUET:
+		return nil, ErrSchemaInferenceUnsupported
+	case FORMAT_CSV:
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		columns, err = reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("error reading the header of '%s': %w", path, err)
+		}
+		for i := 0; i < schemaSampleRows; i++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error reading '%s': %w", path, err)
+			}
+			for j, value := range record {
+				if j < len(columns) {
+					samples[columns[j]] = append(samples[columns[j]], value)
+				}
+			}
+		}
+	default:
+		rows := 0
+		err := readJsonRows(path, format, func(row *export.Row) error {
+			if rows >= schemaSampleRows {
+				return errStopReading
+			}
+			rows++
+			for _, column := range row.Columns {
+				if _, ok := samples[column]; !ok {
+					columns = append(columns, column)
+				}
+				samples[column] = append(samples[column], export.CsvValue(row.Values[column]))
+			}
+			return nil
+		})
+		if err != nil && err != errStopReading {
+			return nil, err
+		}
+	}
+
+	schema := make([]ColumnInfo, len(columns))
+	for i, column := range columns {
+		schema[i] = ColumnInfo{Column: column, Type: inferType(samples[column]), Sample: firstSample(samples[column])}
+	}
+	return schema, nil
+}
+
+// ExistingColumns reads the header of a CSV file already imported into dataDir, or
+// returns nil if there is none.
+func ExistingColumns(dataDir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dataDir, "*.csv"))
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	file, err := os.Open(files[0])
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	columns, err := csv.NewReader(file).Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	return columns, err
+}
+
+// CopyInto places the file in dataDir in its storage format, returning the new file's path.
+// When columns is set, CSV data must have exactly these columns and JSON is written in their order.
+func CopyInto(path string, format string, dataDir string, columns []string) (string, error) {
+	err := os.MkdirAll(dataDir, 0766)
+	if err != nil {
+		return "", err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	destination := filepath.Join(dataDir, fmt.Sprintf("%s.%s", base, StorageFormat(format)))
+	if _, err := os.Stat(destination); err == nil {
+		destination = filepath.Join(dataDir, fmt.Sprintf("%s-%d.%s", base, time.Now().UnixNano(), StorageFormat(format)))
+	}
+
+	if format == FORMAT_JSON || format == FORMAT_JSONL {
+		return destination, convertJsonToCsv(path, format, destination, columns)
+	}
+
+	if format == FORMAT_CSV && columns != nil {
+		if err := checkCsvColumns(path, columns); err != nil {
+			return "", err
+		}
+	}
+
+	source, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+
+	target, err := os.Create(destination)
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(target, source); err != nil {
+		target.Close()
+		return "", err
+	}
+	return destination, target.Close()
+}
+
+// NewDatasetSpec describes an accelerated dataset reading every file in dataDir.
+func NewDatasetSpec(name string, dataDir string, storageFormat string) (spec.DatasetSpec, error) {
+	absDataDir, err := filepath.Abs(dataDir)
+	if err != nil {
+		return spec.DatasetSpec{}, err
+	}
+
+	return spec.DatasetSpec{
+		From:   fmt.Sprintf("file:%s/", filepath.ToSlash(absDataDir)),
+		Name:   name,
+		Params: map[string]string{"file_format": storageFormat},
+		Acceleration: &spec.AccelerationSpec{
+			Enabled:     true,
+			RefreshMode: spec.REFRESH_MODE_FULL,
+		},
+	}, nil
+}
+
+// WriteDataset saves the dataset under datasets/<name>/dataset.yaml and references it
+// from the app's spicepod.yaml.
+func WriteDataset(appDir string, dataset spec.DatasetSpec) (string, error) {
+	datasetBytes, err := yaml.Marshal(dataset)
+	if err != nil {
+		return "", err
+	}
+
+	ref := fmt.Sprintf("datasets/%s", dataset.Name)
+	dirPath := filepath.Join(appDir, ref)
+	err = os.MkdirAll(dirPath, 0766)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(dirPath, "dataset.yaml")
+	err = os.WriteFile(filePath, datasetBytes, 0766)
+	if err != nil {
+		return "", err
+	}
+
+	return filePath, spicepod.AddDatasetReference(appDir, ref)
+}
+
+var errStopReading = errors.New("stop reading")
+
+func readJsonRows(path string, format string, onRow func(row *export.Row) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if format == FORMAT_JSONL {
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			row, err := export.ParseRow(json.RawMessage(line))
+			if err != nil {
+				return err
+			}
+			if err = onRow(row); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+	}
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return fmt.Errorf("'%s' is not a JSON array of objects, use --format jsonl for newline-delimited JSON", path)
+	}
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return fmt.Errorf("error reading '%s': %w", path, err)
+		}
+		row, err := export.ParseRow(raw)
+		if err != nil {
+			return err
+		}
+		if err = onRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkCsvColumns(path string, columns []string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header, err := csv.NewReader(file).Read()
+	if err != nil {
+		return fmt.Errorf("error reading the header of '%s': %w", path, err)
+	}
+	if strings.Join(header, ",") != strings.Join(columns, ",") {
+		return fmt.Errorf("the columns of '%s' (%s) do not match the dataset's columns (%s)", path, strings.Join(header, ", "), strings.Join(columns, ", "))
+	}
+	return nil
+}
+
+// convertJsonToCsv writes the rows as CSV with the given columns. Without columns, it
+// first makes a pass over the file to collect the union of all row keys.
+func convertJsonToCsv(path string, format string, destination string, columns []string) error {
+	known := make(map[string]bool)
+	for _, column := range columns {
+		known[column] = true
+	}
+
+	fixedColumns := columns != nil
+	err := readJsonRows(path, format, func(row *export.Row) error {
+		for _, column := range row.Columns {
+			if known[column] {
+				continue
+			}
+			if fixedColumns {
+				return fmt.Errorf("'%s' has a column '%s' that is not in the dataset", path, column)
+			}
+			known[column] = true
+			columns = append(columns, column)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	target, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(bufio.NewWriter(target))
+	err = writer.Write(columns)
+	if err == nil {
+		err = readJsonRows(path, format, func(row *export.Row) error {
+			record := make([]string, len(columns))
+			for i, column := range columns {
+				record[i] = export.CsvValue(row.Values[column])
+			}
+			return writer.Write(record)
+		})
+	}
+	writer.Flush()
+	if err == nil {
+		err = writer.Error()
+	}
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func inferType(values []string) string {
+	isInt, isFloat, isBool, isDate, isTimestamp := true, true, true, true, true
+	sampled := 0
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		sampled++
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			isInt = false
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			isFloat = false
+		}
+		if lower := strings.ToLower(value); lower != "true" && lower != "false" {
+			isBool = false
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			isDate = false
+		}
+		if !isTimestampValue(value) {
+			isTimestamp = false
+		}
+	}
+
+	switch {
+	case sampled == 0:
+		return "Utf8"
+	case isInt:
+		return "Int64"
+	case isFloat:
+		return "Float64"
+	case isBool:
+		return "Boolean"
+	case isDate:
+		return "Date32"
+	case isTimestamp:
+		return "Timestamp"
+	}
+	return "Utf8"
+}
+
+func isTimestampValue(value string) bool {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04:05.999999999"} {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func firstSample(values []string) string {
+	for _, value := range values {
+		if value != "" {
+			if len(value) > maxSampleLength {
+				return value[:maxSampleLength] + "..."
+			}
+			return value
+		}
+	}
+	return ""
+}
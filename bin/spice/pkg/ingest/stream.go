@@ -0,0 +1,243 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingest
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/export"
+)
+
+type StreamOptions struct {
+	Format string
+	// Maximum number of rows per batch file.
+	BatchSize int
+	// Pending rows are written at least this often.
+	FlushInterval time.Duration
+	// Columns of the dataset being appended to. When empty, the first batch defines them.
+	Columns []string
+}
+
+type Batch struct {
+	File    string
+	Rows    int
+	Invalid int
+}
+
+// Stream reads CSV or JSON Lines rows from r and writes them to dataDir as CSV batch
+// files until r is exhausted or stop is closed. JSON keys that are not dataset columns
+// are ignored. onBatch is called after each batch file is written.
+func Stream(r io.Reader, dataDir string, options StreamOptions, stop <-chan struct{}, onBatch func(Batch) error) error {
+	if options.Format != FORMAT_JSONL && options.Format != FORMAT_CSV {
+		return fmt.Errorf("streaming supports csv and jsonl input, not %s", options.Format)
+	}
+	if err := os.MkdirAll(dataDir, 0766); err != nil {
+		return err
+	}
+
+	lines := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		readErr <- scanner.Err()
+		close(lines)
+	}()
+
+	batcher := &streamBatcher{dataDir: dataDir, columns: options.Columns, known: make(map[string]bool)}
+	for _, column := range options.Columns {
+		batcher.known[column] = true
+	}
+	csvHeaderPending := options.Format == FORMAT_CSV
+
+	flush := func() error {
+		batch, err := batcher.flush()
+		if err != nil || batch == nil {
+			return err
+		}
+		return onBatch(*batch)
+	}
+
+	ticker := time.NewTicker(options.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return flush()
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		case line, ok := <-lines:
+			if !ok {
+				if err := flush(); err != nil {
+					return err
+				}
+				return <-readErr
+			}
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			if csvHeaderPending {
+				csvHeaderPending = false
+				if err := batcher.setCsvHeader(line); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if options.Format == FORMAT_CSV {
+				batcher.addCsv(line)
+			} else {
+				batcher.addJson(line)
+			}
+
+			if len(batcher.rows) >= options.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+type streamBatcher struct {
+	dataDir string
+	columns []string
+	known   map[string]bool
+	// Header of CSV input, mapped onto columns.
+	csvHeader []string
+	// JSON keys in order of first appearance, used when the first batch fixes the columns.
+	seen    []string
+	rows    []map[string]string
+	invalid int
+}
+
+func (b *streamBatcher) setCsvHeader(line string) error {
+	header, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return fmt.Errorf("error reading the CSV header: %w", err)
+	}
+	if len(b.columns) > 0 && strings.Join(header, ",") != strings.Join(b.columns, ",") {
+		return fmt.Errorf("the CSV columns (%s) do not match the dataset's columns (%s)", strings.Join(header, ", "), strings.Join(b.columns, ", "))
+	}
+	b.csvHeader = header
+	return nil
+}
+
+func (b *streamBatcher) addCsv(line string) {
+	record, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil || len(record) != len(b.csvHeader) {
+		b.invalid++
+		return
+	}
+	row := make(map[string]string, len(record))
+	for i, value := range record {
+		row[b.csvHeader[i]] = value
+	}
+	b.rows = append(b.rows, row)
+}
+
+func (b *streamBatcher) addJson(line string) {
+	parsed, err := export.ParseRow(json.RawMessage(line))
+	if err != nil {
+		b.invalid++
+		return
+	}
+	row := make(map[string]string, len(parsed.Columns))
+	for _, column := range parsed.Columns {
+		row[column] = export.CsvValue(parsed.Values[column])
+		if !b.known[column] {
+			b.known[column] = true
+			b.seen = append(b.seen, column)
+		}
+	}
+	b.rows = append(b.rows, row)
+}
+
+func (b *streamBatcher) flush() (*Batch, error) {
+	if len(b.rows) == 0 {
+		if b.invalid > 0 {
+			batch := &Batch{Invalid: b.invalid}
+			b.invalid = 0
+			return batch, nil
+		}
+		return nil, nil
+	}
+
+	// The first batch of a new dataset fixes its columns
+	if len(b.columns) == 0 {
+		b.columns = b.seen
+		if b.csvHeader != nil {
+			b.columns = b.csvHeader
+		}
+	}
+
+	// Written under a temporary name so the runtime never reads a partial batch
+	name := fmt.Sprintf("batch-%d", time.Now().UnixNano())
+	tmpPath := filepath.Join(b.dataDir, fmt.Sprintf(".%s.tmp", name))
+	path := filepath.Join(b.dataDir, fmt.Sprintf("%s.csv", name))
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(file)
+	err = writer.Write(b.columns)
+	for _, row := range b.rows {
+		if err != nil {
+			break
+		}
+		record := make([]string, len(b.columns))
+		for i, column := range b.columns {
+			record[i] = row[column]
+		}
+		err = writer.Write(record)
+	}
+	writer.Flush()
+	if err == nil {
+		err = writer.Error()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	batch := &Batch{File: path, Rows: len(b.rows), Invalid: b.invalid}
+	b.rows = nil
+	b.invalid = 0
+	return batch, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	HELM_REPO  = "https://helm.spiceai.org"
+	HELM_CHART = "spiceai"
+)
+
+type InstallOptions struct {
+	Namespace string
+	Release   string
+	// Chart in the Spice.ai Helm repository, or the path of a local chart.
+	Chart        string
+	ChartVersion string
+	// Runtime image tag, the chart's default when empty.
+	ImageTag   string
+	Replicas   int
+	Env        []string
+	Set        []string
+	PodMonitor bool
+	Wait       bool
+	Timeout    time.Duration
+}
+
+// BuildValues derives the chart values from the Spicepod in spicepodDir and the options. The
+// Spicepod is embedded with its component references inlined, keeping its key order.
+func BuildValues(spicepodDir string, options InstallOptions) (yaml.MapSlice, error) {
+	manifest, err := spicepod.Flatten(spicepodDir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading spicepod: %w", err)
+	}
+
+	values := yaml.MapSlice{}
+	if options.ImageTag != "" {
+		values = append(values, yaml.MapItem{Key: "image", Value: yaml.MapSlice{{Key: "tag", Value: options.ImageTag}}})
+	}
+	if options.Replicas > 0 {
+		values = append(values, yaml.MapItem{Key: "replicaCount", Value: options.Replicas})
+	}
+	if options.PodMonitor {
+		values = append(values, yaml.MapItem{Key: "monitoring", Value: yaml.MapSlice{
+			{Key: "podMonitor", Value: yaml.MapSlice{{Key: "enabled", Value: true}}},
+		}})
+	}
+
+	if len(options.Env) > 0 {
+		env := make([]yaml.MapSlice, 0, len(options.Env))
+		for _, e := range options.Env {
+			name, value, ok := strings.Cut(e, "=")
+			if !ok || name == "" {
+				return nil, fmt.Errorf("invalid environment variable '%s', expected NAME=VALUE", e)
+			}
+			env = append(env, yaml.MapSlice{{Key: "name", Value: name}, {Key: "value", Value: value}})
+		}
+		values = append(values, yaml.MapItem{Key: "additionalEnv", Value: env})
+	}
+
+	values = append(values, yaml.MapItem{Key: "spicepod", Value: manifest})
+	return values, nil
+}
+
+// HelmInstallArgs returns the arguments of the helm upgrade --install invocation.
+func HelmInstallArgs(options InstallOptions, valuesPath string) []string {
+	args := []string{"upgrade", "--install", options.Release, options.Chart, "--namespace", options.Namespace, "--create-namespace", "--values", valuesPath}
+
+	if !isLocalChart(options.Chart) {
+		args = append(args, "--repo", HELM_REPO)
+	}
+	if options.ChartVersion != "" {
+		args = append(args, "--version", options.ChartVersion)
+	}
+	for _, set := range options.Set {
+		args = append(args, "--set", set)
+	}
+	if options.Wait {
+		args = append(args, "--wait")
+		if options.Timeout > 0 {
+			args = append(args, "--timeout", options.Timeout.String())
+		}
+	}
+
+	return args
+}
+
+// Install installs or upgrades the Helm release with values, returning helm's output.
+func Install(options InstallOptions, values yaml.MapSlice) (string, error) {
+	valuesBytes, err := yaml.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	valuesFile, err := os.CreateTemp("", "spice-values-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(valuesFile.Name())
+
+	_, err = valuesFile.Write(valuesBytes)
+	if closeErr := valuesFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	output, err := run("helm", HelmInstallArgs(options, valuesFile.Name())...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// ImageTag returns the runtime image tag matching a CLI version, e.g. 0.13.1-alpha for
+// v0.13.1-alpha, or "" for local builds so the chart's default is used.
+func ImageTag(cliVersion string) string {
+	if !strings.HasPrefix(cliVersion, "v") {
+		return ""
+	}
+	return strings.TrimPrefix(cliVersion, "v")
+}
+
+func isLocalChart(chart string) bool {
+	if strings.HasPrefix(chart, ".") || filepath.IsAbs(chart) {
+		return true
+	}
+	info, err := os.Stat(chart)
+	return err == nil && info.IsDir()
+}
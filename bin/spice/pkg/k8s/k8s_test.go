@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestBuildValues(t *testing.T) {
+	dir := t.TempDir()
+	spicepod := "version: v1beta1\nkind: Spicepod\nname: app\ndatasets:\n- from: postgres:trips\n  name: trips\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "spicepod.yaml"), []byte(spicepod), 0644))
+
+	values, err := BuildValues(dir, InstallOptions{ImageTag: "0.13.1-alpha", Replicas: 2, Env: []string{"SPICE_SECRET_POSTGRES_PASSWORD=pw=1"}})
+	assert.NoError(t, err)
+
+	valuesBytes, err := yaml.Marshal(values)
+	assert.NoError(t, err)
+	assert.Equal(t, `image:
+  tag: 0.13.1-alpha
+replicaCount: 2
+additionalEnv:
+- name: SPICE_SECRET_POSTGRES_PASSWORD
+  value: pw=1
+spicepod:
+  version: v1beta1
+  kind: Spicepod
+  name: app
+  datasets:
+  - from: postgres:trips
+    name: trips
+`, string(valuesBytes))
+
+	_, err = BuildValues(dir, InstallOptions{Env: []string{"NOVALUE"}})
+	assert.ErrorContains(t, err, "expected NAME=VALUE")
+
+	_, err = BuildValues(t.TempDir(), InstallOptions{})
+	assert.ErrorContains(t, err, "error reading")
+}
+
+func TestHelmInstallArgs(t *testing.T) {
+	options := InstallOptions{Namespace: "spice", Release: "spiceai", Chart: HELM_CHART, ChartVersion: "0.1.11", Set: []string{"resources.limits.memory=4Gi"}, Wait: true, Timeout: 5 * time.Minute}
+	assert.Equal(t, []string{
+		"upgrade", "--install", "spiceai", "spiceai", "--namespace", "spice", "--create-namespace", "--values", "values.yaml",
+		"--repo", HELM_REPO, "--version", "0.1.11", "--set", "resources.limits.memory=4Gi", "--wait", "--timeout", "5m0s",
+	}, HelmInstallArgs(options, "values.yaml"))
+
+	options = InstallOptions{Namespace: "default", Release: "app", Chart: "./deploy/chart"}
+	assert.Equal(t, []string{
+		"upgrade", "--install", "app", "./deploy/chart", "--namespace", "default", "--create-namespace", "--values", "values.yaml",
+	}, HelmInstallArgs(options, "values.yaml"))
+}
+
+func TestImageTag(t *testing.T) {
+	assert.Equal(t, "0.13.1-alpha", ImageTag("v0.13.1-alpha"))
+	assert.Equal(t, "", ImageTag("local-dev"))
+}
+
+func TestParsePods(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	output := `{"items": [
+		{"metadata": {"name": "spiceai-7d9f-abcde", "creationTimestamp": "2024-06-01T11:50:00Z"},
+		 "spec": {"nodeName": "node-1"},
+		 "status": {"phase": "Running", "containerStatuses": [{"ready": true, "restartCount": 1, "state": {"running": {}}}]}},
+		{"metadata": {"name": "spiceai-7d9f-fghij", "creationTimestamp": "2024-06-01T11:59:30Z"},
+		 "status": {"phase": "Pending", "containerStatuses": [{"ready": false, "restartCount": 0, "state": {"waiting": {"reason": "ImagePullBackOff"}}}]}}
+	]}`
+
+	pods, err := parsePods([]byte(output), now)
+	assert.NoError(t, err)
+	assert.Equal(t, []PodStatus{
+		{Pod: "spiceai-7d9f-abcde", Ready: "1/1", Status: "Running", Restarts: 1, Age: "10m", Node: "node-1"},
+		{Pod: "spiceai-7d9f-fghij", Ready: "0/1", Status: "ImagePullBackOff", Restarts: 0, Age: "30s"},
+	}, pods)
+}
+
+func TestFormatAge(t *testing.T) {
+	assert.Equal(t, "5s", formatAge(5*time.Second))
+	assert.Equal(t, "59m", formatAge(59*time.Minute))
+	assert.Equal(t, "47h", formatAge(47*time.Hour))
+	assert.Equal(t, "3d", formatAge(72*time.Hour))
+}
+
+func TestSelectPod(t *testing.T) {
+	pod, err := SelectPod([]PodStatus{
+		{Pod: "spiceai-old", Ready: "0/1", Status: "Terminating"},
+		{Pod: "spiceai-starting", Ready: "0/1", Status: "Running"},
+		{Pod: "spiceai-ready", Ready: "1/1", Status: "Running"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "spiceai-ready", pod)
+
+	_, err = SelectPod([]PodStatus{{Pod: "spiceai-crash", Ready: "0/1", Status: "CrashLoopBackOff"}})
+	assert.EqualError(t, err, "no ready pod found among 1 pods")
+}
+
+func TestPortForwardArgs(t *testing.T) {
+	assert.Equal(t, []string{"port-forward", "pod/spiceai-ready", "--namespace", "spice", "13000:3000", "50051:50051"},
+		PortForwardArgs("spice", "spiceai-ready", []PortMapping{{Local: 13000, Remote: RUNTIME_HTTP_PORT}, {Local: 50051, Remote: RUNTIME_FLIGHT_PORT}}))
+	assert.Equal(t, "http://127.0.0.1:13000", LocalEndpoint("http", 13000))
+}
+
+func TestSpicepodsEqual(t *testing.T) {
+	local := []byte("version: v1beta1\nkind: Spicepod\nname: app\ndatasets:\n- from: s3://bucket/\n  name: trips\n")
+	rendered := []byte("datasets:\n- from: s3://bucket/\n  name: trips\nkind: Spicepod\nname: app\nversion: v1beta1\n")
+	assert.True(t, spicepodsEqual(local, rendered))
+	assert.False(t, spicepodsEqual(local, []byte("name: app\n")))
+	assert.False(t, spicepodsEqual(local, []byte("")))
+}
+
+func TestConfigMapPatch(t *testing.T) {
+	patch, err := configMapPatch([]byte("name: app\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"data":{"spicepod.yaml":"name: app\n"}}`, patch)
+	assert.Equal(t, "spiceai-config", ConfigMapName("spiceai"))
+}
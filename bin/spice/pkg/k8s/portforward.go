@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	RUNTIME_HTTP_PORT   = 3000
+	RUNTIME_FLIGHT_PORT = 50051
+)
+
+// PortMapping forwards a local port to a port of the runtime pod.
+type PortMapping struct {
+	Local  int
+	Remote int
+}
+
+func (m PortMapping) String() string {
+	return fmt.Sprintf("%d:%d", m.Local, m.Remote)
+}
+
+// SelectPod returns the first running pod with all of its containers ready.
+func SelectPod(pods []PodStatus) (string, error) {
+	for _, pod := range pods {
+		if pod.Status == "Running" && pod.isReady() {
+			return pod.Pod, nil
+		}
+	}
+	return "", fmt.Errorf("no ready pod found among %d pods", len(pods))
+}
+
+func (p PodStatus) isReady() bool {
+	ready, total, found := strings.Cut(p.Ready, "/")
+	return found && total != "0" && ready == total
+}
+
+func PortForwardArgs(namespace string, pod string, mappings []PortMapping) []string {
+	args := []string{"port-forward", fmt.Sprintf("pod/%s", pod), "--namespace", namespace}
+	for _, mapping := range mappings {
+		args = append(args, mapping.String())
+	}
+	return args
+}
+
+// PortForwardCommand returns the kubectl port-forward command for the pod, to be run in the
+// foreground until it is interrupted.
+func PortForwardCommand(namespace string, pod string, mappings []PortMapping) (*exec.Cmd, error) {
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		return nil, fmt.Errorf("kubectl was not found on the PATH, install it to manage Spice on Kubernetes")
+	}
+	return exec.Command("kubectl", PortForwardArgs(namespace, pod, mappings)...), nil
+}
+
+// LocalEndpoint is the endpoint a forwarded port is reachable at.
+func LocalEndpoint(scheme string, port int) string {
+	return fmt.Sprintf("%s://127.0.0.1:%d", scheme, port)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type PodStatus struct {
+	Pod      string `json:"pod" csv:"pod"`
+	Ready    string `json:"ready" csv:"ready"`
+	Status   string `json:"status" csv:"status"`
+	Restarts int    `json:"restarts" csv:"restarts"`
+	Age      string `json:"age" csv:"age"`
+	Node     string `json:"node" csv:"node"`
+}
+
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string    `json:"name"`
+			CreationTimestamp time.Time `json:"creationTimestamp"`
+			DeletionTimestamp string    `json:"deletionTimestamp"`
+		} `json:"metadata"`
+		Spec struct {
+			NodeName string `json:"nodeName"`
+		} `json:"spec"`
+		Status struct {
+			Phase             string `json:"phase"`
+			ContainerStatuses []struct {
+				Ready        bool `json:"ready"`
+				RestartCount int  `json:"restartCount"`
+				State        struct {
+					Waiting *struct {
+						Reason string `json:"reason"`
+					} `json:"waiting"`
+					Terminated *struct {
+						Reason string `json:"reason"`
+					} `json:"terminated"`
+				} `json:"state"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// GetPods returns the pods of a release, selected by the app label the chart sets.
+func GetPods(namespace string, release string) ([]PodStatus, error) {
+	output, err := run("kubectl", "get", "pods", "--namespace", namespace, "--selector", fmt.Sprintf("app=%s", release), "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	return parsePods(output, time.Now())
+}
+
+// RolloutStatus waits for the release's deployment to finish rolling out and returns
+// kubectl's report.
+func RolloutStatus(namespace string, release string, timeout time.Duration) (string, error) {
+	output, err := run("kubectl", "rollout", "status", fmt.Sprintf("deployment/%s", release), "--namespace", namespace, "--timeout", timeout.String())
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+func parsePods(output []byte, now time.Time) ([]PodStatus, error) {
+	var pods podList
+	err := json.Unmarshal(output, &pods)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing kubectl output: %w", err)
+	}
+
+	statuses := make([]PodStatus, 0, len(pods.Items))
+	for _, item := range pods.Items {
+		ready, restarts := 0, 0
+		status := item.Status.Phase
+		for _, container := range item.Status.ContainerStatuses {
+			if container.Ready {
+				ready++
+			}
+			restarts += container.RestartCount
+			switch {
+			case container.State.Waiting != nil && container.State.Waiting.Reason != "":
+				status = container.State.Waiting.Reason
+			case container.State.Terminated != nil && container.State.Terminated.Reason != "":
+				status = container.State.Terminated.Reason
+			}
+		}
+		if item.Metadata.DeletionTimestamp != "" {
+			status = "Terminating"
+		}
+
+		statuses = append(statuses, PodStatus{
+			Pod:      item.Metadata.Name,
+			Ready:    fmt.Sprintf("%d/%d", ready, len(item.Status.ContainerStatuses)),
+			Status:   status,
+			Restarts: restarts,
+			Age:      formatAge(now.Sub(item.Metadata.CreationTimestamp)),
+			Node:     item.Spec.NodeName,
+		})
+	}
+	return statuses, nil
+}
+
+// formatAge abbreviates a duration the way kubectl does, e.g. 45s, 12m or 3d.
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
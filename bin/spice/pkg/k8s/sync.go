@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"gopkg.in/yaml.v2"
+)
+
+// The chart mounts this ConfigMap key as /app/spicepod.yaml.
+const CONFIG_MAP_KEY = "spicepod.yaml"
+
+func ConfigMapName(release string) string {
+	return fmt.Sprintf("%s-config", release)
+}
+
+// SyncSpicepod updates the release's ConfigMap with the Spicepod in spicepodDir, with its component
+// references inlined, and reports whether it changed. The chart mounts the Spicepod with a subPath,
+// so running pods only see the change after a restart.
+func SyncSpicepod(namespace string, release string, spicepodDir string, dryRun bool) (bool, error) {
+	manifest, err := spicepod.Flatten(spicepodDir)
+	if err != nil {
+		return false, fmt.Errorf("error reading spicepod: %w", err)
+	}
+	local, err := yaml.Marshal(manifest)
+	if err != nil {
+		return false, err
+	}
+
+	name := ConfigMapName(release)
+	jsonPath := fmt.Sprintf("{.data.%s}", strings.ReplaceAll(CONFIG_MAP_KEY, ".", "\\."))
+	deployed, err := run("kubectl", "get", "configmap", name, "--namespace", namespace, "--output", fmt.Sprintf("jsonpath=%s", jsonPath))
+	if err != nil {
+		return false, err
+	}
+
+	if spicepodsEqual(deployed, local) {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	patch, err := configMapPatch(local)
+	if err != nil {
+		return false, err
+	}
+	_, err = run("kubectl", "patch", "configmap", name, "--namespace", namespace, "--type", "merge", "--patch", patch)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RolloutRestart restarts the release's pods one by one, so they mount the updated Spicepod.
+func RolloutRestart(namespace string, release string) error {
+	_, err := run("kubectl", "rollout", "restart", fmt.Sprintf("deployment/%s", release), "--namespace", namespace)
+	return err
+}
+
+// spicepodsEqual compares two Spicepods by content, as helm renders the ConfigMap with its keys sorted.
+func spicepodsEqual(a []byte, b []byte) bool {
+	var parsedA, parsedB interface{}
+	if yaml.Unmarshal(a, &parsedA) != nil || yaml.Unmarshal(b, &parsedB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(parsedA, parsedB)
+}
+
+func configMapPatch(spicepodBytes []byte) (string, error) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{CONFIG_MAP_KEY: string(spicepodBytes)},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(patch), nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loggers
+
+import (
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+const runtimeLogFilename = "runtime.log"
+
+// RuntimeLogPath is where spice run keeps a copy of the runtime's output, in the app's .spice
+// directory, so commands like spice refresh history can read it back.
+func RuntimeLogPath(appDir string) string {
+	return filepath.Join(appDir, constants.DotSpice, "log", runtimeLogFilename)
+}
+
+func NewRuntimeLogWriter(appDir string) (io.WriteCloser, error) {
+	logPath, err := createLogDirectory(filepath.Join(appDir, constants.DotSpice))
+	if err != nil {
+		return nil, err
+	}
+
+	return &lumberjack.Logger{
+		Filename:   filepath.Join(logPath, runtimeLogFilename),
+		MaxSize:    100, // megabytes
+		MaxBackups: 3,
+		MaxAge:     60, // days
+	}, nil
+}
+
+// RuntimeLogFiles returns the runtime log and its rotated backups, oldest first.
+func RuntimeLogFiles(appDir string) ([]string, error) {
+	logPath := RuntimeLogPath(appDir)
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(logPath), "runtime-*.log"))
+	if err != nil {
+		return nil, err
+	}
+	// Backups are named runtime-<timestamp>.log, so they sort by name in rotation order.
+	sort.Strings(backups)
+	return append(backups, logPath), nil
+}
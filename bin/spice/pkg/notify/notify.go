@@ -0,0 +1,265 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/accel"
+	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
+	"github.com/spiceai/spiceai/bin/spice/pkg/http"
+	"gopkg.in/yaml.v2"
+)
+
+const SubscriptionsFileName = "notifications.yaml"
+
+const (
+	EVENT_REFRESH_FAILED    = "refresh_failed"
+	EVENT_REFRESH_SUCCEEDED = "refresh_succeeded"
+	EVENT_RUNTIME_FAILED    = "runtime_failed"
+	EVENT_TEST              = "test"
+)
+
+var Events = []string{EVENT_REFRESH_FAILED, EVENT_REFRESH_SUCCEEDED, EVENT_RUNTIME_FAILED}
+
+// Subscription is a webhook called when an event happens in the runtime started by spice run.
+type Subscription struct {
+	Id    string `json:"id" csv:"id" yaml:"id"`
+	Event string `json:"event" csv:"event" yaml:"event"`
+	Url   string `json:"url" csv:"url" yaml:"url"`
+	// Only notify about refreshes of this dataset, all datasets when empty.
+	Dataset string `json:"dataset,omitempty" csv:"dataset" yaml:"dataset,omitempty"`
+}
+
+// Notification is the JSON body posted to a webhook. Text is a summary in the format of Slack
+// and compatible incoming webhooks.
+type Notification struct {
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	App     string    `json:"app,omitempty"`
+	Dataset string    `json:"dataset,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Text    string    `json:"text"`
+}
+
+func SubscriptionsPath(appDir string) string {
+	return filepath.Join(appDir, constants.DotSpice, SubscriptionsFileName)
+}
+
+// Load reads the app's webhook subscriptions. A missing file yields no subscriptions.
+func Load(appDir string) ([]Subscription, error) {
+	path := SubscriptionsPath(appDir)
+	subscriptionsBytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var subscriptions []Subscription
+	err = yaml.Unmarshal(subscriptionsBytes, &subscriptions)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	return subscriptions, nil
+}
+
+// Save writes the subscriptions readable only by the current user, as webhook URLs often embed
+// a secret.
+func Save(appDir string, subscriptions []Subscription) error {
+	path := SubscriptionsPath(appDir)
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+
+	subscriptionsBytes, err := yaml.Marshal(subscriptions)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, subscriptionsBytes, 0600)
+}
+
+// Add validates a subscription, assigns it the next free id and saves it.
+func Add(appDir string, subscription Subscription) (*Subscription, error) {
+	if !slices.Contains(Events, subscription.Event) {
+		return nil, fmt.Errorf("unknown event %q, expected one of: %s", subscription.Event, strings.Join(Events, ", "))
+	}
+	if subscription.Dataset != "" && subscription.Event == EVENT_RUNTIME_FAILED {
+		return nil, fmt.Errorf("%s is not a dataset event", EVENT_RUNTIME_FAILED)
+	}
+	webhookUrl, err := url.Parse(subscription.Url)
+	if err != nil || (webhookUrl.Scheme != "https" && webhookUrl.Scheme != "http") || webhookUrl.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q, expected an http or https URL", subscription.Url)
+	}
+
+	subscriptions, err := Load(appDir)
+	if err != nil {
+		return nil, err
+	}
+
+	lastId := 0
+	for _, existing := range subscriptions {
+		if existing.Event == subscription.Event && existing.Url == subscription.Url && existing.Dataset == subscription.Dataset {
+			return nil, fmt.Errorf("subscription %s already sends %s to this URL", existing.Id, existing.Event)
+		}
+		if id, err := strconv.Atoi(existing.Id); err == nil && id > lastId {
+			lastId = id
+		}
+	}
+	subscription.Id = strconv.Itoa(lastId + 1)
+
+	err = Save(appDir, append(subscriptions, subscription))
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// Remove deletes the subscription with the given id.
+func Remove(appDir string, id string) error {
+	subscriptions, err := Load(appDir)
+	if err != nil {
+		return err
+	}
+
+	index := slices.IndexFunc(subscriptions, func(s Subscription) bool { return s.Id == id })
+	if index < 0 {
+		return fmt.Errorf("no subscription with id %s", id)
+	}
+	return Save(appDir, slices.Delete(subscriptions, index, index+1))
+}
+
+// Send posts a notification to a webhook, giving up when ctx ends.
+func Send(ctx context.Context, webhookUrl string, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(ctx, webhookUrl, "application/json", body, nil)
+	if err != nil {
+		return fmt.Errorf("error calling webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// Notifier delivers notifications to the subscriptions of their event in the background.
+type Notifier struct {
+	app           string
+	subscriptions []Subscription
+	// Context each delivery is bound to, e.g. the runtime context's RequestContext.
+	requestContext func() (context.Context, context.CancelFunc)
+	onError        func(subscription Subscription, err error)
+	pending        sync.WaitGroup
+}
+
+// NewNotifier creates a notifier for the app's subscriptions whose deliveries are bound to the
+// contexts returned by requestContext. Delivery errors are passed to onError.
+func NewNotifier(app string, subscriptions []Subscription, requestContext func() (context.Context, context.CancelFunc), onError func(subscription Subscription, err error)) *Notifier {
+	return &Notifier{app: app, subscriptions: subscriptions, requestContext: requestContext, onError: onError}
+}
+
+// Notify sends the notification to each subscription of its event and dataset.
+func (n *Notifier) Notify(notification Notification) {
+	if n.app != "" {
+		notification.App = n.app
+		notification.Text = fmt.Sprintf("%s: %s", n.app, notification.Text)
+	}
+	for _, subscription := range n.subscriptions {
+		if subscription.Event != notification.Event || (subscription.Dataset != "" && !strings.EqualFold(subscription.Dataset, notification.Dataset)) {
+			continue
+		}
+		n.pending.Add(1)
+		go func(subscription Subscription) {
+			defer n.pending.Done()
+			ctx, cancel := n.requestContext()
+			defer cancel()
+			if err := Send(ctx, subscription.Url, notification); err != nil && n.onError != nil {
+				n.onError(subscription, err)
+			}
+		}(subscription)
+	}
+}
+
+// Wait blocks until all notifications have been delivered or failed.
+func (n *Notifier) Wait() {
+	n.pending.Wait()
+}
+
+// LogWriter returns a writer for the runtime's output that notifies about the refreshes it
+// logs. Each output stream needs its own writer, as lines are assembled from the writes.
+func (n *Notifier) LogWriter() *LogWriter {
+	return &LogWriter{notifier: n}
+}
+
+// LogWriter scans runtime output for finished refreshes.
+type LogWriter struct {
+	notifier *Notifier
+	line     []byte
+}
+
+func (w *LogWriter) Write(p []byte) (int, error) {
+	w.line = append(w.line, p...)
+	for {
+		end := bytes.IndexByte(w.line, '\n')
+		if end < 0 {
+			break
+		}
+		w.notifyLine(string(w.line[:end]))
+		w.line = w.line[end+1:]
+	}
+	return len(p), nil
+}
+
+func (w *LogWriter) notifyLine(line string) {
+	dataset, event := accel.ParseRefreshEvent(line)
+	if event == nil {
+		return
+	}
+
+	notification := Notification{Dataset: dataset, Time: event.Time}
+	if notification.Time.IsZero() {
+		notification.Time = time.Now().UTC()
+	}
+	if event.Status == accel.REFRESH_STATUS_FAILED {
+		notification.Event = EVENT_REFRESH_FAILED
+		notification.Error = event.Error
+		notification.Text = fmt.Sprintf("Refresh of dataset %s failed: %s", dataset, event.Error)
+	} else {
+		notification.Event = EVENT_REFRESH_SUCCEEDED
+		notification.Text = fmt.Sprintf("Refreshed dataset %s: %d rows in %s", dataset, event.Rows, event.Duration)
+	}
+	w.notifier.Notify(notification)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptions(t *testing.T) {
+	appDir := t.TempDir()
+
+	subscriptions, err := Load(appDir)
+	assert.NoError(t, err)
+	assert.Empty(t, subscriptions)
+
+	first, err := Add(appDir, Subscription{Event: EVENT_REFRESH_FAILED, Url: "https://hooks.example.com/a"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", first.Id)
+	second, err := Add(appDir, Subscription{Event: EVENT_REFRESH_FAILED, Url: "https://hooks.example.com/a", Dataset: "taxi_trips"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", second.Id)
+
+	_, err = Add(appDir, Subscription{Event: EVENT_REFRESH_FAILED, Url: "https://hooks.example.com/a"})
+	assert.ErrorContains(t, err, "subscription 1 already sends refresh_failed")
+	_, err = Add(appDir, Subscription{Event: "dataset_deleted", Url: "https://hooks.example.com/a"})
+	assert.ErrorContains(t, err, "unknown event \"dataset_deleted\"")
+	_, err = Add(appDir, Subscription{Event: EVENT_RUNTIME_FAILED, Url: "https://hooks.example.com/a", Dataset: "taxi_trips"})
+	assert.ErrorContains(t, err, "not a dataset event")
+	_, err = Add(appDir, Subscription{Event: EVENT_REFRESH_FAILED, Url: "hooks.example.com/a"})
+	assert.ErrorContains(t, err, "invalid webhook URL")
+
+	assert.NoError(t, Remove(appDir, "1"))
+	assert.ErrorContains(t, Remove(appDir, "1"), "no subscription with id 1")
+	third, err := Add(appDir, Subscription{Event: EVENT_RUNTIME_FAILED, Url: "https://hooks.example.com/b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", third.Id)
+
+	subscriptions, err = Load(appDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []Subscription{*second, *third}, subscriptions)
+}
+
+func TestLogWriter(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string][]Notification{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification Notification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+		mu.Lock()
+		received[r.URL.Path] = append(received[r.URL.Path], notification)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier("app", []Subscription{
+		{Id: "1", Event: EVENT_REFRESH_FAILED, Url: server.URL + "/failed"},
+		{Id: "2", Event: EVENT_REFRESH_SUCCEEDED, Url: server.URL + "/taxi_trips", Dataset: "taxi_trips"},
+	}, func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), 5*time.Second)
+	}, func(subscription Subscription, err error) {
+		t.Errorf("error sending notification %s: %s", subscription.Id, err.Error())
+	})
+
+	// Lines may be split across writes.
+	w := notifier.LogWriter()
+	for _, chunk := range []string{
+		"2024-05-01T12:00:00Z  INFO runtime::accelerated_table::refresh: Loaded 42 rows for dataset taxi_trips in 310ms.\n2024-05-01T12:00:01Z  INFO runtime::accelerated_table::refresh: Loa",
+		"ded 0 rows for dataset other in 5ms.\n2024-05-01T13:00:00Z ERROR runtime::accelerated_table::refresh: Failed to load data for dataset other: connection refused\n",
+		"2024-05-01T13:00:01Z ERROR runtime::accelerated_table::refresh: Failed to load data for dataset taxi_trips: timeout",
+	} {
+		_, err := w.Write([]byte(chunk))
+		assert.NoError(t, err)
+	}
+	notifier.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if assert.Len(t, received["/taxi_trips"], 1) {
+		assert.Equal(t, "app: Refreshed dataset taxi_trips: 42 rows in 310ms", received["/taxi_trips"][0].Text)
+	}
+	// The last line is incomplete until the runtime writes its newline.
+	if assert.Len(t, received["/failed"], 1) {
+		notification := received["/failed"][0]
+		assert.Equal(t, EVENT_REFRESH_FAILED, notification.Event)
+		assert.Equal(t, "app", notification.App)
+		assert.Equal(t, "other", notification.Dataset)
+		assert.Equal(t, "connection refused", notification.Error)
+		assert.Equal(t, "app: Refresh of dataset other failed: connection refused", notification.Text)
+	}
+}
+
+func TestSendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := Send(context.Background(), server.URL, Notification{Event: EVENT_TEST, Text: "test"})
+	assert.ErrorContains(t, err, "webhook responded with 404 Not Found")
+}
@@ -0,0 +1,505 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	SCHEME = "oci://"
+
+	MEDIA_TYPE_MANIFEST = "application/vnd.oci.image.manifest.v1+json"
+	MEDIA_TYPE_EMPTY    = "application/vnd.oci.empty.v1+json"
+	ARTIFACT_TYPE_POD   = "application/vnd.spiceai.spicepod.v1"
+	MEDIA_TYPE_POD      = "application/vnd.spiceai.spicepod.layer.v1.tar+gzip"
+
+	ANNOTATION_TITLE   = "org.opencontainers.image.title"
+	ANNOTATION_VERSION = "org.opencontainers.image.version"
+	ANNOTATION_SOURCE  = "org.opencontainers.image.source"
+)
+
+var ErrNotFound = errors.New("not found")
+
+var (
+	emptyConfig          = []byte("{}")
+	challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// Reference is a parsed artifact reference like oci://ghcr.io/org/pod:1.2.0 or
+// oci://ghcr.io/org/pod@sha256:<digest>. References without a tag or digest resolve to latest.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Client speaks the OCI distribution API to a single registry.
+type Client struct {
+	registry   string
+	httpClient *http.Client
+	username   string
+	password   string
+	// Bearer tokens by scope
+	tokens map[string]string
+
+	ctx context.Context
+}
+
+func IsReference(path string) bool {
+	return strings.HasPrefix(path, SCHEME)
+}
+
+func ParseReference(ref string) (*Reference, error) {
+	trimmed := strings.TrimPrefix(ref, SCHEME)
+	registry, repository, found := strings.Cut(trimmed, "/")
+	if !found || registry == "" || repository == "" {
+		return nil, fmt.Errorf("invalid OCI reference '%s', expected oci://<registry>/<repository>:<tag>", ref)
+	}
+
+	parsed := &Reference{Registry: registry}
+	if name, digest, found := strings.Cut(repository, "@"); found {
+		parsed.Repository, parsed.Digest = name, digest
+		if !strings.HasPrefix(digest, "sha256:") {
+			return nil, fmt.Errorf("invalid digest '%s' in OCI reference '%s'", digest, ref)
+		}
+	} else if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		parsed.Repository, parsed.Tag = repository[:i], repository[i+1:]
+	} else {
+		parsed.Repository = repository
+	}
+
+	return parsed, nil
+}
+
+// Name is the last path segment of the repository, used as the Spicepod directory name.
+func (r *Reference) Name() string {
+	return r.Repository[strings.LastIndex(r.Repository, "/")+1:]
+}
+
+// Pinned returns the reference to the same repository at the given digest.
+func (r *Reference) Pinned(digest string) *Reference {
+	return &Reference{Registry: r.Registry, Repository: r.Repository, Digest: digest}
+}
+
+func (r *Reference) String() string {
+	if r.Digest != "" {
+		return fmt.Sprintf("%s%s/%s@%s", SCHEME, r.Registry, r.Repository, r.Digest)
+	}
+	if r.Tag != "" {
+		return fmt.Sprintf("%s%s/%s:%s", SCHEME, r.Registry, r.Repository, r.Tag)
+	}
+	return fmt.Sprintf("%s%s/%s", SCHEME, r.Registry, r.Repository)
+}
+
+func (r *Reference) tagOrDigest() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	if r.Tag != "" {
+		return r.Tag
+	}
+	return "latest"
+}
+
+// NewClient creates a client for a registry using credentials from SPICE_OCI_USERNAME and
+// SPICE_OCI_PASSWORD, or from the Docker config written by docker login.
+func NewClient(registry string) *Client {
+	client := &Client{
+		registry:   registry,
+		httpClient: &http.Client{},
+		tokens:     map[string]string{},
+	}
+
+	client.username, client.password = os.Getenv("SPICE_OCI_USERNAME"), os.Getenv("SPICE_OCI_PASSWORD")
+	if client.username == "" {
+		client.username, client.password = dockerCredentials(registry)
+	}
+
+	return client
+}
+
+// WithContext returns a copy of the client whose requests are bound to ctx.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	client := *c
+	client.ctx = ctx
+	return &client
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// GetManifest fetches the manifest of the artifact and returns it with its digest.
+func (c *Client) GetManifest(ref *Reference) (string, *Manifest, error) {
+	scope := fmt.Sprintf("repository:%s:pull", ref.Repository)
+
+	response, err := c.do("GET", fmt.Sprintf("/v2/%s/manifests/%s", ref.Repository, ref.tagOrDigest()), scope, nil, map[string]string{"Accept": MEDIA_TYPE_MANIFEST})
+	if err != nil {
+		return "", nil, err
+	}
+	manifestBytes, err := readResponse(response, http.StatusOK)
+	if err != nil {
+		return "", nil, fmt.Errorf("error fetching manifest for %s: %w", ref, err)
+	}
+	digest := sha256Digest(manifestBytes)
+	if ref.Digest != "" && ref.Digest != digest {
+		return "", nil, fmt.Errorf("manifest digest %s does not match the pinned digest %s", digest, ref.Digest)
+	}
+
+	var manifest Manifest
+	if err = json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return "", nil, fmt.Errorf("error decoding manifest for %s: %w", ref, err)
+	}
+	return digest, &manifest, nil
+}
+
+// Pull downloads the Spicepod artifact and returns its manifest digest and layer content.
+func (c *Client) Pull(ref *Reference) (string, []byte, error) {
+	scope := fmt.Sprintf("repository:%s:pull", ref.Repository)
+
+	digest, manifest, err := c.GetManifest(ref)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var layer *Descriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].MediaType == MEDIA_TYPE_POD {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return "", nil, fmt.Errorf("%s is not a Spicepod artifact, it has no %s layer", ref, MEDIA_TYPE_POD)
+	}
+
+	response, err := c.do("GET", fmt.Sprintf("/v2/%s/blobs/%s", ref.Repository, layer.Digest), scope, nil, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	content, err := readResponse(response, http.StatusOK)
+	if err != nil {
+		return "", nil, fmt.Errorf("error fetching Spicepod layer for %s: %w", ref, err)
+	}
+	if sha256Digest(content) != layer.Digest {
+		return "", nil, fmt.Errorf("Spicepod layer for %s does not match its digest %s", ref, layer.Digest)
+	}
+
+	return digest, content, nil
+}
+
+// Tags lists the tags of the repository.
+func (c *Client) Tags(ref *Reference) ([]string, error) {
+	response, err := c.do("GET", fmt.Sprintf("/v2/%s/tags/list", ref.Repository), fmt.Sprintf("repository:%s:pull", ref.Repository), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := readResponse(response, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags of %s: %w", ref, err)
+	}
+
+	var tags struct {
+		Tags []string `json:"tags"`
+	}
+	if err = json.Unmarshal(body, &tags); err != nil {
+		return nil, fmt.Errorf("error listing tags of %s: %w", ref, err)
+	}
+	return tags.Tags, nil
+}
+
+// Push uploads a packaged Spicepod as a single layer artifact and returns the manifest digest.
+func (c *Client) Push(ref *Reference, content []byte, annotations map[string]string) (string, error) {
+	if ref.Tag == "" {
+		return "", fmt.Errorf("a tag is required to push %s", ref)
+	}
+
+	scope := fmt.Sprintf("repository:%s:pull,push", ref.Repository)
+	layer := Descriptor{MediaType: MEDIA_TYPE_POD, Digest: sha256Digest(content), Size: int64(len(content))}
+	config := Descriptor{MediaType: MEDIA_TYPE_EMPTY, Digest: sha256Digest(emptyConfig), Size: int64(len(emptyConfig))}
+
+	for _, blob := range []struct {
+		descriptor Descriptor
+		content    []byte
+	}{{config, emptyConfig}, {layer, content}} {
+		if err := c.pushBlob(ref, scope, blob.descriptor, blob.content); err != nil {
+			return "", err
+		}
+	}
+
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     MEDIA_TYPE_MANIFEST,
+		ArtifactType:  ARTIFACT_TYPE_POD,
+		Config:        config,
+		Layers:        []Descriptor{layer},
+		Annotations:   annotations,
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := c.do("PUT", fmt.Sprintf("/v2/%s/manifests/%s", ref.Repository, ref.Tag), scope, manifestBytes, map[string]string{"Content-Type": MEDIA_TYPE_MANIFEST})
+	if err != nil {
+		return "", err
+	}
+	if _, err = readResponse(response, http.StatusCreated); err != nil {
+		return "", fmt.Errorf("error pushing manifest for %s: %w", ref, err)
+	}
+
+	return sha256Digest(manifestBytes), nil
+}
+
+func (c *Client) pushBlob(ref *Reference, scope string, descriptor Descriptor, content []byte) error {
+	response, err := c.do("HEAD", fmt.Sprintf("/v2/%s/blobs/%s", ref.Repository, descriptor.Digest), scope, nil, nil)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	response, err = c.do("POST", fmt.Sprintf("/v2/%s/blobs/uploads/", ref.Repository), scope, nil, nil)
+	if err != nil {
+		return err
+	}
+	if _, err = readResponse(response, http.StatusAccepted); err != nil {
+		return fmt.Errorf("error starting upload to %s: %w", ref, err)
+	}
+
+	location, err := response.Location()
+	if err != nil {
+		return fmt.Errorf("error starting upload to %s: %w", ref, err)
+	}
+	query := location.Query()
+	query.Set("digest", descriptor.Digest)
+	location.RawQuery = query.Encode()
+
+	response, err = c.do("PUT", location.String(), scope, content, map[string]string{"Content-Type": "application/octet-stream"})
+	if err != nil {
+		return err
+	}
+	if _, err = readResponse(response, http.StatusCreated); err != nil {
+		return fmt.Errorf("error uploading blob %s to %s: %w", descriptor.Digest, ref, err)
+	}
+	return nil
+}
+
+// do sends a request to the registry, authenticating and retrying once when challenged.
+func (c *Client) do(method string, path string, scope string, body []byte, headers map[string]string) (*http.Response, error) {
+	target := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		target = fmt.Sprintf("%s://%s%s", c.scheme(), c.registry, path)
+	}
+
+	send := func() (*http.Response, error) {
+		request, err := http.NewRequestWithContext(c.context(), method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		if token, ok := c.tokens[scope]; ok {
+			request.Header.Set("Authorization", "Bearer "+token)
+		} else if c.username != "" {
+			request.SetBasicAuth(c.username, c.password)
+		}
+		return c.httpClient.Do(request)
+	}
+
+	response, err := send()
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %w", c.registry, err)
+	}
+	if response.StatusCode != http.StatusUnauthorized {
+		return response, nil
+	}
+
+	challenge := response.Header.Get("WWW-Authenticate")
+	response.Body.Close()
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, fmt.Errorf("not authorized by %s, run docker login %s or set SPICE_OCI_USERNAME and SPICE_OCI_PASSWORD", c.registry, c.registry)
+	}
+
+	token, err := c.fetchToken(challenge, scope)
+	if err != nil {
+		return nil, err
+	}
+	c.tokens[scope] = token
+
+	response, err = send()
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %w", c.registry, err)
+	}
+	if response.StatusCode == http.StatusUnauthorized {
+		response.Body.Close()
+		return nil, fmt.Errorf("not authorized by %s for %s", c.registry, scope)
+	}
+	return response, nil
+}
+
+func (c *Client) fetchToken(challenge string, scope string) (string, error) {
+	params := map[string]string{}
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("invalid authentication challenge from %s: %s", c.registry, challenge)
+	}
+
+	tokenUrl, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", err
+	}
+	query := tokenUrl.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", scope)
+	tokenUrl.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(c.context(), "GET", tokenUrl.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.username != "" {
+		request.SetBasicAuth(c.username, c.password)
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error authenticating with %s: %w", c.registry, err)
+	}
+	body, err := readResponse(response, http.StatusOK)
+	if err != nil {
+		return "", fmt.Errorf("error authenticating with %s: %w", c.registry, err)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("error authenticating with %s: %w", c.registry, err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// scheme uses plain HTTP for registries on the local machine, as with docker.
+func (c *Client) scheme() string {
+	host := c.registry
+	if h, _, found := strings.Cut(host, ":"); found {
+		host = h
+	}
+	if host == "localhost" || host == "127.0.0.1" {
+		return "http"
+	}
+	return "https"
+}
+
+func readResponse(response *http.Response, expectedStatus int) ([]byte, error) {
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if response.StatusCode != expectedStatus {
+		return nil, fmt.Errorf("unexpected response %s: %s", response.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func sha256Digest(content []byte) string {
+	hash := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+// dockerCredentials reads the username and password for a registry from ~/.docker/config.json.
+func dockerCredentials(registry string) (string, string) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", ""
+	}
+	configBytes, err := os.ReadFile(filepath.Join(homeDir, ".docker", "config.json"))
+	if err != nil {
+		return "", ""
+	}
+
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err = json.Unmarshal(configBytes, &config); err != nil {
+		return "", ""
+	}
+
+	for _, key := range []string{registry, "https://" + registry, "https://" + registry + "/v1/"} {
+		if auth, ok := config.Auths[key]; ok && auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return "", ""
+			}
+			username, password, _ := strings.Cut(string(decoded), ":")
+			return username, password
+		}
+	}
+	return "", ""
+}
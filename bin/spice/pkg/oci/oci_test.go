@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	ref, err := ParseReference("oci://ghcr.io/myorg/taxi:1.2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, &Reference{Registry: "ghcr.io", Repository: "myorg/taxi", Tag: "1.2.0"}, ref)
+	assert.Equal(t, "taxi", ref.Name())
+	assert.Equal(t, "oci://ghcr.io/myorg/taxi@sha256:abc", ref.Pinned("sha256:abc").String())
+
+	ref, err = ParseReference("oci://localhost:5000/taxi")
+	assert.NoError(t, err)
+	assert.Equal(t, &Reference{Registry: "localhost:5000", Repository: "taxi"}, ref)
+	assert.Equal(t, "latest", ref.tagOrDigest())
+
+	ref, err = ParseReference("oci://ghcr.io/myorg/taxi@sha256:abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:abc", ref.tagOrDigest())
+
+	_, err = ParseReference("oci://ghcr.io")
+	assert.Error(t, err)
+	_, err = ParseReference("oci://ghcr.io/myorg/taxi@md5:abc")
+	assert.Error(t, err)
+}
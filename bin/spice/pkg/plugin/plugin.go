@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Executables named spice-<name> on PATH run as spice <name>. Dashes in the name are
+// subcommands: spice-team-deploy runs as spice team deploy.
+const PLUGIN_PREFIX = "spice-"
+
+// Environment variables passed to plugins, so they reach the same runtime as the CLI.
+const (
+	ENV_CLI_VERSION     = "SPICE_CLI_VERSION"
+	ENV_APP_DIR         = "SPICE_APP_DIR"
+	ENV_HTTP_ENDPOINT   = "SPICE_HTTP_ENDPOINT"
+	ENV_FLIGHT_ENDPOINT = "SPICE_FLIGHT_ENDPOINT"
+	ENV_TLS_CERT        = "SPICE_TLS_CERT"
+	ENV_API_KEY         = "SPICE_API_KEY"
+	ENV_CONFIG_PATH     = "SPICE_CONFIG"
+)
+
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// Find returns the plugin for the longest prefix of args naming one, and the remaining
+// arguments to pass to it.
+func Find(args []string) (*Plugin, []string) {
+	var parts []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		parts = append(parts, arg)
+	}
+
+	for i := len(parts); i > 0; i-- {
+		name := strings.Join(parts[:i], "-")
+		if path, err := exec.LookPath(PLUGIN_PREFIX + name); err == nil {
+			return &Plugin{Name: strings.Join(parts[:i], " "), Path: path}, args[i:]
+		}
+	}
+	return nil, nil
+}
+
+// List returns the plugins on PATH, sorted by name. A plugin found in several directories is
+// listed once, with the path that runs.
+func List() []Plugin {
+	seen := map[string]bool{}
+	var plugins []Plugin
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if entry.IsDir() || !strings.HasPrefix(name, PLUGIN_PREFIX) || len(name) == len(PLUGIN_PREFIX) || seen[name] {
+				continue
+			}
+			path, err := exec.LookPath(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{
+				Name: strings.ReplaceAll(strings.TrimPrefix(name, PLUGIN_PREFIX), "-", " "),
+				Path: path,
+			})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// Command returns the command running the plugin with args and env added to the CLI's
+// environment, connected to the CLI's standard streams.
+func (p *Plugin) Command(args []string, env map[string]string) *exec.Cmd {
+	command := exec.Command(p.Path, args...)
+	command.Env = os.Environ()
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if env[key] != "" {
+			command.Env = append(command.Env, key+"="+env[key])
+		}
+	}
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	return command
+}
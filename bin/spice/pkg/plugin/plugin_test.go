@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePlugin(t *testing.T, dir string, name string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755))
+	return path
+}
+
+func TestFindAndList(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts in this test")
+	}
+
+	first, second := t.TempDir(), t.TempDir()
+	deploy := writePlugin(t, first, "spice-team-deploy")
+	team := writePlugin(t, first, "spice-team")
+	writePlugin(t, second, "spice-team")
+	assert.NoError(t, os.WriteFile(filepath.Join(first, "spice-notes"), []byte("not executable"), 0o644))
+	t.Setenv("PATH", first+string(os.PathListSeparator)+second)
+
+	p, args := Find([]string{"team", "deploy", "prod", "--force"})
+	assert.Equal(t, &Plugin{Name: "team deploy", Path: deploy}, p)
+	assert.Equal(t, []string{"prod", "--force"}, args)
+
+	p, args = Find([]string{"team", "--help", "deploy"})
+	assert.Equal(t, &Plugin{Name: "team", Path: team}, p)
+	assert.Equal(t, []string{"--help", "deploy"}, args)
+
+	p, _ = Find([]string{"missing"})
+	assert.Nil(t, p)
+
+	assert.Equal(t, []Plugin{{Name: "team", Path: team}, {Name: "team deploy", Path: deploy}}, List())
+}
+
+func TestCommandEnv(t *testing.T) {
+	p := &Plugin{Name: "hello", Path: "/bin/true"}
+	command := p.Command([]string{"a"}, map[string]string{ENV_HTTP_ENDPOINT: "http://127.0.0.1:3000", ENV_API_KEY: ""})
+	assert.Equal(t, []string{"/bin/true", "a"}, command.Args)
+	assert.Contains(t, command.Env, "SPICE_HTTP_ENDPOINT=http://127.0.0.1:3000")
+	assert.NotContains(t, command.Env, "SPICE_API_KEY=")
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/schema"
+)
+
+var histogramBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+var htmlReport = template.Must(template.New("profile").Funcs(template.FuncMap{
+	"value": formatValue,
+	"width": func(count int64, buckets []Bucket) float64 {
+		var largest int64
+		for _, bucket := range buckets {
+			if bucket.Count > largest {
+				largest = bucket.Count
+			}
+		}
+		if largest == 0 {
+			return 0
+		}
+		return float64(count) / float64(largest) * 100
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Profile of {{.Dataset}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+.bar { background: #4c8bf5; height: 10px; }
+.histogram td { border: none; padding: 1px 4px; font-size: small; }
+</style>
+</head>
+<body>
+<h1>{{.Dataset}}</h1>
+<p>{{.Rows}} rows{{if .SampleSize}} (sample of at most {{.SampleSize}}){{end}}</p>
+<table>
+<tr><th>Column</th><th>Type</th><th>Nulls</th><th>Distinct</th><th>Min</th><th>Max</th><th>Histogram</th></tr>
+{{range .Columns}}<tr>
+<td>{{.Column}}</td><td>{{.Type}}</td><td>{{printf "%.2f" .NullPct}}%</td><td>{{.Distinct}}</td><td>{{value .Min}}</td><td>{{value .Max}}</td>
+<td>{{if .Histogram}}<table class="histogram">{{$buckets := .Histogram}}{{range .Histogram}}<tr><td>{{printf "%g" .Lower}} – {{printf "%g" .Upper}}</td><td style="width: 200px"><div class="bar" style="width: {{printf "%.0f" (width .Count $buckets)}}%"></div></td><td>{{.Count}}</td></tr>{{end}}</table>{{end}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+type Options struct {
+	// Number of rows to sample, or 0 to profile the whole dataset.
+	SampleSize int
+	// Number of equal-width histogram buckets for numeric columns.
+	Buckets int
+}
+
+type Profile struct {
+	Dataset    string          `json:"dataset"`
+	Rows       int64           `json:"rows"`
+	SampleSize int             `json:"sample_size,omitempty"`
+	Columns    []ColumnProfile `json:"columns"`
+}
+
+type ColumnProfile struct {
+	Column    string      `json:"column"`
+	Type      string      `json:"type"`
+	Nulls     int64       `json:"nulls"`
+	NullPct   float64     `json:"null_pct"`
+	Distinct  int64       `json:"distinct"`
+	Min       interface{} `json:"min,omitempty"`
+	Max       interface{} `json:"max,omitempty"`
+	Histogram []Bucket    `json:"histogram,omitempty"`
+}
+
+type Bucket struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int64   `json:"count"`
+}
+
+// ColumnSummary is the table row rendered for a column profile.
+type ColumnSummary struct {
+	Column    string
+	Type      string
+	Nulls     string
+	Distinct  int64
+	Min       string
+	Max       string
+	Histogram string
+}
+
+// Run computes column statistics for a dataset with generated SQL: one aggregate query over
+// all columns, then one histogram query per numeric column.
+func Run(rtcontext *context.RuntimeContext, dataset string, options Options) (*Profile, error) {
+	columns, err := api.GetDatasetColumns(rtcontext, dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	source := dataset
+	if options.SampleSize > 0 {
+		source = fmt.Sprintf("(SELECT * FROM %s LIMIT %d) AS sample", dataset, options.SampleSize)
+	}
+
+	aggregates := []string{"COUNT(*) AS row_count"}
+	for i, column := range columns {
+		name := quoteIdentifier(column.Name)
+		aggregates = append(aggregates,
+			fmt.Sprintf("COUNT(%s) AS c%d_count", name, i),
+			fmt.Sprintf("COUNT(DISTINCT %s) AS c%d_distinct", name, i))
+		if schema.IsOrdered(column.DataType) {
+			aggregates = append(aggregates,
+				fmt.Sprintf("MIN(%s) AS c%d_min", name, i),
+				fmt.Sprintf("MAX(%s) AS c%d_max", name, i))
+		}
+	}
+
+	rows, err := api.Sql[map[string]interface{}](rtcontext, fmt.Sprintf("SELECT %s FROM %s", strings.Join(aggregates, ", "), source))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) != 1 {
+		return nil, fmt.Errorf("expected a single row of statistics for '%s', got %d", dataset, len(rows))
+	}
+	stats := rows[0]
+
+	profile := &Profile{
+		Dataset:    dataset,
+		Rows:       toInt(stats["row_count"]),
+		SampleSize: options.SampleSize,
+	}
+	for i, column := range columns {
+		nonNull := toInt(stats[fmt.Sprintf("c%d_count", i)])
+		columnProfile := ColumnProfile{
+			Column:   column.Name,
+			Type:     column.DataType,
+			Nulls:    profile.Rows - nonNull,
+			Distinct: toInt(stats[fmt.Sprintf("c%d_distinct", i)]),
+			Min:      stats[fmt.Sprintf("c%d_min", i)],
+			Max:      stats[fmt.Sprintf("c%d_max", i)],
+		}
+		if profile.Rows > 0 {
+			columnProfile.NullPct = math.Round(float64(columnProfile.Nulls)/float64(profile.Rows)*10000) / 100
+		}
+
+		if schema.IsNumeric(column.DataType) && options.Buckets > 0 {
+			columnProfile.Histogram, err = histogram(rtcontext, source, column.Name, columnProfile.Min, columnProfile.Max, options.Buckets)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute histogram for column '%s': %w", column.Name, err)
+			}
+		}
+
+		profile.Columns = append(profile.Columns, columnProfile)
+	}
+
+	return profile, nil
+}
+
+func histogram(rtcontext *context.RuntimeContext, source string, column string, minValue interface{}, maxValue interface{}, buckets int) ([]Bucket, error) {
+	min, okMin := toFloat(minValue)
+	max, okMax := toFloat(maxValue)
+	if !okMin || !okMax {
+		return nil, nil
+	}
+
+	width := (max - min) / float64(buckets)
+	if width == 0 {
+		// A single distinct value fills one bucket
+		buckets, width = 1, 1
+	}
+
+	name := quoteIdentifier(column)
+	query := fmt.Sprintf(
+		"SELECT CASE WHEN CAST(%[1]s AS DOUBLE) >= %[3]v THEN %[5]d ELSE CAST(FLOOR((CAST(%[1]s AS DOUBLE) - %[2]v) / %[4]v) AS BIGINT) END AS bucket, COUNT(*) AS count FROM %[6]s WHERE %[1]s IS NOT NULL GROUP BY 1 ORDER BY 1",
+		name, min, max, width, buckets-1, source)
+	rows, err := api.Sql[map[string]interface{}](rtcontext, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Bucket, buckets)
+	for i := range result {
+		result[i].Lower = min + float64(i)*width
+		result[i].Upper = min + float64(i+1)*width
+	}
+	result[buckets-1].Upper = max
+	for _, row := range rows {
+		index := int(toInt(row["bucket"]))
+		if index >= 0 && index < buckets {
+			result[index].Count = toInt(row["count"])
+		}
+	}
+
+	return result, nil
+}
+
+// Save writes the profile as an HTML report when path ends in .html, otherwise as JSON.
+func (p *Profile) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		return htmlReport.Execute(file, p)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// Summaries returns one table row per column profile.
+func (p *Profile) Summaries() []ColumnSummary {
+	summaries := make([]ColumnSummary, len(p.Columns))
+	for i, column := range p.Columns {
+		summaries[i] = ColumnSummary{
+			Column:    column.Column,
+			Type:      formatValue(column.Type),
+			Nulls:     fmt.Sprintf("%.2f%%", column.NullPct),
+			Distinct:  column.Distinct,
+			Min:       formatValue(column.Min),
+			Max:       formatValue(column.Max),
+			Histogram: column.Sparkline(),
+		}
+	}
+	return summaries
+}
+
+// Sparkline renders the histogram of a column with block characters scaled to the largest bucket.
+func (c ColumnProfile) Sparkline() string {
+	var largest int64
+	for _, bucket := range c.Histogram {
+		if bucket.Count > largest {
+			largest = bucket.Count
+		}
+	}
+
+	var line strings.Builder
+	for _, bucket := range c.Histogram {
+		index := 0
+		if largest > 0 {
+			index = int(math.Ceil(float64(bucket.Count) / float64(largest) * float64(len(histogramBlocks)-1)))
+		}
+		line.WriteRune(histogramBlocks[index])
+	}
+	return line.String()
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	formatted := fmt.Sprintf("%v", value)
+	if len(formatted) > 32 {
+		formatted = formatted[:29] + "..."
+	}
+	return formatted
+}
+
+func quoteIdentifier(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
+
+func toInt(value interface{}) int64 {
+	f, _ := toFloat(value)
+	return int64(f)
+}
+
+// toFloat converts a JSON number, or a number encoded as a string as is done for decimals.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const (
+	barWidth = 30
+	// Without a terminal, a line is printed each time another quarter is completed.
+	plainStepPercent = 25
+)
+
+// Bar reports progress through a known number of items, or of bytes for a ByteBar.
+type Bar struct {
+	w       io.Writer
+	message string
+	total   int
+	current int
+	bytes   bool
+	mode    string
+	start   time.Time
+
+	lastPercent int
+}
+
+func NewBar(w io.Writer, message string, total int) *Bar {
+	return &Bar{w: w, message: message, total: total, mode: resolveMode(w), start: now()}
+}
+
+// NewByteBar reports progress through total bytes, e.g. of a download, with an estimate of the
+// time remaining. A total of 0 means the size is not known yet, see SetTotal.
+func NewByteBar(w io.Writer, message string, total int) *Bar {
+	return &Bar{w: w, message: message, total: total, bytes: true, mode: resolveMode(w), start: now()}
+}
+
+// SetTotal sets the total once it becomes known, e.g. from a response's Content-Length.
+func (b *Bar) SetTotal(total int) {
+	b.total = total
+}
+
+// Add advances the bar by n items.
+func (b *Bar) Add(n int) {
+	b.Set(b.current + n)
+}
+
+func (b *Bar) Set(current int) {
+	if current > b.total && b.total > 0 {
+		current = b.total
+	}
+	b.current = current
+
+	percent := b.percent()
+	switch b.mode {
+	case MODE_TTY:
+		fmt.Fprintf(b.w, "%s%s %s", clearLine, b.message, b.render())
+		return
+	case MODE_ACCESSIBLE:
+		if percent/plainStepPercent > b.lastPercent/plainStepPercent && current < b.total {
+			fmt.Fprintf(b.w, "%s: %s of %s done, %d percent.\n", b.message, b.format(b.current), b.format(b.total), percent)
+		}
+		b.lastPercent = percent
+		return
+	case MODE_JSON:
+		if percent > b.lastPercent && current < b.total {
+			b.writeEvent(EVENT_PROGRESS, b.counts(), "")
+		}
+		b.lastPercent = percent
+		return
+	}
+
+	if percent/plainStepPercent > b.lastPercent/plainStepPercent && current < b.total {
+		fmt.Fprintf(b.w, "%s %s (%d%%)\n", b.message, b.counts(), percent)
+	}
+	b.lastPercent = percent
+}
+
+// Printf prints a line above the bar, e.g. to report a failed item.
+func (b *Bar) Printf(format string, args ...interface{}) {
+	if b.mode == MODE_JSON {
+		b.writeEvent(EVENT_MESSAGE, strings.TrimSpace(fmt.Sprintf(format, args...)), "")
+		return
+	}
+	if b.mode == MODE_TTY {
+		fmt.Fprint(b.w, clearLine)
+	}
+	fmt.Fprintf(b.w, format, args...)
+	if b.mode == MODE_TTY {
+		fmt.Fprintf(b.w, "%s %s", b.message, b.render())
+	}
+}
+
+// Finish ends the bar with a final line reporting the items completed and the elapsed time.
+func (b *Bar) Finish() {
+	if b.mode == MODE_JSON {
+		b.writeEvent(EVENT_DONE, b.counts(), "done")
+		return
+	}
+	if b.mode == MODE_ACCESSIBLE {
+		fmt.Fprintf(b.w, "Finished: %s, %s of %s done, after %s.\n", b.message, b.format(b.current), b.format(b.total), describeDuration(elapsedSince(b.start)))
+		return
+	}
+	if b.mode == MODE_TTY {
+		fmt.Fprint(b.w, clearLine)
+	}
+	fmt.Fprintf(b.w, "%s %s done (%s)\n", b.message, b.counts(), elapsedSince(b.start))
+}
+
+func (b *Bar) writeEvent(event string, message string, status string) {
+	percent := b.percent()
+	writeEvent(b.w, Event{Event: event, Phase: b.message, Percent: &percent, Message: message, Status: status, ElapsedMs: elapsedSince(b.start).Milliseconds()})
+}
+
+func (b *Bar) percent() int {
+	if b.total <= 0 {
+		return 100
+	}
+	return b.current * 100 / b.total
+}
+
+func (b *Bar) render() string {
+	filled := barWidth * b.percent() / 100
+	if !b.bytes {
+		return fmt.Sprintf("[%s%s] %d/%d %s", strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled), b.current, b.total, elapsedSince(b.start))
+	}
+	if b.total <= 0 {
+		return fmt.Sprintf("%s %s", util.FormatBytes(float64(b.current)), elapsedSince(b.start))
+	}
+	return fmt.Sprintf("[%s%s] %s %d%% ETA %s", strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled), b.counts(), b.percent(), b.eta())
+}
+
+// counts renders the progress as current/total, in bytes for a ByteBar.
+func (b *Bar) counts() string {
+	if b.bytes && b.total <= 0 {
+		return b.format(b.current)
+	}
+	return fmt.Sprintf("%s/%s", b.format(b.current), b.format(b.total))
+}
+
+func (b *Bar) format(n int) string {
+	if b.bytes {
+		return util.FormatBytes(float64(n))
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// eta extrapolates the time remaining from the average rate so far.
+func (b *Bar) eta() time.Duration {
+	if b.current <= 0 {
+		return 0
+	}
+	remaining := float64(elapsedSince(b.start)) * float64(b.total-b.current) / float64(b.current)
+	return time.Duration(remaining).Round(time.Second)
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Pick MODE_TTY when writing to a terminal and MODE_PLAIN otherwise.
+	MODE_AUTO = "auto"
+	// Redraw spinners and bars in place with carriage returns and ANSI escapes.
+	MODE_TTY = "tty"
+	// Print plain lines only, without animation, for logs, CI and command tests.
+	MODE_PLAIN = "plain"
+	// Print one JSON Event per line, for GUIs and CI wrappers rendering their own progress.
+	MODE_JSON = "json"
+	// Print plain lines describing each step in words, for screen readers.
+	MODE_ACCESSIBLE = "accessible"
+)
+
+var Modes = []string{MODE_AUTO, MODE_TTY, MODE_PLAIN, MODE_JSON, MODE_ACCESSIBLE}
+
+const (
+	EVENT_START    = "start"
+	EVENT_PROGRESS = "progress"
+	EVENT_MESSAGE  = "message"
+	EVENT_DONE     = "done"
+)
+
+// Event is a step of a spinner or bar in MODE_JSON. Phase is the message the spinner or bar
+// was created with and identifies it across events.
+type Event struct {
+	Event   string `json:"event"`
+	Phase   string `json:"phase"`
+	Percent *int   `json:"percent,omitempty"`
+	Message string `json:"message,omitempty"`
+	// The final status, set on EVENT_DONE.
+	Status    string `json:"status,omitempty"`
+	ElapsedMs int64  `json:"elapsed_ms"`
+}
+
+const clearLine = "\r\033[K"
+
+// Clock is the source of time used to report elapsed durations.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// The clock is read by spinners rendering in the background while tests replace it.
+var (
+	clockMu sync.Mutex
+	clock   Clock = systemClock{}
+)
+
+// SetClock replaces the clock spinners and bars measure elapsed time with, e.g. with a fake
+// clock in tests. A nil clock restores the system clock.
+func SetClock(c Clock) {
+	if c == nil {
+		c = systemClock{}
+	}
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	clock = c
+}
+
+func now() time.Time {
+	clockMu.Lock()
+	c := clock
+	clockMu.Unlock()
+	return c.Now()
+}
+
+// Writer is output that spinners and bars render to in a given mode, e.g. the one selected
+// with --progress. Spinners and bars writing to anything else pick their mode automatically.
+type Writer struct {
+	io.Writer
+	Mode string
+}
+
+// resolveMode returns the renderer for output to w, any mode but MODE_AUTO.
+// SPICE_PROGRESS overrides MODE_AUTO.
+func resolveMode(w io.Writer) string {
+	m := MODE_AUTO
+	if pw, ok := w.(*Writer); ok {
+		w = pw.Writer
+		if pw.Mode != "" {
+			m = pw.Mode
+		}
+	}
+	if m == MODE_AUTO && os.Getenv("SPICE_PROGRESS") != "" {
+		m = strings.ToLower(os.Getenv("SPICE_PROGRESS"))
+	}
+
+	switch m {
+	case MODE_TTY, MODE_PLAIN, MODE_JSON, MODE_ACCESSIBLE:
+		return m
+	}
+
+	if os.Getenv("TERM") == "dumb" {
+		return MODE_PLAIN
+	}
+	file, ok := w.(*os.File)
+	if !ok {
+		return MODE_PLAIN
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return MODE_PLAIN
+	}
+	if info.Mode()&os.ModeCharDevice != 0 {
+		return MODE_TTY
+	}
+	return MODE_PLAIN
+}
+
+func writeEvent(w io.Writer, event Event) {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = w.Write(append(eventBytes, '\n'))
+}
+
+func elapsedSince(start time.Time) time.Duration {
+	elapsed := now().Sub(start)
+	if elapsed < time.Second {
+		return elapsed.Round(time.Millisecond)
+	}
+	return elapsed.Round(100 * time.Millisecond)
+}
+
+// describeDuration spells out a duration for screen readers, e.g. "1 minute 5 seconds"
+// rather than "1m5s".
+func describeDuration(d time.Duration) string {
+	if d < time.Second {
+		return plural(int(d.Milliseconds()), "millisecond")
+	}
+	d = d.Round(time.Second)
+	var parts []string
+	if hours := int(d.Hours()); hours > 0 {
+		parts = append(parts, plural(hours, "hour"))
+	}
+	if minutes := int(d.Minutes()) % 60; minutes > 0 {
+		parts = append(parts, plural(minutes, "minute"))
+	}
+	if seconds := int(d.Seconds()) % 60; seconds > 0 || len(parts) == 0 {
+		parts = append(parts, plural(seconds, "second"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
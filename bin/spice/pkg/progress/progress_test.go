@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+	"github.com/spiceai/spiceai/bin/spice/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpinnerPlain(t *testing.T) {
+	clock := testutils.UseFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var out bytes.Buffer
+	spinner := progress.NewSpinner(&out, "Waiting for taxi_trips")
+	spinner.Start()
+	clock.Advance(1500 * time.Millisecond)
+	spinner.Update("Waiting for orders")
+	clock.Advance(250 * time.Millisecond)
+	spinner.Stop("ready")
+
+	assert.Equal(t, "Waiting for taxi_trips ...\nWaiting for orders ...\nWaiting for orders ready (1.8s)\n", out.String())
+}
+
+func TestBarPlain(t *testing.T) {
+	clock := testutils.UseFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var out bytes.Buffer
+	bar := progress.NewBar(&out, "Queries", 8)
+	for i := 0; i < 8; i++ {
+		clock.Advance(100 * time.Millisecond)
+		if i == 4 {
+			bar.Printf("  q%d failed\n", i+1)
+		}
+		bar.Add(1)
+	}
+	bar.Finish()
+
+	assert.Equal(t, "Queries 2/8 (25%)\nQueries 4/8 (50%)\n  q5 failed\nQueries 6/8 (75%)\nQueries 8/8 done (800ms)\n", out.String())
+}
+
+func TestByteBar(t *testing.T) {
+	clock := testutils.UseFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var out bytes.Buffer
+	bar := progress.NewByteBar(&out, "Downloading spiced.tar.gz", 4<<20)
+	for i := 0; i < 4; i++ {
+		clock.Advance(time.Second)
+		bar.Add(1 << 20)
+	}
+	bar.Finish()
+	assert.Equal(t, "Downloading spiced.tar.gz 1.0MiB/4.0MiB (25%)\nDownloading spiced.tar.gz 2.0MiB/4.0MiB (50%)\nDownloading spiced.tar.gz 3.0MiB/4.0MiB (75%)\nDownloading spiced.tar.gz 4.0MiB/4.0MiB done (4s)\n", out.String())
+
+	out.Reset()
+	bar = progress.NewByteBar(&progress.Writer{Writer: &out, Mode: progress.MODE_TTY}, "Downloading", 4<<20)
+	clock.Advance(2 * time.Second)
+	bar.Set(1 << 20)
+	assert.Equal(t, "\r\033[KDownloading [=======                       ] 1.0MiB/4.0MiB 25% ETA 6s", out.String())
+}
+
+func TestTTY(t *testing.T) {
+	testutils.UseFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var out bytes.Buffer
+	bar := progress.NewBar(&progress.Writer{Writer: &out, Mode: progress.MODE_TTY}, "Queries", 2)
+	bar.Add(1)
+	bar.Finish()
+
+	assert.True(t, strings.HasPrefix(out.String(), "\r\033[KQueries [===============               ] 1/2 0s"))
+	assert.True(t, strings.HasSuffix(out.String(), "\r\033[KQueries 1/2 done (0s)\n"))
+}
+
+func TestJSON(t *testing.T) {
+	clock := testutils.UseFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	out := &progress.Writer{Writer: &buf, Mode: progress.MODE_JSON}
+	spinner := progress.NewSpinner(out, "Installing")
+	spinner.Start()
+	clock.Advance(time.Second)
+	spinner.Update("Extracting")
+	spinner.Stop("done")
+
+	bar := progress.NewBar(out, "Queries", 2)
+	bar.Add(1)
+	bar.Printf("  q2 failed\n")
+	bar.Add(1)
+	bar.Finish()
+
+	assert.Equal(t, `{"event":"start","phase":"Installing","message":"Installing","elapsed_ms":0}
+{"event":"message","phase":"Installing","message":"Extracting","elapsed_ms":1000}
+{"event":"done","phase":"Installing","message":"Extracting","status":"done","elapsed_ms":1000}
+{"event":"progress","phase":"Queries","percent":50,"message":"1/2","elapsed_ms":0}
+{"event":"message","phase":"Queries","percent":50,"message":"q2 failed","elapsed_ms":0}
+{"event":"done","phase":"Queries","percent":100,"message":"2/2","status":"done","elapsed_ms":0}
+`, buf.String())
+}
+
+func TestAccessible(t *testing.T) {
+	clock := testutils.UseFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	out := &progress.Writer{Writer: &buf, Mode: progress.MODE_ACCESSIBLE}
+	spinner := progress.NewSpinner(out, "Waiting for taxi_trips")
+	spinner.Start()
+	clock.Advance(65 * time.Second)
+	spinner.Stop("ready")
+
+	bar := progress.NewBar(out, "Queries", 4)
+	for i := 0; i < 4; i++ {
+		clock.Advance(100 * time.Millisecond)
+		bar.Add(1)
+	}
+	bar.Finish()
+
+	assert.Equal(t, `Started: Waiting for taxi_trips.
+Finished: Waiting for taxi_trips, ready, after 1 minute 5 seconds.
+Queries: 1 of 4 done, 25 percent.
+Queries: 2 of 4 done, 50 percent.
+Queries: 3 of 4 done, 75 percent.
+Finished: Queries, 4 of 4 done, after 400 milliseconds.
+`, buf.String())
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const spinnerInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner shows that a step of unknown length is running. On a terminal it animates in place,
+// otherwise it prints the message once when started and once more when stopped.
+type Spinner struct {
+	w       io.Writer
+	phase   string
+	message string
+	mode    string
+	start   time.Time
+
+	mu      sync.Mutex
+	stopped chan struct{}
+	done    chan struct{}
+}
+
+func NewSpinner(w io.Writer, message string) *Spinner {
+	return &Spinner{w: w, phase: message, message: message, mode: resolveMode(w)}
+}
+
+func (s *Spinner) Start() {
+	s.start = now()
+	switch s.mode {
+	case MODE_JSON:
+		writeEvent(s.w, Event{Event: EVENT_START, Phase: s.phase, Message: s.message})
+		return
+	case MODE_PLAIN:
+		fmt.Fprintf(s.w, "%s ...\n", s.message)
+		return
+	case MODE_ACCESSIBLE:
+		fmt.Fprintf(s.w, "Started: %s.\n", s.message)
+		return
+	}
+
+	s.stopped = make(chan struct{})
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(spinnerInterval)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			s.mu.Lock()
+			fmt.Fprintf(s.w, "%s%s %s", clearLine, spinnerFrames[frame%len(spinnerFrames)], s.message)
+			s.mu.Unlock()
+
+			select {
+			case <-s.stopped:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Update replaces the message shown next to the spinner. Without a terminal it is printed
+// on its own line.
+func (s *Spinner) Update(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = message
+	switch s.mode {
+	case MODE_JSON:
+		writeEvent(s.w, Event{Event: EVENT_MESSAGE, Phase: s.phase, Message: message, ElapsedMs: elapsedSince(s.start).Milliseconds()})
+	case MODE_PLAIN:
+		fmt.Fprintf(s.w, "%s ...\n", message)
+	case MODE_ACCESSIBLE:
+		fmt.Fprintf(s.w, "Now: %s, after %s.\n", message, describeDuration(elapsedSince(s.start)))
+	}
+}
+
+// Stop ends the spinner with a final line reporting status and the elapsed time.
+func (s *Spinner) Stop(status string) {
+	if s.mode == MODE_JSON {
+		writeEvent(s.w, Event{Event: EVENT_DONE, Phase: s.phase, Message: s.message, Status: status, ElapsedMs: elapsedSince(s.start).Milliseconds()})
+		return
+	}
+	if s.mode == MODE_ACCESSIBLE {
+		fmt.Fprintf(s.w, "Finished: %s, %s, after %s.\n", s.message, status, describeDuration(elapsedSince(s.start)))
+		return
+	}
+	if s.mode == MODE_TTY && s.stopped != nil {
+		close(s.stopped)
+		<-s.done
+		s.stopped = nil
+		fmt.Fprint(s.w, clearLine)
+	}
+	fmt.Fprintf(s.w, "%s %s (%s)\n", s.message, status, elapsedSince(s.start))
+}
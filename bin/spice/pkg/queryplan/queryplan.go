@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryplan
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	LOCATION_SOURCE = "source"
+	LOCATION_LOCAL  = "local"
+
+	KIND_SCAN       = "scan"
+	KIND_FILTER     = "filter"
+	KIND_PROJECTION = "projection"
+	KIND_JOIN       = "join"
+	KIND_LIMIT      = "limit"
+)
+
+// Scans that run a SQL query against the source, such as a federated database or an
+// accelerator, and print it as sql=<query>.
+var sqlScans = []string{"SqlExec", "FlightSqlExec", "FlightExec"}
+
+// Scans of local data that produce no source rows.
+var emptyScans = []string{"EmptyExec", "PlaceholderRowExec"}
+
+var localOperators = map[string]string{
+	"FilterExec":            KIND_FILTER,
+	"HashJoinExec":          KIND_JOIN,
+	"SortMergeJoinExec":     KIND_JOIN,
+	"NestedLoopJoinExec":    KIND_JOIN,
+	"CrossJoinExec":         KIND_JOIN,
+	"SymmetricHashJoinExec": KIND_JOIN,
+	"GlobalLimitExec":       KIND_LIMIT,
+}
+
+var (
+	selectPattern = regexp.MustCompile(`(?is)^\s*SELECT\s+(.*?)\s+FROM\s`)
+	wherePattern  = regexp.MustCompile(`(?is)\sWHERE\s+(.*?)(\s+(GROUP BY|ORDER BY|LIMIT)\s.*)?$`)
+	joinPattern   = regexp.MustCompile(`(?i)\sJOIN\s`)
+	limitPattern  = regexp.MustCompile(`(?i)\sLIMIT\s+(\d+)`)
+	filePredicate = regexp.MustCompile(`predicate=(.*?)(, \w+=|$)`)
+	fileProjected = regexp.MustCompile(`projection=\[(.*?)\]`)
+)
+
+// Node is an operator of a physical plan as printed by EXPLAIN.
+type Node struct {
+	Depth    int
+	Operator string
+	Detail   string
+}
+
+// Finding describes where part of a query runs: pushed down into a scan of the source, or
+// executed locally by the runtime after the rows were fetched.
+type Finding struct {
+	Operator string `json:"operator" csv:"operator" yaml:"operator"`
+	Kind     string `json:"kind" csv:"kind" yaml:"kind"`
+	Location string `json:"location" csv:"location" yaml:"location"`
+	FullScan bool   `json:"full_scan" csv:"full_scan" yaml:"full_scan"`
+	Detail   string `json:"detail" csv:"detail" yaml:"detail"`
+}
+
+// Parse reads the operators of a physical plan, one per line, nested by two spaces per level.
+func Parse(plan string) []Node {
+	var nodes []Node
+	for _, line := range strings.Split(plan, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" {
+			continue
+		}
+		operator, detail, _ := strings.Cut(trimmed, " ")
+		nodes = append(nodes, Node{
+			Depth:    (len(line) - len(trimmed)) / 2,
+			Operator: strings.TrimSuffix(operator, ":"),
+			Detail:   strings.TrimSpace(detail),
+		})
+	}
+	return nodes
+}
+
+// Analyze reports the scans of a physical plan with the filters, projections, joins and limits
+// pushed into them, and the filters, joins and limits the runtime executes locally. Scans that
+// read a whole table, without a filter or limit, are flagged as full scans.
+func Analyze(nodes []Node) []Finding {
+	var findings []Finding
+	for i, node := range nodes {
+		if kind, ok := localOperators[node.Operator]; ok {
+			findings = append(findings, Finding{Operator: node.Operator, Kind: kind, Location: LOCATION_LOCAL, Detail: node.Detail})
+			continue
+		}
+
+		isLeaf := i+1 == len(nodes) || nodes[i+1].Depth <= node.Depth
+		if !isLeaf || slices.Contains(emptyScans, node.Operator) {
+			continue
+		}
+
+		if slices.Contains(sqlScans, node.Operator) {
+			findings = append(findings, analyzeSqlScan(node)...)
+		} else {
+			findings = append(findings, analyzeFileScan(node)...)
+		}
+	}
+	return findings
+}
+
+func analyzeSqlScan(node Node) []Finding {
+	sql := strings.TrimPrefix(node.Detail, "sql=")
+	scan := Finding{Operator: node.Operator, Kind: KIND_SCAN, Location: LOCATION_SOURCE, Detail: sql}
+	var pushed []Finding
+
+	if match := selectPattern.FindStringSubmatch(sql); match != nil && strings.TrimSpace(match[1]) != "*" {
+		pushed = append(pushed, Finding{Operator: node.Operator, Kind: KIND_PROJECTION, Location: LOCATION_SOURCE, Detail: match[1]})
+	}
+	if match := wherePattern.FindStringSubmatch(sql); match != nil {
+		pushed = append(pushed, Finding{Operator: node.Operator, Kind: KIND_FILTER, Location: LOCATION_SOURCE, Detail: match[1]})
+	}
+	if joinPattern.MatchString(sql) {
+		pushed = append(pushed, Finding{Operator: node.Operator, Kind: KIND_JOIN, Location: LOCATION_SOURCE, Detail: "joined in the source query"})
+	}
+	if match := limitPattern.FindStringSubmatch(sql); match != nil {
+		pushed = append(pushed, Finding{Operator: node.Operator, Kind: KIND_LIMIT, Location: LOCATION_SOURCE, Detail: match[1]})
+	}
+
+	scan.FullScan = !wherePattern.MatchString(sql) && !limitPattern.MatchString(sql)
+	return append([]Finding{scan}, pushed...)
+}
+
+// analyzeFileScan handles scans that print their pushed down predicate and projection as
+// fields, such as ParquetExec, and scans of in-memory data that print neither.
+func analyzeFileScan(node Node) []Finding {
+	scan := Finding{Operator: node.Operator, Kind: KIND_SCAN, Location: LOCATION_LOCAL, Detail: node.Detail}
+	var pushed []Finding
+
+	if match := fileProjected.FindStringSubmatch(node.Detail); match != nil {
+		pushed = append(pushed, Finding{Operator: node.Operator, Kind: KIND_PROJECTION, Location: LOCATION_SOURCE, Detail: match[1]})
+	}
+	match := filePredicate.FindStringSubmatch(node.Detail)
+	if match != nil {
+		pushed = append(pushed, Finding{Operator: node.Operator, Kind: KIND_FILTER, Location: LOCATION_SOURCE, Detail: match[1]})
+	}
+	if strings.Contains(node.Detail, "file_groups=") {
+		scan.Location = LOCATION_SOURCE
+	}
+
+	scan.FullScan = match == nil
+	return append([]Finding{scan}, pushed...)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryplan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPlan = `ProjectionExec: expr=[name@0 as name, total@1 as total]
+  GlobalLimitExec: skip=0, fetch=10
+    HashJoinExec: mode=Partitioned, join_type=Inner, on=[(id@0, customer_id@0)]
+      SchemaCastScanExec
+        SqlExec sql=SELECT "id", "name" FROM customers WHERE ("region" = 'EU')
+      FilterExec: total@1 > 100
+        SqlExec sql=SELECT * FROM orders
+`
+
+func TestParse(t *testing.T) {
+	nodes := Parse(testPlan)
+	assert.Len(t, nodes, 7)
+	assert.Equal(t, Node{Depth: 0, Operator: "ProjectionExec", Detail: "expr=[name@0 as name, total@1 as total]"}, nodes[0])
+	assert.Equal(t, Node{Depth: 4, Operator: "SqlExec", Detail: `sql=SELECT "id", "name" FROM customers WHERE ("region" = 'EU')`}, nodes[4])
+}
+
+func TestAnalyze(t *testing.T) {
+	findings := Analyze(Parse(testPlan))
+	assert.Equal(t, []Finding{
+		{Operator: "GlobalLimitExec", Kind: KIND_LIMIT, Location: LOCATION_LOCAL, Detail: "skip=0, fetch=10"},
+		{Operator: "HashJoinExec", Kind: KIND_JOIN, Location: LOCATION_LOCAL, Detail: "mode=Partitioned, join_type=Inner, on=[(id@0, customer_id@0)]"},
+		{Operator: "SqlExec", Kind: KIND_SCAN, Location: LOCATION_SOURCE, Detail: `SELECT "id", "name" FROM customers WHERE ("region" = 'EU')`},
+		{Operator: "SqlExec", Kind: KIND_PROJECTION, Location: LOCATION_SOURCE, Detail: `"id", "name"`},
+		{Operator: "SqlExec", Kind: KIND_FILTER, Location: LOCATION_SOURCE, Detail: `("region" = 'EU')`},
+		{Operator: "FilterExec", Kind: KIND_FILTER, Location: LOCATION_LOCAL, Detail: "total@1 > 100"},
+		{Operator: "SqlExec", Kind: KIND_SCAN, Location: LOCATION_SOURCE, FullScan: true, Detail: "SELECT * FROM orders"},
+	}, findings)
+}
+
+func TestAnalyzeFileScan(t *testing.T) {
+	findings := Analyze(Parse(`ParquetExec: file_groups={1 group: [[trips.parquet]]}, projection=[fare, tip], predicate=fare@0 > 10, pruning_predicate=fare_max@0 > 10`))
+	assert.Equal(t, []Finding{
+		{Operator: "ParquetExec", Kind: KIND_SCAN, Location: LOCATION_SOURCE, Detail: "file_groups={1 group: [[trips.parquet]]}, projection=[fare, tip], predicate=fare@0 > 10, pruning_predicate=fare_max@0 > 10"},
+		{Operator: "ParquetExec", Kind: KIND_PROJECTION, Location: LOCATION_SOURCE, Detail: "fare, tip"},
+		{Operator: "ParquetExec", Kind: KIND_FILTER, Location: LOCATION_SOURCE, Detail: "fare@0 > 10"},
+	}, findings)
+
+	findings = Analyze(Parse("MemoryExec: partitions=1, partition_sizes=[1]"))
+	assert.Equal(t, []Finding{{Operator: "MemoryExec", Kind: KIND_SCAN, Location: LOCATION_LOCAL, FullScan: true, Detail: "partitions=1, partition_sizes=[1]"}}, findings)
+
+	assert.Empty(t, Analyze(Parse("ProjectionExec: expr=[1 as one]\n  PlaceholderRowExec")))
+}
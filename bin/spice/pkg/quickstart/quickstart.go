@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quickstart
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/github"
+	"github.com/spiceai/spiceai/bin/spice/pkg/tempdir"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+const (
+	QUICKSTARTS_OWNER = "spiceai"
+	QUICKSTARTS_REPO  = "quickstarts"
+)
+
+// Files listing the environment variables a quickstart needs, in order of preference.
+var envTemplateFiles = []string{".env.example", ".env.sample", ".env.template"}
+
+type Quickstart struct {
+	Name string `json:"name" csv:"name" yaml:"name"`
+	Url  string `json:"url" csv:"url" yaml:"url"`
+}
+
+// EnvVar is a variable declared by a quickstart's .env.example, with its example value.
+type EnvVar struct {
+	Name    string
+	Default string
+}
+
+// List returns the quickstarts published in github.com/spiceai/quickstarts.
+func List() ([]Quickstart, error) {
+	gh := github.NewGitHubClient(QUICKSTARTS_OWNER, QUICKSTARTS_REPO)
+	contents, err := github.GetContents(gh, "")
+	if err != nil {
+		return nil, fmt.Errorf("error listing quickstarts: %w", err)
+	}
+
+	var quickstarts []Quickstart
+	for _, content := range contents {
+		if content.Type != "dir" || strings.HasPrefix(content.Name, ".") {
+			continue
+		}
+		quickstarts = append(quickstarts, Quickstart{Name: content.Name, Url: content.HTMLURL})
+	}
+	sort.Slice(quickstarts, func(i, j int) bool { return quickstarts[i].Name < quickstarts[j].Name })
+
+	return quickstarts, nil
+}
+
+// Download copies the named quickstart from the latest quickstarts repository tarball into dest.
+func Download(name string, dest string) error {
+	gh := github.NewGitHubClient(QUICKSTARTS_OWNER, QUICKSTARTS_REPO)
+
+	downloadDir, err := tempdir.CreateTempDir("quickstart")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(downloadDir)
+
+	err = gh.DownloadTarGzip(gh.RepoApiUrl("tarball"), downloadDir)
+	if err != nil {
+		return fmt.Errorf("error downloading quickstarts: %w", err)
+	}
+
+	// The tarball contains a single <owner>-<repo>-<sha> directory
+	roots, err := os.ReadDir(downloadDir)
+	if err != nil {
+		return err
+	}
+	if len(roots) != 1 || !roots[0].IsDir() {
+		return errors.New("unexpected layout of the quickstarts archive")
+	}
+
+	source := filepath.Join(downloadDir, roots[0].Name(), name)
+	if stat, err := os.Stat(filepath.Join(source, "spicepod.yaml")); err != nil || stat.IsDir() {
+		return fmt.Errorf("quickstart '%s' not found, run spice quickstart list to see the available quickstarts", name)
+	}
+
+	return filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		return util.CopyFile(path, filepath.Join(dest, relativePath))
+	})
+}
+
+// RequiredEnv returns the variables declared in the quickstart's .env.example, if it has one.
+func RequiredEnv(dir string) ([]EnvVar, error) {
+	for _, name := range envTemplateFiles {
+		vars, err := readEnvFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return vars, err
+	}
+	return nil, nil
+}
+
+// LoadEnv returns the variables saved in the quickstart's .env file.
+func LoadEnv(dir string) (map[string]string, error) {
+	vars, err := readEnvFile(filepath.Join(dir, ".env"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+
+	values := make(map[string]string, len(vars))
+	for _, v := range vars {
+		values[v.Name] = v.Default
+	}
+	return values, nil
+}
+
+// SaveEnv writes vars to the quickstart's .env file, readable only by the current user.
+func SaveEnv(dir string, vars []EnvVar) error {
+	var content strings.Builder
+	for _, v := range vars {
+		content.WriteString(fmt.Sprintf("%s=%s\n", v.Name, v.Default))
+	}
+	return os.WriteFile(filepath.Join(dir, ".env"), []byte(content.String()), 0600)
+}
+
+func readEnvFile(path string) ([]EnvVar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var vars []EnvVar
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, _ := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		vars = append(vars, EnvVar{
+			Name:    strings.TrimSpace(name),
+			Default: strings.Trim(strings.TrimSpace(value), `"'`),
+		})
+	}
+	return vars, scanner.Err()
+}
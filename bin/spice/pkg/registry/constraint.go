@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Constraint is a semantic version range such as ^1.2.0, ~1.2.0, 1.x or ">=1.2.0 <2.0.0".
+type Constraint struct {
+	raw         string
+	comparators []comparator
+	// Exact constraints name a single version and are fetched without listing versions.
+	exact bool
+}
+
+type comparator struct {
+	operator string
+	version  string
+}
+
+// ParseConstraint parses a version constraint. Comparators are separated by spaces or commas
+// and must all hold; an empty constraint, * or latest matches any release.
+func ParseConstraint(raw string) (*Constraint, error) {
+	constraint := &Constraint{raw: raw}
+
+	fields := strings.FieldsFunc(raw, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 1 && isFullVersion(fields[0]) {
+		constraint.exact = true
+	}
+
+	for _, field := range fields {
+		if field == "*" || field == "latest" {
+			continue
+		}
+
+		operator := ""
+		for _, op := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+			if strings.HasPrefix(field, op) {
+				operator = op
+				break
+			}
+		}
+		version := strings.TrimPrefix(strings.TrimPrefix(field, operator), "v")
+
+		core := strings.SplitN(strings.SplitN(version, "+", 2)[0], "-", 2)[0]
+		parts := strings.Split(core, ".")
+		numbers := make([]int, 0, 3)
+		for _, part := range parts {
+			if part == "x" || part == "X" || part == "*" {
+				break
+			}
+			number, err := strconv.Atoi(part)
+			if err != nil || len(parts) > 3 {
+				return nil, fmt.Errorf("invalid version constraint '%s'", raw)
+			}
+			numbers = append(numbers, number)
+		}
+		if len(numbers) == 0 {
+			return nil, fmt.Errorf("invalid version constraint '%s'", raw)
+		}
+
+		lower := canonical(version)
+		// A partial version like 1.2 names the versions from 1.2.0 up to next, here 1.3.0
+		next := ""
+		if len(numbers) < 3 {
+			lower = versionOf(numbers...)
+			next = nextVersion(numbers)
+		}
+
+		switch {
+		case operator == "^":
+			// The leftmost non-zero part may not change, e.g. ^0.2.3 is <0.3.0 and ^0.0.3 is <0.0.4
+			upper := versionOf(numbers[0] + 1)
+			switch {
+			case numbers[0] > 0 || len(numbers) == 1:
+			case numbers[1] > 0 || len(numbers) == 2:
+				upper = versionOf(0, numbers[1]+1)
+			default:
+				upper = versionOf(0, 0, numbers[2]+1)
+			}
+			constraint.add(">=", lower).add("<", upper)
+		case operator == "~":
+			upper := versionOf(numbers[0] + 1)
+			if len(numbers) > 1 {
+				upper = versionOf(numbers[0], numbers[1]+1)
+			}
+			constraint.add(">=", lower).add("<", upper)
+		case next != "" && (operator == "" || operator == "="):
+			// Partial versions like 1.2 or 1.2.x match any patch release
+			constraint.add(">=", lower).add("<", next)
+		case next != "" && operator == ">":
+			constraint.add(">=", next)
+		case next != "" && operator == "<=":
+			constraint.add("<", next)
+		case operator == "":
+			constraint.add("=", lower)
+		default:
+			constraint.add(operator, lower)
+		}
+	}
+
+	return constraint, nil
+}
+
+func (c *Constraint) add(operator string, version string) *Constraint {
+	c.comparators = append(c.comparators, comparator{operator: operator, version: version})
+	return c
+}
+
+// IsExact reports whether the constraint names a single version.
+func (c *Constraint) IsExact() bool {
+	return c.exact
+}
+
+// Check reports whether version satisfies the constraint. Pre-releases only match exact constraints.
+func (c *Constraint) Check(version string) bool {
+	v := canonical(version)
+	if !semver.IsValid(v) || (semver.Prerelease(v) != "" && !c.exact) {
+		return false
+	}
+
+	for _, comparator := range c.comparators {
+		compare := semver.Compare(v, comparator.version)
+		var ok bool
+		switch comparator.operator {
+		case "=":
+			ok = compare == 0
+		case ">=":
+			ok = compare >= 0
+		case ">":
+			ok = compare > 0
+		case "<=":
+			ok = compare <= 0
+		case "<":
+			ok = compare < 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Highest returns the highest of versions that satisfies the constraint, or "" if none do.
+func (c *Constraint) Highest(versions []string) string {
+	highest := ""
+	for _, version := range versions {
+		if c.Check(version) && (highest == "" || semver.Compare(canonical(version), canonical(highest)) > 0) {
+			highest = version
+		}
+	}
+	return highest
+}
+
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+func isFullVersion(version string) bool {
+	return semver.IsValid(canonical(version)) && strings.Count(strings.SplitN(version, "-", 2)[0], ".") == 2 &&
+		!strings.ContainsAny(version, "^~<>=*xX")
+}
+
+// canonical prefixes a version with v as expected by golang.org/x/mod/semver.
+func canonical(version string) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return semver.Canonical(version)
+}
+
+// nextVersion returns the first version after those the partial version numbers names, e.g.
+// v1.3.0 for 1.2.
+func nextVersion(numbers []int) string {
+	next := append([]int(nil), numbers...)
+	next[len(next)-1]++
+	return versionOf(next...)
+}
+
+func versionOf(numbers ...int) string {
+	for len(numbers) < 3 {
+		numbers = append(numbers, 0)
+	}
+	return fmt.Sprintf("v%d.%d.%d", numbers[0], numbers[1], numbers[2])
+}
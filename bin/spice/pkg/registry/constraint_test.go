@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraint(t *testing.T) {
+	versions := []string{"v0.9.0", "v1.0.0", "v1.2.0", "v1.2.5", "v1.3.0", "v2.0.0", "v2.1.0-rc.1"}
+
+	for raw, expected := range map[string]string{
+		"":             "v2.0.0",
+		"*":            "v2.0.0",
+		"^1.2.0":       "v1.3.0",
+		"~1.2.0":       "v1.2.5",
+		"1.2.x":        "v1.2.5",
+		"1":            "v1.3.0",
+		">=1.0.0 <1.3": "v1.2.5",
+		">=1.0.0,<1.2": "v1.0.0",
+		"1.2.0":        "v1.2.0",
+		"v2.1.0-rc.1":  "v2.1.0-rc.1",
+		"^0.9":         "v0.9.0",
+		">2.0.0":       "",
+		"^3":           "",
+		"<=1.0.0 >0.9": "v1.0.0",
+		"=1.2.5":       "v1.2.5",
+		"latest":       "v2.0.0",
+		"~1":           "v1.3.0",
+		"2.x":          "v2.0.0",
+		">1.2 <2":      "v1.3.0",
+		">1.2.0 <2":    "v1.3.0",
+		"<=1.2":        "v1.2.5",
+		"<=1.2.0":      "v1.2.0",
+		">=1.2 <1.3":   "v1.2.5",
+		"<1.2":         "v1.0.0",
+	} {
+		constraint, err := ParseConstraint(raw)
+		assert.NoError(t, err, raw)
+		assert.Equal(t, expected, constraint.Highest(versions), raw)
+	}
+
+	constraint, err := ParseConstraint("1.2.0")
+	assert.NoError(t, err)
+	assert.True(t, constraint.IsExact())
+	constraint, err = ParseConstraint("^1.2.0")
+	assert.NoError(t, err)
+	assert.False(t, constraint.IsExact())
+	assert.True(t, constraint.Check("1.2.3"))
+
+	for raw, checks := range map[string]map[string]bool{
+		"^0.0.3": {"0.0.3": true, "0.0.4": false, "0.0.9": false, "0.0.2": false},
+		"^0.0":   {"0.0.9": true, "0.1.0": false},
+		"^0.2.3": {"0.2.9": true, "0.3.0": false},
+		"^0":     {"0.9.0": true, "1.0.0": false},
+		">1.2":   {"1.2.5": false, "1.3.0": true},
+		"<=1.2":  {"1.2.5": true, "1.3.0": false},
+	} {
+		constraint, err := ParseConstraint(raw)
+		assert.NoError(t, err, raw)
+		for version, expected := range checks {
+			assert.Equal(t, expected, constraint.Check(version), "%s %s", raw, version)
+		}
+	}
+
+	_, err = ParseConstraint("^abc")
+	assert.Error(t, err)
+	_, err = ParseConstraint("1.2.0 || 2.0.0")
+	assert.Error(t, err)
+}
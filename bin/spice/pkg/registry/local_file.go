@@ -28,7 +28,9 @@ import (
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
 )
 
-type LocalFileRegistry struct{}
+type LocalFileRegistry struct {
+	rtcontext *context.RuntimeContext
+}
 
 func (r *LocalFileRegistry) GetPod(podPath string) (string, error) {
 	stat, err := os.Stat(podPath)
@@ -50,7 +52,7 @@ func (r *LocalFileRegistry) GetPod(podPath string) (string, error) {
 		}
 	}
 
-	rtcontext := context.NewContext()
+	rtcontext := r.rtcontext
 
 	// Validate source
 	podManifestFileName := fmt.Sprintf("%s.yaml", strings.ToLower(filepath.Base(podPath)))
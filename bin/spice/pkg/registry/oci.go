@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/oci"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+// OciRegistry pulls Spicepods published as OCI artifacts, e.g. oci://ghcr.io/org/pod:1.2.0.
+type OciRegistry struct {
+	// Manifest digest to pull instead of resolving the tag, as recorded in the lockfile.
+	PinnedDigest string
+	// Manifest digest of the last Spicepod pulled.
+	Digest string
+
+	rtcontext *context.RuntimeContext
+}
+
+func (r *OciRegistry) GetPod(podPath string) (string, error) {
+	ref, err := oci.ParseReference(podPath)
+	if err != nil {
+		return "", err
+	}
+	if r.PinnedDigest != "" {
+		ref = ref.Pinned(r.PinnedDigest)
+	}
+
+	ctx, cancel := r.rtcontext.RequestContext()
+	defer cancel()
+	digest, content, err := oci.NewClient(ref.Registry).WithContext(ctx).Pull(ref)
+	if err != nil {
+		if errors.Is(err, oci.ErrNotFound) {
+			return "", NewRegistryItemNotFound(fmt.Errorf("spicepod %s not found", ref))
+		}
+		return "", err
+	}
+
+	podDir := filepath.Join(r.rtcontext.PodsDir(), ref.Name())
+	if err = os.RemoveAll(podDir); err != nil {
+		return "", err
+	}
+	if _, err = util.MkDirAllInheritPerm(podDir); err != nil {
+		return "", err
+	}
+	if err = util.ExtractTarGz(content, podDir); err != nil {
+		return "", fmt.Errorf("error extracting Spicepod %s: %w", ref, err)
+	}
+
+	r.Digest = digest
+	return podDir, nil
+}
+
+func (r *OciRegistry) ListVersions(podPath string) ([]string, error) {
+	ref, err := oci.ParseReference(podPath)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := r.rtcontext.RequestContext()
+	defer cancel()
+	return oci.NewClient(ref.Registry).WithContext(ctx).Tags(ref)
+}
+
+// ChangelogUrl links to the release notes in the source repository of the version, as annotated
+// by spice pods publish from metadata.repository.
+func (r *OciRegistry) ChangelogUrl(podPath string, version string) (string, error) {
+	ref, err := oci.ParseReference(podPath)
+	if err != nil {
+		return "", err
+	}
+	ref.Tag, ref.Digest = version, ""
+
+	ctx, cancel := r.rtcontext.RequestContext()
+	defer cancel()
+	_, manifest, err := oci.NewClient(ref.Registry).WithContext(ctx).GetManifest(ref)
+	if err != nil {
+		return "", err
+	}
+	return releaseNotesUrl(manifest.Annotations[oci.ANNOTATION_SOURCE], version), nil
+}
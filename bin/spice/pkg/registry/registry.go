@@ -17,22 +17,75 @@ limitations under the License.
 package registry
 
 import (
+	"fmt"
 	"os"
 	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/config"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/oci"
 )
 
 type SpiceRegistry interface {
 	GetPod(podPath string) (string, error)
 }
 
-func GetRegistry(path string) SpiceRegistry {
+// GetRegistry returns the registry a Spicepod path is fetched from. Its requests are bound to
+// rtcontext's context and request timeout, and Spicepods are fetched into its pods directory.
+func GetRegistry(rtcontext *context.RuntimeContext, path string) SpiceRegistry {
+	if oci.IsReference(path) {
+		return &OciRegistry{rtcontext: rtcontext}
+	}
+
 	if strings.HasPrefix(path, "/") || strings.HasPrefix(path, "../") || strings.HasPrefix(path, "file://") {
-		return &LocalFileRegistry{}
+		return &LocalFileRegistry{rtcontext: rtcontext}
 	}
 
 	if _, err := os.Stat(path); err == nil {
-		return &LocalFileRegistry{}
+		return &LocalFileRegistry{rtcontext: rtcontext}
+	}
+
+	return getSpiceRackRegistry(rtcontext, path)
+}
+
+// getSpiceRackRegistry returns the private registry named by the path's <name>: prefix, else the
+// default private registry, else spicerack.org.
+func getSpiceRackRegistry(rtcontext *context.RuntimeContext, path string) *SpiceRackRegistry {
+	cliConfig, err := config.Load(rtcontext.SpiceRuntimeDir())
+	if err != nil {
+		zaplog.Sugar().Warnf("Ignoring private registries: %s", err.Error())
+		return &SpiceRackRegistry{rtcontext: rtcontext}
+	}
+
+	registryConfig := cliConfig.DefaultRegistry()
+	if name, _, ok := strings.Cut(path, ":"); ok && cliConfig.GetRegistry(name) != nil {
+		registryConfig = cliConfig.GetRegistry(name)
 	}
+	if registryConfig == nil {
+		return &SpiceRackRegistry{rtcontext: rtcontext}
+	}
+
+	return newPrivateRegistry(rtcontext, registryConfig)
+}
+
+// NewSpiceRackRegistry returns the configured private registry with the given name, or the
+// default registry when name is empty.
+func NewSpiceRackRegistry(rtcontext *context.RuntimeContext, name string) (*SpiceRackRegistry, error) {
+	if name == "" {
+		return getSpiceRackRegistry(rtcontext, ""), nil
+	}
+
+	cliConfig, err := config.Load(rtcontext.SpiceRuntimeDir())
+	if err != nil {
+		return nil, err
+	}
+	registryConfig := cliConfig.GetRegistry(name)
+	if registryConfig == nil {
+		return nil, fmt.Errorf("no registry named '%s' is configured, add it with: spice registry add %s <endpoint>", name, name)
+	}
+	return newPrivateRegistry(rtcontext, registryConfig), nil
+}
 
-	return &SpiceRackRegistry{}
+func newPrivateRegistry(rtcontext *context.RuntimeContext, registryConfig *config.RegistryConfig) *SpiceRackRegistry {
+	return &SpiceRackRegistry{Name: registryConfig.Name, Endpoint: registryConfig.Endpoint, Token: registryConfig.GetToken(), rtcontext: rtcontext}
 }
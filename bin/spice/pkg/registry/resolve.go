@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/oci"
+)
+
+var ErrUnversioned = errors.New("dependency has no published versions")
+
+// VersionLister is implemented by registries that can list the published versions of a Spicepod.
+type VersionLister interface {
+	ListVersions(podPath string) ([]string, error)
+}
+
+// ChangelogLinker is implemented by registries that can link to the release notes of a version.
+type ChangelogLinker interface {
+	ChangelogUrl(podPath string, version string) (string, error)
+}
+
+// Resolution is the concrete version a dependency resolved to.
+type Resolution struct {
+	// Dependency as written in spicepod.yaml, e.g. spiceai/quickstart@^1.2
+	Dependency string
+	// Path to fetch, e.g. spiceai/quickstart@v1.2.3
+	Path    string
+	Version string
+}
+
+// SplitDependency splits a registry dependency into its path and version constraint:
+// spiceai/quickstart@^1.2 and oci://ghcr.io/org/pod:^1.2 have the constraint ^1.2.
+// Local paths and OCI digests have no constraint.
+func SplitDependency(rtcontext *context.RuntimeContext, dependency string) (string, string) {
+	switch GetRegistry(rtcontext, dependency).(type) {
+	case *OciRegistry:
+		ref, err := oci.ParseReference(dependency)
+		if err != nil || ref.Digest != "" {
+			return dependency, ""
+		}
+		tag := ref.Tag
+		ref.Tag = ""
+		return ref.String(), tag
+	case *SpiceRackRegistry:
+		path, constraint, _ := strings.Cut(dependency, "@")
+		return path, constraint
+	}
+	return dependency, ""
+}
+
+// WithVersion returns the dependency path that fetches a specific version.
+func WithVersion(path string, version string) string {
+	if oci.IsReference(path) {
+		return fmt.Sprintf("%s:%s", path, version)
+	}
+	return fmt.Sprintf("%s@%s", path, version)
+}
+
+// Resolve picks the version of a dependency to fetch. A locked version that still satisfies the
+// constraint is kept; otherwise the highest published version satisfying it is chosen.
+func Resolve(rtcontext *context.RuntimeContext, dependency string, lockedVersion string) (*Resolution, error) {
+	path, raw := SplitDependency(rtcontext, dependency)
+	resolution := &Resolution{Dependency: dependency, Path: dependency, Version: raw}
+	if raw == "" {
+		return resolution, nil
+	}
+
+	constraint, err := ParseConstraint(raw)
+	if err != nil {
+		return nil, err
+	}
+	if constraint.IsExact() {
+		return resolution, nil
+	}
+	if lockedVersion != "" && constraint.Check(lockedVersion) {
+		resolution.Path, resolution.Version = WithVersion(path, lockedVersion), lockedVersion
+		return resolution, nil
+	}
+
+	lister, ok := GetRegistry(rtcontext, path).(VersionLister)
+	if !ok {
+		return nil, fmt.Errorf("version constraints are not supported for '%s'", dependency)
+	}
+	versions, err := lister.ListVersions(path)
+	if err != nil {
+		return nil, err
+	}
+
+	version := constraint.Highest(versions)
+	if version == "" {
+		return nil, fmt.Errorf("no published version of %s matches %s, available versions: %s", path, constraint, strings.Join(versions, ", "))
+	}
+	resolution.Path, resolution.Version = WithVersion(path, version), version
+	return resolution, nil
+}
+
+// Available returns the newest published version satisfying a dependency's constraint and the
+// newest stable version overall. Local dependencies and OCI digests return ErrUnversioned.
+func Available(rtcontext *context.RuntimeContext, dependency string) (string, string, error) {
+	if oci.IsReference(dependency) {
+		if ref, err := oci.ParseReference(dependency); err != nil || ref.Digest != "" {
+			return "", "", ErrUnversioned
+		}
+	}
+	path, raw := SplitDependency(rtcontext, dependency)
+	lister, ok := GetRegistry(rtcontext, path).(VersionLister)
+	if !ok {
+		return "", "", ErrUnversioned
+	}
+
+	constraint, err := ParseConstraint(raw)
+	if err != nil {
+		return "", "", err
+	}
+	versions, err := lister.ListVersions(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	latest, err := ParseConstraint("")
+	if err != nil {
+		return "", "", err
+	}
+	return constraint.Highest(versions), latest.Highest(versions), nil
+}
+
+// DependencyDir returns the directory below the spicepods directory that a registry dependency
+// is downloaded to, or "" for local dependencies.
+func DependencyDir(rtcontext *context.RuntimeContext, dependency string) string {
+	switch r := GetRegistry(rtcontext, dependency).(type) {
+	case *OciRegistry:
+		ref, err := oci.ParseReference(dependency)
+		if err != nil {
+			return ""
+		}
+		return ref.Name()
+	case *SpiceRackRegistry:
+		path, _ := SplitDependency(rtcontext, dependency)
+		return r.trimName(path)
+	}
+	return ""
+}
+
+// releaseNotesUrl links to the release of a version in a GitHub or GitLab repository, or to the
+// repository itself for other hosts.
+func releaseNotesUrl(repository string, version string) string {
+	repository = strings.TrimSuffix(strings.TrimSuffix(repository, "/"), ".git")
+	switch {
+	case repository == "":
+		return ""
+	case strings.HasPrefix(repository, "https://github.com/"):
+		return fmt.Sprintf("%s/releases/tag/%s", repository, version)
+	case strings.HasPrefix(repository, "https://gitlab.com/"):
+		return fmt.Sprintf("%s/-/releases/%s", repository, version)
+	}
+	return repository
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseNotesUrl(t *testing.T) {
+	assert.Equal(t, "https://github.com/org/pod/releases/tag/v1.2.0", releaseNotesUrl("https://github.com/org/pod.git", "v1.2.0"))
+	assert.Equal(t, "https://gitlab.com/org/pod/-/releases/v1.2.0", releaseNotesUrl("https://gitlab.com/org/pod/", "v1.2.0"))
+	assert.Equal(t, "https://git.example.com/org/pod", releaseNotesUrl("https://git.example.com/org/pod", "v1.2.0"))
+	assert.Equal(t, "", releaseNotesUrl("", "v1.2.0"))
+}
@@ -18,9 +18,11 @@ package registry
 
 import (
 	"archive/zip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -38,9 +40,68 @@ var (
 	zaplog *zap.Logger = loggers.ZapLogger()
 )
 
-type SpiceRackRegistry struct{}
+// SpiceRackRegistry fetches Spicepods from spicerack.org, or from a private registry serving the
+// same API when Endpoint is set.
+type SpiceRackRegistry struct {
+	// Name of a configured private registry, used as the <name>: prefix in dependencies
+	Name     string
+	Endpoint string
+	Token    string
+
+	rtcontext *context.RuntimeContext
+}
+
+// SpicepodSummary describes a Spicepod published to spicerack.org.
+type SpicepodSummary struct {
+	Path        string `json:"path" csv:"path" yaml:"path"`
+	Description string `json:"description" csv:"description" yaml:"description"`
+	Version     string `json:"version" csv:"version" yaml:"version"`
+	Installs    int64  `json:"installs" csv:"installs" yaml:"installs"`
+}
+
+// SpicepodDetails describes a Spicepod and its published versions.
+type SpicepodDetails struct {
+	SpicepodSummary `yaml:",inline"`
+	Author          string   `json:"author" csv:"author" yaml:"author"`
+	Repository      string   `json:"repository,omitempty" csv:"repository" yaml:"repository,omitempty"`
+	Versions        []string `json:"versions" csv:"versions" yaml:"versions"`
+	Datasets        []string `json:"datasets" csv:"datasets" yaml:"datasets"`
+	Models          []string `json:"models" csv:"models" yaml:"models"`
+}
+
+func (r *SpiceRackRegistry) baseUrl() string {
+	if r.Endpoint != "" {
+		return strings.TrimSuffix(r.Endpoint, "/")
+	}
+	return getSpiceRackBaseUrl()
+}
+
+func (r *SpiceRackRegistry) String() string {
+	if r.Name != "" {
+		return fmt.Sprintf("registry '%s'", r.Name)
+	}
+	return "spicerack.org"
+}
+
+func (r *SpiceRackRegistry) headers() map[string]string {
+	if r.Token == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": fmt.Sprintf("Bearer %s", r.Token)}
+}
+
+// trimName strips the <name>: registry prefix from a dependency path.
+func (r *SpiceRackRegistry) trimName(podPath string) string {
+	if r.Name == "" {
+		return podPath
+	}
+	return strings.TrimPrefix(podPath, r.Name+":")
+}
 
 func getSpiceRackBaseUrl() string {
+	if os.Getenv("SPICE_SPICERACK_ENDPOINT") != "" {
+		return strings.TrimSuffix(os.Getenv("SPICE_SPICERACK_ENDPOINT"), "/")
+	}
 	if strings.HasSuffix(version.Version(), "-dev") {
 		return "https://dev-data.spiceai.io/v0.1"
 	} else {
@@ -48,8 +109,117 @@ func getSpiceRackBaseUrl() string {
 	}
 }
 
+// Search returns the Spicepods in the registry whose path or description matches term.
+func (r *SpiceRackRegistry) Search(term string) ([]SpicepodSummary, error) {
+	var results []SpicepodSummary
+	err := r.getJson(fmt.Sprintf("%s/search?q=%s", r.baseUrl(), url.QueryEscape(term)), &results)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred searching %s for '%s': %w", r, term, err)
+	}
+	return results, nil
+}
+
+// GetPodDetails returns the description and published versions of a Spicepod without downloading it.
+func (r *SpiceRackRegistry) GetPodDetails(podPath string) (*SpicepodDetails, error) {
+	podPath = r.trimName(podPath)
+	var details SpicepodDetails
+	err := r.getJson(fmt.Sprintf("%s/spicepods/%s", r.baseUrl(), podPath), &details)
+	if err != nil {
+		var itemNotFound *RegistryItemNotFound
+		if errors.As(err, &itemNotFound) {
+			return nil, NewRegistryItemNotFound(fmt.Errorf("spicepod %s not found", podPath))
+		}
+		return nil, fmt.Errorf("an error occurred fetching Spicepod '%s' from %s: %w", podPath, r, err)
+	}
+	return &details, nil
+}
+
+// Publish uploads a packaged Spicepod to the registry as podPath at podVersion. Private
+// registries authenticate with their token, spicerack.org with the Spice.ai API key.
+func (r *SpiceRackRegistry) Publish(podPath string, podVersion string, archivePath string, apiKey string) error {
+	podPath = r.trimName(podPath)
+	archive, err := os.ReadFile(archivePath)
+	if err != nil {
+		return err
+	}
+
+	headers := r.headers()
+	if headers == nil && apiKey != "" {
+		headers = map[string]string{"X-API-Key": apiKey}
+	}
+
+	url := fmt.Sprintf("%s/spicepods/%s/%s", r.baseUrl(), podPath, podVersion)
+	ctx, cancel := r.rtcontext.RequestContext()
+	defer cancel()
+	response, err := spice_http.Post(ctx, url, "application/gzip", archive, headers)
+	if err != nil {
+		return fmt.Errorf("an error occurred publishing Spicepod '%s' to %s: %w", podPath, r, err)
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case 200, 201:
+		return nil
+	case 401, 403:
+		if r.Name != "" {
+			return fmt.Errorf("not authorized to publish '%s', check the token configured for %s", podPath, r)
+		}
+		return fmt.Errorf("not authorized to publish '%s', run spice login and check you are a member of the organization", podPath)
+	case 409:
+		return fmt.Errorf("version %s of '%s' is already published, versions cannot be overwritten", podVersion, podPath)
+	}
+
+	body, _ := io.ReadAll(response.Body)
+	return fmt.Errorf("an error occurred publishing Spicepod '%s' to %s: %s %s", podPath, r, response.Status, strings.TrimSpace(string(body)))
+}
+
+func (r *SpiceRackRegistry) ChangelogUrl(podPath string, version string) (string, error) {
+	details, err := r.GetPodDetails(podPath)
+	if err != nil {
+		return "", err
+	}
+	return releaseNotesUrl(details.Repository, version), nil
+}
+
+func (r *SpiceRackRegistry) ListVersions(podPath string) ([]string, error) {
+	details, err := r.GetPodDetails(podPath)
+	if err != nil {
+		return nil, err
+	}
+	return details.Versions, nil
+}
+
+func (r *SpiceRackRegistry) getJson(url string, result interface{}) error {
+	ctx, cancel := r.rtcontext.RequestContext()
+	defer cancel()
+	response, err := spice_http.Get(ctx, url, "application/json", r.headers())
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == 404 {
+		return NewRegistryItemNotFound(fmt.Errorf("%s not found", url))
+	}
+	if response.StatusCode == 401 || response.StatusCode == 403 {
+		return r.unauthorized()
+	}
+	if response.StatusCode != 200 {
+		return fmt.Errorf("unexpected response %s", response.Status)
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+func (r *SpiceRackRegistry) unauthorized() error {
+	if r.Name != "" {
+		return fmt.Errorf("not authorized by %s, check its token with: spice registry list", r)
+	}
+	return fmt.Errorf("not authorized by %s", r)
+}
+
 func (r *SpiceRackRegistry) GetPod(podFullPath string) (string, error) {
-	parts := strings.Split(podFullPath, "@")
+	parts := strings.Split(r.trimName(podFullPath), "@")
 	podPath := podFullPath
 	podVersion := ""
 	if len(parts) == 2 {
@@ -57,13 +227,15 @@ func (r *SpiceRackRegistry) GetPod(podFullPath string) (string, error) {
 		podVersion = parts[1]
 	}
 
-	url := fmt.Sprintf("%s/spicepods/%s", getSpiceRackBaseUrl(), podPath)
+	url := fmt.Sprintf("%s/spicepods/%s", r.baseUrl(), podPath)
 	if podVersion != "" {
 		url = fmt.Sprintf("%s/%s", url, podVersion)
 	}
-	failureMessage := fmt.Sprintf("An error occurred while fetching Spicepod '%s' from spicerack.org", podFullPath)
+	failureMessage := fmt.Sprintf("An error occurred while fetching Spicepod '%s' from %s", podFullPath, r)
 
-	response, err := spice_http.Get(url, "application/zip")
+	ctx, cancel := r.rtcontext.RequestContext()
+	defer cancel()
+	response, err := spice_http.Get(ctx, url, "application/zip", r.headers())
 	if err != nil {
 		zaplog.Sugar().Debugf("%s: %s", failureMessage, err.Error())
 		return "", errors.New(failureMessage)
@@ -73,6 +245,9 @@ func (r *SpiceRackRegistry) GetPod(podFullPath string) (string, error) {
 	if response.StatusCode == 404 {
 		return "", NewRegistryItemNotFound(fmt.Errorf("spicepod %s not found", podPath))
 	}
+	if response.StatusCode == 401 || response.StatusCode == 403 {
+		return "", r.unauthorized()
+	}
 
 	if response.StatusCode != 200 {
 		return "", fmt.Errorf("an error occurred fetching Spicepod '%s'", podPath)
@@ -89,7 +264,7 @@ func (r *SpiceRackRegistry) GetPod(podFullPath string) (string, error) {
 		return "", err
 	}
 
-	podsPath := context.NewContext().PodsDir()
+	podsPath := r.rtcontext.PodsDir()
 	podsPathWithName := filepath.Join(podsPath, podPath)
 
 	podsPerm, err := util.MkDirAllInheritPerm(podsPathWithName)
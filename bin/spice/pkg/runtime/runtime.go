@@ -18,57 +18,173 @@ package runtime
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/spiceai/spiceai/bin/spice/pkg/docker"
+	"github.com/spiceai/spiceai/bin/spice/pkg/loggers"
+	"github.com/spiceai/spiceai/bin/spice/pkg/notify"
 	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
 )
 
-func Run() error {
-	fmt.Println("Spice.ai runtime starting...")
+// Run starts the installed runtime in rtcontext's app directory, installing or upgrading it first
+// when needed, and returns when it exits.
+func Run(rtcontext *context.RuntimeContext) error {
+	fmt.Fprintln(rtcontext.Out(), "Spice.ai runtime starting...")
 
-	rtcontext := context.NewContext()
+	var err error
+	if rtcontext.IsRuntimeInstallRequired() {
+		err = EnsureInstalled(rtcontext, "")
+		if err != nil {
+			return err
+		}
+	} else {
+		upgradeVersion, err := rtcontext.IsRuntimeUpgradeAvailable()
+		if err != nil {
+			fmt.Fprintf(rtcontext.ErrOut(), "error checking for runtime upgrade: %s\n", err.Error())
+		} else if upgradeVersion != "" {
+			err = rtcontext.InstallOrUpgradeRuntime()
+			if err != nil {
+				return err
+			}
+		}
+	}
 
-	err := rtcontext.Init()
+	cmd, err := rtcontext.GetRunCmd()
 	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+		return err
 	}
 
-	shouldInstall := false
-	var upgradeVersion string
-	if installRequired := rtcontext.IsRuntimeInstallRequired(); installRequired {
-		fmt.Println("The Spice.ai runtime has not yet been installed.")
-		shouldInstall = true
-	} else {
-		upgradeVersion, err = rtcontext.IsRuntimeUpgradeAvailable()
-		if err != nil {
-			log.Printf("error checking for runtime upgrade: %s", err.Error())
-		} else if upgradeVersion != "" {
-			shouldInstall = true
+	notifier := runtimeNotifier(rtcontext)
+	stdout, stderr, closeLog := runtimeOutput(rtcontext, notifier)
+	defer closeLog()
+	cmd.Stderr = stderr
+	cmd.Stdout = stdout
+
+	err = util.RunCommand(cmd)
+	notifyRuntimeFailed(notifier, err)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EnsureInstalled installs the runtime release tagged runtimeVersion unless it is already
+// installed. Without a version, it installs the latest release if no runtime is installed yet.
+func EnsureInstalled(rtcontext *context.RuntimeContext, runtimeVersion string) error {
+	if runtimeVersion != "" {
+		if !strings.HasPrefix(runtimeVersion, "v") {
+			runtimeVersion = "v" + runtimeVersion
 		}
+		if !rtcontext.IsRuntimeInstallRequired() {
+			installedVersion, err := rtcontext.Version()
+			if err == nil && installedVersion == runtimeVersion {
+				fmt.Fprintf(rtcontext.Out(), "Spice.ai runtime %s is already installed.\n", runtimeVersion)
+				return nil
+			}
+		}
+		return rtcontext.InstallRuntimeVersion(runtimeVersion)
 	}
 
-	if shouldInstall {
-		err = rtcontext.InstallOrUpgradeRuntime()
-		if err != nil {
-			return err
+	if !rtcontext.IsRuntimeInstallRequired() {
+		return nil
+	}
+	fmt.Fprintln(rtcontext.Out(), "The Spice.ai runtime has not yet been installed.")
+	return rtcontext.InstallOrUpgradeRuntime()
+}
+
+// RunDocker starts the runtime from the official container image with the app directory mounted.
+// Without an image tag, the image matches the installed runtime, or the CLI if none is installed.
+func RunDocker(rtcontext *context.RuntimeContext, imageTag string) error {
+	if imageTag == "" {
+		runtimeVersion := version.Version()
+		if !rtcontext.IsRuntimeInstallRequired() {
+			installedVersion, err := rtcontext.Version()
+			if err == nil {
+				runtimeVersion = installedVersion
+			}
 		}
+		imageTag = docker.ImageTag(runtimeVersion)
 	}
 
-	cmd, err := rtcontext.GetRunCmd()
+	cmd, err := docker.RunCommand(rtcontext.AppDir(), imageTag)
 	if err != nil {
 		return err
 	}
 
-	cmd.Stderr = os.Stderr
-	cmd.Stdout = os.Stdout
+	fmt.Fprintf(rtcontext.Out(), "Spice.ai runtime starting in Docker (%s:%s)...\n", docker.IMAGE_REPOSITORY, imageTag)
+
+	notifier := runtimeNotifier(rtcontext)
+	stdout, stderr, closeLog := runtimeOutput(rtcontext, notifier)
+	defer closeLog()
+	cmd.Stderr = stderr
+	cmd.Stdout = stdout
 
 	err = util.RunCommand(cmd)
+	notifyRuntimeFailed(notifier, err)
+	return err
+}
+
+// runtimeOutput copies the runtime's output to the app's runtime log as well as the runtime context's
+// output. Without a log, e.g. when the app directory is read-only, the output only goes to the
+// runtime context's output.
+// With a notifier, the output is also scanned for events to send to webhooks.
+func runtimeOutput(rtcontext *context.RuntimeContext, notifier *notify.Notifier) (io.Writer, io.Writer, func()) {
+	stdout := []io.Writer{rtcontext.Out()}
+	stderr := []io.Writer{rtcontext.ErrOut()}
+	closeLog := func() {}
+
+	logWriter, err := loggers.NewRuntimeLogWriter(rtcontext.AppDir())
 	if err != nil {
-		return err
+		fmt.Fprintf(rtcontext.ErrOut(), "error creating runtime log: %s\n", err.Error())
+	} else {
+		stdout = append(stdout, logWriter)
+		stderr = append(stderr, logWriter)
+		closeLog = func() { logWriter.Close() }
 	}
 
-	return nil
+	if notifier != nil {
+		stdout = append(stdout, notifier.LogWriter())
+		stderr = append(stderr, notifier.LogWriter())
+	}
+
+	return io.MultiWriter(stdout...), io.MultiWriter(stderr...), closeLog
+}
+
+// runtimeNotifier returns a notifier for the app's webhook subscriptions, or nil if it has none.
+func runtimeNotifier(rtcontext *context.RuntimeContext) *notify.Notifier {
+	subscriptions, err := notify.Load(rtcontext.AppDir())
+	if err != nil {
+		fmt.Fprintf(rtcontext.ErrOut(), "error loading notification subscriptions: %s\n", err.Error())
+		return nil
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	return notify.NewNotifier(filepath.Base(rtcontext.AppDir()), subscriptions, rtcontext.RequestContext, func(subscription notify.Subscription, err error) {
+		fmt.Fprintf(rtcontext.ErrOut(), "error sending %s notification %s: %s\n", subscription.Event, subscription.Id, err.Error())
+	})
+}
+
+// notifyRuntimeFailed notifies about the runtime exiting with an error, and waits for pending
+// notifications to be delivered before spice run exits.
+func notifyRuntimeFailed(notifier *notify.Notifier, err error) {
+	if notifier == nil {
+		return
+	}
+	if err != nil {
+		notifier.Notify(notify.Notification{
+			Event: notify.EVENT_RUNTIME_FAILED,
+			Time:  time.Now().UTC(),
+			Error: err.Error(),
+			Text:  fmt.Sprintf("Spice runtime exited: %s", err.Error()),
+		})
+	}
+	notifier.Wait()
 }
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtimeconfig
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	STATUS_CHANGED = "changed"
+	// Set in the local file but not reported by the runtime, e.g. a misspelled setting.
+	STATUS_UNKNOWN = "unknown"
+)
+
+type Setting struct {
+	Name  string `json:"name" csv:"name" yaml:"name"`
+	Value string `json:"value" csv:"value" yaml:"value"`
+}
+
+type Difference struct {
+	Name    string `json:"name" csv:"name" yaml:"name"`
+	Local   string `json:"local" csv:"local" yaml:"local"`
+	Runtime string `json:"runtime" csv:"runtime" yaml:"runtime"`
+	Status  string `json:"status" csv:"status" yaml:"status"`
+}
+
+// Fetch returns the effective configuration of a running runtime, sorted by name: the
+// runtime section of its Spicepod with defaults applied, as runtime.* settings, and the
+// settings of its query engine, such as datafusion.execution.batch_size.
+func Fetch(rtcontext *context.RuntimeContext) ([]Setting, error) {
+	spicepods, err := api.GetData[map[string]interface{}](rtcontext, "/v1/spicepods")
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	if len(spicepods) > 0 {
+		flatten("runtime", spicepods[0]["runtime"], values)
+	}
+
+	engineSettings, err := api.Sql[Setting](rtcontext, "SELECT name, value FROM information_schema.df_settings")
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range engineSettings {
+		values[setting.Name] = setting.Value
+	}
+
+	return toSettings(values), nil
+}
+
+// LoadFile reads settings from a YAML or JSON file. For a spicepod.yaml only its runtime
+// section is read, other files may set any setting, nested or by its dotted name.
+func LoadFile(path string) ([]Setting, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML
+	var document map[string]interface{}
+	if err = yaml.Unmarshal(fileBytes, &document); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	if document["kind"] == "Spicepod" {
+		flatten("runtime", document["runtime"], values)
+	} else {
+		flatten("", document, values)
+	}
+	return toSettings(values), nil
+}
+
+// Diff compares the settings of a local file with those of the runtime. Settings the file
+// leaves out are not compared.
+func Diff(local []Setting, effective []Setting) []Difference {
+	runtimeValues := map[string]string{}
+	for _, setting := range effective {
+		runtimeValues[setting.Name] = setting.Value
+	}
+
+	var differences []Difference
+	for _, setting := range local {
+		runtimeValue, ok := runtimeValues[setting.Name]
+		switch {
+		case !ok:
+			differences = append(differences, Difference{Name: setting.Name, Local: setting.Value, Status: STATUS_UNKNOWN})
+		case runtimeValue != setting.Value:
+			differences = append(differences, Difference{Name: setting.Name, Local: setting.Value, Runtime: runtimeValue, Status: STATUS_CHANGED})
+		}
+	}
+	return differences
+}
+
+// flatten adds the leaves of value to values, keyed by their dotted path under prefix.
+func flatten(prefix string, value interface{}, values map[string]string) {
+	join := func(key interface{}) string {
+		if prefix == "" {
+			return fmt.Sprintf("%v", key)
+		}
+		return fmt.Sprintf("%s.%v", prefix, key)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flatten(join(key), child, values)
+		}
+	case map[interface{}]interface{}:
+		for key, child := range v {
+			flatten(join(key), child, values)
+		}
+	case nil:
+		if prefix != "" {
+			values[prefix] = ""
+		}
+	default:
+		values[prefix] = fmt.Sprintf("%v", v)
+	}
+}
+
+func toSettings(values map[string]string) []Setting {
+	settings := make([]Setting, 0, len(values))
+	for name, value := range values {
+		settings = append(settings, Setting{Name: name, Value: value})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Name < settings[j].Name })
+	return settings
+}
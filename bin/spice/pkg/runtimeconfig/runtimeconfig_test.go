@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtimeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	spicepodPath := filepath.Join(dir, "spicepod.yaml")
+	err := os.WriteFile(spicepodPath, []byte("version: v1beta1\nkind: Spicepod\nname: app\nruntime:\n  results_cache:\n    item_ttl: 10s\n    enabled: true\n"), 0644)
+	assert.NoError(t, err)
+	settings, err := LoadFile(spicepodPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []Setting{{Name: "runtime.results_cache.enabled", Value: "true"}, {Name: "runtime.results_cache.item_ttl", Value: "10s"}}, settings)
+
+	settingsPath := filepath.Join(dir, "settings.json")
+	err = os.WriteFile(settingsPath, []byte(`{"datafusion": {"execution": {"batch_size": 8192}}, "runtime.num_of_parallel_loading_at_start_up": null}`), 0644)
+	assert.NoError(t, err)
+	settings, err = LoadFile(settingsPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []Setting{{Name: "datafusion.execution.batch_size", Value: "8192"}, {Name: "runtime.num_of_parallel_loading_at_start_up", Value: ""}}, settings)
+}
+
+func TestDiff(t *testing.T) {
+	effective := []Setting{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}, {Name: "c", Value: "3"}}
+	local := []Setting{{Name: "a", Value: "1"}, {Name: "b", Value: "4"}, {Name: "d", Value: "5"}}
+
+	assert.Equal(t, []Difference{
+		{Name: "b", Local: "4", Runtime: "2", Status: STATUS_CHANGED},
+		{Name: "d", Local: "5", Status: STATUS_UNKNOWN},
+	}, Diff(local, effective))
+	assert.Empty(t, Diff(local[:1], effective))
+}
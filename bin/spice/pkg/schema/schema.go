@@ -0,0 +1,482 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+)
+
+const (
+	FORMAT_SQL         = "sql"
+	FORMAT_JSON_SCHEMA = "jsonschema"
+	FORMAT_GO          = "go"
+	FORMAT_PYTHON      = "python"
+)
+
+var Formats = []string{FORMAT_SQL, FORMAT_JSON_SCHEMA, FORMAT_GO, FORMAT_PYTHON}
+
+// Kinds of Arrow data types, used to map a column to each output format.
+const (
+	kindBoolean   = "boolean"
+	kindInteger   = "integer"
+	kindUnsigned  = "unsigned"
+	kindFloat     = "float"
+	kindDecimal   = "decimal"
+	kindString    = "string"
+	kindBinary    = "binary"
+	kindDate      = "date"
+	kindTime      = "time"
+	kindTimestamp = "timestamp"
+	kindList      = "list"
+	kindUnknown   = "unknown"
+)
+
+var (
+	decimalPattern  = regexp.MustCompile(`^Decimal(?:128|256)?\((\d+),\s*(-?\d+)\)$`)
+	listItemPattern = regexp.MustCompile(`data_type:\s*([A-Za-z0-9]+(?:\([^()]*\))?)`)
+	pythonKeywords  = map[string]bool{
+		"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true, "async": true,
+		"await": true, "break": true, "class": true, "continue": true, "def": true, "del": true, "elif": true,
+		"else": true, "except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+		"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true, "or": true,
+		"pass": true, "raise": true, "return": true, "try": true, "while": true, "with": true, "yield": true,
+	}
+)
+
+type columnType struct {
+	kind string
+	bits int
+	// Precision and scale of decimals.
+	precision string
+	scale     string
+	// Element type of lists.
+	item *columnType
+}
+
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported format '%s', expected one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// Generate renders the schema of a dataset in the given format.
+func Generate(dataset string, columns []api.Column, format string) (string, error) {
+	if err := ValidateFormat(format); err != nil {
+		return "", err
+	}
+
+	switch format {
+	case FORMAT_SQL:
+		return generateSql(dataset, columns), nil
+	case FORMAT_JSON_SCHEMA:
+		return generateJsonSchema(dataset, columns)
+	case FORMAT_GO:
+		return generateGo(dataset, columns)
+	}
+	return generatePython(dataset, columns), nil
+}
+
+// parseDataType classifies an Arrow data type as displayed by DataFusion, e.g. "Int64",
+// "Timestamp(Nanosecond, None)" or "List(Field { name: \"item\", data_type: Utf8, ... })".
+func parseDataType(dataType string) columnType {
+	dataType = strings.TrimSpace(dataType)
+	name := dataType
+	if i := strings.Index(dataType, "("); i >= 0 {
+		name = dataType[:i]
+	}
+
+	switch name {
+	case "Boolean":
+		return columnType{kind: kindBoolean}
+	case "Int8", "Int16", "Int32", "Int64":
+		return columnType{kind: kindInteger, bits: bitsOf(name, "Int")}
+	case "UInt8", "UInt16", "UInt32", "UInt64":
+		return columnType{kind: kindUnsigned, bits: bitsOf(name, "UInt")}
+	case "Float16", "Float32", "Float64":
+		return columnType{kind: kindFloat, bits: bitsOf(name, "Float")}
+	case "Decimal", "Decimal128", "Decimal256":
+		t := columnType{kind: kindDecimal}
+		if match := decimalPattern.FindStringSubmatch(dataType); match != nil {
+			t.precision, t.scale = match[1], match[2]
+		}
+		return t
+	case "Utf8", "LargeUtf8", "Utf8View":
+		return columnType{kind: kindString}
+	case "Binary", "LargeBinary", "BinaryView", "FixedSizeBinary":
+		return columnType{kind: kindBinary}
+	case "Date32", "Date64":
+		return columnType{kind: kindDate}
+	case "Time32", "Time64":
+		return columnType{kind: kindTime}
+	case "Timestamp":
+		return columnType{kind: kindTimestamp}
+	case "List", "LargeList", "FixedSizeList":
+		t := columnType{kind: kindList, item: &columnType{kind: kindUnknown}}
+		if match := listItemPattern.FindStringSubmatch(dataType); match != nil {
+			item := parseDataType(match[1])
+			t.item = &item
+		}
+		return t
+	case "Dictionary":
+		// Dictionary(Int32, Utf8) is encoded as its value type
+		if parts := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(dataType, "Dictionary("), ")"), ",", 2); len(parts) == 2 {
+			return parseDataType(parts[1])
+		}
+	}
+
+	return columnType{kind: kindUnknown}
+}
+
+// IsNumeric reports whether an Arrow data type holds integers, floats or decimals.
+func IsNumeric(dataType string) bool {
+	switch parseDataType(dataType).kind {
+	case kindInteger, kindUnsigned, kindFloat, kindDecimal:
+		return true
+	}
+	return false
+}
+
+// IsOrdered reports whether values of an Arrow data type can be compared with MIN and MAX.
+func IsOrdered(dataType string) bool {
+	switch parseDataType(dataType).kind {
+	case kindList, kindBinary, kindUnknown:
+		return false
+	}
+	return true
+}
+
+func bitsOf(name string, prefix string) int {
+	var bits int
+	_, _ = fmt.Sscanf(strings.TrimPrefix(name, prefix), "%d", &bits)
+	return bits
+}
+
+func generateSql(dataset string, columns []api.Column) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s (\n", quoteSqlIdentifier(dataset))
+	for i, column := range columns {
+		fmt.Fprintf(&b, "  %s %s", quoteSqlIdentifier(column.Name), sqlType(parseDataType(column.DataType)))
+		if !column.Nullable() {
+			b.WriteString(" NOT NULL")
+		}
+		if i < len(columns)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(");\n")
+	return b.String()
+}
+
+func sqlType(t columnType) string {
+	switch t.kind {
+	case kindBoolean:
+		return "BOOLEAN"
+	case kindInteger, kindUnsigned:
+		switch t.bits {
+		case 8:
+			return "TINYINT"
+		case 16:
+			return "SMALLINT"
+		case 32:
+			return "INTEGER"
+		}
+		return "BIGINT"
+	case kindFloat:
+		if t.bits == 64 {
+			return "DOUBLE"
+		}
+		return "REAL"
+	case kindDecimal:
+		if t.precision != "" {
+			return fmt.Sprintf("DECIMAL(%s, %s)", t.precision, t.scale)
+		}
+		return "DECIMAL"
+	case kindBinary:
+		return "BYTEA"
+	case kindDate:
+		return "DATE"
+	case kindTime:
+		return "TIME"
+	case kindTimestamp:
+		return "TIMESTAMP"
+	case kindList:
+		return sqlType(*t.item) + "[]"
+	}
+	return "VARCHAR"
+}
+
+func quoteSqlIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if !isSimpleIdentifier(part) {
+			parts[i] = fmt.Sprintf(`"%s"`, strings.ReplaceAll(part, `"`, `""`))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func isSimpleIdentifier(name string) bool {
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func generateJsonSchema(dataset string, columns []api.Column) (string, error) {
+	type property = map[string]interface{}
+
+	properties := make(map[string]property, len(columns))
+	required := []string{}
+	for _, column := range columns {
+		prop := jsonSchemaType(parseDataType(column.DataType))
+		if column.Nullable() {
+			if t, ok := prop["type"].(string); ok {
+				prop["type"] = []string{t, "null"}
+			}
+		} else {
+			required = append(required, column.Name)
+		}
+		properties[column.Name] = prop
+	}
+
+	document := map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      dataset,
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+
+	output, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(output) + "\n", nil
+}
+
+func jsonSchemaType(t columnType) map[string]interface{} {
+	switch t.kind {
+	case kindBoolean:
+		return map[string]interface{}{"type": "boolean"}
+	case kindInteger:
+		return map[string]interface{}{"type": "integer"}
+	case kindUnsigned:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case kindFloat:
+		return map[string]interface{}{"type": "number"}
+	case kindDecimal:
+		return map[string]interface{}{"type": "number"}
+	case kindBinary:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	case kindDate:
+		return map[string]interface{}{"type": "string", "format": "date"}
+	case kindTime:
+		return map[string]interface{}{"type": "string", "format": "time"}
+	case kindTimestamp:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case kindList:
+		return map[string]interface{}{"type": "array", "items": jsonSchemaType(*t.item)}
+	case kindString:
+		return map[string]interface{}{"type": "string"}
+	}
+	return map[string]interface{}{}
+}
+
+func generateGo(dataset string, columns []api.Column) (string, error) {
+	types := make([]string, len(columns))
+	usesTime := false
+	for i, column := range columns {
+		types[i] = goType(parseDataType(column.DataType))
+		if column.Nullable() && !strings.HasPrefix(types[i], "[]") && types[i] != "interface{}" {
+			types[i] = "*" + types[i]
+		}
+		usesTime = usesTime || strings.Contains(types[i], "time.Time")
+	}
+
+	var b strings.Builder
+	if usesTime {
+		b.WriteString("import \"time\"\n\n")
+	}
+	fmt.Fprintf(&b, "type %s struct {\n", pascalCase(dataset))
+	for i, column := range columns {
+		fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", pascalCase(column.Name), types[i], column.Name)
+	}
+	b.WriteString("}\n")
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+func goType(t columnType) string {
+	switch t.kind {
+	case kindBoolean:
+		return "bool"
+	case kindInteger:
+		if t.bits == 0 {
+			return "int64"
+		}
+		return fmt.Sprintf("int%d", t.bits)
+	case kindUnsigned:
+		if t.bits == 0 {
+			return "uint64"
+		}
+		return fmt.Sprintf("uint%d", t.bits)
+	case kindFloat:
+		if t.bits == 64 {
+			return "float64"
+		}
+		return "float32"
+	case kindDecimal:
+		return "float64"
+	case kindString:
+		return "string"
+	case kindBinary:
+		return "[]byte"
+	case kindDate, kindTimestamp:
+		return "time.Time"
+	case kindTime:
+		return "string"
+	case kindList:
+		return "[]" + goType(*t.item)
+	}
+	return "interface{}"
+}
+
+func generatePython(dataset string, columns []api.Column) string {
+	// Names to import, keyed by module
+	imports := map[string]map[string]bool{}
+	types := make([]string, len(columns))
+	for i, column := range columns {
+		types[i] = pythonType(parseDataType(column.DataType), imports)
+		if column.Nullable() {
+			types[i] = fmt.Sprintf("%s[%s]", pythonImport(imports, "typing", "Optional"), types[i])
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("from dataclasses import dataclass\n")
+	for _, module := range []string{"datetime", "decimal", "typing"} {
+		var names []string
+		for name := range imports[module] {
+			names = append(names, name)
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			fmt.Fprintf(&b, "from %s import %s\n", module, strings.Join(names, ", "))
+		}
+	}
+	fmt.Fprintf(&b, "\n\n@dataclass\nclass %s:\n", pascalCase(dataset))
+	for i, column := range columns {
+		fmt.Fprintf(&b, "    %s: %s\n", pythonIdentifier(column.Name), types[i])
+	}
+	return b.String()
+}
+
+func pythonImport(imports map[string]map[string]bool, module string, name string) string {
+	if imports[module] == nil {
+		imports[module] = map[string]bool{}
+	}
+	imports[module][name] = true
+	return name
+}
+
+func pythonType(t columnType, imports map[string]map[string]bool) string {
+	switch t.kind {
+	case kindBoolean:
+		return "bool"
+	case kindInteger, kindUnsigned:
+		return "int"
+	case kindFloat:
+		return "float"
+	case kindDecimal:
+		return pythonImport(imports, "decimal", "Decimal")
+	case kindString:
+		return "str"
+	case kindBinary:
+		return "bytes"
+	case kindDate:
+		return pythonImport(imports, "datetime", "date")
+	case kindTime:
+		return pythonImport(imports, "datetime", "time")
+	case kindTimestamp:
+		return pythonImport(imports, "datetime", "datetime")
+	case kindList:
+		return fmt.Sprintf("%s[%s]", pythonImport(imports, "typing", "List"), pythonType(*t.item, imports))
+	}
+	return pythonImport(imports, "typing", "Any")
+}
+
+// pascalCase converts a dataset or column name like "taxi_trips" into "TaxiTrips".
+func pascalCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	result := b.String()
+	if result == "" || unicode.IsDigit(rune(result[0])) {
+		result = "X" + result
+	}
+	return result
+}
+
+func pythonIdentifier(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	result := b.String()
+	if result == "" || unicode.IsDigit(rune(result[0])) {
+		result = "_" + result
+	}
+	if pythonKeywords[result] {
+		result += "_"
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDataType(t *testing.T) {
+	assert.Equal(t, columnType{kind: kindInteger, bits: 32}, parseDataType("Int32"))
+	assert.Equal(t, columnType{kind: kindDecimal, precision: "38", scale: "10"}, parseDataType("Decimal128(38, 10)"))
+	assert.Equal(t, columnType{kind: kindTimestamp}, parseDataType(`Timestamp(Microsecond, Some("UTC"))`))
+	assert.Equal(t, columnType{kind: kindString}, parseDataType("Dictionary(Int32, Utf8)"))
+	assert.Equal(t, columnType{kind: kindList, item: &columnType{kind: kindFloat, bits: 64}},
+		parseDataType(`List(Field { name: "item", data_type: Float64, nullable: true, dict_id: 0, dict_is_ordered: false, metadata: {} })`))
+	assert.Equal(t, columnType{kind: kindUnknown}, parseDataType("Interval(MonthDayNano)"))
+}
+
+func TestGenerate(t *testing.T) {
+	columns := []api.Column{
+		{Name: "id", DataType: "Int64", IsNullable: "NO"},
+		{Name: "pickup time", DataType: "Timestamp(Nanosecond, None)", IsNullable: "YES"},
+	}
+
+	sql, err := Generate("taxi_trips", columns, FORMAT_SQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE taxi_trips (\n  id BIGINT NOT NULL,\n  \"pickup time\" TIMESTAMP\n);\n", sql)
+
+	python, err := Generate("taxi_trips", columns, FORMAT_PYTHON)
+	assert.NoError(t, err)
+	assert.Contains(t, python, "from datetime import datetime\nfrom typing import Optional\n")
+	assert.Contains(t, python, "class TaxiTrips:\n    id: int\n    pickup_time: Optional[datetime]\n")
+
+	_, err = Generate("taxi_trips", columns, "xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const historyDirName = "history"
+
+// HistoryPath returns the file in dotSpiceDir the history of a REPL is kept in, e.g.
+// ~/.spice/history/sql_history for "sql".
+func HistoryPath(dotSpiceDir string, name string) (string, error) {
+	historyDir := filepath.Join(dotSpiceDir, historyDirName)
+	if err := os.MkdirAll(historyDir, 0700); err != nil {
+		return "", err
+	}
+	return filepath.Join(historyDir, name+"_history"), nil
+}
+
+// LoadHistory reads the history saved at path, one entry per line, and saves every entry
+// added afterwards. A missing file starts an empty history.
+func LoadHistory(path string, limit int) (*History, error) {
+	h := NewHistory(limit)
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		h.path = path
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		h.Add(scanner.Text())
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	h.path = path
+	return h, nil
+}
+
+// save rewrites the history file, readable only by the user since entries may hold
+// credentials typed into a query.
+func (h *History) save() error {
+	return os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0600)
+}
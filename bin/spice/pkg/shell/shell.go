@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"strings"
+)
+
+const (
+	MODE_SQL    = "sql"
+	MODE_NSQL   = "nsql"
+	MODE_SEARCH = "search"
+	MODE_CHAT   = "chat"
+
+	// Input starting with SYSTEM_PREFIX runs in the operating system shell, with META_PREFIX
+	// it is a command to the shell itself, e.g. .connect.
+	SYSTEM_PREFIX = "!"
+	META_PREFIX   = "."
+)
+
+var Modes = []string{MODE_SQL, MODE_NSQL, MODE_SEARCH, MODE_CHAT}
+
+const (
+	KIND_QUERY  = "query"
+	KIND_SYSTEM = "system"
+	KIND_META   = "meta"
+)
+
+type Input struct {
+	Kind string
+	// The backend a KIND_QUERY input is routed to.
+	Mode string
+	// For KIND_META, the command without the leading dot.
+	Command string
+	Text    string
+}
+
+// Parse routes a line of input. Lines prefixed with a mode, e.g. "sql:", go to that backend,
+// unprefixed lines to mode. A prefix without text, e.g. "nsql:", switches mode and yields an
+// input with an empty Text.
+func Parse(line string, mode string) Input {
+	line = strings.TrimSpace(line)
+
+	if text, ok := strings.CutPrefix(line, SYSTEM_PREFIX); ok {
+		return Input{Kind: KIND_SYSTEM, Text: strings.TrimSpace(text)}
+	}
+	if text, ok := strings.CutPrefix(line, META_PREFIX); ok {
+		command, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+		return Input{Kind: KIND_META, Command: strings.ToLower(command), Text: strings.TrimSpace(args)}
+	}
+
+	if prefix, text, ok := strings.Cut(line, ":"); ok {
+		for _, m := range Modes {
+			if strings.EqualFold(strings.TrimSpace(prefix), m) {
+				mode = m
+				line = strings.TrimSpace(text)
+				break
+			}
+		}
+	}
+
+	if mode == MODE_SQL {
+		line = strings.TrimSpace(strings.TrimRight(line, ";"))
+	}
+	return Input{Kind: KIND_QUERY, Mode: mode, Text: line}
+}
+
+// History is the input of all modes in the order it was entered.
+type History struct {
+	entries []string
+	limit   int
+	// File the entries are saved to as they are added, see LoadHistory.
+	path string
+}
+
+func NewHistory(limit int) *History {
+	return &History{limit: limit}
+}
+
+// Add records a line, skipping blank lines and immediate repeats.
+func (h *History) Add(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == line) {
+		return
+	}
+	h.entries = append(h.entries, line)
+	if h.limit > 0 && len(h.entries) > h.limit {
+		h.entries = h.entries[len(h.entries)-h.limit:]
+	}
+	if h.path != "" {
+		// Failing to save only loses the history of later sessions, not worth interrupting the REPL.
+		_ = h.save()
+	}
+}
+
+func (h *History) Entries() []string {
+	return h.entries
+}
+
+// Get returns the n-th entry, counting from 1 as listed by .history.
+func (h *History) Get(n int) (string, bool) {
+	if n < 1 || n > len(h.entries) {
+		return "", false
+	}
+	return h.entries[n-1], true
+}
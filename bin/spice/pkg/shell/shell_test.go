@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert.Equal(t, Input{Kind: KIND_QUERY, Mode: MODE_SQL, Text: "SELECT 1"}, Parse("SELECT 1;", MODE_SQL))
+	assert.Equal(t, Input{Kind: KIND_QUERY, Mode: MODE_NSQL, Text: "how many trips?"}, Parse("how many trips?", MODE_NSQL))
+	assert.Equal(t, Input{Kind: KIND_QUERY, Mode: MODE_SQL, Text: "SELECT 'a:b'"}, Parse("sql: SELECT 'a:b'", MODE_NSQL))
+	assert.Equal(t, Input{Kind: KIND_QUERY, Mode: MODE_SEARCH, Text: "airport fares"}, Parse("SEARCH: airport fares", MODE_SQL))
+	assert.Equal(t, Input{Kind: KIND_QUERY, Mode: MODE_CHAT}, Parse("chat:", MODE_SQL))
+	assert.Equal(t, Input{Kind: KIND_QUERY, Mode: MODE_SQL, Text: "SELECT x::int FROM t"}, Parse("SELECT x::int FROM t", MODE_SQL))
+	assert.Equal(t, Input{Kind: KIND_SYSTEM, Text: "ls -l"}, Parse("! ls -l", MODE_SQL))
+	assert.Equal(t, Input{Kind: KIND_META, Command: "connect", Text: "http://localhost:8090"}, Parse(".Connect  http://localhost:8090 ", MODE_SQL))
+}
+
+func TestHistory(t *testing.T) {
+	history := NewHistory(3)
+	for _, line := range []string{"a", "b", "b", " ", "c", "d"} {
+		history.Add(line)
+	}
+	assert.Equal(t, []string{"b", "c", "d"}, history.Entries())
+
+	entry, ok := history.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "b", entry)
+	_, ok = history.Get(4)
+	assert.False(t, ok)
+}
+
+func TestLoadHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search_history")
+
+	history, err := LoadHistory(path, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, history.Entries())
+	history.Add("a")
+	history.Add("b")
+	history.Add("c")
+
+	history, err = LoadHistory(path, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, history.Entries())
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
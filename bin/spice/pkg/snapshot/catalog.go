@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+)
+
+const SNAPSHOT_ID_LAYOUT = "20060102T150405Z"
+
+type Entry struct {
+	ID       string
+	Location string
+	Manifest *Manifest
+}
+
+// DefaultLocation is where snapshots are kept when no location is given, in the app's
+// .spice directory.
+func DefaultLocation(appDir string) string {
+	return filepath.Join(appDir, ".spice", "snapshots")
+}
+
+// SnapshotLocation returns where a dataset's snapshot is stored below location, a local
+// directory or an s3:// URL. Snapshots are kept as <location>/<dataset>/<id>.
+func SnapshotLocation(location string, dataset string, id string) string {
+	if isS3(location) {
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(location, "/"), dataset, id)
+	}
+	return filepath.Join(location, dataset, id)
+}
+
+// Create backs up the dataset's acceleration file as a new timestamped snapshot below location.
+func Create(appDir string, definition *spicepod.DatasetDefinition, location string) (*Entry, error) {
+	id := time.Now().UTC().Format(SNAPSHOT_ID_LAYOUT)
+	destination := SnapshotLocation(location, definition.Name, id)
+	manifest, err := Backup(appDir, definition, destination)
+	if err != nil {
+		return nil, err
+	}
+	return &Entry{ID: id, Location: destination, Manifest: manifest}, nil
+}
+
+// List returns the dataset's snapshots below location, newest first.
+func List(location string, dataset string) ([]Entry, error) {
+	var ids []string
+	var err error
+	if isS3(location) {
+		ids, err = s3ListPrefixes(SnapshotLocation(location, dataset, ""))
+	} else {
+		ids, err = listDirs(filepath.Join(location, dataset))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	entries := make([]Entry, 0, len(ids))
+	for _, id := range ids {
+		entryLocation := SnapshotLocation(location, dataset, id)
+		var manifest *Manifest
+		if isS3(location) {
+			manifest, err = s3ReadManifest(entryLocation + "/" + ManifestFileName)
+		} else {
+			manifest, err = readManifest(filepath.Join(entryLocation, ManifestFileName))
+		}
+		if err != nil {
+			// A directory without a readable manifest is an interrupted or foreign upload
+			continue
+		}
+		entries = append(entries, Entry{ID: id, Location: entryLocation, Manifest: manifest})
+	}
+	return entries, nil
+}
+
+// Find returns the snapshot with the given id, or the newest one if id is empty.
+func Find(location string, dataset string, id string) (*Entry, error) {
+	entries, err := List(location, dataset)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no snapshots of dataset %s found in %s", dataset, location)
+	}
+	if id == "" {
+		return &entries[0], nil
+	}
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot %s of dataset %s not found in %s", id, dataset, location)
+}
+
+func listDirs(dir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range dirEntries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func s3ListPrefixes(location string) ([]string, error) {
+	if _, err := exec.LookPath("aws"); err != nil {
+		return nil, fmt.Errorf("the AWS CLI (aws) is required to list snapshots in S3")
+	}
+	output, err := exec.Command("aws", "s3", "ls", location).CombinedOutput()
+	if err != nil {
+		// aws s3 ls exits with 1 when the prefix does not exist
+		if len(strings.TrimSpace(string(output))) == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing %s: %s", location, strings.TrimSpace(string(output)))
+	}
+
+	var prefixes []string
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "PRE" {
+			prefixes = append(prefixes, strings.TrimSuffix(fields[1], "/"))
+		}
+	}
+	return prefixes, nil
+}
+
+func s3ReadManifest(location string) (*Manifest, error) {
+	output, err := exec.Command("aws", "s3", "cp", location, "-").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", location, err)
+	}
+	var manifest Manifest
+	if err = json.Unmarshal(output, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing backup manifest: %w", err)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateListRestore(t *testing.T) {
+	appDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(appDir, "spicepod.yaml"), []byte(`version: v1beta1
+kind: Spicepod
+name: app
+datasets:
+- from: file:orders.csv
+  name: orders
+  acceleration:
+    enabled: true
+    engine: sqlite
+    mode: file
+`), 0o600))
+	dataPath := filepath.Join(appDir, "orders_sqlite.db")
+	original := append([]byte("SQLite format 3\x00"), []byte("page data")...)
+	assert.NoError(t, os.WriteFile(dataPath, original, 0o600))
+
+	definition, err := spicepod.FindDatasetDefinition(appDir, "orders")
+	assert.NoError(t, err)
+
+	location := t.TempDir()
+	entries, err := List(location, "orders")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	created, err := Create(appDir, definition, location)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(location, "orders", created.ID), created.Location)
+
+	found, err := Find(location, "orders", "")
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+	assert.Equal(t, ENGINE_SQLITE, found.Manifest.Engine)
+
+	_, err = Find(location, "orders", "20000101T000000Z")
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(dataPath, []byte("changed"), 0o600))
+	_, err = Restore(appDir, definition, found.Location)
+	assert.NoError(t, err)
+	restored, err := os.ReadFile(dataPath)
+	assert.NoError(t, err)
+	assert.Equal(t, original, restored)
+}
+
+func TestCheckFileHeader(t *testing.T) {
+	duckdb := append(make([]byte, 8), []byte("DUCK\x00\x00\x00\x00")...)
+	assert.NoError(t, checkFileHeader(bytes.NewReader(duckdb), ENGINE_DUCKDB))
+	assert.Error(t, checkFileHeader(bytes.NewReader(duckdb), ENGINE_SQLITE))
+	assert.NoError(t, checkFileHeader(bytes.NewReader([]byte("SQLite format 3\x00")), ENGINE_SQLITE))
+	assert.Error(t, checkFileHeader(bytes.NewReader([]byte("short")), ENGINE_SQLITE))
+}
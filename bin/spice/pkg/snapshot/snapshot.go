@@ -0,0 +1,305 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spicepod"
+	"github.com/spiceai/spiceai/bin/spice/pkg/tempdir"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+)
+
+const (
+	ENGINE_DUCKDB = "duckdb"
+	ENGINE_SQLITE = "sqlite"
+
+	ManifestFileName = "manifest.json"
+)
+
+type Manifest struct {
+	Dataset    string         `json:"dataset"`
+	Engine     string         `json:"engine"`
+	CreatedAt  time.Time      `json:"created_at"`
+	CliVersion string         `json:"cli_version"`
+	Files      []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// AccelerationFile returns the engine and the path of the file holding a dataset's
+// accelerated data. Only file-mode DuckDB and SQLite accelerations are backed by a file.
+func AccelerationFile(appDir string, definition *spicepod.DatasetDefinition) (string, string, error) {
+	engine, _ := definition.Get("acceleration.engine").(string)
+	mode, _ := definition.Get("acceleration.mode").(string)
+	if enabled, ok := definition.Get("acceleration.enabled").(bool); ok && !enabled {
+		return "", "", fmt.Errorf("dataset %s is not accelerated", definition.Name)
+	}
+	if mode != "file" || (engine != ENGINE_DUCKDB && engine != ENGINE_SQLITE) {
+		return "", "", fmt.Errorf("dataset %s is not accelerated to a file; only duckdb and sqlite accelerations with mode: file can be backed up, use spice export for a data snapshot", definition.Name)
+	}
+
+	var path string
+	switch engine {
+	case ENGINE_DUCKDB:
+		path, _ = definition.Get("acceleration.params.duckdb_file").(string)
+		if path == "" {
+			path = fmt.Sprintf("%s.db", definition.Name)
+		}
+	case ENGINE_SQLITE:
+		path, _ = definition.Get("acceleration.params.sqlite_file").(string)
+		if path == "" {
+			path = fmt.Sprintf("%s_sqlite.db", definition.Name)
+		}
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(appDir, path)
+	}
+	return engine, path, nil
+}
+
+// Backup copies the dataset's acceleration file and a manifest with its checksum to
+// destination, a local directory or an s3:// URL.
+func Backup(appDir string, definition *spicepod.DatasetDefinition, destination string) (*Manifest, error) {
+	engine, path, err := AccelerationFile(appDir, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	stagingDir := destination
+	if isS3(destination) {
+		stagingDir, err = tempdir.CreateTempDir("backup")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	name := filepath.Base(path)
+	size, checksum, err := copyWithChecksum(path, filepath.Join(stagingDir, name))
+	if err != nil {
+		return nil, fmt.Errorf("error copying %s: %w", path, err)
+	}
+
+	manifest := &Manifest{
+		Dataset:    definition.Name,
+		Engine:     engine,
+		CreatedAt:  time.Now().UTC(),
+		CliVersion: version.Version(),
+		Files:      []ManifestFile{{Name: name, Size: size, Sha256: checksum}},
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(filepath.Join(stagingDir, ManifestFileName), manifestBytes, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if isS3(destination) {
+		err = s3Copy(stagingDir, destination)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// Restore verifies the backup at source against its manifest and copies the acceleration
+// file into place. The runtime must not have the file open.
+func Restore(appDir string, definition *spicepod.DatasetDefinition, source string) (*Manifest, error) {
+	engine, path, err := AccelerationFile(appDir, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, backupPath, err := verify(source, engine, definition.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy next to the target first so a failed copy never leaves a partial file in place
+	tmpPath := path + ".restore"
+	if _, _, err = copyWithChecksum(backupPath, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// Verify checks that the backup at source matches its manifest and holds a file the
+// dataset's acceleration engine can open, without changing the dataset's acceleration file.
+func Verify(appDir string, definition *spicepod.DatasetDefinition, source string) (*Manifest, error) {
+	engine, _, err := AccelerationFile(appDir, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, _, err := verify(source, engine, definition.Name)
+	return manifest, err
+}
+
+func verify(source string, engine string, dataset string) (*Manifest, string, error) {
+	sourceDir := source
+	if isS3(source) {
+		var err error
+		sourceDir, err = tempdir.CreateTempDir("restore")
+		if err != nil {
+			return nil, "", err
+		}
+		err = s3Copy(source, sourceDir)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	manifest, err := readManifest(filepath.Join(sourceDir, ManifestFileName))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if manifest.Engine != engine {
+		return nil, "", fmt.Errorf("backup is a %s snapshot but dataset %s is accelerated with %s", manifest.Engine, dataset, engine)
+	}
+	if len(manifest.Files) != 1 {
+		return nil, "", fmt.Errorf("backup manifest lists %d files, expected 1", len(manifest.Files))
+	}
+
+	file := manifest.Files[0]
+	backupPath := filepath.Join(sourceDir, filepath.Base(file.Name))
+	backupFile, err := os.Open(backupPath)
+	if err != nil {
+		return nil, "", err
+	}
+	defer backupFile.Close()
+
+	hash, err := util.ComputeHash(backupFile)
+	if err != nil {
+		return nil, "", err
+	}
+	if hex.EncodeToString(hash) != file.Sha256 {
+		return nil, "", fmt.Errorf("checksum mismatch for %s, the backup is corrupt", file.Name)
+	}
+
+	if _, err = backupFile.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+	if err = checkFileHeader(backupFile, engine); err != nil {
+		return nil, "", fmt.Errorf("%s cannot be loaded: %w", file.Name, err)
+	}
+
+	return manifest, backupPath, nil
+}
+
+func readManifest(path string) (*Manifest, error) {
+	manifestBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup manifest: %w", err)
+	}
+	var manifest Manifest
+	err = json.Unmarshal(manifestBytes, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing backup manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// checkFileHeader reads the magic bytes each engine writes at the start of its database
+// file, catching snapshots of the wrong engine or of a file that was never initialized.
+func checkFileHeader(r io.Reader, engine string) error {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("file is too short to be a %s database", engine)
+	}
+
+	switch engine {
+	case ENGINE_SQLITE:
+		if string(header) != "SQLite format 3\x00" {
+			return fmt.Errorf("not a sqlite database")
+		}
+	case ENGINE_DUCKDB:
+		if string(header[8:12]) != "DUCK" {
+			return fmt.Errorf("not a duckdb database")
+		}
+	}
+	return nil
+}
+
+func copyWithChecksum(src string, dst string) (int64, string, error) {
+	source, err := os.Open(src)
+	if err != nil {
+		return 0, "", err
+	}
+	defer source.Close()
+
+	if _, err = util.MkDirAllInheritPerm(filepath.Dir(dst)); err != nil {
+		return 0, "", err
+	}
+	target, err := os.Create(dst)
+	if err != nil {
+		return 0, "", err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(target, hash), source)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func isS3(location string) bool {
+	return strings.HasPrefix(location, "s3://")
+}
+
+// s3Copy transfers a directory to or from S3 with the AWS CLI, which handles credentials
+// the same way as other AWS tooling on the machine.
+func s3Copy(from string, to string) error {
+	if _, err := exec.LookPath("aws"); err != nil {
+		return fmt.Errorf("the AWS CLI (aws) is required to copy backups to and from S3")
+	}
+	output, err := exec.Command("aws", "s3", "cp", "--recursive", from, to).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error copying %s to %s: %s", from, to, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	COMPONENT_DATASET    = "dataset"
+	COMPONENT_VIEW       = "view"
+	COMPONENT_MODEL      = "model"
+	COMPONENT_LLM        = "llm"
+	COMPONENT_EMBEDDINGS = "embeddings"
+)
+
+// Component is a dataset, view, model, LLM or embeddings model contributed by a Spicepod.
+type Component struct {
+	Kind   string
+	Name   string
+	From   string
+	Detail string
+}
+
+// Component lists in spicepod.yaml, with the base name of the YAML file a reference points to.
+var componentLists = []struct {
+	key      string
+	kind     string
+	basename string
+}{
+	{"datasets", COMPONENT_DATASET, "dataset"},
+	{"models", COMPONENT_MODEL, "model"},
+	{"llms", COMPONENT_LLM, "llms"},
+	{"embeddings", COMPONENT_EMBEDDINGS, "embeddings"},
+}
+
+// ListComponents returns the components defined by the Spicepod in spicepodDir, resolving
+// references the same way as the runtime.
+func ListComponents(spicepodDir string) ([]Component, error) {
+	manifest, err := readYamlMap(filepath.Join(spicepodDir, "spicepod.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var components []Component
+	for _, list := range componentLists {
+		items, _ := manifest[list.key].([]interface{})
+		for _, item := range items {
+			definition, ok := item.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+
+			if ref, ok := definition["ref"].(string); ok {
+				definition, err = readComponentReference(filepath.Join(spicepodDir, ref), list.basename)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			components = append(components, newComponent(list.kind, definition))
+		}
+	}
+
+	return components, nil
+}
+
+// FindPods returns the directories below podsDir that contain a spicepod.yaml.
+func FindPods(podsDir string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(podsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() && d.Name() == "spicepod.yaml" {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	sort.Strings(dirs)
+	return dirs, err
+}
+
+func newComponent(kind string, definition map[interface{}]interface{}) Component {
+	component := Component{Kind: kind}
+	component.Name, _ = definition["name"].(string)
+	component.From, _ = definition["from"].(string)
+
+	if kind == COMPONENT_DATASET {
+		if definition["sql"] != nil || definition["sql_ref"] != nil {
+			component.Kind = COMPONENT_VIEW
+		}
+		if acceleration, ok := definition["acceleration"].(map[interface{}]interface{}); ok && acceleration["enabled"] == true {
+			engine, _ := acceleration["engine"].(string)
+			if engine == "" {
+				engine = "arrow"
+			}
+			component.Detail = fmt.Sprintf("accelerated (%s)", engine)
+		}
+	}
+
+	return component
+}
+
+func readComponentReference(refPath string, basename string) (map[interface{}]interface{}, error) {
+	path, err := componentReferencePath(refPath, basename)
+	if err != nil {
+		return nil, err
+	}
+	return readYamlMap(path)
+}
+
+// componentReferencePath returns the YAML file a reference points to: the file itself, or the
+// <basename>.yaml in a referenced directory.
+func componentReferencePath(refPath string, basename string) (string, error) {
+	if stat, err := os.Stat(refPath); err == nil && !stat.IsDir() {
+		return refPath, nil
+	}
+	for _, extension := range []string{".yaml", ".yml"} {
+		path := filepath.Join(refPath, basename+extension)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("component reference '%s' does not contain a %s.yaml", refPath, basename)
+}
+
+func readYamlMap(path string) (map[interface{}]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[interface{}]interface{}{}
+	if err = yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("%s is not valid: %w", strings.TrimPrefix(path, "./"), err)
+	}
+	return values, nil
+}
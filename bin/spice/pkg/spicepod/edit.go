@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DatasetDefinition locates the YAML document defining a dataset, either inline in the
+// spicepod manifest or in a file referenced from it. Values preserves key order so
+// edits can be written back without reformatting the whole document.
+type DatasetDefinition struct {
+	FilePath string
+	Name     string
+	document yaml.MapSlice
+	values   yaml.MapSlice
+	// Index of the dataset within the manifest's datasets list, or -1 for referenced files.
+	index int
+}
+
+func FindDatasetDefinition(spicepodDir string, datasetName string) (*DatasetDefinition, error) {
+	definitions, err := ListDatasetDefinitions(spicepodDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, definition := range definitions {
+		if strings.EqualFold(definition.Name, datasetName) {
+			return definition, nil
+		}
+	}
+
+	return nil, fmt.Errorf("dataset '%s' is not defined in %s", datasetName, filepath.Join(spicepodDir, "spicepod.yaml"))
+}
+
+// ListDatasetDefinitions returns the definitions of the datasets in the manifest, in order.
+// Referenced files that cannot be read are skipped.
+func ListDatasetDefinitions(spicepodDir string) ([]*DatasetDefinition, error) {
+	manifestPath := filepath.Join(spicepodDir, "spicepod.yaml")
+	manifest, err := readMapSlice(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []*DatasetDefinition
+	datasets, _ := getValue(manifest, "datasets").([]interface{})
+	for i, item := range datasets {
+		dataset, ok := item.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+
+		if name, ok := getValue(dataset, "name").(string); ok {
+			definitions = append(definitions, &DatasetDefinition{FilePath: manifestPath, Name: name, document: manifest, values: dataset, index: i})
+			continue
+		}
+
+		ref, ok := getValue(dataset, "ref").(string)
+		if !ok {
+			continue
+		}
+		refPath := filepath.Join(spicepodDir, ref)
+		if stat, err := os.Stat(refPath); err == nil && stat.IsDir() {
+			refPath = filepath.Join(refPath, "dataset.yaml")
+		}
+		refDataset, err := readMapSlice(refPath)
+		if err != nil {
+			continue
+		}
+		if name, ok := getValue(refDataset, "name").(string); ok {
+			definitions = append(definitions, &DatasetDefinition{FilePath: refPath, Name: name, document: refDataset, values: refDataset, index: -1})
+		}
+	}
+
+	return definitions, nil
+}
+
+// Get returns the value at a dotted path, e.g. "acceleration.engine".
+func (d *DatasetDefinition) Get(path string) interface{} {
+	return getPath(d.values, strings.Split(path, "."))
+}
+
+// Set assigns the value at a dotted path, creating intermediate maps as needed.
+func (d *DatasetDefinition) Set(path string, value interface{}) {
+	d.values = setPath(d.values, strings.Split(path, "."), value)
+}
+
+func (d *DatasetDefinition) Save() error {
+	if d.index >= 0 {
+		datasets, _ := getValue(d.document, "datasets").([]interface{})
+		datasets[d.index] = d.values
+		d.document = setValue(d.document, "datasets", datasets)
+	} else {
+		d.document = d.values
+	}
+
+	return writeMapSlice(d.FilePath, d.document)
+}
+
+// AddDatasetReference appends a `ref` entry to the manifest's datasets unless one for ref
+// already exists, leaving the rest of the manifest untouched.
+func AddDatasetReference(spicepodDir string, ref string) error {
+	manifestPath := filepath.Join(spicepodDir, "spicepod.yaml")
+	manifest, err := readMapSlice(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	datasets, _ := getValue(manifest, "datasets").([]interface{})
+	for _, item := range datasets {
+		if dataset, ok := item.(yaml.MapSlice); ok && getValue(dataset, "ref") == ref {
+			return nil
+		}
+	}
+	datasets = append(datasets, yaml.MapSlice{{Key: "ref", Value: ref}})
+	manifest = setValue(manifest, "datasets", datasets)
+
+	return writeMapSlice(manifestPath, manifest)
+}
+
+// ManifestValue returns the value at a dotted path in the spicepod manifest, e.g.
+// "runtime.results_cache.item_ttl", or nil when it is not set.
+func ManifestValue(spicepodDir string, path string) (interface{}, error) {
+	manifest, err := readMapSlice(filepath.Join(spicepodDir, "spicepod.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	return getPath(manifest, strings.Split(path, ".")), nil
+}
+
+// SetManifestValues assigns each value at its dotted path in the spicepod manifest,
+// creating intermediate maps as needed and leaving the rest of the manifest untouched.
+func SetManifestValues(spicepodDir string, values yaml.MapSlice) error {
+	manifestPath := filepath.Join(spicepodDir, "spicepod.yaml")
+	manifest, err := readMapSlice(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range values {
+		manifest = setPath(manifest, strings.Split(item.Key.(string), "."), item.Value)
+	}
+
+	return writeMapSlice(manifestPath, manifest)
+}
+
+func readMapSlice(path string) (yaml.MapSlice, error) {
+	contentBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.MapSlice
+	err = yaml.Unmarshal(contentBytes, &document)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+
+	return document, nil
+}
+
+func writeMapSlice(path string, document yaml.MapSlice) error {
+	documentBytes, err := yaml.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("error marshalling %s: %w", path, err)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, documentBytes, stat.Mode())
+}
+
+func getValue(m yaml.MapSlice, key string) interface{} {
+	for _, item := range m {
+		if k, ok := item.Key.(string); ok && k == key {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+func getPath(m yaml.MapSlice, keys []string) interface{} {
+	var current interface{} = m
+	for _, key := range keys {
+		currentMap, ok := current.(yaml.MapSlice)
+		if !ok {
+			return nil
+		}
+		current = getValue(currentMap, key)
+	}
+	return current
+}
+
+func setValue(m yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for i, item := range m {
+		if k, ok := item.Key.(string); ok && k == key {
+			m[i].Value = value
+			return m
+		}
+	}
+	return append(m, yaml.MapItem{Key: key, Value: value})
+}
+
+func setPath(m yaml.MapSlice, keys []string, value interface{}) yaml.MapSlice {
+	if len(keys) == 1 {
+		return setValue(m, keys[0], value)
+	}
+
+	child, _ := getValue(m, keys[0]).(yaml.MapSlice)
+	return setValue(m, keys[0], setPath(child, keys[1:], value))
+}
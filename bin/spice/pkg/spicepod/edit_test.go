@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSetManifestValues(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "spicepod.yaml")
+	assert.NoError(t, os.WriteFile(manifestPath, []byte(`version: v1beta1
+kind: Spicepod
+name: app
+runtime:
+  results_cache:
+    item_ttl: 1s
+datasets:
+- from: file:data.csv
+  name: data
+`), 0o600))
+
+	err := SetManifestValues(dir, yaml.MapSlice{
+		{Key: "runtime.results_cache.item_ttl", Value: "30s"},
+		{Key: "runtime.results_cache.cache_max_size", Value: "1GiB"},
+	})
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(manifestPath)
+	assert.NoError(t, err)
+	assert.Equal(t, `version: v1beta1
+kind: Spicepod
+name: app
+runtime:
+  results_cache:
+    item_ttl: 30s
+    cache_max_size: 1GiB
+datasets:
+- from: file:data.csv
+  name: data
+`, string(content))
+
+	stat, err := os.Stat(manifestPath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), stat.Mode())
+
+	value, err := ManifestValue(dir, "runtime.results_cache.cache_max_size")
+	assert.NoError(t, err)
+	assert.Equal(t, "1GiB", value)
+
+	value, err = ManifestValue(dir, "runtime.results_cache.eviction_policy")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
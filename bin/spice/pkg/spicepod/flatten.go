@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"gopkg.in/yaml.v2"
+)
+
+// Flatten returns the spicepod.yaml in spicepodDir with every component reference and sql_ref
+// replaced by the definition it points to, so the manifest can be deployed without the
+// files next to it, e.g. as a Kubernetes ConfigMap.
+func Flatten(spicepodDir string) (yaml.MapSlice, error) {
+	manifest, err := readMapSlice(filepath.Join(spicepodDir, "spicepod.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, list := range componentLists {
+		items, ok := getValue(manifest, list.key).([]interface{})
+		if !ok {
+			continue
+		}
+
+		for i, item := range items {
+			definition, ok := item.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+
+			definitionDir := spicepodDir
+			if ref, ok := getValue(definition, "ref").(string); ok {
+				refPath, err := componentReferencePath(filepath.Join(spicepodDir, ref), list.basename)
+				if err != nil {
+					return nil, err
+				}
+				definition, err = readMapSlice(refPath)
+				if err != nil {
+					return nil, err
+				}
+				definitionDir = filepath.Dir(refPath)
+			}
+
+			if sqlRef, ok := getValue(definition, "sql_ref").(string); ok {
+				definition, err = inlineSqlRef(definition, filepath.Join(definitionDir, sqlRef))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			items[i] = definition
+		}
+	}
+
+	return manifest, nil
+}
+
+// LoadDatasets returns every dataset defined in the spicepod, whether inline or referenced.
+func LoadDatasets(spicepodDir string) ([]*spec.DatasetSpec, error) {
+	manifest, err := Flatten(spicepodDir)
+	if err != nil {
+		return nil, err
+	}
+
+	datasetsBytes, err := yaml.Marshal(getValue(manifest, "datasets"))
+	if err != nil {
+		return nil, err
+	}
+
+	var datasets []*spec.DatasetSpec
+	err = yaml.Unmarshal(datasetsBytes, &datasets)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing datasets: %w", err)
+	}
+	return datasets, nil
+}
+
+func inlineSqlRef(definition yaml.MapSlice, sqlPath string) (yaml.MapSlice, error) {
+	sql, err := os.ReadFile(sqlPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading sql_ref: %w", err)
+	}
+
+	inlined := make(yaml.MapSlice, 0, len(definition))
+	for _, item := range definition {
+		if key, ok := item.Key.(string); ok && key == "sql_ref" {
+			inlined = append(inlined, yaml.MapItem{Key: "sql", Value: string(sql)})
+			continue
+		}
+		inlined = append(inlined, item)
+	}
+	return inlined, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestFlatten(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(path string, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0o644))
+	}
+	writeFile("spicepod.yaml", `version: v1beta1
+kind: Spicepod
+name: app
+datasets:
+- ref: datasets/taxi_trips
+- from: file:data.csv
+  name: data
+models:
+- ref: models/nql.yaml
+`)
+	writeFile("datasets/taxi_trips/dataset.yml", `from: s3://bucket/taxi_trips/
+name: taxi_trips
+`)
+	writeFile("models/nql.yaml", `from: openai:gpt-4o
+name: nql
+`)
+	writeFile("datasets/trips_view/dataset.yaml", `name: trips_view
+sql_ref: view.sql
+`)
+
+	manifest, err := Flatten(dir)
+	assert.NoError(t, err)
+	out, err := yaml.Marshal(manifest)
+	assert.NoError(t, err)
+	assert.Equal(t, `version: v1beta1
+kind: Spicepod
+name: app
+datasets:
+- from: s3://bucket/taxi_trips/
+  name: taxi_trips
+- from: file:data.csv
+  name: data
+models:
+- from: openai:gpt-4o
+  name: nql
+`, string(out))
+
+	writeFile("spicepod.yaml", `name: app
+datasets:
+- ref: datasets/trips_view
+`)
+	writeFile("datasets/trips_view/view.sql", "SELECT * FROM taxi_trips")
+	manifest, err = Flatten(dir)
+	assert.NoError(t, err)
+	out, err = yaml.Marshal(manifest)
+	assert.NoError(t, err)
+	assert.Equal(t, `name: app
+datasets:
+- name: trips_view
+  sql: SELECT * FROM taxi_trips
+`, string(out))
+
+	writeFile("spicepod.yaml", `name: app
+datasets:
+- ref: datasets/missing
+`)
+	_, err = Flatten(dir)
+	assert.Error(t, err)
+}
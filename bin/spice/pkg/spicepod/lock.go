@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	LockFileName    = "spicepod.lock"
+	lockFileVersion = 1
+)
+
+const (
+	SOURCE_SPICERACK = "spicerack"
+	SOURCE_OCI       = "oci"
+)
+
+// LockFile records exactly which version of each dependency was installed, so installs are
+// reproducible even when a registry tag is moved.
+type LockFile struct {
+	Version      int                `yaml:"version"`
+	Dependencies []LockedDependency `yaml:"dependencies"`
+}
+
+type LockedDependency struct {
+	// Dependency as written in spicepod.yaml
+	Name    string `yaml:"name"`
+	Source  string `yaml:"source"`
+	Version string `yaml:"version,omitempty"`
+	Digest  string `yaml:"digest,omitempty"`
+}
+
+// LoadLockFile reads the lockfile in spicepodDir, returning an empty lockfile if there is none.
+func LoadLockFile(spicepodDir string) (*LockFile, error) {
+	lock := &LockFile{Version: lockFileVersion}
+
+	lockBytes, err := os.ReadFile(filepath.Join(spicepodDir, LockFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return lock, nil
+		}
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(lockBytes, lock); err != nil {
+		return nil, fmt.Errorf("%s is not valid: %w", LockFileName, err)
+	}
+	return lock, nil
+}
+
+func (l *LockFile) Get(name string) *LockedDependency {
+	for i := range l.Dependencies {
+		if l.Dependencies[i].Name == name {
+			return &l.Dependencies[i]
+		}
+	}
+	return nil
+}
+
+// Set adds or replaces the locked version of a dependency.
+func (l *LockFile) Set(dependency LockedDependency) {
+	if existing := l.Get(dependency.Name); existing != nil {
+		*existing = dependency
+		return
+	}
+	l.Dependencies = append(l.Dependencies, dependency)
+	sort.Slice(l.Dependencies, func(i, j int) bool { return l.Dependencies[i].Name < l.Dependencies[j].Name })
+}
+
+func (l *LockFile) Save(spicepodDir string) error {
+	lockBytes, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	content := append([]byte("# Generated by the Spice CLI, do not edit.\n"), lockBytes...)
+	return os.WriteFile(filepath.Join(spicepodDir, LockFileName), content, 0644)
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
+	"github.com/spiceai/spiceai/bin/spice/pkg/spec"
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v2"
+)
+
+const PackageManifestFileName = "manifest.json"
+
+// Paths never included in a package: local state, secrets and downloaded dependencies.
+var packageExcludes = []string{".git", ".spice", ".env", constants.SpicePodsDirectoryName}
+
+// Local acceleration files are rebuilt by the runtime and never packaged.
+var packageExcludeExtensions = []string{".db", ".db.wal"}
+
+// PackageManifest is written at the root of a package and lists the checksum of every file.
+type PackageManifest struct {
+	Name       string        `json:"name"`
+	Version    string        `json:"version"`
+	CreatedAt  time.Time     `json:"created_at"`
+	CliVersion string        `json:"cli_version"`
+	Files      []PackageFile `json:"files"`
+}
+
+type PackageFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// LoadManifest reads the spicepod.yaml in spicepodDir.
+func LoadManifest(spicepodDir string) (*spec.SpicepodSpec, error) {
+	manifestBytes, err := os.ReadFile(filepath.Join(spicepodDir, "spicepod.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var pod spec.SpicepodSpec
+	if err = yaml.Unmarshal(manifestBytes, &pod); err != nil {
+		return nil, fmt.Errorf("spicepod.yaml is not valid: %w", err)
+	}
+	return &pod, nil
+}
+
+// Validate checks that a Spicepod can be published: it has a name, a semantic version and
+// every component it references exists.
+func Validate(spicepodDir string, pod *spec.SpicepodSpec, podVersion string) []error {
+	var errs []error
+	if pod.Name == "" {
+		errs = append(errs, fmt.Errorf("spicepod.yaml must set a name"))
+	}
+	if pod.Kind != "" && !strings.EqualFold(pod.Kind, "Spicepod") {
+		errs = append(errs, fmt.Errorf("spicepod.yaml has kind '%s', expected 'Spicepod'", pod.Kind))
+	}
+	if !semver.IsValid(podVersion) {
+		errs = append(errs, fmt.Errorf("version '%s' is not a semantic version like v1.2.0", podVersion))
+	}
+
+	for kind, references := range map[string][]*spec.Reference{"dataset": pod.Datasets, "function": pod.Functions, "model": pod.Models} {
+		for _, reference := range references {
+			if reference == nil || reference.Ref == "" {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(spicepodDir, reference.Ref)); err != nil {
+				errs = append(errs, fmt.Errorf("%s reference '%s' does not exist", kind, reference.Ref))
+			}
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+// Package writes the Spicepod in spicepodDir as a gzipped tarball at dest, with a manifest of
+// file checksums, and returns the manifest.
+func Package(spicepodDir string, name string, podVersion string, dest string) (*PackageManifest, error) {
+	manifest := &PackageManifest{
+		Name:       name,
+		Version:    podVersion,
+		CreatedAt:  time.Now().UTC(),
+		CliVersion: version.Version(),
+	}
+
+	destPath := absolutePath(dest)
+	var paths []string
+	err := filepath.WalkDir(spicepodDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(spicepodDir, path)
+		if err != nil {
+			return err
+		}
+		for _, exclude := range packageExcludes {
+			if relativePath == exclude {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		// Skip the archive itself when it is written inside the Spicepod
+		if absolutePath(path) == destPath {
+			return nil
+		}
+		for _, extension := range packageExcludeExtensions {
+			if strings.HasSuffix(relativePath, extension) {
+				return nil
+			}
+		}
+		if d.Type().IsRegular() {
+			paths = append(paths, relativePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := os.Create(dest)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	gzipWriter := gzip.NewWriter(archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, path := range paths {
+		file, err := addFileToTar(tarWriter, filepath.Join(spicepodDir, path), filepath.ToSlash(path))
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, *file)
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:    PackageManifestFileName,
+		Mode:    0644,
+		Size:    int64(len(manifestBytes)),
+		ModTime: manifest.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tarWriter.Write(manifestBytes); err != nil {
+		return nil, err
+	}
+
+	if err = tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err = gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// FileSha256 returns the hex encoded SHA-256 checksum of a file.
+func FileSha256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func addFileToTar(tarWriter *tar.Writer, path string, name string) (*PackageFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := tar.FileInfoHeader(stat, "")
+	if err != nil {
+		return nil, err
+	}
+	header.Name = name
+	if err = tarWriter.WriteHeader(header); err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tarWriter, hash), file); err != nil {
+		return nil, err
+	}
+
+	return &PackageFile{Path: name, Size: stat.Size(), Sha256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+func absolutePath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"fmt"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Partition is the share of a Spicepod's datasets served by one node of a cluster.
+type Partition struct {
+	Name     string
+	Datasets []string
+	// Spicepod for the node: the original manifest with only the partition's datasets.
+	Spicepod yaml.MapSlice
+	// Set when related datasets alone exceed the datasets per node.
+	Oversized bool
+}
+
+// PartitionDatasets splits the datasets of a flattened Spicepod into partitions of at most
+// datasetsPerNode datasets, keeping their order. Datasets that depend on each other, through
+// dependsOn or a view's SQL, stay in the same partition.
+func PartitionDatasets(manifest yaml.MapSlice, datasetsPerNode int) ([]Partition, error) {
+	if datasetsPerNode < 1 {
+		return nil, fmt.Errorf("datasets per node must be at least 1")
+	}
+
+	name, _ := getValue(manifest, "name").(string)
+	if name == "" {
+		name = "spicepod"
+	}
+
+	items, _ := getValue(manifest, "datasets").([]interface{})
+	datasets := make([]yaml.MapSlice, 0, len(items))
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		definition, ok := item.(yaml.MapSlice)
+		if !ok {
+			return nil, fmt.Errorf("invalid dataset definition: %v", item)
+		}
+		datasetName, _ := getValue(definition, "name").(string)
+		if datasetName == "" {
+			return nil, fmt.Errorf("dataset without a name: %v", definition)
+		}
+		datasets = append(datasets, definition)
+		names = append(names, datasetName)
+	}
+
+	groups := groupRelatedDatasets(datasets, names)
+
+	var partitions []Partition
+	var current []int
+	flush := func(oversized bool) {
+		if len(current) == 0 {
+			return
+		}
+		partition := Partition{
+			Name:      fmt.Sprintf("%s-node-%d", name, len(partitions)+1),
+			Oversized: oversized,
+		}
+		partitionItems := make([]interface{}, len(current))
+		for i, index := range current {
+			partition.Datasets = append(partition.Datasets, names[index])
+			partitionItems[i] = datasets[index]
+		}
+		partition.Spicepod = nodeSpicepod(manifest, partition.Name, partitionItems)
+		partitions = append(partitions, partition)
+		current = nil
+	}
+
+	for _, group := range groups {
+		if len(current)+len(group) > datasetsPerNode {
+			flush(false)
+		}
+		current = append(current, group...)
+		if len(current) > datasetsPerNode {
+			flush(true)
+		}
+	}
+	flush(false)
+
+	return partitions, nil
+}
+
+// groupRelatedDatasets returns the indexes of related datasets grouped together, ordered by
+// their first dataset.
+func groupRelatedDatasets(datasets []yaml.MapSlice, names []string) [][]int {
+	parents := make([]int, len(datasets))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	indexes := make(map[string]int, len(names))
+	for i, name := range names {
+		indexes[name] = i
+	}
+
+	for i, dataset := range datasets {
+		for _, dependency := range datasetDependencies(dataset, names) {
+			if j, ok := indexes[dependency]; ok {
+				parents[find(i)] = find(j)
+			}
+		}
+	}
+
+	var groups [][]int
+	groupIndexes := map[int]int{}
+	for i := range datasets {
+		root := find(i)
+		group, ok := groupIndexes[root]
+		if !ok {
+			group = len(groups)
+			groupIndexes[root] = group
+			groups = append(groups, nil)
+		}
+		groups[group] = append(groups[group], i)
+	}
+	return groups
+}
+
+// datasetDependencies returns the datasets named in dependsOn, or referenced by a view's SQL.
+func datasetDependencies(dataset yaml.MapSlice, names []string) []string {
+	var dependencies []string
+	switch dependsOn := getValue(dataset, "dependsOn").(type) {
+	case string:
+		dependencies = append(dependencies, dependsOn)
+	case []interface{}:
+		for _, dependency := range dependsOn {
+			if name, ok := dependency.(string); ok {
+				dependencies = append(dependencies, name)
+			}
+		}
+	}
+
+	if sql, ok := getValue(dataset, "sql").(string); ok {
+		for _, name := range names {
+			if regexp.MustCompile(fmt.Sprintf(`(?i)\b%s\b`, regexp.QuoteMeta(name))).MatchString(sql) {
+				dependencies = append(dependencies, name)
+			}
+		}
+	}
+	return dependencies
+}
+
+// nodeSpicepod copies the manifest with a node's name and datasets. Other components, such as
+// models, are kept on every node.
+func nodeSpicepod(manifest yaml.MapSlice, name string, datasets []interface{}) yaml.MapSlice {
+	spicepod := make(yaml.MapSlice, 0, len(manifest))
+	for _, item := range manifest {
+		switch item.Key {
+		case "name":
+			spicepod = append(spicepod, yaml.MapItem{Key: "name", Value: name})
+		case "datasets":
+			spicepod = append(spicepod, yaml.MapItem{Key: "datasets", Value: datasets})
+		default:
+			spicepod = append(spicepod, item)
+		}
+	}
+	return spicepod
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPartitionDatasets(t *testing.T) {
+	var manifest yaml.MapSlice
+	assert.NoError(t, yaml.Unmarshal([]byte(`version: v1beta1
+kind: Spicepod
+name: big
+models:
+- from: openai:gpt-4o
+  name: nql
+datasets:
+- from: s3://bucket/orders/
+  name: orders
+- from: s3://bucket/customers/
+  name: customers
+- from: s3://bucket/trips/
+  name: trips
+- name: orders_by_customer
+  sql: SELECT * FROM orders JOIN customers USING (customer_id)
+- from: s3://bucket/zones/
+  name: zones
+  dependsOn: trips
+`), &manifest))
+
+	partitions, err := PartitionDatasets(manifest, 2)
+	assert.NoError(t, err)
+	assert.Len(t, partitions, 2)
+
+	assert.Equal(t, "big-node-1", partitions[0].Name)
+	assert.Equal(t, []string{"orders", "customers", "orders_by_customer"}, partitions[0].Datasets)
+	assert.True(t, partitions[0].Oversized)
+	assert.Equal(t, []string{"trips", "zones"}, partitions[1].Datasets)
+	assert.False(t, partitions[1].Oversized)
+
+	out, err := yaml.Marshal(partitions[1].Spicepod)
+	assert.NoError(t, err)
+	assert.Equal(t, `version: v1beta1
+kind: Spicepod
+name: big-node-2
+models:
+- from: openai:gpt-4o
+  name: nql
+datasets:
+- from: s3://bucket/trips/
+  name: trips
+- from: s3://bucket/zones/
+  name: zones
+  dependsOn: trips
+`, string(out))
+
+	partitions, err = PartitionDatasets(manifest, 50)
+	assert.NoError(t, err)
+	assert.Len(t, partitions, 1)
+	assert.Len(t, partitions[0].Datasets, 5)
+
+	_, err = PartitionDatasets(manifest, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+)
+
+// Placeholders are written as {{ name }} or {{ name | default }} in a Spicepod's YAML files.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*(?:\|\s*([^}]*?)\s*)?\}\}`)
+
+// Files never copied from a template, in addition to those excluded from packages.
+var templateExcludes = append([]string{PackageManifestFileName, LockFileName}, packageExcludes...)
+
+type Placeholder struct {
+	Name    string
+	Default string
+}
+
+// FindPlaceholders returns the placeholders in a Spicepod's YAML files, in order of first use
+// starting with spicepod.yaml.
+func FindPlaceholders(spicepodDir string) ([]Placeholder, error) {
+	var templates []string
+	err := walkTemplate(spicepodDir, func(path string, rel string, isTemplate bool) error {
+		switch {
+		case rel == "spicepod.yaml":
+			templates = append([]string{path}, templates...)
+		case isTemplate:
+			templates = append(templates, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var placeholders []Placeholder
+	seen := map[string]int{}
+	for _, path := range templates {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range placeholderPattern.FindAllStringSubmatch(string(content), -1) {
+			placeholder := newPlaceholder(match)
+			if i, ok := seen[placeholder.Name]; ok {
+				if placeholders[i].Default == "" {
+					placeholders[i].Default = placeholder.Default
+				}
+				continue
+			}
+			seen[placeholder.Name] = len(placeholders)
+			placeholders = append(placeholders, placeholder)
+		}
+	}
+	return placeholders, nil
+}
+
+// RenderTemplate copies a Spicepod to destDir, substituting placeholders in its YAML files with
+// values. It returns the files written, relative to destDir.
+func RenderTemplate(spicepodDir string, destDir string, values map[string]string) ([]string, error) {
+	var written []string
+	err := walkTemplate(spicepodDir, func(path string, rel string, isTemplate bool) error {
+		dest := filepath.Join(destDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+
+		if !isTemplate {
+			written = append(written, rel)
+			return util.CopyFile(path, dest)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rendered, err := RenderPlaceholders(string(content), values)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		written = append(written, rel)
+		return os.WriteFile(dest, []byte(rendered), 0644)
+	})
+	return written, err
+}
+
+// RenderPlaceholders substitutes placeholders in content, falling back to their defaults.
+func RenderPlaceholders(content string, values map[string]string) (string, error) {
+	var missing []string
+	rendered := placeholderPattern.ReplaceAllStringFunc(content, func(text string) string {
+		placeholder := newPlaceholder(placeholderPattern.FindStringSubmatch(text))
+		if value, ok := values[placeholder.Name]; ok {
+			return value
+		}
+		if placeholder.Default != "" {
+			return placeholder.Default
+		}
+		missing = append(missing, placeholder.Name)
+		return text
+	})
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("no value for placeholders: %s", strings.Join(missing, ", "))
+	}
+	return rendered, nil
+}
+
+func newPlaceholder(match []string) Placeholder {
+	defaultValue := match[2]
+	if len(defaultValue) >= 2 && (defaultValue[0] == '"' || defaultValue[0] == '\'') && defaultValue[len(defaultValue)-1] == defaultValue[0] {
+		defaultValue = defaultValue[1 : len(defaultValue)-1]
+	}
+	return Placeholder{Name: match[1], Default: defaultValue}
+}
+
+// walkTemplate visits the files of a Spicepod that are copied when rendering it, skipping the
+// same local state and dependencies that are excluded from packages.
+func walkTemplate(spicepodDir string, visit func(path string, rel string, isTemplate bool) error) error {
+	return filepath.WalkDir(spicepodDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(spicepodDir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		for _, exclude := range templateExcludes {
+			if rel == exclude {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if d.IsDir() {
+			return nil
+		}
+		for _, ext := range packageExcludeExtensions {
+			if strings.HasSuffix(rel, ext) {
+				return nil
+			}
+		}
+
+		ext := strings.ToLower(filepath.Ext(rel))
+		return visit(path, rel, ext == ".yaml" || ext == ".yml")
+	})
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spicepod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPlaceholders(t *testing.T) {
+	template := `from: s3://{{ bucket | "my-bucket" }}/{{org}}/
+params:
+  region: {{ region | us-east-1 }}`
+
+	rendered, err := RenderPlaceholders(template, map[string]string{"org": "acme", "region": "eu-west-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, `from: s3://my-bucket/acme/
+params:
+  region: eu-west-1`, rendered)
+
+	_, err = RenderPlaceholders(template, map[string]string{})
+	assert.EqualError(t, err, "no value for placeholders: org")
+
+	assert.Equal(t, Placeholder{Name: "bucket", Default: "my-bucket"}, newPlaceholder(placeholderPattern.FindStringSubmatch(`{{ bucket | "my-bucket" }}`)))
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/version"
+)
+
+const (
+	// Recorded events are sent here. Nothing is recorded when it is not set.
+	ENDPOINT_ENV = "SPICE_TELEMETRY_ENDPOINT"
+
+	ERROR_CLASS_NONE = ""
+	// The runtime or another service the command needed could not be reached.
+	ERROR_CLASS_UNAVAILABLE = "unavailable"
+	// A request did not finish in time.
+	ERROR_CLASS_TIMEOUT = "timeout"
+	// The runtime rejected the credentials of the request.
+	ERROR_CLASS_AUTH = "auth"
+	// The runtime answered a request with an error.
+	ERROR_CLASS_API = "api"
+	// The command failed for any other reason, e.g. an invalid spicepod.
+	ERROR_CLASS_FAILED = "failed"
+	// The command never finished, e.g. it was killed.
+	ERROR_CLASS_INTERRUPTED = "interrupted"
+
+	queueFileName   = "telemetry.jsonl"
+	pendingFileName = "telemetry.pending.json"
+	// Only the most recent events are kept while they cannot be sent.
+	queueLimit   = 1000
+	flushTimeout = 2 * time.Second
+)
+
+// Event is everything sent about one invocation: no arguments, flag values, paths or
+// identifiers of the user or machine.
+type Event struct {
+	Command    string    `json:"command" csv:"command" yaml:"command"`
+	Time       time.Time `json:"time" csv:"time" yaml:"time"`
+	DurationMs *int64    `json:"duration_ms,omitempty" csv:"duration_ms" yaml:"duration_ms,omitempty"`
+	ErrorClass string    `json:"error_class,omitempty" csv:"error_class" yaml:"error_class,omitempty"`
+	CliVersion string    `json:"cli_version" csv:"cli_version" yaml:"cli_version"`
+	OS         string    `json:"os" csv:"os" yaml:"os"`
+	Arch       string    `json:"arch" csv:"arch" yaml:"arch"`
+}
+
+func NewEvent(command string, start time.Time) Event {
+	return Event{
+		Command:    command,
+		Time:       start.UTC().Truncate(time.Second),
+		CliVersion: version.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+func Endpoint() string {
+	return os.Getenv(ENDPOINT_ENV)
+}
+
+// Begin marks command as running, keeping the marker and recorded events in dotSpiceDir. An
+// invocation that never reaches End is recorded by the next Begin as ERROR_CLASS_INTERRUPTED.
+func Begin(dotSpiceDir string, command string, start time.Time) error {
+	if err := RecoverPending(dotSpiceDir); err != nil {
+		return err
+	}
+	pendingBytes, err := json.Marshal(NewEvent(command, start))
+	if err != nil {
+		return err
+	}
+	path, err := statePath(dotSpiceDir, pendingFileName)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, pendingBytes, 0600)
+}
+
+// End records the invocation marked by Begin.
+func End(dotSpiceDir string, command string, start time.Time, errorClass string) error {
+	path, err := statePath(dotSpiceDir, pendingFileName)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	event := NewEvent(command, start)
+	duration := time.Since(start).Milliseconds()
+	event.DurationMs = &duration
+	event.ErrorClass = errorClass
+	return appendEvents(dotSpiceDir, []Event{event})
+}
+
+// RecoverPending records an invocation that began but never ended as ERROR_CLASS_INTERRUPTED.
+func RecoverPending(dotSpiceDir string) error {
+	path, err := statePath(dotSpiceDir, pendingFileName)
+	if err != nil {
+		return err
+	}
+	pendingBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	os.Remove(path)
+
+	var event Event
+	if err = json.Unmarshal(pendingBytes, &event); err != nil {
+		return nil
+	}
+	event.ErrorClass = ERROR_CLASS_INTERRUPTED
+	return appendEvents(dotSpiceDir, []Event{event})
+}
+
+// Queued returns the recorded events that have not been sent yet, oldest first.
+func Queued(dotSpiceDir string) ([]Event, error) {
+	path, err := statePath(dotSpiceDir, queueFileName)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err == nil {
+			events = append(events, event)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// Flush sends the queued events to endpoint as a JSON array and clears the queue once the
+// endpoint has accepted them. It gives up when ctx ends or after flushTimeout.
+func Flush(ctx context.Context, dotSpiceDir string, endpoint string) error {
+	events, err := Queued(dotSpiceDir)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, flushTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", response.Status)
+	}
+	return Clear(dotSpiceDir)
+}
+
+// Clear deletes the queued events and any running invocation's marker.
+func Clear(dotSpiceDir string) error {
+	for _, name := range []string{queueFileName, pendingFileName} {
+		path, err := statePath(dotSpiceDir, name)
+		if err != nil {
+			return err
+		}
+		if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendEvents(dotSpiceDir string, events []Event) error {
+	queued, err := Queued(dotSpiceDir)
+	if err != nil {
+		return err
+	}
+	queued = append(queued, events...)
+	if len(queued) > queueLimit {
+		queued = queued[len(queued)-queueLimit:]
+	}
+
+	var buf bytes.Buffer
+	for _, event := range queued {
+		eventBytes, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		buf.Write(eventBytes)
+		buf.WriteByte('\n')
+	}
+
+	path, err := statePath(dotSpiceDir, queueFileName)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+func statePath(dotSpiceDir string, name string) (string, error) {
+	if err := os.MkdirAll(dotSpiceDir, 0700); err != nil {
+		return "", err
+	}
+	return filepath.Join(dotSpiceDir, name), nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeginEnd(t *testing.T) {
+	dotSpiceDir := t.TempDir()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, Begin(dotSpiceDir, "sql", start))
+	assert.NoError(t, End(dotSpiceDir, "sql", start, ERROR_CLASS_NONE))
+
+	// An invocation that exits without End is recorded as interrupted by the next one.
+	assert.NoError(t, Begin(dotSpiceDir, "refresh", start))
+	assert.NoError(t, Begin(dotSpiceDir, "status", start))
+	assert.NoError(t, End(dotSpiceDir, "status", start, ERROR_CLASS_UNAVAILABLE))
+
+	events, err := Queued(dotSpiceDir)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, "sql", events[0].Command)
+	assert.NotNil(t, events[0].DurationMs)
+	assert.Equal(t, ERROR_CLASS_NONE, events[0].ErrorClass)
+	assert.Equal(t, "refresh", events[1].Command)
+	assert.Nil(t, events[1].DurationMs)
+	assert.Equal(t, ERROR_CLASS_INTERRUPTED, events[1].ErrorClass)
+	assert.Equal(t, "status", events[2].Command)
+	assert.Equal(t, ERROR_CLASS_UNAVAILABLE, events[2].ErrorClass)
+}
+
+func TestFlush(t *testing.T) {
+	dotSpiceDir := t.TempDir()
+
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	assert.NoError(t, Begin(dotSpiceDir, "datasets", start))
+	assert.NoError(t, End(dotSpiceDir, "datasets", start, ERROR_CLASS_NONE))
+	assert.NoError(t, Flush(context.Background(), dotSpiceDir, server.URL))
+
+	assert.Len(t, received, 1)
+	assert.Equal(t, "datasets", received[0].Command)
+	events, err := Queued(dotSpiceDir)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
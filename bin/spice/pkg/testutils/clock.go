@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/progress"
+)
+
+// FakeClock is a clock that only moves when advanced.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// UseFakeClock makes spinners and progress bars measure elapsed time with a fake clock
+// starting at start, until the test ends.
+func UseFakeClock(t *testing.T, start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	progress.SetClock(c)
+	t.Cleanup(func() { progress.SetClock(nil) })
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+const slowBodyChunkSize = 16
+
+// Fault describes how the FaultProxy misbehaves for matching requests. Faults combine, e.g. a
+// Delay with a truncated body.
+type Fault struct {
+	// Method and Path restrict the fault to matching requests, empty matches any.
+	Method string
+	Path   string
+	// Number of matching requests the fault applies to, negative for all of them.
+	Times int
+
+	// Wait this long before responding, e.g. to trigger client timeouts.
+	Delay time.Duration
+	// Respond with this status and a JSON error instead of forwarding the request.
+	Status int
+	// Close the connection without responding.
+	Drop bool
+	// Write the body in small chunks, waiting this long between them.
+	SlowBody time.Duration
+	// Cut the forwarded body to this many bytes, producing truncated JSON.
+	TruncateAt int
+}
+
+// FaultProxy is a reverse proxy placed between the CLI and a (mock) runtime that injects the
+// faults it is scripted with, in the order they were added.
+type FaultProxy struct {
+	Server *httptest.Server
+	// The sandboxed .spice directory of the runtime contexts returned by Context.
+	DotSpiceDir string
+
+	proxy    *httputil.ReverseProxy
+	mu       sync.Mutex
+	faults   []*Fault
+	requests int
+	injected int
+}
+
+// NewFaultProxy starts a proxy forwarding to target that is closed when the test ends.
+func NewFaultProxy(t *testing.T, target string) *FaultProxy {
+	targetUrl, err := url.Parse(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &FaultProxy{DotSpiceDir: EnsureTestSpiceDirectory(t), proxy: httputil.NewSingleHostReverseProxy(targetUrl)}
+	p.Server = httptest.NewServer(http.HandlerFunc(p.serveHTTP))
+	t.Cleanup(p.Server.Close)
+	return p
+}
+
+// Context returns a runtime context whose HTTP endpoint is the proxy. Its requests are retried
+// without the usual wait.
+func (p *FaultProxy) Context() *context.RuntimeContext {
+	rtcontext := context.NewContextWithDotSpiceDir(p.DotSpiceDir)
+	rtcontext.SetHttpEndpoint(p.Server.URL)
+	rtcontext.SetRetryWait(time.Millisecond, 10*time.Millisecond)
+	return rtcontext
+}
+
+// Inject adds a fault to the script. A request is affected by the first fault that matches it
+// and has not been used up.
+func (p *FaultProxy) Inject(fault Fault) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if fault.Times == 0 {
+		fault.Times = 1
+	}
+	p.faults = append(p.faults, &fault)
+}
+
+// Reset removes all faults, so requests are forwarded untouched.
+func (p *FaultProxy) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = nil
+}
+
+// Requests returns the number of requests received, and how many of them had a fault injected.
+func (p *FaultProxy) Requests() (int, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests, p.injected
+}
+
+func (p *FaultProxy) nextFault(r *http.Request) *Fault {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests++
+	for _, fault := range p.faults {
+		if fault.Times == 0 || (fault.Method != "" && fault.Method != r.Method) || (fault.Path != "" && fault.Path != r.URL.Path) {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+		}
+		p.injected++
+		f := *fault
+		return &f
+	}
+	return nil
+}
+
+func (p *FaultProxy) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	fault := p.nextFault(r)
+	if fault == nil {
+		p.proxy.ServeHTTP(w, r)
+		return
+	}
+
+	if fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault.Drop {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			panic("fault proxy: response writer does not support hijacking")
+		}
+		conn, _, err := hijacker.Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+
+	if fault.Status != 0 {
+		writeMockResponse(w, fault.Status, map[string]string{"message": fmt.Sprintf("injected fault for %s %s", r.Method, r.URL.Path)})
+		return
+	}
+
+	recorder := httptest.NewRecorder()
+	p.proxy.ServeHTTP(recorder, r)
+	body := recorder.Body.Bytes()
+	if fault.TruncateAt > 0 && fault.TruncateAt < len(body) {
+		body = body[:fault.TruncateAt]
+	}
+
+	for key, values := range recorder.Header() {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(recorder.Code)
+
+	if fault.SlowBody <= 0 {
+		_, _ = w.Write(body)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	for start := 0; start < len(body); start += slowBodyChunkSize {
+		end := start + slowBodyChunkSize
+		if end > len(body) {
+			end = len(body)
+		}
+		if _, err := w.Write(body[start:end]); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		select {
+		case <-time.After(fault.SlowBody):
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
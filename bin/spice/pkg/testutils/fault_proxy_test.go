@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultProxy(t *testing.T) {
+	mock := NewMockRuntime(t)
+	mock.SetDatasets(api.Dataset{Name: "taxi_trips", From: "s3://bucket/taxi_trips/"})
+	proxy := NewFaultProxy(t, mock.Server.URL)
+	rtcontext := proxy.Context()
+
+	// Each fault should reach the client unretried, see TestRuntimeApiRetries.
+	assert.NoError(t, rtcontext.SetRetries(0))
+
+	proxy.Inject(Fault{Path: "/v1/datasets", Status: http.StatusServiceUnavailable, Times: 2})
+	for i := 0; i < 2; i++ {
+		_, err := api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+		var apiErr *api.RuntimeApiError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	}
+	datasets, err := api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.NoError(t, err)
+	assert.Len(t, datasets, 1)
+
+	proxy.Inject(Fault{Path: "/v1/datasets", TruncateAt: 20})
+	_, err = api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.ErrorContains(t, err, "Error decoding response")
+
+	// The transport retries idempotent requests on a dropped keep-alive connection, so every
+	// request is dropped.
+	proxy.Inject(Fault{Drop: true, Times: -1})
+	_, err = api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.Error(t, err)
+	proxy.Reset()
+
+	proxy.Inject(Fault{SlowBody: 5 * time.Millisecond})
+	start := time.Now()
+	datasets, err = api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.NoError(t, err)
+	assert.Len(t, datasets, 1)
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+
+	proxy.Inject(Fault{Method: "GET", Path: "/health", Delay: time.Second})
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	_, err = client.Get(proxy.Server.URL + "/health")
+	assert.ErrorContains(t, err, "Client.Timeout exceeded")
+
+	// The unfaulted, truncated and slow requests reached the runtime.
+	requests, injected := proxy.Requests()
+	assert.Equal(t, 1, requests-injected)
+	assert.Equal(t, 3, mock.Requests("GET", "/v1/datasets"))
+}
+
+func TestRuntimeApiRetries(t *testing.T) {
+	mock := NewMockRuntime(t)
+	mock.SetDatasets(api.Dataset{Name: "taxi_trips", From: "s3://bucket/taxi_trips/"})
+	proxy := NewFaultProxy(t, mock.Server.URL)
+	rtcontext := proxy.Context()
+
+	// A dropped connection and an unavailable runtime are retried.
+	proxy.Inject(Fault{Path: "/v1/datasets", Drop: true, Times: 2})
+	proxy.Inject(Fault{Path: "/v1/datasets", Status: http.StatusServiceUnavailable})
+	datasets, err := api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.NoError(t, err)
+	assert.Len(t, datasets, 1)
+	assert.Equal(t, 1, mock.Requests("GET", "/v1/datasets"))
+
+	// The last response is reported once retries are exhausted.
+	proxy.Inject(Fault{Path: "/v1/datasets", Status: http.StatusBadGateway, Times: -1})
+	_, err = api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	var apiErr *api.RuntimeApiError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	proxy.Reset()
+
+	// Errors that retrying won't fix are not retried.
+	proxy.Inject(Fault{Path: "/v1/datasets", Status: http.StatusInternalServerError, Times: -1})
+	requests, _ := proxy.Requests()
+	_, err = api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.Error(t, err)
+	after, _ := proxy.Requests()
+	assert.Equal(t, 1, after-requests)
+	proxy.Reset()
+
+	// Queries are not idempotent in general and are never retried.
+	proxy.Inject(Fault{Path: "/v1/sql", Status: http.StatusServiceUnavailable, Times: -1})
+	requests, _ = proxy.Requests()
+	_, err = api.Sql[map[string]interface{}](rtcontext, "SELECT 1")
+	assert.Error(t, err)
+	after, _ = proxy.Requests()
+	assert.Equal(t, 1, after-requests)
+	proxy.Reset()
+
+	assert.NoError(t, rtcontext.SetRetries(1))
+	proxy.Inject(Fault{Path: "/v1/datasets", Status: http.StatusServiceUnavailable, Times: 2})
+	_, err = api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.Error(t, err)
+	assert.Error(t, rtcontext.SetRetries(-1))
+
+	// A runtime that is not running is reported as unavailable after the retries.
+	proxy.Server.Close()
+	_, err = api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.ErrorContains(t, err, "is unavailable at")
+}
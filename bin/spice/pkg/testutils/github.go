@@ -0,0 +1,315 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	gocontext "context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/github"
+)
+
+// FakeGitHub serves GitHub release metadata and release assets from memory. Requests bound to
+// its Context are sent to it, so install and upgrade paths can be tested without network access.
+type FakeGitHub struct {
+	Server *httptest.Server
+
+	mu        sync.Mutex
+	releases  map[string][]*FakeRelease
+	assets    map[int64][]byte
+	nextId    int64
+	downloads map[string]int
+	// Asset name to the byte offsets at which to cut off its next downloads
+	interrupts map[string][]int
+	// Asset name to how many of its next range requests are answered from the first byte
+	ignoredRanges map[string]int
+}
+
+// FakeRelease is a release of a repository. Assets maps asset names to their content.
+type FakeRelease struct {
+	TagName    string
+	Draft      bool
+	Prerelease bool
+	Assets     map[string][]byte
+
+	assetIds map[string]int64
+}
+
+// NewFakeGitHub starts a fake GitHub that is closed when the test ends.
+func NewFakeGitHub(t *testing.T) *FakeGitHub {
+	f := &FakeGitHub{
+		releases:      map[string][]*FakeRelease{},
+		assets:        map[int64][]byte{},
+		nextId:        1,
+		downloads:     map[string]int{},
+		interrupts:    map[string][]int{},
+		ignoredRanges: map[string]int{},
+	}
+
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Server.Close)
+	return f
+}
+
+// Context returns a copy of ctx whose GitHub requests are sent to the fake GitHub. Interrupted
+// downloads resume without the usual wait.
+func (f *FakeGitHub) Context(ctx gocontext.Context) gocontext.Context {
+	return github.WithSettings(ctx, github.Settings{BaseUrl: f.Server.URL, ResumeBackoff: time.Millisecond})
+}
+
+// AddRelease publishes a release of owner/repo.
+func (f *FakeGitHub) AddRelease(owner string, repo string, release FakeRelease) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	release.assetIds = map[string]int64{}
+	for name, content := range release.Assets {
+		release.assetIds[name] = f.nextId
+		f.assets[f.nextId] = content
+		f.nextId++
+	}
+
+	key := repoKey(owner, repo)
+	f.releases[key] = append(f.releases[key], &release)
+}
+
+// InterruptDownload drops the connection of the next download of an asset once it has served
+// the asset up to offset, e.g. to test resuming. Calls queue further interruptions.
+func (f *FakeGitHub) InterruptDownload(assetName string, offset int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.interrupts[assetName] = append(f.interrupts[assetName], offset)
+}
+
+// IgnoreRange answers the next range request of an asset with all of it as partial content,
+// as a proxy ignoring the requested offset does.
+func (f *FakeGitHub) IgnoreRange(assetName string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ignoredRanges[assetName]++
+}
+
+// Downloads returns how many times an asset was downloaded, through the API or a release URL.
+func (f *FakeGitHub) Downloads(assetName string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.downloads[assetName]
+}
+
+func (f *FakeGitHub) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	// /repos/{owner}/{repo}/releases
+	case len(parts) == 4 && parts[0] == "repos" && parts[3] == "releases":
+		f.writeReleases(w, parts[1], parts[2])
+		return
+	// /repos/{owner}/{repo}/releases/tags/{tag}
+	case len(parts) == 6 && parts[0] == "repos" && parts[3] == "releases" && parts[4] == "tags":
+		for _, release := range f.releases[repoKey(parts[1], parts[2])] {
+			if release.TagName == parts[5] {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(f.repoRelease(parts[1], parts[2], release))
+				return
+			}
+		}
+	// /repos/{owner}/{repo}/releases/assets/{id}
+	case len(parts) == 6 && parts[0] == "repos" && parts[3] == "releases" && parts[4] == "assets":
+		id, err := strconv.ParseInt(parts[5], 10, 64)
+		if err == nil {
+			if name, ok := f.findAsset(parts[1], parts[2], id); ok {
+				f.writeAsset(w, r, name, f.assets[id])
+				return
+			}
+		}
+	// /{owner}/{repo}/releases/download/{tag}/{name}
+	case len(parts) == 6 && parts[2] == "releases" && parts[3] == "download":
+		for _, release := range f.releases[repoKey(parts[0], parts[1])] {
+			if id, ok := release.assetIds[parts[5]]; ok && release.TagName == parts[4] {
+				f.writeAsset(w, r, parts[5], f.assets[id])
+				return
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+	_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+}
+
+func (f *FakeGitHub) writeReleases(w http.ResponseWriter, owner string, repo string) {
+	releases := []github.RepoRelease{}
+	for _, release := range f.releases[repoKey(owner, repo)] {
+		releases = append(releases, f.repoRelease(owner, repo, release))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(releases)
+}
+
+func (f *FakeGitHub) repoRelease(owner string, repo string, release *FakeRelease) github.RepoRelease {
+	repoRelease := github.RepoRelease{
+		TagName:    release.TagName,
+		Name:       release.TagName,
+		Draft:      release.Draft,
+		Prerelease: release.Prerelease,
+		HTMLURL:    fmt.Sprintf("%s/%s/%s/releases/tag/%s", f.Server.URL, owner, repo, release.TagName),
+		Assets:     []github.ReleaseAsset{},
+	}
+	names := make([]string, 0, len(release.assetIds))
+	for name := range release.assetIds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		id := release.assetIds[name]
+		repoRelease.Assets = append(repoRelease.Assets, github.ReleaseAsset{
+			ID:                 id,
+			Name:               name,
+			Size:               int64(len(f.assets[id])),
+			URL:                fmt.Sprintf("%s/repos/%s/%s/releases/assets/%d", f.Server.URL, owner, repo, id),
+			BrowserDownloadURL: fmt.Sprintf("%s/%s/%s/releases/download/%s/%s", f.Server.URL, owner, repo, release.TagName, name),
+			State:              "uploaded",
+			ContentType:        "application/octet-stream",
+		})
+	}
+	return repoRelease
+}
+
+func (f *FakeGitHub) findAsset(owner string, repo string, id int64) (string, bool) {
+	for _, release := range f.releases[repoKey(owner, repo)] {
+		for name, assetId := range release.assetIds {
+			if assetId == id {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// writeAsset serves an asset, honoring Range requests as GitHub's asset storage does.
+func (f *FakeGitHub) writeAsset(w http.ResponseWriter, r *http.Request, name string, content []byte) {
+	f.downloads[name]++
+	w.Header().Set("Content-Type", "application/octet-stream")
+
+	if r.Header.Get("Range") != "" && f.ignoredRanges[name] > 0 {
+		f.ignoredRanges[name]--
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(content)-1, len(content)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(content)
+		return
+	}
+
+	if interrupts := f.interrupts[name]; len(interrupts) > 0 {
+		offset := interrupts[0]
+		f.interrupts[name] = interrupts[1:]
+
+		start := 0
+		if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+			_, _ = fmt.Sscanf(rangeHeader, "bytes=%d-", &start)
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)-start))
+			w.WriteHeader(http.StatusPartialContent)
+		} else {
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusOK)
+		}
+		if offset > start {
+			_, _ = w.Write(content[start:offset])
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		// Aborting the handler closes the connection mid-body
+		panic(http.ErrAbortHandler)
+	}
+
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
+}
+
+func repoKey(owner string, repo string) string {
+	return fmt.Sprintf("%s/%s", owner, repo)
+}
+
+// TarGzAsset builds a release tarball holding files, all of them executable, as the Spice
+// release archives do.
+func TarGzAsset(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0755,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = tarWriter.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// ChecksumsAsset builds the checksums.txt release asset listing the SHA256 digest of each
+// of assets, in the format written by sha256sum.
+func ChecksumsAsset(assets map[string][]byte) []byte {
+	names := make([]string, 0, len(assets))
+	for name := range assets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		hash := sha256.Sum256(assets[name])
+		fmt.Fprintf(&buf, "%s  %s\n", hex.EncodeToString(hash[:]), name)
+	}
+	return buf.Bytes()
+}
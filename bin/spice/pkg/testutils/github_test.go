@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeGitHub(t *testing.T) {
+	fake := NewFakeGitHub(t)
+	asset := TarGzAsset(t, map[string]string{"tool": "#!/bin/sh\necho v1.1.0\n"})
+	fake.AddRelease("org", "tool", FakeRelease{TagName: "v1.0.0", Assets: map[string][]byte{"tool.tar.gz": asset}})
+	fake.AddRelease("org", "tool", FakeRelease{TagName: "v1.1.0", Assets: map[string][]byte{"tool.tar.gz": asset}})
+	fake.AddRelease("org", "tool", FakeRelease{TagName: "v1.2.0-rc1", Prerelease: true, Assets: map[string][]byte{"tool.tar.gz": asset}})
+
+	var warnings bytes.Buffer
+	gh := github.NewGitHubClient("org", "tool").WithContext(fake.Context(context.Background())).WithProgressOutput(&warnings)
+	release, err := github.GetLatestRelease(gh, "tool.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.1.0", release.TagName)
+
+	dir := t.TempDir()
+	assert.NoError(t, github.DownloadReleaseAsset(gh, release, "tool.tar.gz", dir))
+	content, err := os.ReadFile(filepath.Join(dir, "tool"))
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\necho v1.1.0\n", string(content))
+
+	assert.Contains(t, warnings.String(), "Warning: release v1.1.0 has no checksums.txt, the download of tool.tar.gz was not verified.")
+
+	assert.NoError(t, github.DownloadReleaseByTagName(gh, "v1.0.0", t.TempDir(), "tool"))
+	assert.Equal(t, 2, fake.Downloads("tool.tar.gz"))
+	assert.Contains(t, warnings.String(), "Warning: release v1.0.0 has no checksums.txt, the download of tool.tar.gz was not verified.")
+
+	_, err = github.GetLatestRelease(github.NewGitHubClient("org", "missing").WithContext(fake.Context(context.Background())), "")
+	assert.EqualError(t, err, "no releases")
+}
+
+func TestFakeGitHubChecksums(t *testing.T) {
+	fake := NewFakeGitHub(t)
+	asset := TarGzAsset(t, map[string]string{"tool": "#!/bin/sh\necho v1.0.0\n"})
+	tampered := TarGzAsset(t, map[string]string{"tool": "#!/bin/sh\necho pwned\n"})
+	fake.AddRelease("org", "tool", FakeRelease{TagName: "v1.0.0", Assets: map[string][]byte{
+		"tool.tar.gz":   asset,
+		"checksums.txt": ChecksumsAsset(map[string][]byte{"tool.tar.gz": asset}),
+	}})
+	fake.AddRelease("org", "tool", FakeRelease{TagName: "v1.1.0", Assets: map[string][]byte{
+		"tool.tar.gz":   tampered,
+		"checksums.txt": ChecksumsAsset(map[string][]byte{"tool.tar.gz": asset}),
+	}})
+	fake.AddRelease("org", "tool", FakeRelease{TagName: "v1.2.0", Assets: map[string][]byte{
+		"tool.tar.gz":   asset,
+		"checksums.txt": ChecksumsAsset(map[string][]byte{"other.tar.gz": asset}),
+	}})
+
+	gh := github.NewGitHubClient("org", "tool").WithContext(fake.Context(context.Background()))
+	releases, err := github.GetReleases(gh)
+	assert.NoError(t, err)
+	assert.Len(t, releases, 3)
+
+	dir := t.TempDir()
+	assert.NoError(t, github.DownloadReleaseAsset(gh, &releases[0], "tool.tar.gz", dir))
+	assert.FileExists(t, filepath.Join(dir, "tool"))
+	assert.NoError(t, github.DownloadReleaseByTagName(gh, "v1.0.0", t.TempDir(), "tool"))
+
+	dir = t.TempDir()
+	err = github.DownloadReleaseAsset(gh, &releases[1], "tool.tar.gz", dir)
+	var checksumErr *github.ChecksumError
+	assert.True(t, errors.As(err, &checksumErr), "expected a checksum error, got %v", err)
+	assert.Equal(t, "tool.tar.gz", checksumErr.AssetName)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "nothing is extracted and the download is removed")
+	err = github.DownloadReleaseByTagName(gh, "v1.1.0", t.TempDir(), "tool")
+	assert.True(t, errors.As(err, &checksumErr), "expected a checksum error, got %v", err)
+
+	err = github.DownloadReleaseAsset(gh, &releases[2], "tool.tar.gz", t.TempDir())
+	assert.EqualError(t, err, "checksums.txt has no checksum for tool.tar.gz, refusing to install an unverified download")
+}
+
+func TestParseChecksums(t *testing.T) {
+	digest := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	checksums, err := github.ParseChecksums([]byte(digest + "  spiced_linux_x86_64.tar.gz\n\n" + digest + " *spice_linux_x86_64.tar.gz\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"spiced_linux_x86_64.tar.gz": digest, "spice_linux_x86_64.tar.gz": digest}, checksums)
+	assert.NoError(t, github.VerifyChecksum(checksums, "spice_linux_x86_64.tar.gz", []byte("test")))
+
+	_, err = github.ParseChecksums([]byte("not-a-digest  spiced.tar.gz\n"))
+	assert.Error(t, err)
+}
+
+func TestFakeGitHubResume(t *testing.T) {
+	fake := NewFakeGitHub(t)
+	script := "#!/bin/sh\n" + strings.Repeat("# padding\n", 10000)
+	asset := TarGzAsset(t, map[string]string{"tool": script})
+	fake.AddRelease("org", "tool", FakeRelease{TagName: "v1.0.0", Assets: map[string][]byte{
+		"tool.tar.gz":   asset,
+		"checksums.txt": ChecksumsAsset(map[string][]byte{"tool.tar.gz": asset}),
+	}})
+
+	gh := github.NewGitHubClient("org", "tool").WithContext(fake.Context(context.Background()))
+	release, err := github.GetLatestRelease(gh, "tool.tar.gz")
+	assert.NoError(t, err)
+
+	fake.InterruptDownload("tool.tar.gz", 100)
+	fake.InterruptDownload("tool.tar.gz", 200)
+	dir := t.TempDir()
+	assert.NoError(t, github.DownloadReleaseAsset(gh, release, "tool.tar.gz", dir))
+	content, err := os.ReadFile(filepath.Join(dir, "tool"))
+	assert.NoError(t, err)
+	assert.Equal(t, script, string(content))
+	assert.Equal(t, 3, fake.Downloads("tool.tar.gz"))
+
+	fake.InterruptDownload("tool.tar.gz", 50)
+	assert.NoError(t, github.DownloadReleaseByTagName(gh, "v1.0.0", t.TempDir(), "tool"))
+
+	for i := 0; i < 5; i++ {
+		fake.InterruptDownload("tool.tar.gz", 10*(i+1))
+	}
+	dir = t.TempDir()
+	assert.Error(t, github.DownloadReleaseAsset(gh, release, "tool.tar.gz", dir))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "the partial download is removed")
+
+	ctx, cancel := context.WithCancel(fake.Context(context.Background()))
+	cancel()
+	downloads := fake.Downloads("tool.tar.gz")
+	err = github.DownloadReleaseAsset(gh.WithContext(ctx), release, "tool.tar.gz", t.TempDir())
+	assert.ErrorContains(t, err, "context canceled")
+	assert.Equal(t, downloads, fake.Downloads("tool.tar.gz"))
+}
+
+func TestFakeGitHubResumeIgnoringRange(t *testing.T) {
+	fake := NewFakeGitHub(t)
+	script := "#!/bin/sh\n" + strings.Repeat("# padding\n", 10000)
+	// Without checksums, only the Content-Range check keeps the download from being corrupted
+	fake.AddRelease("org", "tool", FakeRelease{TagName: "v1.0.0", Assets: map[string][]byte{
+		"tool.tar.gz": TarGzAsset(t, map[string]string{"tool": script}),
+	}})
+
+	gh := github.NewGitHubClient("org", "tool").WithContext(fake.Context(context.Background())).WithProgressOutput(io.Discard)
+	release, err := github.GetLatestRelease(gh, "tool.tar.gz")
+	assert.NoError(t, err)
+
+	fake.InterruptDownload("tool.tar.gz", 100)
+	fake.IgnoreRange("tool.tar.gz")
+	dir := t.TempDir()
+	assert.NoError(t, github.DownloadReleaseAsset(gh, release, "tool.tar.gz", dir))
+	content, err := os.ReadFile(filepath.Join(dir, "tool"))
+	assert.NoError(t, err)
+	assert.Equal(t, script, string(content))
+	assert.Equal(t, 3, fake.Downloads("tool.tar.gz"), "the download restarts from the first byte")
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"bytes"
+	gocontext "context"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "Update golden files with the actual output")
+
+var (
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	durationPattern  = regexp.MustCompile(`\b(\d+(\.\d+)?(h|m|s|ms|µs|us|ns))+\b`)
+)
+
+// CommandOutput is what a command wrote while running in-process.
+type CommandOutput struct {
+	Stdout string
+	Stderr string
+	Err    error
+}
+
+// RunCommand executes root with args in-process, capturing what commands write to their output
+// and error streams along with the error they return. Flags are reset to their defaults first so
+// commands can be run repeatedly.
+func RunCommand(t *testing.T, root *cobra.Command, args ...string) CommandOutput {
+	t.Helper()
+	return RunCommandContext(t, gocontext.Background(), root, args...)
+}
+
+// RunCommandContext is RunCommand executing root with ctx, e.g. one carrying the dependencies
+// commands should use instead of their defaults.
+func RunCommandContext(t *testing.T, ctx gocontext.Context, root *cobra.Command, args ...string) CommandOutput {
+	t.Helper()
+
+	resetFlags(root)
+	resetContexts(root)
+	root.SetArgs(args)
+
+	var stdout, stderr bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+
+	err := root.ExecuteContext(ctx)
+
+	// cobra leaves its hidden completion command registered after serving a completion request
+	for _, command := range root.Commands() {
+		if command.Name() == cobra.ShellCompRequestCmd {
+			root.RemoveCommand(command)
+		}
+	}
+
+	root.SetOut(nil)
+	root.SetErr(nil)
+	return CommandOutput{Stdout: stdout.String(), Stderr: stderr.String(), Err: err}
+}
+
+// Normalize replaces timestamps and durations, which change between runs, with placeholders.
+// replacements are pairs of literal strings to replace, e.g. a temporary directory.
+func Normalize(output string, replacements ...string) string {
+	output = strings.NewReplacer(replacements...).Replace(output)
+	output = timestampPattern.ReplaceAllString(output, "<timestamp>")
+	return durationPattern.ReplaceAllString(output, "<duration>")
+}
+
+// AssertGolden compares actual with testdata/<test name>.golden. Run the tests with -update to
+// write the actual output as the new golden file.
+func AssertGolden(t *testing.T, actual string) {
+	t.Helper()
+
+	goldenPath := filepath.Join("testdata", strings.ReplaceAll(t.Name(), "/", "_")+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenPath, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("error reading golden file, create it with -update: %s", err.Error())
+	}
+	assert.Equal(t, string(expected), actual, "output differs from %s, run the tests with -update to accept it", goldenPath)
+}
+
+// resetContexts clears the Go context of every command, as cobra only hands the root's context
+// down to subcommands that have none yet.
+func resetContexts(command *cobra.Command) {
+	command.SetContext(nil)
+	for _, child := range command.Commands() {
+		resetContexts(child)
+	}
+}
+
+func resetFlags(command *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sliceValue, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sliceValue.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	command.Flags().VisitAll(reset)
+	command.PersistentFlags().VisitAll(reset)
+
+	for _, child := range command.Commands() {
+		resetFlags(child)
+	}
+}
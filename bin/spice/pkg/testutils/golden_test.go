@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t,
+		"Refreshed at <timestamp> in <duration> (p99 <duration>) from <tmp>/spicepod.yaml v1.2.0",
+		Normalize("Refreshed at 2024-06-01T12:30:45.123Z in 1m2.5s (p99 12.3ms) from /tmp/x1/spicepod.yaml v1.2.0", "/tmp/x1", "<tmp>"))
+}
+
+func TestRunCommand(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	child := &cobra.Command{
+		Use: "child",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("name")
+			if name == "" {
+				return errors.New("name is empty")
+			}
+			cmd.Printf("hello %s\n", name)
+			fmt.Fprintln(cmd.OutOrStdout(), "direct")
+			fmt.Fprintln(cmd.ErrOrStderr(), "warning")
+			return nil
+		},
+	}
+	child.Flags().String("name", "world", "")
+	root.AddCommand(child)
+
+	output := RunCommand(t, root, "child", "--name", "spice")
+	assert.NoError(t, output.Err)
+	assert.Equal(t, "hello spice\ndirect\n", output.Stdout)
+	assert.Equal(t, "warning\n", output.Stderr)
+
+	output = RunCommand(t, root, "child")
+	assert.Equal(t, "hello world\ndirect\n", output.Stdout)
+
+	output = RunCommand(t, root, "child", "--name", "")
+	assert.EqualError(t, output.Err, "name is empty")
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+)
+
+// MockRuntime is an httptest server standing in for spiced. It serves the datasets and models it
+// is configured with, and can be scripted to respond slowly or fail.
+type MockRuntime struct {
+	Server *httptest.Server
+	// The sandboxed .spice directory of the runtime contexts returned by Context.
+	DotSpiceDir string
+
+	mu       sync.Mutex
+	datasets []api.Dataset
+	models   []api.Model
+	routes   map[string]*mockRoute
+	requests map[string]int
+}
+
+// MockRequest is a request received by the mock runtime.
+type MockRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+type mockRoute struct {
+	handler  func(request MockRequest) (int, interface{})
+	latency  time.Duration
+	failures int
+	status   int
+}
+
+// NewMockRuntime starts a mock runtime that is closed when the test ends.
+func NewMockRuntime(t *testing.T) *MockRuntime {
+	m := &MockRuntime{
+		DotSpiceDir: EnsureTestSpiceDirectory(t),
+		routes:      map[string]*mockRoute{},
+		requests:    map[string]int{},
+		datasets:    []api.Dataset{},
+		models:      []api.Model{},
+	}
+
+	m.Handle("GET", "/health", func(MockRequest) (int, interface{}) {
+		return http.StatusOK, "ok"
+	})
+	m.Handle("GET", "/v1/datasets", func(MockRequest) (int, interface{}) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return http.StatusOK, m.datasets
+	})
+	m.Handle("GET", "/v1/models", func(MockRequest) (int, interface{}) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return http.StatusOK, m.models
+	})
+
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	t.Cleanup(m.Server.Close)
+	return m
+}
+
+// Context returns a runtime context whose HTTP endpoint is the mock runtime. Its requests are
+// retried without the usual wait.
+func (m *MockRuntime) Context() *context.RuntimeContext {
+	rtcontext := context.NewContextWithDotSpiceDir(m.DotSpiceDir)
+	rtcontext.SetHttpEndpoint(m.Server.URL)
+	rtcontext.SetRetryWait(time.Millisecond, 10*time.Millisecond)
+	return rtcontext
+}
+
+func (m *MockRuntime) SetDatasets(datasets ...api.Dataset) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.datasets = datasets
+}
+
+func (m *MockRuntime) SetModels(models ...api.Model) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.models = models
+}
+
+// Handle serves a route with handler, replacing any canned response. String bodies are written
+// as text, anything else as JSON.
+func (m *MockRuntime) Handle(method string, path string, handler func(request MockRequest) (int, interface{})) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	route := m.route(method, path)
+	route.handler = handler
+}
+
+// SetLatency delays every response of a route.
+func (m *MockRuntime) SetLatency(method string, path string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.route(method, path).latency = latency
+}
+
+// Fail makes the next times requests to a route respond with status. A negative times fails
+// every request.
+func (m *MockRuntime) Fail(method string, path string, status int, times int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	route := m.route(method, path)
+	route.status, route.failures = status, times
+}
+
+// Requests returns the number of requests received for a route.
+func (m *MockRuntime) Requests(method string, path string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests[routeKey(method, path)]
+}
+
+func (m *MockRuntime) route(method string, path string) *mockRoute {
+	key := routeKey(method, path)
+	if _, ok := m.routes[key]; !ok {
+		m.routes[key] = &mockRoute{}
+	}
+	return m.routes[key]
+}
+
+func (m *MockRuntime) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := routeKey(r.Method, r.URL.Path)
+	m.mu.Lock()
+	m.requests[key]++
+	route, ok := m.routes[key]
+	var handler func(MockRequest) (int, interface{})
+	var latency time.Duration
+	failStatus := 0
+	if ok {
+		handler, latency = route.handler, route.latency
+		if route.failures != 0 {
+			failStatus = route.status
+			if route.failures > 0 {
+				route.failures--
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	switch {
+	case failStatus != 0:
+		writeMockResponse(w, failStatus, map[string]string{"message": fmt.Sprintf("mock failure for %s %s", r.Method, r.URL.Path)})
+	case handler == nil:
+		writeMockResponse(w, http.StatusNotFound, map[string]string{"message": fmt.Sprintf("no mock for %s %s", r.Method, r.URL.Path)})
+	default:
+		status, responseBody := handler(MockRequest{Method: r.Method, Path: r.URL.Path, Body: body})
+		writeMockResponse(w, status, responseBody)
+	}
+}
+
+func writeMockResponse(w http.ResponseWriter, status int, body interface{}) {
+	if text, ok := body.(string); ok {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(text))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func routeKey(method string, path string) string {
+	return fmt.Sprintf("%s %s", method, path)
+}
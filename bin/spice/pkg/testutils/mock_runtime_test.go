@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/api"
+	"github.com/spiceai/spiceai/bin/spice/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockRuntime(t *testing.T) {
+	runtime := NewMockRuntime(t)
+	runtime.SetDatasets(api.Dataset{Name: "taxi_trips", From: "s3://spiceai-demo-datasets/taxi_trips/2024/"})
+	runtime.SetModels(api.Model{Name: "text_to_sql", From: "openai"})
+	rtcontext := runtime.Context()
+
+	assert.NoError(t, util.IsRuntimeServerHealthy(rtcontext.Context(), rtcontext.HttpEndpoint(), http.DefaultClient))
+
+	datasets, err := api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.NoError(t, err)
+	assert.Equal(t, "taxi_trips", datasets[0].Name)
+
+	models, err := api.GetData[api.Model](rtcontext, "/v1/models")
+	assert.NoError(t, err)
+	assert.Equal(t, "text_to_sql", models[0].Name)
+
+	_, err = api.GetData[api.Dataset](rtcontext, "/v1/unknown")
+	var apiErr *api.RuntimeApiError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func TestMockRuntimeFailures(t *testing.T) {
+	runtime := NewMockRuntime(t)
+	rtcontext := runtime.Context()
+
+	runtime.Fail("GET", "/v1/datasets", http.StatusServiceUnavailable, api.DEFAULT_RETRIES+1)
+	_, err := api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	var apiErr *api.RuntimeApiError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+
+	_, err = api.GetData[api.Dataset](rtcontext, "/v1/datasets")
+	assert.NoError(t, err)
+	assert.Equal(t, api.DEFAULT_RETRIES+2, runtime.Requests("GET", "/v1/datasets"))
+
+	runtime.SetLatency("GET", "/v1/models", 50*time.Millisecond)
+	start := time.Now()
+	_, err = api.GetData[api.Model](rtcontext, "/v1/models")
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	runtime.Handle("POST", "/v1/sql", func(request MockRequest) (int, interface{}) {
+		return http.StatusOK, []map[string]string{{"query": string(request.Body)}}
+	})
+	rows, err := api.Sql[map[string]string](rtcontext, "SELECT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1", rows[0]["query"])
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	gocontext "context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// SmokeCase is one entry of the command smoke-test matrix: a command and the arguments to run
+// or parse it with.
+type SmokeCase struct {
+	Command *cobra.Command
+	Args    []string
+	// Parse the flags without running the command.
+	DryRun bool
+}
+
+func (c SmokeCase) Name() string {
+	mode := "help"
+	if c.DryRun {
+		mode = "dry-run"
+	}
+	return fmt.Sprintf("%s/%s", strings.ReplaceAll(c.Command.CommandPath(), " ", "_"), mode)
+}
+
+// CommandMatrix enumerates every command under root. Each command gets a --help case and a
+// dry-run case that sets every flag it accepts, local and inherited, to a sample value.
+func CommandMatrix(root *cobra.Command) []SmokeCase {
+	var cases []SmokeCase
+	walkCommands(root, func(command *cobra.Command) {
+		cases = append(cases, SmokeCase{Command: command, Args: append(commandPath(command), "--help")})
+
+		var flagArgs []string
+		command.Flags().VisitAll(func(f *pflag.Flag) {
+			flagArgs = append(flagArgs, sampleFlagArgs(f)...)
+		})
+		command.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+			flagArgs = append(flagArgs, sampleFlagArgs(f)...)
+		})
+		cases = append(cases, SmokeCase{Command: command, Args: flagArgs, DryRun: true})
+	})
+	return cases
+}
+
+// RunSmokeMatrix checks the command tree for registration conflicts, then runs every case of
+// CommandMatrix as a subtest, failing on errors and panics.
+func RunSmokeMatrix(t *testing.T, root *cobra.Command) {
+	RunSmokeMatrixContext(t, gocontext.Background(), root)
+}
+
+// RunSmokeMatrixContext is RunSmokeMatrix executing root with ctx.
+func RunSmokeMatrixContext(t *testing.T, ctx gocontext.Context, root *cobra.Command) {
+	for _, conflict := range FindRegistrationConflicts(root) {
+		t.Error(conflict)
+	}
+
+	for _, c := range CommandMatrix(root) {
+		c := c
+		t.Run(c.Name(), func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("%s panicked: %v", strings.Join(c.Args, " "), r)
+				}
+			}()
+
+			if !c.DryRun {
+				output := RunCommandContext(t, ctx, root, c.Args...)
+				if output.Err != nil {
+					t.Fatalf("%s failed: %s", strings.Join(c.Args, " "), output.Err.Error())
+				}
+				if !strings.Contains(output.Stdout+output.Stderr, "Usage:") {
+					t.Fatalf("%s printed no usage", strings.Join(c.Args, " "))
+				}
+				return
+			}
+
+			resetFlags(root)
+			defer resetFlags(root)
+			if err := c.Command.ParseFlags(c.Args); err != nil {
+				t.Fatalf("parsing %s failed: %s", strings.Join(c.Args, " "), err.Error())
+			}
+		})
+	}
+}
+
+// FindRegistrationConflicts reports subcommands registered twice under the same name or alias,
+// and local flags whose name or shorthand collides with a flag inherited from a parent.
+func FindRegistrationConflicts(root *cobra.Command) []string {
+	var conflicts []string
+	walkCommands(root, func(command *cobra.Command) {
+		names := map[string]string{}
+		for _, child := range command.Commands() {
+			for _, name := range append([]string{child.Name()}, child.Aliases...) {
+				if other, ok := names[name]; ok {
+					conflicts = append(conflicts, fmt.Sprintf("%s: '%s' is registered by both %s and %s", command.CommandPath(), name, other, child.CommandPath()))
+				}
+				names[name] = child.CommandPath()
+			}
+		}
+
+		// Walk the flags directly: cobra's own flag merging panics on shorthand conflicts.
+		type inheritedFlag struct {
+			flag   *pflag.Flag
+			parent string
+		}
+		inherited := map[string]inheritedFlag{}
+		for parent := command.Parent(); parent != nil; parent = parent.Parent() {
+			parentPath := parent.CommandPath()
+			parent.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+				inherited["--"+f.Name] = inheritedFlag{f, parentPath}
+				if f.Shorthand != "" {
+					inherited["-"+f.Shorthand] = inheritedFlag{f, parentPath}
+				}
+			})
+		}
+		command.Flags().VisitAll(func(f *pflag.Flag) {
+			for _, name := range []string{"--" + f.Name, "-" + f.Shorthand} {
+				if name == "-" || name == "--help" || name == "-h" {
+					continue
+				}
+				if other, ok := inherited[name]; ok && other.flag != f {
+					conflicts = append(conflicts, fmt.Sprintf("%s: flag %s shadows the flag inherited from %s", command.CommandPath(), name, other.parent))
+				}
+			}
+		})
+	})
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+func walkCommands(command *cobra.Command, fn func(command *cobra.Command)) {
+	fn(command)
+	for _, child := range command.Commands() {
+		walkCommands(child, fn)
+	}
+}
+
+// commandPath returns the arguments that select command from the root, e.g. [pods list].
+func commandPath(command *cobra.Command) []string {
+	var path []string
+	for c := command; c.HasParent(); c = c.Parent() {
+		path = append([]string{c.Name()}, path...)
+	}
+	return path
+}
+
+// sampleFlagArgs returns arguments setting f to a value valid for its type. The help flag is
+// skipped, as it short-circuits parsing.
+func sampleFlagArgs(f *pflag.Flag) []string {
+	if f.Name == "help" {
+		return nil
+	}
+
+	name := "--" + f.Name
+	switch f.Value.Type() {
+	case "bool":
+		return []string{name}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "count":
+		return []string{name, "1"}
+	case "float32", "float64":
+		return []string{name, "1.5"}
+	case "duration":
+		return []string{name, "1s"}
+	case "stringArray", "stringSlice":
+		return []string{name, "a", name, "b"}
+	case "intSlice":
+		return []string{name, "1,2"}
+	case "stringToString":
+		return []string{name, "key=value"}
+	}
+	return []string{name, "sample"}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandMatrix(t *testing.T) {
+	root := &cobra.Command{Use: "tool"}
+	root.PersistentFlags().Bool("verbose", false, "")
+	list := &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+	list.Flags().Duration("timeout", time.Second, "")
+	list.Flags().StringArray("filter", nil, "")
+	root.AddCommand(list)
+
+	cases := CommandMatrix(root)
+	assert.Len(t, cases, 4)
+	assert.Equal(t, "tool_list/help", cases[2].Name())
+	assert.Equal(t, []string{"list", "--help"}, cases[2].Args)
+	assert.Equal(t, "tool_list/dry-run", cases[3].Name())
+	assert.Equal(t, []string{"--filter", "a", "--filter", "b", "--timeout", "1s", "--verbose"}, cases[3].Args)
+
+	RunSmokeMatrix(t, root)
+}
+
+func TestFindRegistrationConflicts(t *testing.T) {
+	root := &cobra.Command{Use: "tool"}
+	root.PersistentFlags().StringP("output", "o", "", "")
+	list := &cobra.Command{Use: "list", Aliases: []string{"ls"}}
+	list.Flags().StringP("order", "o", "", "")
+	ls := &cobra.Command{Use: "ls"}
+	root.AddCommand(list, ls)
+
+	assert.Equal(t, []string{
+		"tool list: flag -o shadows the flag inherited from tool",
+		"tool: 'ls' is registered by both tool list and tool ls",
+	}, FindRegistrationConflicts(root))
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package testutils
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -25,25 +24,17 @@ import (
 	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
 )
 
-func EnsureTestSpiceDirectory(t *testing.T) {
-	// Ensure test config directory doesn't exist already so we don't hose it on cleanup
-	_, err := os.Stat(constants.DotSpice)
-	if err == nil {
-		t.Errorf(".spice directory already exists")
-		return
-	}
-
-	podsPath := filepath.Join(constants.DotSpice, "pods")
-	err = os.MkdirAll(podsPath, 0766)
-	if err != nil {
-		t.Error(err)
-		return
-	}
-}
+// EnsureTestSpiceDirectory creates a sandboxed .spice directory in the test's temporary
+// directory, which is removed when the test ends. Pass it to the code under test, e.g. with
+// context.NewContextWithDotSpiceDir, so tests never read or clobber the real ~/.spice and can run
+// in parallel.
+func EnsureTestSpiceDirectory(t *testing.T) string {
+	t.Helper()
 
-func CleanupTestSpiceDirectory() {
-	err := os.RemoveAll(constants.DotSpice)
+	dotSpiceDir := filepath.Join(t.TempDir(), constants.DotSpice)
+	err := os.MkdirAll(filepath.Join(dotSpiceDir, "pods"), 0766)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
+	return dotSpiceDir
 }
@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spiceai/spiceai/bin/spice/pkg/constants"
+	"github.com/spiceai/spiceai/bin/spice/pkg/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnsureTestSpiceDirectory(t *testing.T) {
+	defaultDir, err := constants.DotSpiceDir()
+	assert.NoError(t, err)
+
+	dotSpiceDir := EnsureTestSpiceDirectory(t)
+	assert.DirExists(t, filepath.Join(dotSpiceDir, "pods"))
+	assert.NotEqual(t, defaultDir, dotSpiceDir)
+	assert.NotEqual(t, dotSpiceDir, EnsureTestSpiceDirectory(t))
+
+	rtcontext := context.NewContextWithDotSpiceDir(dotSpiceDir)
+	assert.Equal(t, dotSpiceDir, rtcontext.SpiceRuntimeDir())
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timing
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	PHASE_CONFIG = "config"
+	PHASE_HTTP   = "http"
+	PHASE_RENDER = "render"
+)
+
+var phases = []string{PHASE_CONFIG, PHASE_HTTP, PHASE_RENDER}
+
+type Span struct {
+	Phase  string
+	Detail string
+	// Offset from Enable to the start of the span.
+	Offset   time.Duration
+	Duration time.Duration
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	start   time.Time
+	spans   []Span
+)
+
+// Enable starts recording spans. Until it is called, Start is a no-op.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+	start = time.Now()
+	spans = nil
+}
+
+// Start begins a span and returns the function that ends it, e.g.
+// defer timing.Start(timing.PHASE_CONFIG, path)().
+func Start(phase string, detail string) func() {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return func() {}
+	}
+
+	spanStart := time.Now()
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		spans = append(spans, Span{Phase: phase, Detail: detail, Offset: spanStart.Sub(start), Duration: time.Since(spanStart)})
+	}
+}
+
+// Spans returns the ended spans in the order they ended.
+func Spans() []Span {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Span(nil), spans...)
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip times a request until its response headers are received.
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	defer Start(PHASE_HTTP, fmt.Sprintf("%s %s", request.Method, redact(request.URL)))()
+	return t.next.RoundTrip(request)
+}
+
+// InstrumentHTTP records a span for every request sent through http.DefaultTransport, which
+// every client without its own Transport uses.
+func InstrumentHTTP() {
+	http.DefaultTransport = Transport(http.DefaultTransport)
+}
+
+// Transport returns next recording a span for every request, for clients with their own
+// Transport. The spans are only recorded once Enable is called.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next}
+}
+
+func redact(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	redacted.RawQuery = ""
+	return redacted.String()
+}
+
+// Report writes every span and the total time per phase since Enable. Time outside any span,
+// e.g. in flag parsing or waiting between requests, is reported as other.
+func Report(w io.Writer) {
+	total := func() time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		return time.Since(start)
+	}()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OFFSET\tPHASE\tDURATION\tDETAIL")
+	totals := map[string]time.Duration{}
+	for _, span := range Spans() {
+		totals[span.Phase] += span.Duration
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", round(span.Offset), span.Phase, round(span.Duration), span.Detail)
+	}
+	fmt.Fprintln(tw)
+
+	other := total
+	for _, phase := range phases {
+		other -= totals[phase]
+		fmt.Fprintf(tw, "\t%s\t%s\t\n", phase, round(totals[phase]))
+	}
+	if other < 0 {
+		other = 0
+	}
+	fmt.Fprintf(tw, "\tother\t%s\t\n", round(other))
+	fmt.Fprintf(tw, "\ttotal\t%s\t\n", round(total))
+	tw.Flush()
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(100 * time.Microsecond)
+}
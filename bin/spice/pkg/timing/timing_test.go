@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timing
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpans(t *testing.T) {
+	Start(PHASE_CONFIG, "before enable")()
+	Enable()
+	Start(PHASE_CONFIG, "/home/user/.spice/config.yaml")()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := &http.Client{Transport: &transport{next: http.DefaultTransport}}
+	response, err := client.Get(server.URL + "/v1/datasets?status=true")
+	assert.NoError(t, err)
+	response.Body.Close()
+
+	spans := Spans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "/home/user/.spice/config.yaml", spans[0].Detail)
+	assert.Equal(t, PHASE_HTTP, spans[1].Phase)
+	assert.Equal(t, "GET "+server.URL+"/v1/datasets", spans[1].Detail)
+
+	var out bytes.Buffer
+	Report(&out)
+	assert.True(t, strings.HasPrefix(out.String(), "OFFSET"))
+	assert.Contains(t, out.String(), "GET "+server.URL+"/v1/datasets")
+	assert.Contains(t, out.String(), "total")
+}
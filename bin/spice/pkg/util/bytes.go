@@ -18,8 +18,16 @@ package util
 
 import (
 	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB"}
+
+var byteSizePattern = regexp.MustCompile(`^\s*([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)\s*$`)
+
 // Returns new hash if b has changed from a
 func ComputeNewHash(a []byte, hashA []byte, b []byte) ([]byte, error) {
 	if a == nil && b == nil {
@@ -43,3 +51,39 @@ func ComputeNewHash(a []byte, hashA []byte, b []byte) ([]byte, error) {
 
 	return hashB, nil
 }
+
+// FormatBytes formats a byte count with binary units, e.g. 128.0MiB.
+func FormatBytes(bytes float64) string {
+	i := 0
+	for bytes >= 1024 && i < len(byteUnits)-1 {
+		bytes /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f%s", bytes, byteUnits[i])
+	}
+	return fmt.Sprintf("%.1f%s", bytes, byteUnits[i])
+}
+
+// ParseBytes parses a size such as 512MiB, 10GB or 1024. Units are binary, so 1GB is 1GiB.
+func ParseBytes(size string) (int64, error) {
+	match := byteSizePattern.FindStringSubmatch(size)
+	if match == nil {
+		return 0, fmt.Errorf("invalid size '%s'", size)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size '%s': %w", size, err)
+	}
+
+	unit := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(match[2]), "B"), "I")
+	for i, u := range []string{"", "K", "M", "G", "T"} {
+		if unit == u {
+			for ; i > 0; i-- {
+				value *= 1024
+			}
+			return int64(value), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid size '%s': unknown unit '%s'", size, match[2])
+}
@@ -83,3 +83,22 @@ func testComputeNewHashFunc(tc computeNewHashTestCase) func(*testing.T) {
 		assert.Equal(t, tc.expectedResult, encoded)
 	}
 }
+
+func TestParseBytes(t *testing.T) {
+	for size, expected := range map[string]int64{
+		"1024":   1024,
+		"512MiB": 512 * 1024 * 1024,
+		"10GB":   10 * 1024 * 1024 * 1024,
+		"1.5 kb": 1536,
+	} {
+		actual, err := ParseBytes(size)
+		assert.NoError(t, err, size)
+		assert.Equal(t, expected, actual, size)
+	}
+
+	_, err := ParseBytes("10 parsecs")
+	assert.Error(t, err)
+
+	assert.Equal(t, "512B", FormatBytes(512))
+	assert.Equal(t, "1.5GiB", FormatBytes(1.5*1024*1024*1024))
+}
@@ -125,7 +125,7 @@ func ExtractTarGzInsideZip(body []byte, downloadDir string) error {
 func ExtractTarGz(body []byte, downloadDir string) error {
 	bodyReader := bytes.NewReader(body)
 	err := Untar(bodyReader, downloadDir, true)
-	if err.Error() == "requires gzip-compressed body: gzip: invalid header" {
+	if err != nil && err.Error() == "requires gzip-compressed body: gzip: invalid header" {
 		_, err = bodyReader.Seek(0, io.SeekStart)
 		if err != nil {
 			return err
@@ -135,6 +135,59 @@ func ExtractTarGz(body []byte, downloadDir string) error {
 	return err
 }
 
+// ExtractTarGzFile extracts the tarball at path, gzipped or not, without reading it into memory.
+func ExtractTarGzFile(path string, downloadDir string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = Untar(file, downloadDir, true)
+	if err != nil && err.Error() == "requires gzip-compressed body: gzip: invalid header" {
+		_, err = file.Seek(0, io.SeekStart)
+		if err != nil {
+			return err
+		}
+		return Untar(file, downloadDir, false)
+	}
+	return err
+}
+
+// ExtractZipFile extracts the zip archive at path, without reading it into memory.
+func ExtractZipFile(path string, downloadDir string) error {
+	zipReader, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zipReader.Close()
+
+	for _, file := range zipReader.File {
+		err = extractZipEntry(file, downloadDir)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipEntry(file *zip.File, downloadDir string) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	newFile, err := os.Create(filepath.Join(downloadDir, file.FileInfo().Name()))
+	if err != nil {
+		return err
+	}
+	defer newFile.Close()
+
+	_, err = io.Copy(newFile, reader)
+	return err
+}
+
 // We have to manually swap out environment variables,
 // as Viper's AutomaticEnv() doesn't work with Unmarshal() and the workarounds do not work for nested structures.
 // See https://github.com/spf13/viper/issues/761
@@ -18,16 +18,21 @@ package util
 
 import (
 	"fmt"
-	"os"
 	"reflect"
 	"strings"
 
 	"github.com/olekukonko/tablewriter"
+	"github.com/spiceai/spiceai/bin/spice/pkg/timing"
 )
 
-func WriteTable(items []interface{}) {
+// WriteTable prints items, structs of the same type, as a table with a column per field, or
+// in the output's format.
+func (o *Output) WriteTable(items []interface{}) error {
+	if o.format != OUTPUT_TABLE {
+		return o.writeRecords(structRecords(items, o.format))
+	}
 	if len(items) == 0 {
-		return
+		return nil
 	}
 
 	t := reflect.TypeOf(items[0])
@@ -37,7 +42,27 @@ func WriteTable(items []interface{}) {
 		headers[i] = strings.TrimSuffix(t.Field(i).Name, "Enabled")
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		v := reflect.ValueOf(item)
+		row := make([]string, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			row[i] = fmt.Sprintf("%v", v.Field(i))
+		}
+		rows = append(rows, row)
+	}
+
+	return o.WriteRowsTable(headers, rows)
+}
+
+// WriteRowsTable writes rows in the same layout as WriteTable, for results whose columns are
+// only known at runtime, such as query results.
+func (o *Output) WriteRowsTable(headers []string, rows [][]string) error {
+	defer timing.Start(timing.PHASE_RENDER, fmt.Sprintf("table of %d rows", len(rows)))()
+	if o.format != OUTPUT_TABLE {
+		return o.writeRecords(rowRecords(headers, rows))
+	}
+	table := tablewriter.NewWriter(o.w)
 	table.SetHeader(headers)
 	table.SetAutoWrapText(false)
 	table.SetAutoFormatHeaders(true)
@@ -50,14 +75,17 @@ func WriteTable(items []interface{}) {
 	table.SetTablePadding(" ")
 	table.SetNoWhiteSpace(true)
 
-	for _, item := range items {
-		v := reflect.ValueOf(item)
-		row := make([]string, v.NumField())
-		for i := 0; i < v.NumField(); i++ {
-			row[i] = fmt.Sprintf("%v", v.Field(i))
-		}
+	for _, row := range rows {
 		table.Append(row)
 	}
 
 	table.Render()
+	return nil
+}
+
+func (o *Output) writeRecords(records []record) error {
+	if err := writeRecords(o.w, records, o.format); err != nil {
+		return fmt.Errorf("error writing %s output: %w", o.format, err)
+	}
+	return nil
 }
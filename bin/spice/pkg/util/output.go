@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	OUTPUT_TABLE = "table"
+	OUTPUT_JSON  = "json"
+	OUTPUT_YAML  = "yaml"
+	OUTPUT_CSV   = "csv"
+)
+
+var OutputFormats = []string{OUTPUT_TABLE, OUTPUT_JSON, OUTPUT_YAML, OUTPUT_CSV}
+
+// Output is where a command prints listings, in one of OutputFormats.
+type Output struct {
+	w      io.Writer
+	format string
+}
+
+// NewOutput returns an Output writing to w in format, e.g. from --output. An empty format is
+// OUTPUT_TABLE.
+func NewOutput(w io.Writer, format string) (*Output, error) {
+	format = strings.ToLower(format)
+	if format == "" {
+		format = OUTPUT_TABLE
+	}
+	if !slices.Contains(OutputFormats, format) {
+		return nil, fmt.Errorf("invalid output format %q, expected one of: %s", format, strings.Join(OutputFormats, ", "))
+	}
+	return &Output{w: w, format: format}, nil
+}
+
+func (o *Output) Format() string {
+	return o.format
+}
+
+func (o *Output) Writer() io.Writer {
+	return o.w
+}
+
+// record is one item of machine-readable output, keeping the order of its fields.
+type record struct {
+	keys   []string
+	values []interface{}
+}
+
+func (r record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyBytes, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyBytes)
+		buf.WriteByte(':')
+		buf.Write(valueBytes)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (r record) mapSlice() yaml.MapSlice {
+	items := make(yaml.MapSlice, len(r.keys))
+	for i, key := range r.keys {
+		items[i] = yaml.MapItem{Key: key, Value: r.values[i]}
+	}
+	return items
+}
+
+// structRecords converts the structs WriteTable prints into records keyed by the field's tag
+// for the format, e.g. json:"name", or its snake_cased name.
+func structRecords(items []interface{}, format string) []record {
+	records := make([]record, 0, len(items))
+	for _, item := range items {
+		v := reflect.Indirect(reflect.ValueOf(item))
+		t := v.Type()
+		var r record
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			key, _, _ := strings.Cut(field.Tag.Get(format), ",")
+			if key == "-" {
+				continue
+			}
+			if key == "" {
+				key = snakeCase(field.Name)
+			}
+			r.keys = append(r.keys, key)
+			r.values = append(r.values, v.Field(i).Interface())
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func rowRecords(headers []string, rows [][]string) []record {
+	records := make([]record, 0, len(rows))
+	for _, row := range rows {
+		r := record{keys: headers, values: make([]interface{}, len(row))}
+		for i, value := range row {
+			r.values[i] = value
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func writeRecords(w io.Writer, records []record, format string) error {
+	switch format {
+	case OUTPUT_JSON:
+		if records == nil {
+			records = []record{}
+		}
+		recordsBytes, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(recordsBytes))
+		return err
+	case OUTPUT_YAML:
+		items := make([]yaml.MapSlice, len(records))
+		for i, r := range records {
+			items[i] = r.mapSlice()
+		}
+		recordsBytes, err := yaml.Marshal(items)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(recordsBytes)
+		return err
+	case OUTPUT_CSV:
+		if len(records) == 0 {
+			return nil
+		}
+		writer := csv.NewWriter(w)
+		if err := writer.Write(records[0].keys); err != nil {
+			return err
+		}
+		for _, r := range records {
+			values := make([]string, len(r.values))
+			for i, value := range r.values {
+				if value != nil {
+					values[i] = fmt.Sprintf("%v", value)
+				}
+			}
+			if err := writer.Write(values); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	return fmt.Errorf("invalid output format %q", format)
+}
+
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
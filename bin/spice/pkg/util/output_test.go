@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type outputTestItem struct {
+	Name              string
+	DependsOn         string `json:"depends_on_dataset"`
+	Rows              int    `yaml:"row_count" csv:"rows"`
+	Internal          string `json:"-" yaml:"-" csv:"-"`
+	AccelerationReady bool
+}
+
+func TestNewOutput(t *testing.T) {
+	var buf bytes.Buffer
+	out, err := NewOutput(&buf, "JSON")
+	assert.NoError(t, err)
+	assert.Equal(t, OUTPUT_JSON, out.Format())
+	out, err = NewOutput(&buf, "")
+	assert.NoError(t, err)
+	assert.Equal(t, OUTPUT_TABLE, out.Format())
+	_, err = NewOutput(&buf, "xml")
+	assert.Error(t, err)
+}
+
+func TestWriteRecords(t *testing.T) {
+	items := []interface{}{
+		outputTestItem{Name: "orders", DependsOn: "customers", Rows: 10, Internal: "x", AccelerationReady: true},
+		&outputTestItem{Name: "taxi, trips", Rows: 3},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeRecords(&buf, structRecords(items, OUTPUT_JSON), OUTPUT_JSON))
+	assert.Equal(t, `[
+  {
+    "name": "orders",
+    "depends_on_dataset": "customers",
+    "rows": 10,
+    "acceleration_ready": true
+  },
+  {
+    "name": "taxi, trips",
+    "depends_on_dataset": "",
+    "rows": 3,
+    "acceleration_ready": false
+  }
+]
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, writeRecords(&buf, structRecords(items[:1], OUTPUT_YAML), OUTPUT_YAML))
+	assert.Equal(t, `- name: orders
+  depends_on: customers
+  row_count: 10
+  acceleration_ready: true
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, writeRecords(&buf, structRecords(items, OUTPUT_CSV), OUTPUT_CSV))
+	assert.Equal(t, "name,depends_on,rows,acceleration_ready\norders,customers,10,true\n\"taxi, trips\",,3,false\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, writeRecords(&buf, rowRecords([]string{"a", "b"}, nil), OUTPUT_JSON))
+	assert.Equal(t, "[]\n", buf.String())
+}
@@ -16,8 +16,17 @@ limitations under the License.
 
 package util
 
-import "runtime"
+import (
+	"os"
+	"runtime"
+)
 
 func IsWindows() bool {
 	return runtime.GOOS == "windows"
 }
+
+// IsTerminal reports whether file is an interactive terminal rather than a pipe or file.
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
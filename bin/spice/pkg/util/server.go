@@ -17,18 +17,24 @@ limitations under the License.
 package util
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 )
 
-func IsRuntimeServerHealthy(serverBaseUrl string, httpClient *http.Client) error {
+func IsRuntimeServerHealthy(ctx context.Context, serverBaseUrl string, httpClient *http.Client) error {
 	url := fmt.Sprintf("%s/health", serverBaseUrl)
-	resp, err := httpClient.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		return errors.New(resp.Status)
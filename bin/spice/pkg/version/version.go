@@ -19,6 +19,8 @@ package version
 import (
 	"fmt"
 	"strings"
+
+	"golang.org/x/mod/semver"
 )
 
 var (
@@ -35,3 +37,19 @@ func Version() string {
 
 	return fmt.Sprintf("v%s", version)
 }
+
+// CheckRuntimeCompatibility fails unless runtimeVersion can be used with the CLI at cliVersion.
+// Minor releases may change the API between the CLI and runtime, so both must share a major
+// and minor version. Local builds of the CLI accept any runtime.
+func CheckRuntimeCompatibility(cliVersion string, runtimeVersion string) error {
+	if !semver.IsValid(runtimeVersion) {
+		return fmt.Errorf("invalid runtime version %s, expected a release tag such as v0.14.0", runtimeVersion)
+	}
+	if strings.HasPrefix(cliVersion, "local") || !semver.IsValid(cliVersion) {
+		return nil
+	}
+	if semver.MajorMinor(cliVersion) != semver.MajorMinor(runtimeVersion) {
+		return fmt.Errorf("runtime %s is not compatible with Spice CLI %s, install a %s.x runtime or run spice upgrade first", runtimeVersion, cliVersion, semver.MajorMinor(cliVersion))
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The Spice.ai OSS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRuntimeCompatibility(t *testing.T) {
+	assert.NoError(t, CheckRuntimeCompatibility("v0.14.1", "v0.14.0"))
+	assert.NoError(t, CheckRuntimeCompatibility("v0.14.0", "v0.14.2-rc1"))
+	assert.NoError(t, CheckRuntimeCompatibility("local-dev", "v0.1.0"))
+	assert.EqualError(t, CheckRuntimeCompatibility("v0.14.0", "v0.13.0"), "runtime v0.13.0 is not compatible with Spice CLI v0.14.0, install a v0.14.x runtime or run spice upgrade first")
+	assert.Error(t, CheckRuntimeCompatibility("v0.14.0", "0.14"))
+}
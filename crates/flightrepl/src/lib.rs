@@ -61,6 +61,18 @@ pub struct ReplConfig {
 }
 
 const NQL_LINE_PREFIX: &str = "nql ";
+const NSQL_COMMAND_PREFIX: &str = ".nsql ";
+
+/// File the REPL history is loaded from and saved to, set by the spice CLI.
+const HISTORY_FILE_ENV: &str = "SPICE_REPL_HISTORY_FILE";
+
+fn add_history_entry(rl: &mut DefaultEditor, line: &str, history_file: Option<&str>) {
+    let _ = rl.add_history_entry(line);
+    // Saved after every entry, so the history survives the REPL exiting on an error.
+    if let Some(history_file) = history_file {
+        let _ = rl.save_history(history_file);
+    }
+}
 
 async fn send_nsql_request(
     client: &Client,
@@ -81,6 +93,36 @@ async fn send_nsql_request(
         .await
 }
 
+/// Use the `POST v1/nsql` HTTP endpoint to generate the SQL answering a question, without running it.
+async fn generate_nsql_query(
+    client: &Client,
+    base_url: String,
+    query: String,
+    runtime: NSQLRuntime,
+) -> Result<String, Box<dyn std::error::Error>> {
+    let resp = client
+        .post(format!("{base_url}/v1/nsql"))
+        .header("Content-Type", "application/json")
+        .json(&json!({
+            "query": query,
+            "use": runtime,
+            "sql_only": true,
+        }))
+        .send()
+        .await?;
+    let status = resp.status();
+    let body = resp.text().await?;
+    if !status.is_success() {
+        return Err(body.into());
+    }
+
+    let value: serde_json::Value = serde_json::from_str(&body)?;
+    match value.get("sql").and_then(serde_json::Value::as_str) {
+        Some(sql) => Ok(sql.to_string()),
+        None => Err("the runtime did not return the generated SQL, upgrade it to use .nsql".into()),
+    }
+}
+
 #[allow(clippy::too_many_lines)]
 #[allow(clippy::missing_errors_doc)]
 pub async fn run(repl_config: ReplConfig) -> Result<(), Box<dyn std::error::Error>> {
@@ -95,6 +137,12 @@ pub async fn run(repl_config: ReplConfig) -> Result<(), Box<dyn std::error::Erro
         .max_encoding_message_size(500 * 1024 * 1024);
 
     let mut rl = DefaultEditor::new()?;
+    let history_file = std::env::var(HISTORY_FILE_ENV)
+        .ok()
+        .filter(|history_file| !history_file.is_empty());
+    if let Some(history_file) = &history_file {
+        let _ = rl.load_history(history_file);
+    }
 
     println!("Welcome to the Spice.ai SQL REPL! Type 'help' for help.\n");
     println!("show tables; -- list available tables");
@@ -120,6 +168,7 @@ pub async fn run(repl_config: ReplConfig) -> Result<(), Box<dyn std::error::Erro
         if line.is_empty() {
             continue;
         }
+        let generated_query: String;
         let line = match line {
             ".exit" | "exit" | "quit" | "q" => break,
             ".error" => {
@@ -139,6 +188,10 @@ pub async fn run(repl_config: ReplConfig) -> Result<(), Box<dyn std::error::Erro
                     "{} Show details of the last error",
                     prompt_color.paint(".error:")
                 );
+                println!(
+                    "{} Generate SQL for a question and run it once confirmed",
+                    prompt_color.paint(".nsql <question>:")
+                );
                 println!("{} Show this help message", prompt_color.paint("help:"));
                 println!("\nOther lines will be interpreted as SQL");
                 continue;
@@ -147,17 +200,45 @@ pub async fn run(repl_config: ReplConfig) -> Result<(), Box<dyn std::error::Erro
                 "select table_catalog, table_schema, table_name, table_type from information_schema.tables where table_schema != 'information_schema'"
             }
             line if line.to_lowercase().starts_with(NQL_LINE_PREFIX) => {
-                let _ = rl.add_history_entry(line);
+                add_history_entry(&mut rl, line, history_file.as_deref());
                 get_and_display_nql_records(
                     repl_config.http_endpoint.clone(),
                      line.strip_prefix(NQL_LINE_PREFIX).unwrap_or(line).to_string()
                 ).await.map_err(|e| format!("Error occured on NQL request: {e}"))?;
                 continue;
             }
+            line if line.to_lowercase().starts_with(NSQL_COMMAND_PREFIX) => {
+                add_history_entry(&mut rl, line, history_file.as_deref());
+                let question = line[NSQL_COMMAND_PREFIX.len()..].trim().to_string();
+                match generate_nsql_query(
+                    &Client::new(),
+                    repl_config.http_endpoint.clone(),
+                    question,
+                    NSQLRuntime::Openai,
+                )
+                .await
+                {
+                    Ok(query) => generated_query = query,
+                    Err(e) => {
+                        println!("Error generating SQL: {}", Colour::Red.paint(e.to_string()));
+                        continue;
+                    }
+                }
+
+                println!("{}", Colour::Blue.paint(&generated_query));
+                let confirmed = rl.readline("Run this query? [y/n] (y): ").map_or(false, |answer| {
+                    matches!(answer.trim().to_lowercase().as_str(), "" | "y" | "yes")
+                });
+                if !confirmed {
+                    println!("The query was not run.");
+                    continue;
+                }
+                generated_query.as_str()
+            }
             _ => line,
         };
 
-        let _ = rl.add_history_entry(line);
+        add_history_entry(&mut rl, line, history_file.as_deref());
 
         let start_time = Instant::now();
         match get_records(client.clone(), line).await {
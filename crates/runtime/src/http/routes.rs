@@ -58,6 +58,7 @@ pub(crate) fn routes(
             patch(v1::datasets::acceleration),
         )
         .route("/v1/spicepods", get(v1::spicepods::get))
+        .route("/v1/features", get(v1::features::get))
         .route_layer(middleware::from_fn(track_metrics));
 
     if cfg!(feature = "models") {
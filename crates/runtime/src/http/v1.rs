@@ -625,6 +625,111 @@ pub(crate) mod spicepods {
     }
 }
 
+pub(crate) mod features {
+    use axum::{
+        http::status,
+        response::{IntoResponse, Response},
+        Json,
+    };
+    use serde::Serialize;
+
+    #[derive(Debug, Serialize)]
+    pub(crate) struct Feature {
+        name: &'static str,
+        description: &'static str,
+        enabled: bool,
+    }
+
+    /// Reports the optional features this runtime was compiled with, so clients can tell
+    /// whether the installed build supports the components a spicepod uses.
+    pub(crate) async fn get() -> Response {
+        let features = vec![
+            Feature {
+                name: "duckdb",
+                description: "DuckDB data connector and accelerator",
+                enabled: cfg!(feature = "duckdb"),
+            },
+            Feature {
+                name: "postgres",
+                description: "PostgreSQL data connector and accelerator",
+                enabled: cfg!(feature = "postgres"),
+            },
+            Feature {
+                name: "sqlite",
+                description: "SQLite accelerator",
+                enabled: cfg!(feature = "sqlite"),
+            },
+            Feature {
+                name: "mysql",
+                description: "MySQL data connector",
+                enabled: cfg!(feature = "mysql"),
+            },
+            Feature {
+                name: "clickhouse",
+                description: "ClickHouse data connector",
+                enabled: cfg!(feature = "clickhouse"),
+            },
+            Feature {
+                name: "flightsql",
+                description: "Arrow Flight SQL data connector",
+                enabled: cfg!(feature = "flightsql"),
+            },
+            Feature {
+                name: "databricks",
+                description: "Databricks data connector",
+                enabled: cfg!(feature = "databricks"),
+            },
+            Feature {
+                name: "spark",
+                description: "Spark Connect data connector",
+                enabled: cfg!(feature = "spark"),
+            },
+            Feature {
+                name: "dremio",
+                description: "Dremio data connector",
+                enabled: cfg!(feature = "dremio"),
+            },
+            Feature {
+                name: "snowflake",
+                description: "Snowflake data connector",
+                enabled: cfg!(feature = "snowflake"),
+            },
+            Feature {
+                name: "odbc",
+                description: "ODBC data connector",
+                enabled: cfg!(feature = "odbc"),
+            },
+            Feature {
+                name: "ftp",
+                description: "FTP and SFTP data connectors",
+                enabled: cfg!(feature = "ftp"),
+            },
+            Feature {
+                name: "models",
+                description: "Model inference, LLMs, embeddings and NSQL",
+                enabled: cfg!(feature = "models"),
+            },
+            Feature {
+                name: "keyring-secret-store",
+                description: "Keyring secret store",
+                enabled: cfg!(feature = "keyring-secret-store"),
+            },
+            Feature {
+                name: "aws-secrets-manager",
+                description: "AWS Secrets Manager secret store",
+                enabled: cfg!(feature = "aws-secrets-manager"),
+            },
+            Feature {
+                name: "dev",
+                description: "Development build",
+                enabled: cfg!(feature = "dev"),
+            },
+        ];
+
+        (status::StatusCode::OK, Json(features)).into_response()
+    }
+}
+
 pub(crate) mod models {
     use std::{collections::HashMap, sync::Arc};
 
@@ -954,6 +1059,15 @@ pub(crate) mod nsql {
 
         #[serde(rename = "use", default = "default_model")]
         pub model: String,
+
+        /// Respond with the generated SQL instead of running it, so the client can confirm it first.
+        #[serde(default)]
+        pub sql_only: bool,
+    }
+
+    #[derive(Debug, Serialize, Deserialize)]
+    pub struct SqlOnlyResponse {
+        pub sql: String,
     }
 
     fn default_model() -> String {
@@ -1019,6 +1133,10 @@ pub(crate) mod nsql {
         match result {
             Ok(Some(model_sql_query)) => {
                 let cleaned_query = clean_model_based_sql(&model_sql_query);
+                if payload.sql_only {
+                    return (StatusCode::OK, Json(SqlOnlyResponse { sql: cleaned_query }))
+                        .into_response();
+                }
                 tracing::trace!("Running query:\n{cleaned_query}");
 
                 sql_to_http_response(